@@ -0,0 +1,122 @@
+package bitfield
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// RegisterAccessor is implemented by bus backends that can read and write
+// registers, such as I2C, SPI or memory-mapped I/O drivers, or mocks in tests.
+// Addresses are the register offsets recorded by RegisterMap; backends that need
+// a base address should add it themselves.
+type RegisterAccessor interface {
+	Read(ctx context.Context, addr uint64) (uint64, error)
+	Write(ctx context.Context, addr uint64, value uint64) error
+}
+
+// ReadRegister reads the register from the bus and returns the value read.
+// The value is loaded into reg, with ReadToClear fields cleared as the hardware does.
+// Returns an error if the read fails or the value does not fit in the register.
+func ReadRegister[U storageType](ctx context.Context, acc RegisterAccessor, reg *Register[U]) (U, error) {
+	raw, err := acc.Read(ctx, reg.Offset)
+	if err != nil {
+		return 0, fmt.Errorf("register %s: read: %w", reg.Name, err)
+	}
+	if value := U(raw); uint64(value) != raw {
+		return 0, fmt.Errorf("register %s: read value 0x%X exceeds register width", reg.Name, raw)
+	}
+	reg.applyRead(U(raw))
+	return U(raw), nil
+}
+
+// WriteRegister writes the current value of reg to the bus.
+func WriteRegister[U storageType](ctx context.Context, acc RegisterAccessor, reg *Register[U]) error {
+	if err := acc.Write(ctx, reg.Offset, uint64(reg.Value())); err != nil {
+		return fmt.Errorf("register %s: write: %w", reg.Name, err)
+	}
+	return nil
+}
+
+// ReadField reads the register from the bus and returns the value of one field.
+// The value read is loaded into reg.
+// Returns an error if the field does not exist, is write-only, or the read fails.
+func ReadField[U storageType](ctx context.Context, acc RegisterAccessor, reg *Register[U], field string) (uint64, error) {
+	bf, err := reg.readable(field)
+	if err != nil {
+		return 0, err
+	}
+	value, err := ReadRegister(ctx, acc, reg)
+	if err != nil {
+		return 0, err
+	}
+	return bf.Decode(value), nil
+}
+
+// WriteField sets one field of a register with a read-modify-write cycle on the bus,
+// preserving all other fields. Fields with write side effects (W1C, W1S, latches)
+// are written as 0 so they are not triggered, and the effect of the write is
+// applied to reg.
+// Returns an error if the field does not exist, is read-only, the value does not fit,
+// or the bus access fails. Nothing is written if validation fails.
+func WriteField[U storageType](ctx context.Context, acc RegisterAccessor, reg *Register[U], field string, value uint64) error {
+	if _, err := reg.writable(field, value); err != nil {
+		return err
+	}
+	if _, err := ReadRegister(ctx, acc, reg); err != nil {
+		return err
+	}
+	word, err := reg.writeWord(field, value, reg.Value())
+	if err != nil {
+		return err
+	}
+	if err := acc.Write(ctx, reg.Offset, uint64(word)); err != nil {
+		return fmt.Errorf("register %s: write: %w", reg.Name, err)
+	}
+	reg.applyWrite(word)
+	return nil
+}
+
+// MemoryAccessor is a RegisterAccessor backed by a map, for tests and simulations.
+// Unwritten addresses read as 0. It is safe for concurrent use.
+type MemoryAccessor struct {
+	mu     sync.Mutex
+	values map[uint64]uint64
+	reads  int
+	writes int
+}
+
+// NewMemoryAccessor creates an empty MemoryAccessor.
+func NewMemoryAccessor() *MemoryAccessor {
+	return &MemoryAccessor{values: make(map[uint64]uint64)}
+}
+
+// Read returns the value stored at addr.
+func (m *MemoryAccessor) Read(ctx context.Context, addr uint64) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reads++
+	return m.values[addr], nil
+}
+
+// Write stores value at addr.
+func (m *MemoryAccessor) Write(ctx context.Context, addr uint64, value uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writes++
+	m.values[addr] = value
+	return nil
+}
+
+// Counts returns the number of reads and writes performed so far.
+func (m *MemoryAccessor) Counts() (reads, writes int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reads, m.writes
+}
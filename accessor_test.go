@@ -0,0 +1,97 @@
+package bitfield
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// failingAccessor returns err from every bus operation.
+type failingAccessor struct{ err error }
+
+func (f failingAccessor) Read(context.Context, uint64) (uint64, error) { return 0, f.err }
+func (f failingAccessor) Write(context.Context, uint64, uint64) error  { return f.err }
+
+func TestReadField(t *testing.T) {
+	ctx := context.Background()
+	m := newTestRegisterMap(t)
+	ctrl, _ := m.ByName("CTRL")
+	bus := NewMemoryAccessor()
+	_ = bus.Write(ctx, ctrl.Offset, 0x00FF0705)
+
+	got, err := ReadField(ctx, bus, ctrl, "status")
+	if err != nil || got != 7 {
+		t.Errorf("ReadField(status) = %v, %v, want 7, nil", got, err)
+	}
+	if ctrl.Value() != 0x00FF0705 {
+		t.Errorf("register value = 0x%08X, want 0x00FF0705", ctrl.Value())
+	}
+	if _, err := ReadField(ctx, bus, ctrl, "command"); err == nil {
+		t.Error("ReadField(command) on write-only field succeeded, want error")
+	}
+	if _, err := ReadField(ctx, bus, ctrl, "missing"); err == nil {
+		t.Error("ReadField(missing) succeeded, want error")
+	}
+}
+
+func TestWriteField(t *testing.T) {
+	ctx := context.Background()
+	m := newTestRegisterMap(t)
+	data, _ := m.ByName("DATA")
+	ctrl, _ := m.ByName("CTRL")
+	bus := NewMemoryAccessor()
+	_ = bus.Write(ctx, ctrl.Offset, 0x00000A01)
+
+	if err := WriteField(ctx, bus, ctrl, "mode", 3); err != nil {
+		t.Fatalf("WriteField(mode): %v", err)
+	}
+	if got, _ := bus.Read(ctx, ctrl.Offset); got != 0x00000A07 {
+		t.Errorf("bus value = 0x%08X, want 0x00000A07", got)
+	}
+	if got, _ := bus.Read(ctx, data.Offset); got != 0 {
+		t.Errorf("DATA was modified: 0x%08X", got)
+	}
+
+	_, writes := bus.Counts()
+	for _, tt := range []struct {
+		field string
+		value uint64
+	}{
+		{"status", 1},  // read-only
+		{"mode", 4},    // too large
+		{"missing", 0}, // unknown
+	} {
+		if err := WriteField(ctx, bus, ctrl, tt.field, tt.value); err == nil {
+			t.Errorf("WriteField(%q, %v) succeeded, want error", tt.field, tt.value)
+		}
+	}
+	if _, after := bus.Counts(); after != writes {
+		t.Errorf("rejected writes reached the bus: %d writes, want %d", after, writes)
+	}
+}
+
+func TestAccessor_Errors(t *testing.T) {
+	ctx := context.Background()
+	reg := newTestRegister(t)
+	errBus := errors.New("bus fault")
+	acc := failingAccessor{errBus}
+
+	if _, err := ReadField(ctx, acc, reg, "mode"); !errors.Is(err, errBus) {
+		t.Errorf("ReadField: err = %v, want %v", err, errBus)
+	}
+	if err := WriteField(ctx, acc, reg, "mode", 1); !errors.Is(err, errBus) {
+		t.Errorf("WriteField: err = %v, want %v", err, errBus)
+	}
+
+	bus := NewMemoryAccessor()
+	_ = bus.Write(ctx, reg.Offset, 1<<40)
+	if _, err := ReadRegister(ctx, bus, reg); err == nil {
+		t.Error("ReadRegister of 40-bit value into 32-bit register succeeded, want error")
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := ReadField(cancelled, bus, reg, "mode"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadField with cancelled context: err = %v, want context.Canceled", err)
+	}
+}
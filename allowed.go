@@ -0,0 +1,83 @@
+package bitfield
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// WithAllowed returns a copy of the field that only accepts the given values,
+// for fields whose other encodings are reserved, such as a mode field where only
+// 0, 1, 2 and 4 are defined. IsValid, and with it Encode, Update and
+// Layout.Validate, reject the values that are not allowed.
+// Calling WithAllowed without values removes the restriction.
+// Panics if a value does not fit in the field.
+func (bf BitField[T, U]) WithAllowed(values ...T) BitField[T, U] {
+	meta := bf.meta.clone()
+	meta.allowed = nil
+	if len(values) > 0 {
+		meta.allowed = make(map[T]bool, len(values))
+	}
+	for _, value := range values {
+		if uint64(value) > uint64(bf.Mask>>bf.Shift) {
+			panic(fmt.Sprintf("allowed value %v out of range, max %v", value, bf.Max()))
+		}
+		meta.allowed[value] = true
+	}
+	bf.meta = meta
+	return bf
+}
+
+// Allowed returns the allowed values of the field in ascending order,
+// or nil if every value that fits in the field is allowed.
+func (bf BitField[T, U]) Allowed() []T {
+	if bf.meta == nil || bf.meta.allowed == nil {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(bf.meta.allowed))
+}
+
+// invalidValue returns the error for a value that IsValid rejects.
+func (bf BitField[T, U]) invalidValue(value T) error {
+	if uint64(value) > uint64(bf.Mask>>bf.Shift) {
+		return fmt.Errorf("value %v out of range, max %v", value, bf.Max())
+	}
+	return fmt.Errorf("value %v: %w", value, ErrValueNotAllowed)
+}
+
+// clamp returns the largest allowed value not above value, or the smallest
+// allowed value if there is none. Fields without allowed values return value.
+func (bf BitField[T, U]) clamp(value T) T {
+	allowed := bf.Allowed()
+	if allowed == nil {
+		return value
+	}
+	i, found := slices.BinarySearch(allowed, value)
+	switch {
+	case found:
+		return value
+	case i == 0:
+		return allowed[0]
+	default:
+		return allowed[i-1]
+	}
+}
+
+// SetAllowed restricts a field of the layout to the given values, as WithAllowed does.
+// Validate then rejects containers in which the field holds another value.
+// Calling SetAllowed without values removes the restriction.
+// Returns an error if the layout has no such field or a value does not fit in the field.
+func (l *Layout[U]) SetAllowed(name string, values ...uint64) error {
+	i, ok := l.index[name]
+	if !ok {
+		return fmt.Errorf("unknown field %q", name)
+	}
+	bf := l.fields[i].field
+	for _, value := range values {
+		if value > uint64(bf.Mask>>bf.Shift) {
+			return fmt.Errorf("allowed value %v out of range for field %q, max %v", value, name, bf.Max())
+		}
+	}
+	l.fields[i].field = bf.WithAllowed(values...)
+	return nil
+}
@@ -0,0 +1,127 @@
+package bitfield
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBitField_WithAllowed(t *testing.T) {
+	plain := New[uint8, uint32](4, 3)
+	bf := plain.WithAllowed(4, 0, 2, 1)
+
+	if got := plain.Allowed(); got != nil {
+		t.Errorf("Allowed() on plain field = %v, want nil", got)
+	}
+	if got, want := bf.Allowed(), []uint8{0, 1, 2, 4}; !slices.Equal(got, want) {
+		t.Errorf("Allowed() = %v, want %v", got, want)
+	}
+	for value := range uint8(10) {
+		want := value == 0 || value == 1 || value == 2 || value == 4
+		if got := bf.IsValid(value); got != want {
+			t.Errorf("IsValid(%d) = %v, want %v", value, got, want)
+		}
+	}
+	if got := bf.Update(0xFFFFFFFF, 2); got != 0xFFFFFFAF {
+		t.Errorf("Update(0xFFFFFFFF, 2) = 0x%X, want 0xFFFFFFAF", got)
+	}
+	if got := bf.WithAllowed().Allowed(); got != nil {
+		t.Errorf("Allowed() after WithAllowed() = %v, want nil", got)
+	}
+	if !bf.WithAllowed().IsValid(7) {
+		t.Error("IsValid(7) = false after removing the restriction")
+	}
+}
+
+func TestBitField_WithAllowedKeepsEnum(t *testing.T) {
+	bf := New[Color, uint32](0, 3).WithEnum(colorNames).WithAllowed(Red, Green)
+	if name, ok := bf.Name(Green); !ok || name != "Green" {
+		t.Errorf("Name(Green) = %q, %v, want Green, true", name, ok)
+	}
+	if _, err := bf.Parse("Blue"); err != nil {
+		t.Errorf("Parse(Blue) = %v, want the registered value", err)
+	}
+	if _, err := bf.Parse("2"); !errors.Is(err, ErrValueNotAllowed) {
+		t.Errorf("Parse(2) = %v, want ErrValueNotAllowed", err)
+	}
+}
+
+func TestBitField_WithAllowedPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithAllowed(8) did not panic for a 3-bit field")
+		}
+	}()
+	New[uint8, uint32](0, 3).WithAllowed(1, 8)
+}
+
+func TestBitField_EncodeRejectsDisallowed(t *testing.T) {
+	bf := New[uint8, uint32](0, 3).WithAllowed(0, 1, 2, 4)
+	defer func() {
+		if recover() == nil {
+			t.Error("Encode(3) did not panic for a disallowed value")
+		}
+	}()
+	bf.Encode(3)
+}
+
+func TestBitField_EncodeClampAllowed(t *testing.T) {
+	bf := New[uint8, uint32](0, 4).WithAllowed(2, 4, 9)
+	tests := []struct {
+		value uint8
+		want  uint32
+	}{
+		{0, 2},
+		{2, 2},
+		{3, 2},
+		{8, 4},
+		{9, 9},
+		{200, 9},
+	}
+	for _, tt := range tests {
+		if got := bf.EncodeClamp(tt.value); got != tt.want {
+			t.Errorf("EncodeClamp(%d) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLayout_SetAllowed(t *testing.T) {
+	l := newStatusLayout(t)
+	if err := l.SetAllowed("category", 0, 1, 2, 4); err != nil {
+		t.Fatalf("SetAllowed: %v", err)
+	}
+
+	tests := []struct {
+		container uint32
+		want      error
+	}{
+		{0x00000040, nil},
+		{0x00000030, ErrValueNotAllowed},
+		{0x00010040, ErrReservedBits},
+	}
+	for _, tt := range tests {
+		if err := l.Validate(tt.container); !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+			t.Errorf("Validate(0x%X) = %v, want %v", tt.container, err, tt.want)
+		}
+	}
+
+	if _, err := l.EncodeAll(map[string]uint64{"category": 3}); !errors.Is(err, ErrValueNotAllowed) {
+		t.Errorf("EncodeAll(category=3) = %v, want ErrValueNotAllowed", err)
+	}
+	if _, err := l.EncodeAll(map[string]uint64{"category": 4}); err != nil {
+		t.Errorf("EncodeAll(category=4) = %v", err)
+	}
+
+	if err := l.SetAllowed("missing", 1); err == nil {
+		t.Error("SetAllowed(missing) succeeded, want error")
+	}
+	if err := l.SetAllowed("category", 16); err == nil {
+		t.Error("SetAllowed(category, 16) succeeded, want error")
+	}
+	if err := l.SetAllowed("category"); err != nil {
+		t.Fatalf("SetAllowed(category) = %v", err)
+	}
+	if err := l.Validate(0x00000030); err != nil {
+		t.Errorf("Validate(0x30) = %v after removing the restriction", err)
+	}
+}
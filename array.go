@@ -0,0 +1,81 @@
+package bitfield
+
+import "fmt"
+
+// FieldArray represents Count identical adjacent fields of Size bits each,
+// such as the 16 2-bit pin modes of a GPIO mode register.
+// Element i starts at bit Shift + i*Size.
+// T represents the type of values that can be stored in each element.
+// U represents the container type where the fields will be stored.
+type FieldArray[T Unsigned, U storageType] struct {
+	Shift uint // Position of the least significant bit of element 0
+	Size  uint // Number of bits in each element
+	Count uint // Number of elements
+}
+
+// NewFieldArray creates a new FieldArray with count elements of the given size starting at shift.
+// Note: This function doesn't perform validation, use SafeFieldArray for validated creation.
+func NewFieldArray[T Unsigned, U storageType](shift, size, count uint) FieldArray[T, U] {
+	return FieldArray[T, U]{Shift: shift, Size: size, Count: count}
+}
+
+// SafeFieldArray creates a new FieldArray after validating the parameters.
+// Returns an error if:
+// - size or count is 0
+// - size exceeds the bit size of type T
+// - the elements would exceed the container type U
+func SafeFieldArray[T Unsigned, U storageType](shift, size, count uint) (FieldArray[T, U], error) {
+	switch {
+	case size == 0 || size > unsignedSizeOf[T]():
+		return FieldArray[T, U]{}, ErrSizeOutOfRange
+	case count == 0:
+		return FieldArray[T, U]{}, fmt.Errorf("invalid count parameter")
+	case shift+size*count > unsignedSizeOf[U]():
+		return FieldArray[T, U]{}, &FieldOverflowError{Shift: shift, Size: size * count, Width: unsignedSizeOf[U]()}
+	}
+	return NewFieldArray[T, U](shift, size, count), nil
+}
+
+// Len returns the number of elements of the array.
+func (fa FieldArray[T, U]) Len() int {
+	return int(fa.Count)
+}
+
+// At returns the BitField of element i.
+// Panics if i is out of range.
+func (fa FieldArray[T, U]) At(i int) BitField[T, U] {
+	if i < 0 || uint(i) >= fa.Count {
+		panic(fmt.Sprintf("index %d out of range [0, %d)", i, fa.Count))
+	}
+	return New[T, U](fa.Shift+uint(i)*fa.Size, fa.Size)
+}
+
+// DecodeAt extracts element i from the container.
+// Panics if i is out of range.
+func (fa FieldArray[T, U]) DecodeAt(container U, i int) T {
+	return fa.At(i).Decode(container)
+}
+
+// UpdateAt sets element i within an existing container to value.
+// Panics if i is out of range or the value is too large for the element.
+func (fa FieldArray[T, U]) UpdateAt(previous U, i int, value T) U {
+	return fa.At(i).Update(previous, value)
+}
+
+// DecodeAll extracts every element from the container, in index order.
+func (fa FieldArray[T, U]) DecodeAll(container U) []T {
+	values := make([]T, fa.Count)
+	for i := range values {
+		values[i] = fa.At(i).Decode(container)
+	}
+	return values
+}
+
+// UpdateAll sets the first len(values) elements within an existing container.
+// Panics if more values than elements are given or a value is too large for its element.
+func (fa FieldArray[T, U]) UpdateAll(previous U, values []T) U {
+	for i, v := range values {
+		previous = fa.At(i).Update(previous, v)
+	}
+	return previous
+}
@@ -0,0 +1,88 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSafeFieldArray(t *testing.T) {
+	tests := []struct {
+		name               string
+		shift, size, count uint
+		wantErr            bool
+	}{
+		{"gpio moder", 0, 2, 16, false},
+		{"offset array", 8, 4, 6, false},
+		{"zero size", 0, 0, 4, true},
+		{"zero count", 0, 2, 0, true},
+		{"too wide for value type", 0, 9, 2, true},
+		{"exceeds container", 4, 2, 15, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SafeFieldArray[uint8, uint32](tt.shift, tt.size, tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SafeFieldArray(%d, %d, %d): err = %v, want err = %v",
+					tt.shift, tt.size, tt.count, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFieldArray_At(t *testing.T) {
+	fa := NewFieldArray[uint8, uint32](4, 3, 5)
+	if fa.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", fa.Len())
+	}
+	bf := fa.At(2)
+	if bf.Shift != 10 || bf.Size != 3 || bf.Mask != 0x1C00 {
+		t.Errorf("At(2) = %+v, want shift 10, size 3, mask 0x1C00", bf)
+	}
+
+	for _, i := range []int{-1, 5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("At(%d) did not panic", i)
+				}
+			}()
+			fa.At(i)
+		}()
+	}
+}
+
+func TestFieldArray_UpdateAt(t *testing.T) {
+	// GPIO mode register: 16 pins with 2 bits each.
+	moder := NewFieldArray[uint8, uint32](0, 2, 16)
+	var reg uint32
+	reg = moder.UpdateAt(reg, 0, 1)
+	reg = moder.UpdateAt(reg, 5, 2)
+	reg = moder.UpdateAt(reg, 15, 3)
+	if reg != 0xC0000801 {
+		t.Errorf("register = 0x%08X, want 0xC0000801", reg)
+	}
+	if got := moder.DecodeAt(reg, 5); got != 2 {
+		t.Errorf("DecodeAt(5) = %d, want 2", got)
+	}
+	reg = moder.UpdateAt(reg, 15, 0)
+	if reg != 0x00000801 {
+		t.Errorf("register after clearing pin 15 = 0x%08X, want 0x00000801", reg)
+	}
+}
+
+func TestFieldArray_DecodeAll(t *testing.T) {
+	fa := NewFieldArray[uint8, uint64](8, 4, 4)
+	got := fa.DecodeAll(0x0000_0000_00DC_BA00)
+	if want := []uint8{0xA, 0xB, 0xC, 0xD}; !slices.Equal(got, want) {
+		t.Errorf("DecodeAll() = %v, want %v", got, want)
+	}
+}
+
+func TestFieldArray_UpdateAll(t *testing.T) {
+	fa := NewFieldArray[uint8, uint64](8, 4, 4)
+	got := fa.UpdateAll(0xFFFF_FFFF_FFFF_FFFF, []uint8{1, 2, 3})
+	if got != 0xFFFF_FFFF_FFF3_21FF {
+		t.Errorf("UpdateAll() = 0x%X, want 0xFFFFFFFFFFF321FF", got)
+	}
+}
@@ -0,0 +1,41 @@
+package bitfield
+
+// Atomic is the subset of the sync/atomic integer types used by the atomic field helpers.
+// *atomic.Uint32 implements Atomic[uint32] and *atomic.Uint64 implements Atomic[uint64].
+type Atomic[U storageType] interface {
+	Load() U
+	CompareAndSwap(old, new U) bool
+}
+
+// DecodeAtomic atomically loads the container and extracts the bit field from it.
+func (bf BitField[T, U]) DecodeAtomic(container Atomic[U]) T {
+	return bf.Decode(container.Load())
+}
+
+// UpdateAtomic atomically sets the bit field within a shared container to value.
+// Other bits of the container are preserved, even if they are modified concurrently.
+// It retries with a compare-and-swap loop until the update succeeds
+// and returns the new container value.
+// Panics if the value is too large for the field.
+func (bf BitField[T, U]) UpdateAtomic(container Atomic[U], value T) U {
+	encoded := bf.Encode(value)
+	for {
+		old := container.Load()
+		updated := (old &^ bf.Mask) | encoded
+		if container.CompareAndSwap(old, updated) {
+			return updated
+		}
+	}
+}
+
+// ClearAtomic atomically zeroes out the bits of this field in a shared container
+// and returns the new container value.
+func (bf BitField[T, U]) ClearAtomic(container Atomic[U]) U {
+	for {
+		old := container.Load()
+		updated := old &^ bf.Mask
+		if container.CompareAndSwap(old, updated) {
+			return updated
+		}
+	}
+}
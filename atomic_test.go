@@ -0,0 +1,67 @@
+package bitfield
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBitField_UpdateAtomic(t *testing.T) {
+	var word atomic.Uint32
+	word.Store(0xFFFFFFFF)
+
+	bf := New[uint8, uint32](2, 3)
+	if got := bf.UpdateAtomic(&word, 3); got != 0xFFFFFFEF {
+		t.Errorf("UpdateAtomic(3) = 0x%08X, want 0xFFFFFFEF", got)
+	}
+	if got := word.Load(); got != 0xFFFFFFEF {
+		t.Errorf("container = 0x%08X, want 0xFFFFFFEF", got)
+	}
+	if got := bf.DecodeAtomic(&word); got != 3 {
+		t.Errorf("DecodeAtomic() = %v, want 3", got)
+	}
+	if got := bf.ClearAtomic(&word); got != 0xFFFFFFE3 {
+		t.Errorf("ClearAtomic() = 0x%08X, want 0xFFFFFFE3", got)
+	}
+}
+
+func TestBitField_UpdateAtomicPanics(t *testing.T) {
+	var word atomic.Uint64
+	bf := New[uint8, uint64](0, 2)
+	defer func() {
+		if recover() == nil {
+			t.Error("UpdateAtomic(4) did not panic")
+		}
+		if got := word.Load(); got != 0 {
+			t.Errorf("container = 0x%X after failed update, want 0", got)
+		}
+	}()
+	bf.UpdateAtomic(&word, 4)
+}
+
+func TestBitField_UpdateAtomicConcurrent(t *testing.T) {
+	// Each goroutine owns one 8-bit field and counts it up; no update may be lost.
+	var word atomic.Uint64
+	const iterations = 1000
+
+	var wg sync.WaitGroup
+	for i := uint(0); i < 8; i++ {
+		bf := New[uint8, uint64](i*8, 8)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < iterations; n++ {
+				bf.UpdateAtomic(&word, uint8(n%256))
+			}
+			bf.UpdateAtomic(&word, uint8(i+1))
+		}()
+	}
+	wg.Wait()
+
+	for i := uint(0); i < 8; i++ {
+		bf := New[uint8, uint64](i*8, 8)
+		if got := bf.DecodeAtomic(&word); got != uint8(i+1) {
+			t.Errorf("field %d = %v, want %v", i, got, i+1)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// AppendBinary appends the container to b in the given byte order and returns the extended slice.
+// The encoding is unsignedSizeOf(U)/8 bytes long; containers of type uint use the
+// platform word size, so use uint32 or uint64 for data that crosses platforms.
+func AppendBinary[U storageType](b []byte, value U, order binary.ByteOrder) []byte {
+	n := len(b)
+	b = append(b, make([]byte, registerBytes[U]())...)
+	putBinary(b[n:], value, order)
+	return b
+}
+
+// UnmarshalBinary decodes a container from data in the given byte order.
+// Returns an error if data is not exactly the size of the container.
+func UnmarshalBinary[U storageType](data []byte, order binary.ByteOrder) (U, error) {
+	if size := registerBytes[U](); uint64(len(data)) != size {
+		return 0, fmt.Errorf("invalid binary length %d, want %d", len(data), size)
+	}
+	return getBinary[U](data, order), nil
+}
+
+// putBinary stores value in b, which must be the size of U.
+func putBinary[U storageType](b []byte, value U, order binary.ByteOrder) {
+	if len(b) == 4 {
+		order.PutUint32(b, uint32(value))
+	} else {
+		order.PutUint64(b, uint64(value))
+	}
+}
+
+// getBinary loads a value of type U from b, which must be the size of U.
+func getBinary[U storageType](b []byte, order binary.ByteOrder) U {
+	if len(b) == 4 {
+		return U(order.Uint32(b))
+	}
+	return U(order.Uint64(b))
+}
+
+// SetByteOrder sets the byte order used for the binary encoding of the layout's containers.
+// The default is big-endian.
+func (l *Layout[U]) SetByteOrder(order binary.ByteOrder) {
+	l.order = order
+}
+
+// ByteOrder returns the byte order used for the binary encoding of the layout's containers.
+func (l *Layout[U]) ByteOrder() binary.ByteOrder {
+	if l.order == nil {
+		return binary.BigEndian
+	}
+	return l.order
+}
+
+// MarshalBinary encodes the container in the byte order of its layout.
+func (p Packed[U]) MarshalBinary() ([]byte, error) {
+	return p.AppendBinary(nil)
+}
+
+// AppendBinary appends the container to b in the byte order of its layout.
+func (p Packed[U]) AppendBinary(b []byte) ([]byte, error) {
+	l, err := p.layout()
+	if err != nil {
+		return nil, err
+	}
+	return AppendBinary(b, p.Value, l.ByteOrder()), nil
+}
+
+// UnmarshalBinary decodes the container from data in the byte order of its layout.
+// Returns an error, leaving p unchanged, if p has no layout or data is not
+// exactly the size of the container.
+func (p *Packed[U]) UnmarshalBinary(data []byte) error {
+	l, err := p.layout()
+	if err != nil {
+		return err
+	}
+	value, err := UnmarshalBinary[U](data, l.ByteOrder())
+	if err != nil {
+		return err
+	}
+	p.Value = value
+	return nil
+}
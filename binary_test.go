@@ -0,0 +1,66 @@
+package bitfield
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestAppendBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []byte
+		want []byte
+	}{
+		{"uint32 big-endian", AppendBinary[uint32](nil, 0x11223344, binary.BigEndian), []byte{0x11, 0x22, 0x33, 0x44}},
+		{"uint32 little-endian", AppendBinary[uint32](nil, 0x11223344, binary.LittleEndian), []byte{0x44, 0x33, 0x22, 0x11}},
+		{"uint64 big-endian", AppendBinary[uint64](nil, 0x0102030405060708, binary.BigEndian), []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{"appends", AppendBinary[uint32]([]byte{0xFF}, 1, binary.LittleEndian), []byte{0xFF, 1, 0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		if !bytes.Equal(tt.got, tt.want) {
+			t.Errorf("%s: AppendBinary() = % X, want % X", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalBinary(t *testing.T) {
+	got, err := UnmarshalBinary[uint32]([]byte{0x44, 0x33, 0x22, 0x11}, binary.LittleEndian)
+	if err != nil || got != 0x11223344 {
+		t.Errorf("UnmarshalBinary() = 0x%08X, %v, want 0x11223344, nil", got, err)
+	}
+	for _, data := range [][]byte{nil, {1, 2, 3}, {1, 2, 3, 4, 5}} {
+		if _, err := UnmarshalBinary[uint32](data, binary.BigEndian); err == nil {
+			t.Errorf("UnmarshalBinary(% X) succeeded, want error", data)
+		}
+	}
+}
+
+func TestPacked_Binary(t *testing.T) {
+	l := newStatusLayout(t)
+	if l.ByteOrder() != binary.BigEndian {
+		t.Errorf("default ByteOrder() = %v, want big-endian", l.ByteOrder())
+	}
+	data, err := l.Bind(0x00002A57).MarshalBinary()
+	if err != nil || !bytes.Equal(data, []byte{0, 0, 0x2A, 0x57}) {
+		t.Errorf("MarshalBinary() = % X, %v, want 00 00 2A 57, nil", data, err)
+	}
+
+	l.SetByteOrder(binary.LittleEndian)
+	data, err = l.Bind(0x00002A57).AppendBinary([]byte{0xFF})
+	if err != nil || !bytes.Equal(data, []byte{0xFF, 0x57, 0x2A, 0, 0}) {
+		t.Errorf("AppendBinary() = % X, %v, want FF 57 2A 00 00, nil", data, err)
+	}
+
+	p := l.Bind(0)
+	if err := p.UnmarshalBinary([]byte{0x57, 0x2A, 0, 0}); err != nil || p.Value != 0x00002A57 {
+		t.Errorf("UnmarshalBinary() = 0x%08X, %v, want 0x00002A57, nil", p.Value, err)
+	}
+	if err := p.UnmarshalBinary([]byte{1, 2}); err == nil || p.Value != 0x00002A57 {
+		t.Errorf("UnmarshalBinary of short data: Value = 0x%08X, err = %v, want unchanged and error", p.Value, err)
+	}
+	if _, err := (Packed[uint32]{}).MarshalBinary(); err == nil {
+		t.Error("MarshalBinary without layout succeeded, want error")
+	}
+}
@@ -0,0 +1,190 @@
+// Package bitboard provides 64-square board sets for chess and other 8x8 games.
+//
+// Squares are numbered 0 to 63 in little-endian rank-file order: square
+// rank*8 + file, so a1 is 0, h1 is 7 and h8 is 63. Bit i of a Bitboard is set
+// if square i is in the set.
+//
+// The one-square shifts such as North and East drop squares moved off the
+// board instead of wrapping them around to the opposite edge.
+package bitboard
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Bitboard is a set of squares.
+type Bitboard uint64
+
+// Files and ranks.
+const (
+	FileA Bitboard = 0x0101010101010101 << iota
+	FileB
+	FileC
+	FileD
+	FileE
+	FileF
+	FileG
+	FileH
+)
+
+const (
+	Rank1 Bitboard = 0xFF << (8 * iota)
+	Rank2
+	Rank3
+	Rank4
+	Rank5
+	Rank6
+	Rank7
+	Rank8
+)
+
+const (
+	Empty Bitboard = 0
+	Full  Bitboard = ^Bitboard(0)
+
+	mainDiagonal     Bitboard = 0x8040201008040201 // a1-h8
+	mainAntiDiagonal Bitboard = 0x0102040810204080 // h1-a8
+)
+
+// Square returns the square at a file and rank, both counted from 0.
+// Panics if either is outside 0 to 7.
+func Square(file, rank int) int {
+	if file < 0 || file > 7 || rank < 0 || rank > 7 {
+		panic(fmt.Sprintf("bitboard: square (%d, %d) off the board", file, rank))
+	}
+	return rank*8 + file
+}
+
+// ParseSquare returns the square of an algebraic name such as "e4".
+func ParseSquare(name string) (int, error) {
+	if len(name) != 2 || name[0] < 'a' || name[0] > 'h' || name[1] < '1' || name[1] > '8' {
+		return 0, fmt.Errorf("bitboard: invalid square %q", name)
+	}
+	return Square(int(name[0]-'a'), int(name[1]-'1')), nil
+}
+
+// SquareName returns the algebraic name of a square, such as "e4".
+func SquareName(sq int) string {
+	return string([]byte{'a' + byte(sq%8), '1' + byte(sq/8)})
+}
+
+// File returns the file containing the square.
+func File(sq int) Bitboard {
+	return FileA << (sq % 8)
+}
+
+// Rank returns the rank containing the square.
+func Rank(sq int) Bitboard {
+	return Rank1 << (8 * (sq / 8))
+}
+
+// Diagonal returns the a1-h8 direction diagonal containing the square.
+func Diagonal(sq int) Bitboard {
+	if d := sq%8 - sq/8; d > 0 {
+		return mainDiagonal >> (8 * d)
+	}
+	return mainDiagonal << (8 * (sq/8 - sq%8))
+}
+
+// AntiDiagonal returns the h1-a8 direction diagonal containing the square.
+func AntiDiagonal(sq int) Bitboard {
+	if d := 7 - sq%8 - sq/8; d > 0 {
+		return mainAntiDiagonal >> (8 * d)
+	}
+	return mainAntiDiagonal << (8 * (sq%8 + sq/8 - 7))
+}
+
+// Of returns the set of the given squares.
+func Of(squares ...int) Bitboard {
+	var b Bitboard
+	for _, sq := range squares {
+		b |= 1 << sq
+	}
+	return b
+}
+
+// Has reports whether the square is in the set.
+func (b Bitboard) Has(sq int) bool {
+	return b&(1<<sq) != 0
+}
+
+// Set returns the set with the square added.
+func (b Bitboard) Set(sq int) Bitboard {
+	return b | 1<<sq
+}
+
+// Clear returns the set with the square removed.
+func (b Bitboard) Clear(sq int) Bitboard {
+	return b &^ (1 << sq)
+}
+
+// Count returns the number of squares in the set.
+func (b Bitboard) Count() int {
+	return bits.OnesCount64(uint64(b))
+}
+
+// Squares returns an iterator over the squares in the set, in ascending order.
+func (b Bitboard) Squares() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for sq := range bitfield.Ones(uint64(b)) {
+			if !yield(int(sq)) {
+				return
+			}
+		}
+	}
+}
+
+// North returns the set moved one rank up.
+func (b Bitboard) North() Bitboard { return b << 8 }
+
+// South returns the set moved one rank down.
+func (b Bitboard) South() Bitboard { return b >> 8 }
+
+// East returns the set moved one file toward the h-file.
+func (b Bitboard) East() Bitboard { return (b &^ FileH) << 1 }
+
+// West returns the set moved one file toward the a-file.
+func (b Bitboard) West() Bitboard { return (b &^ FileA) >> 1 }
+
+// NorthEast returns the set moved one square up and toward the h-file.
+func (b Bitboard) NorthEast() Bitboard { return (b &^ FileH) << 9 }
+
+// NorthWest returns the set moved one square up and toward the a-file.
+func (b Bitboard) NorthWest() Bitboard { return (b &^ FileA) << 7 }
+
+// SouthEast returns the set moved one square down and toward the h-file.
+func (b Bitboard) SouthEast() Bitboard { return (b &^ FileH) >> 7 }
+
+// SouthWest returns the set moved one square down and toward the a-file.
+func (b Bitboard) SouthWest() Bitboard { return (b &^ FileA) >> 9 }
+
+// FlipVertical returns the set mirrored between rank 1 and rank 8.
+func (b Bitboard) FlipVertical() Bitboard {
+	return Bitboard(bits.ReverseBytes64(uint64(b)))
+}
+
+// FlipDiagonal returns the set mirrored about the a1-h8 diagonal.
+func (b Bitboard) FlipDiagonal() Bitboard {
+	return Bitboard(bitfield.Transpose8(uint64(b)))
+}
+
+// String draws the board with rank 8 at the top, using 'x' for squares in the
+// set and '.' for the others.
+func (b Bitboard) String() string {
+	buf := make([]byte, 0, 8*9)
+	for rank := 7; rank >= 0; rank-- {
+		for file := range 8 {
+			c := byte('.')
+			if b.Has(Square(file, rank)) {
+				c = 'x'
+			}
+			buf = append(buf, c)
+		}
+		buf = append(buf, '\n')
+	}
+	return string(buf)
+}
@@ -0,0 +1,118 @@
+package bitboard
+
+import (
+	"slices"
+	"testing"
+)
+
+func sq(t *testing.T, name string) int {
+	t.Helper()
+	s, err := ParseSquare(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSquares(t *testing.T) {
+	if sq(t, "a1") != 0 || sq(t, "h1") != 7 || sq(t, "e4") != 28 || sq(t, "h8") != 63 {
+		t.Error("ParseSquare numbering is not little-endian rank-file")
+	}
+	for s := range 64 {
+		if got, _ := ParseSquare(SquareName(s)); got != s {
+			t.Errorf("ParseSquare(SquareName(%d)) = %d", s, got)
+		}
+	}
+	for _, name := range []string{"", "i1", "a9", "a10", "E4"} {
+		if _, err := ParseSquare(name); err == nil {
+			t.Errorf("ParseSquare(%q) succeeded, want error", name)
+		}
+	}
+}
+
+func TestMasks(t *testing.T) {
+	e4 := sq(t, "e4")
+	tests := []struct {
+		name string
+		got  Bitboard
+		want Bitboard
+	}{
+		{"file", File(e4), FileE},
+		{"rank", Rank(e4), Rank4},
+		{"diagonal e4", Diagonal(e4), Of(sq(t, "b1"), sq(t, "c2"), sq(t, "d3"), e4, sq(t, "f5"), sq(t, "g6"), sq(t, "h7"))},
+		{"diagonal a1", Diagonal(0), mainDiagonal},
+		{"diagonal a8", Diagonal(sq(t, "a8")), Of(sq(t, "a8"))},
+		{"anti-diagonal e4", AntiDiagonal(e4), Of(sq(t, "h1"), sq(t, "g2"), sq(t, "f3"), e4, sq(t, "d5"), sq(t, "c6"), sq(t, "b7"), sq(t, "a8"))},
+		{"anti-diagonal a1", AntiDiagonal(0), Of(0)},
+		{"anti-diagonal h8", AntiDiagonal(63), Of(63)},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s =\n%v want\n%v", tt.name, tt.got, tt.want)
+		}
+	}
+	if FileA|FileB|FileC|FileD|FileE|FileF|FileG|FileH != Full || Rank1|Rank8 != 0xFF000000000000FF {
+		t.Error("files or ranks do not cover the board")
+	}
+}
+
+func TestShifts(t *testing.T) {
+	edges := FileA | FileH | Rank1 | Rank8
+	tests := []struct {
+		name string
+		fn   func(Bitboard) Bitboard
+		want Bitboard
+	}{
+		{"north", Bitboard.North, (FileA|FileH)&^Rank1 | Rank2},
+		{"east", Bitboard.East, (Rank1|Rank8)&^FileA | FileB},
+		{"west", Bitboard.West, (Rank1|Rank8)&^FileH | FileG},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(edges); got != tt.want {
+			t.Errorf("%s of edges =\n%v want\n%v", tt.name, got, tt.want)
+		}
+	}
+
+	h4 := Of(sq(t, "h4"))
+	for name, got := range map[string]Bitboard{
+		"east": h4.East(), "north-east": h4.NorthEast(), "south-east": h4.SouthEast(),
+	} {
+		if got != Empty {
+			t.Errorf("%s of h4 wrapped to\n%v", name, got)
+		}
+	}
+	a4 := Of(sq(t, "a4"))
+	if a4.West() != Empty || a4.NorthWest() != Empty || a4.SouthWest() != Empty {
+		t.Error("westward shift of a4 wrapped around")
+	}
+	e4 := Of(sq(t, "e4"))
+	all := e4.North() | e4.South() | e4.East() | e4.West() |
+		e4.NorthEast() | e4.NorthWest() | e4.SouthEast() | e4.SouthWest()
+	if want := Of(sq(t, "d3"), sq(t, "e3"), sq(t, "f3"), sq(t, "d4"), sq(t, "f4"), sq(t, "d5"), sq(t, "e5"), sq(t, "f5")); all != want {
+		t.Errorf("neighbors of e4 =\n%v want\n%v", all, want)
+	}
+}
+
+func TestBitboard_Squares(t *testing.T) {
+	b := Of(0, 9, 63).Set(20).Clear(9)
+	if got := slices.Collect(b.Squares()); !slices.Equal(got, []int{0, 20, 63}) {
+		t.Errorf("Squares() = %v, want [0 20 63]", got)
+	}
+	if b.Count() != 3 || !b.Has(20) || b.Has(9) {
+		t.Errorf("Count() = %d, Has(20) = %v, Has(9) = %v", b.Count(), b.Has(20), b.Has(9))
+	}
+}
+
+func TestBitboard_Flip(t *testing.T) {
+	b := Of(sq(t, "b1"), sq(t, "e4"))
+	if got, want := b.FlipVertical(), Of(sq(t, "b8"), sq(t, "e5")); got != want {
+		t.Errorf("FlipVertical =\n%v want\n%v", got, want)
+	}
+	if got, want := b.FlipDiagonal(), Of(sq(t, "a2"), sq(t, "d5")); got != want {
+		t.Errorf("FlipDiagonal =\n%v want\n%v", got, want)
+	}
+	if got, want := Of(sq(t, "a8"), sq(t, "h1")).String(), "x.......\n........\n........\n........\n........\n........\n........\n.......x\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -4,6 +4,7 @@ package bitfield
 
 import (
 	"fmt"
+	"math/bits"
 	"unsafe"
 )
 
@@ -20,44 +21,65 @@ type storageType interface {
 // BitField represents a field of bits within a larger unsigned integer.
 // T represents the type of values that can be stored in the field.
 // U represents the container type where the bit field will be stored.
+//
+// Fields carrying metadata, such as enum names, allowed values or a name set by
+// Layout.Add, refer to it through an unexported pointer, so == reports whether
+// two fields share the same metadata. Use SameBits to compare the bits alone.
+// Fields must be created with New, Safe or the other constructors rather than
+// with unkeyed composite literals.
 type BitField[T Unsigned, U storageType] struct {
 	Shift uint // Position of the least significant bit of the field
 	Size  uint // Number of bits in the field
 	Mask  U    // Mask with 1s in the field position
+
+	meta *fieldMeta[T] // Optional metadata such as enum names, nil for plain fields
 }
 
 // New creates a new BitField with the given shift and size.
 // shift determines the position of the least significant bit of the field.
 // size determines how many bits the field will occupy.
 // The function creates a mask with 1s in the positions of the field.
+// Options such as WithName and WithUnit attach metadata to the field.
 // Note: This function doesn't perform validation, use Safe for validated creation.
-func New[T Unsigned, U storageType](shift, size uint) BitField[T, U] {
-	return BitField[T, U]{
+// Panics only if an option is invalid, such as an enum value that does not fit.
+func New[T Unsigned, U storageType](shift, size uint, opts ...Option) BitField[T, U] {
+	bf := BitField[T, U]{
 		Shift: shift,
 		Size:  size,
 		Mask:  (U(1) << (shift + size)) - (U(1) << shift),
 	}
+	bf, err := bf.withOptions(opts)
+	if err != nil {
+		panic(err.Error())
+	}
+	return bf
 }
 
 // Safe creates a new BitField with the given shift and size, after validating the parameters.
-// Returns an error if:
+// Returns ErrShiftOutOfRange, ErrSizeOutOfRange or a *FieldOverflowError if:
 // - shift is greater than or equal to the bit size of type T
 // - size is greater than or equal to the bit size of type T
 // - shift + size exceeds the bit size of type T
 // - size is less than or equal to 0
-func Safe[T Unsigned, U storageType](shift, size uint) (BitField[T, U], error) {
+//
+// It also returns an error if an option is invalid, such as an enum value that does not fit.
+func Safe[T Unsigned, U storageType](shift, size uint, opts ...Option) (BitField[T, U], error) {
 	var bf BitField[T, U]
 	switch bSize := unsignedSizeOf[T](); {
 	case shift >= bSize:
-		return bf, fmt.Errorf("invalid shift parameter")
+		return bf, ErrShiftOutOfRange
 	case size >= bSize:
-		return bf, fmt.Errorf("invalid size parameter")
+		return bf, ErrSizeOutOfRange
 	case shift+size > bSize:
-		return bf, fmt.Errorf("invalid shift/size parameters")
+		return bf, &FieldOverflowError{Shift: shift, Size: size, Width: bSize}
 	case size <= 0:
-		return bf, fmt.Errorf("invalid size parameter")
+		return bf, ErrSizeOutOfRange
 	}
-	return New[T, U](shift, size), nil
+	bf, err := New[T, U](shift, size).withOptions(opts)
+	if err != nil {
+		return BitField[T, U]{}, err
+	}
+	return bf, nil
 }
 
 // SafeNext creates a new BitField that starts after an existing BitField, with validation.
@@ -73,7 +95,7 @@ func SafeNext[
 	size uint,
 ) (BitField[T, U], error) {
 	if bf.Shift+bf.Size+size > unsignedSizeOf[T]() {
-		return BitField[T, U]{}, fmt.Errorf("field would exceed type bounds")
+		return BitField[T, U]{}, &FieldOverflowError{Shift: bf.Shift + bf.Size, Size: size, Width: unsignedSizeOf[T]()}
 	}
 	return Safe[T, U](bf.Shift+bf.Size, size)
 }
@@ -86,29 +108,130 @@ func Next[T Unsigned, U storageType, Old Unsigned](bf BitField[Old, U], size uin
 	return New[T, U](bf.Shift+bf.Size, size)
 }
 
+// NewMSB0 creates a new BitField using MSB0 bit numbering, where bit 0 is the
+// most significant bit of the container type U, as used by IBM/PowerPC and many datasheets.
+// start is the MSB0 position of the most significant bit of the field.
+// The field is converted to the internal LSB0 representation.
+// Note: This function doesn't perform validation, use SafeMSB0 for validated creation.
+func NewMSB0[T Unsigned, U storageType](start, size uint) BitField[T, U] {
+	return New[T, U](unsignedSizeOf[U]()-start-size, size)
+}
+
+// SafeMSB0 creates a new BitField using MSB0 bit numbering, after validating the parameters.
+// Returns an error if:
+// - size is 0 or exceeds the bit size of type T
+// - start + size exceeds the bit size of the container type U
+func SafeMSB0[T Unsigned, U storageType](start, size uint) (BitField[T, U], error) {
+	switch {
+	case size == 0 || size > unsignedSizeOf[T]():
+		return BitField[T, U]{}, ErrSizeOutOfRange
+	case start >= unsignedSizeOf[U]() || start+size > unsignedSizeOf[U]():
+		return BitField[T, U]{}, fmt.Errorf("MSB0 field of %d bits at bit %d: %w", size, start, ErrFieldOverflow)
+	}
+	return NewMSB0[T, U](start, size), nil
+}
+
+// NewRange creates a new BitField from an inclusive bit range [hi:lo],
+// as fields are usually described in datasheets. For example, NewRange(11, 8)
+// creates a 4-bit field with shift 8.
+// Note: This function doesn't perform validation, use SafeRange for validated creation.
+func NewRange[T Unsigned, U storageType](hi, lo uint) BitField[T, U] {
+	return New[T, U](lo, hi-lo+1)
+}
+
+// SafeRange creates a new BitField from an inclusive bit range [hi:lo], after validating the parameters.
+// Returns an error if:
+// - hi is less than lo
+// - hi is outside the container type U
+// - the range is wider than the bit size of type T
+func SafeRange[T Unsigned, U storageType](hi, lo uint) (BitField[T, U], error) {
+	switch {
+	case hi < lo:
+		return BitField[T, U]{}, fmt.Errorf("range [%d:%d]: %w", hi, lo, ErrSizeOutOfRange)
+	case hi >= unsignedSizeOf[U]():
+		return BitField[T, U]{}, &FieldOverflowError{Shift: lo, Size: hi - lo + 1, Width: unsignedSizeOf[U]()}
+	case hi-lo+1 > unsignedSizeOf[T]():
+		return BitField[T, U]{}, fmt.Errorf("range [%d:%d]: %w", hi, lo, ErrSizeOutOfRange)
+	}
+	return NewRange[T, U](hi, lo), nil
+}
+
+// NewFromMask creates a new BitField from an existing mask constant, such as one
+// copied from a C header. Shift and size are derived from the mask.
+// Returns an error if the mask is 0, its set bits are not contiguous,
+// or the field is wider than the bit size of type T.
+func NewFromMask[T Unsigned, U storageType](mask U) (BitField[T, U], error) {
+	m := uint64(mask)
+	if m == 0 {
+		return BitField[T, U]{}, fmt.Errorf("mask must not be 0: %w", ErrSizeOutOfRange)
+	}
+	shift := uint(bits.TrailingZeros64(m))
+	if v := m >> shift; v&(v+1) != 0 {
+		return BitField[T, U]{}, fmt.Errorf("mask 0x%X is not contiguous", m)
+	}
+	size := uint(bits.OnesCount64(m))
+	if size > unsignedSizeOf[T]() {
+		return BitField[T, U]{}, fmt.Errorf("mask 0x%X: %w", m, ErrSizeOutOfRange)
+	}
+	return New[T, U](shift, size), nil
+}
+
 // IsValid checks if the value fits within the bit field.
-// Returns true if the value can be represented using the field's size.
+// Returns true if the value can be represented using the field's size and,
+// for fields declared with WithAllowed, is one of the allowed values.
 func (bf BitField[T, U]) IsValid(value T) bool {
-	return U(value) < U(1)<<bf.Size
+	if uint64(value) > uint64(bf.Mask>>bf.Shift) {
+		return false
+	}
+	return bf.meta == nil || bf.meta.allowed == nil || bf.meta.allowed[value]
 }
 
 // Encode encodes a value into the bit field.
 // It shifts the value to the appropriate position.
-// Panics if the value is too large for the field.
+// Panics if the value is too large for the field or is not one of its allowed values.
 func (bf BitField[T, U]) Encode(value T) U {
 	if !bf.IsValid(value) {
-		panic(fmt.Sprintf("value %v out of range, max %v", value, U(1)<<bf.Size-1))
+		panic(bf.invalidValue(value).Error())
 	}
 	return U(value) << bf.Shift
 }
 
 // Update updates the bit field within an existing value.
 // It clears the existing bits in the field and sets them to the new value.
-// Panics if the new value is too large for the field.
+// Panics if the new value is too large for the field or is not one of its allowed values.
 func (bf BitField[T, U]) Update(previous U, value T) U {
 	return (previous &^ bf.Mask) | bf.Encode(value)
 }
 
+// Max returns the largest value of type T that fits in the field.
+func (bf BitField[T, U]) Max() T {
+	if max := uint64(bf.Mask >> bf.Shift); max < uint64(^T(0)) {
+		return T(max)
+	}
+	return ^T(0)
+}
+
+// SameBits reports whether bf and other occupy the same bits of the container,
+// ignoring their metadata.
+func (bf BitField[T, U]) SameBits(other BitField[T, U]) bool {
+	return bf.Shift == other.Shift && bf.Size == other.Size && bf.Mask == other.Mask
+}
+
+// EncodeClamp encodes a value into the bit field, saturating it at the field maximum
+// instead of panicking when it is too large. This is useful for counters and gauges
+// packed into small fields.
+// For fields declared with WithAllowed, the value is lowered to the nearest allowed
+// value, or raised to the smallest one if none is lower.
+func (bf BitField[T, U]) EncodeClamp(value T) U {
+	return bf.Encode(bf.clamp(min(value, bf.Max())))
+}
+
+// UpdateClamp updates the bit field within an existing value like Update,
+// saturating the new value at the field maximum instead of panicking.
+func (bf BitField[T, U]) UpdateClamp(previous U, value T) U {
+	return (previous &^ bf.Mask) | bf.EncodeClamp(value)
+}
+
 // Decode extracts the bit field from a value.
 // It masks out all other bits and shifts the field down to position 0.
 func (bf BitField[T, U]) Decode(value U) T {
@@ -195,3 +195,205 @@ func TestBitField_HasValue(t *testing.T) {
 		}
 	}
 }
+
+func TestNewMSB0(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     uint
+		size      uint
+		wantShift uint
+		wantMask  uint32
+	}{
+		{"most significant bit", 0, 1, 31, 0x80000000},
+		{"least significant bit", 31, 1, 0, 0x00000001},
+		{"powerpc style field", 6, 5, 21, 0x03E00000},
+		{"low byte", 24, 8, 0, 0x000000FF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bf := NewMSB0[uint8, uint32](tt.start, tt.size)
+			if bf.Shift != tt.wantShift || bf.Size != tt.size || bf.Mask != tt.wantMask {
+				t.Errorf("NewMSB0(%d, %d) = shift %d, size %d, mask 0x%08X, want shift %d, size %d, mask 0x%08X",
+					tt.start, tt.size, bf.Shift, bf.Size, bf.Mask, tt.wantShift, tt.size, tt.wantMask)
+			}
+		})
+	}
+}
+
+func TestSafeMSB0(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   uint
+		size    uint
+		wantErr bool
+	}{
+		{"valid", 6, 5, false},
+		{"last bits", 56, 8, false},
+		{"zero size", 0, 0, true},
+		{"too wide for value type", 0, 9, true},
+		{"start out of range", 64, 1, true},
+		{"exceeds container", 60, 8, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bf, err := SafeMSB0[uint8, uint64](tt.start, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SafeMSB0(%v, %v): err = %v, want err = %v", tt.start, tt.size, err, tt.wantErr)
+			}
+			if !tt.wantErr && bf.Shift != 64-tt.start-tt.size {
+				t.Errorf("shift = %v, want %v", bf.Shift, 64-tt.start-tt.size)
+			}
+		})
+	}
+}
+
+func TestNewRange(t *testing.T) {
+	tests := []struct {
+		hi, lo    uint
+		wantShift uint
+		wantSize  uint
+		wantMask  uint32
+	}{
+		{11, 8, 8, 4, 0x00000F00},
+		{0, 0, 0, 1, 0x00000001},
+		{31, 24, 24, 8, 0xFF000000},
+	}
+
+	for _, tt := range tests {
+		bf := NewRange[uint8, uint32](tt.hi, tt.lo)
+		if bf.Shift != tt.wantShift || bf.Size != tt.wantSize || bf.Mask != tt.wantMask {
+			t.Errorf("NewRange(%d, %d) = shift %d, size %d, mask 0x%08X, want shift %d, size %d, mask 0x%08X",
+				tt.hi, tt.lo, bf.Shift, bf.Size, bf.Mask, tt.wantShift, tt.wantSize, tt.wantMask)
+		}
+	}
+}
+
+func TestSafeRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		hi, lo  uint
+		wantErr bool
+	}{
+		{"valid", 11, 8, false},
+		{"single bit", 31, 31, false},
+		{"reversed", 8, 11, true},
+		{"outside container", 32, 30, true},
+		{"too wide for value type", 15, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SafeRange[uint8, uint32](tt.hi, tt.lo)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SafeRange(%v, %v): err = %v, want err = %v", tt.hi, tt.lo, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewFromMask(t *testing.T) {
+	tests := []struct {
+		name      string
+		mask      uint64
+		wantShift uint
+		wantSize  uint
+		wantErr   bool
+	}{
+		{"low nibble", 0xF, 0, 4, false},
+		{"middle bits", 0x0000_0F00, 8, 4, false},
+		{"top bit", 0x8000_0000_0000_0000, 63, 1, false},
+		{"top byte", 0xFF00_0000_0000_0000, 56, 8, false},
+		{"zero mask", 0, 0, 0, true},
+		{"gap", 0x0000_0F0F, 0, 0, true},
+		{"single gap bit", 0x5, 0, 0, true},
+		{"wider than value type", 0x1FF, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bf, err := NewFromMask[uint8](tt.mask)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewFromMask(0x%X): err = %v, want err = %v", tt.mask, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if bf.Shift != tt.wantShift || bf.Size != tt.wantSize || bf.Mask != tt.mask {
+				t.Errorf("NewFromMask(0x%X) = shift %d, size %d, mask 0x%X, want shift %d, size %d",
+					tt.mask, bf.Shift, bf.Size, bf.Mask, tt.wantShift, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestBitField_Max(t *testing.T) {
+	tests := []struct {
+		name string
+		bf   BitField[uint8, uint64]
+		want uint8
+	}{
+		{"3-bit field", New[uint8, uint64](4, 3), 7},
+		{"8-bit field", New[uint8, uint64](8, 8), 255},
+		{"wider than value type", New[uint8, uint64](0, 12), 255},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bf.Max(); got != tt.want {
+				t.Errorf("Max() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBitField_SameBits(t *testing.T) {
+	plain := New[uint8, uint32](4, 3)
+	named := New[uint8, uint32](4, 3, WithName("mode"), WithEnum(map[uint8]string{1: "on"}))
+	if !plain.SameBits(named) || !named.SameBits(New[uint8, uint32](4, 3, WithEnum(map[uint8]string{1: "on"}))) {
+		t.Error("SameBits() = false for fields with the same bits")
+	}
+	if plain.SameBits(New[uint8, uint32](4, 2)) || plain.SameBits(New[uint8, uint32](5, 3)) {
+		t.Error("SameBits() = true for fields with different bits")
+	}
+}
+
+func TestBitField_EncodeClamp(t *testing.T) {
+	bf := New[uint8, uint32](2, 3)
+	tests := []struct {
+		value uint8
+		want  uint32
+	}{
+		{0, 0},
+		{5, 20},
+		{7, 28},
+		{8, 28},
+		{255, 28},
+	}
+
+	for _, tt := range tests {
+		if got := bf.EncodeClamp(tt.value); got != tt.want {
+			t.Errorf("EncodeClamp(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBitField_UpdateClamp(t *testing.T) {
+	bf := New[uint16, uint32](4, 4)
+	tests := []struct {
+		previous uint32
+		value    uint16
+		want     uint32
+	}{
+		{0xFFFFFFFF, 0, 0xFFFFFF0F},
+		{0x00000000, 3, 0x00000030},
+		{0x0000000F, 1000, 0x000000FF},
+	}
+
+	for _, tt := range tests {
+		if got := bf.UpdateClamp(tt.previous, tt.value); got != tt.want {
+			t.Errorf("UpdateClamp(0x%X, %v) = 0x%X, want 0x%X", tt.previous, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package bitfieldfuzz helps fuzz decoders built on bitfield layouts with
+// native Go fuzzing.
+//
+// A Fuzzer derives corpus seeds from a Layout, covering the boundary values of
+// every field and every value of enum fields, and maps the arbitrary inputs
+// produced by the fuzzing engine onto containers that respect the layout:
+// fields restricted with allowed values hold one of them, enum fields hold one
+// of their named values, and reserved bits, those outside every field, are zero,
+// as are the bits of conditional fields that are not present.
+//
+//	func FuzzDecode(f *testing.F) {
+//		fz := bitfieldfuzz.New(layout)
+//		fz.AddSeeds(f)
+//		f.Fuzz(func(t *testing.T, raw uint32) {
+//			status := Decode(fz.Conform(raw))
+//			...
+//		})
+//	}
+//
+// Decoders should also be fuzzed with raw inputs, since data from untrusted
+// sources does not respect the layout either.
+//
+// For property tests with testing/quick, Container generates random containers
+// of a layout, and CheckRoundTrip and CheckLayoutRoundTrip check the laws of
+// Encode, Decode and Update for a field definition in one line.
+package bitfieldfuzz
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Fuzzer generates and mutates containers of a Layout.
+// The layout must not be changed after the Fuzzer is created.
+type Fuzzer[U uint32 | uint64] struct {
+	layout *bitfield.Layout[U]
+	fields []field[U]
+}
+
+// field is a field of the layout with its valid values.
+type field[U uint32 | uint64] struct {
+	name   string
+	bf     bitfield.BitField[uint64, U]
+	values []uint64 // Sorted allowed or enum values, nil if every value is valid
+}
+
+// New creates a Fuzzer for the containers of layout.
+func New[U uint32 | uint64](layout *bitfield.Layout[U]) *Fuzzer[U] {
+	fz := &Fuzzer[U]{layout: layout}
+	for _, name := range layout.Names() {
+		bf, _ := layout.Field(name)
+		f := field[U]{name: name, bf: bf, values: bf.Allowed()}
+		if f.values == nil {
+			for value := range bf.Enum() {
+				f.values = append(f.values, value)
+			}
+			slices.Sort(f.values)
+		}
+		fz.fields = append(fz.fields, f)
+	}
+	return fz
+}
+
+// min returns the smallest valid value of the field.
+func (f field[U]) min() uint64 {
+	if f.values != nil {
+		return f.values[0]
+	}
+	return 0
+}
+
+// boundaries returns the values of the field worth seeding:
+// every allowed or enum value, or 0, 1, the middle and the maximum.
+func (f field[U]) boundaries() []uint64 {
+	if f.values != nil {
+		return f.values
+	}
+	hi := f.bf.Max()
+	return []uint64{0, 1, hi/2 + 1, hi}
+}
+
+// valid reports whether value is a valid value of the field.
+func (f field[U]) valid(value uint64) bool {
+	if f.values == nil {
+		return true
+	}
+	_, ok := slices.BinarySearch(f.values, value)
+	return ok
+}
+
+// base returns the container with every present field at its smallest valid value.
+func (fz *Fuzzer[U]) base() U {
+	var container U
+	for _, f := range fz.fields {
+		container = f.bf.Update(container, f.min())
+	}
+	return fz.layout.Normalize(container)
+}
+
+// Seeds returns containers for a fuzzing corpus: every field at its smallest
+// valid value, then each boundary value of each field with the other fields at
+// their smallest value, then every field at its largest valid value.
+// All seeds respect the layout, and duplicates are removed.
+func (fz *Fuzzer[U]) Seeds() []U {
+	base := fz.base()
+	seeds := []U{base}
+	seen := map[U]bool{base: true}
+	add := func(container U) {
+		container = fz.layout.Normalize(container)
+		if !seen[container] {
+			seen[container] = true
+			seeds = append(seeds, container)
+		}
+	}
+	for _, f := range fz.fields {
+		for _, value := range f.boundaries() {
+			add(f.bf.Update(base, value))
+		}
+	}
+	var top U
+	for _, f := range fz.fields {
+		b := f.boundaries()
+		top = f.bf.Update(top, b[len(b)-1])
+	}
+	add(top)
+	return seeds
+}
+
+// AddSeeds adds the seeds to the corpus of f.
+// The fuzz target must take a single argument of type U.
+func (fz *Fuzzer[U]) AddSeeds(f *testing.F) {
+	for _, seed := range fz.Seeds() {
+		f.Add(seed)
+	}
+}
+
+// AddBinarySeeds adds the seeds to the corpus of f, encoded in the byte order
+// of the layout. The fuzz target must take a single []byte argument.
+func (fz *Fuzzer[U]) AddBinarySeeds(f *testing.F) {
+	for _, seed := range fz.Seeds() {
+		f.Add(bitfield.AppendBinary(nil, seed, fz.layout.ByteOrder()))
+	}
+}
+
+// Conform maps an arbitrary container onto one that respects the layout.
+// Reserved bits are cleared, and a field holding a value that is not allowed,
+// or an enum field holding an unnamed value, gets one of its valid values
+// instead, chosen by the value it held. Conditional fields that are not present
+// in the result are cleared.
+// Containers that already respect the layout are returned unchanged, so the
+// fuzzing engine can still reach every valid container.
+func (fz *Fuzzer[U]) Conform(raw U) U {
+	var container U
+	for _, f := range fz.fields {
+		value := f.bf.Decode(raw)
+		if !f.valid(value) {
+			value = f.values[value%uint64(len(f.values))]
+		}
+		container = f.bf.Update(container, value)
+	}
+	return fz.layout.Normalize(container)
+}
+
+// Valid reports whether container respects the layout: its reserved bits are
+// zero, including the bits of absent conditional fields, and every present field
+// holds an allowed value, or a named value for enum fields.
+func (fz *Fuzzer[U]) Valid(container U) bool {
+	return fz.Conform(container) == container
+}
+
+// Random returns a random container that respects the layout.
+// Fields with allowed values or enum names hold one of those, chosen at random,
+// and the other fields hold a uniformly random value.
+func (fz *Fuzzer[U]) Random(r *rand.Rand) U {
+	return fz.random(r.Uint64)
+}
+
+// random returns a random container, drawing random bits from next.
+func (fz *Fuzzer[U]) random(next func() uint64) U {
+	var container U
+	for _, f := range fz.fields {
+		var value uint64
+		if f.values != nil {
+			value = f.values[next()%uint64(len(f.values))]
+		} else {
+			value = next() & f.bf.Max()
+		}
+		container = f.bf.Update(container, value)
+	}
+	return fz.layout.Normalize(container)
+}
+
+// Mutate returns container with one field present in it, chosen at random, set
+// to another valid value. Fields with a single valid value are never chosen.
+// The other fields and the reserved bits are left as they are, except for
+// conditional fields that the mutation makes absent, which are cleared.
+// Returns container unchanged if no field can be mutated.
+func (fz *Fuzzer[U]) Mutate(container U, r *rand.Rand) U {
+	var mutable []field[U]
+	for _, f := range fz.fields {
+		if f.bf.Max() > 0 && len(f.values) != 1 && fz.layout.Present(f.name, container) {
+			mutable = append(mutable, f)
+		}
+	}
+	if len(mutable) == 0 {
+		return container
+	}
+	f := mutable[r.IntN(len(mutable))]
+	old := f.bf.Decode(container)
+	value := old
+	for value == old {
+		if f.values != nil {
+			value = f.values[r.IntN(len(f.values))]
+		} else {
+			value = r.Uint64() & f.bf.Max()
+		}
+	}
+	updated := f.bf.Update(container, value)
+	return updated&fz.layout.Reserved() | fz.layout.Normalize(updated)
+}
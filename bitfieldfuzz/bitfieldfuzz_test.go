@@ -0,0 +1,239 @@
+package bitfieldfuzz
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+func newLayout(t testing.TB) *bitfield.Layout[uint32] {
+	t.Helper()
+	l := bitfield.NewLayout[uint32]()
+	if err := l.Add("enable", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Add("mode", 4, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Add("count", 8, 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetEnum("mode", map[uint64]string{1: "idle", 2: "normal", 5: "turbo"}); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// newConditionLayout returns a layout whose address is present only when ext is set.
+func newConditionLayout(t testing.TB) *bitfield.Layout[uint32] {
+	t.Helper()
+	l := bitfield.NewLayout[uint32]()
+	for _, err := range []error{
+		l.Add("ext", 0, 1),
+		l.Add("addr", 1, 8),
+		l.SetCondition("addr", "ext", 1),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func TestFuzzer_Seeds(t *testing.T) {
+	l := newLayout(t)
+	fz := New(l)
+	seeds := fz.Seeds()
+
+	want := []uint32{
+		0x00000010, // Base: mode = idle
+		0x00000011, // enable = 1
+		0x00000020, // mode = normal
+		0x00000050, // mode = turbo
+		0x00000110, // count = 1
+		0x00008010, // count = 0x80
+		0x0000FF10, // count = 0xFF
+		0x0000FF51, // Every field at its maximum
+	}
+	if !slices.Equal(seeds, want) {
+		t.Errorf("Seeds() = %#x, want %#x", seeds, want)
+	}
+	for _, seed := range seeds {
+		if !fz.Valid(seed) {
+			t.Errorf("seed 0x%X does not respect the layout", seed)
+		}
+	}
+}
+
+func TestFuzzer_Conform(t *testing.T) {
+	fz := New(newLayout(t))
+
+	tests := []struct {
+		name string
+		raw  uint32
+		want uint32
+	}{
+		{"valid", 0x00001221, 0x00001221},
+		{"reserved bits", 0xFFFF001E, 0x00000010},
+		{"unnamed enum value 0", 0x00000000, 0x00000010},
+		{"unnamed enum value 3", 0x00000030, 0x00000010},
+		{"unnamed enum value 7", 0x00000070, 0x00000020},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fz.Conform(tt.raw); got != tt.want {
+				t.Errorf("Conform(0x%X) = 0x%X, want 0x%X", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzer_Valid(t *testing.T) {
+	fz := New(newLayout(t))
+
+	tests := []struct {
+		container uint32
+		want      bool
+	}{
+		{0x00000010, true},
+		{0x0000AB51, true},
+		{0x00000000, false}, // mode 0 is not named
+		{0x00010010, false}, // Reserved bit 16
+		{0x00000018, false}, // Reserved bit 3
+	}
+	for _, tt := range tests {
+		if got := fz.Valid(tt.container); got != tt.want {
+			t.Errorf("Valid(0x%X) = %v, want %v", tt.container, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzer_Mutate(t *testing.T) {
+	l := newLayout(t)
+	fz := New(l)
+	r := rand.New(rand.NewPCG(1, 2))
+
+	container := uint32(0x00000010)
+	for range 1000 {
+		next := fz.Mutate(container, r)
+		if !fz.Valid(next) {
+			t.Fatalf("Mutate(0x%X) = 0x%X, which does not respect the layout", container, next)
+		}
+		before, after := l.DecodeAll(container), l.DecodeAll(next)
+		changed := 0
+		for name := range before {
+			if before[name] != after[name] {
+				changed++
+			}
+		}
+		if changed != 1 {
+			t.Fatalf("Mutate(0x%X) = 0x%X changed %d fields, want 1", container, next, changed)
+		}
+		container = next
+	}
+}
+
+func TestFuzzer_MutateImmutable(t *testing.T) {
+	l := bitfield.NewLayout[uint64]()
+	if err := l.Add("mode", 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetEnum("mode", map[uint64]string{3: "only"}); err != nil {
+		t.Fatal(err)
+	}
+	fz := New(l)
+	r := rand.New(rand.NewPCG(1, 2))
+
+	if got := fz.Mutate(3, r); got != 3 {
+		t.Errorf("Mutate(3) = %d, want 3", got)
+	}
+}
+
+func TestFuzzer_Allowed(t *testing.T) {
+	l := newLayout(t)
+	if err := l.SetAllowed("count", 0, 3, 200); err != nil {
+		t.Fatal(err)
+	}
+	fz := New(l)
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for _, seed := range fz.Seeds() {
+		if err := l.Validate(seed); err != nil {
+			t.Errorf("seed 0x%X: %v", seed, err)
+		}
+	}
+	for range 100 {
+		if c := fz.Random(r); l.Validate(c) != nil {
+			t.Fatalf("Random() = 0x%X, which does not validate: %v", c, l.Validate(c))
+		}
+	}
+	if got := fz.Conform(0x00000510); got != 0x0000C810 {
+		t.Errorf("Conform(0x510) = 0x%X, want 0xC810", got)
+	}
+}
+
+func TestFuzzer_Condition(t *testing.T) {
+	l := newConditionLayout(t)
+	fz := New(l)
+	r := rand.New(rand.NewPCG(1, 2))
+
+	tests := []struct {
+		raw, want uint32
+	}{
+		{0x000001FE, 0x00000000}, // addr is not present
+		{0x000001FF, 0x000001FF},
+		{0x5D9E3BAA, 0x00000000},
+		{0x5D9E3BAB, 0x000001AB},
+	}
+	for _, tt := range tests {
+		if got := fz.Conform(tt.raw); got != tt.want {
+			t.Errorf("Conform(0x%X) = 0x%X, want 0x%X", tt.raw, got, tt.want)
+		}
+	}
+	for _, seed := range fz.Seeds() {
+		if err := l.Validate(seed); err != nil {
+			t.Errorf("seed 0x%X: %v", seed, err)
+		}
+	}
+	container := fz.Random(r)
+	for range 100 {
+		if err := l.Validate(container); err != nil {
+			t.Fatalf("0x%X does not validate: %v", container, err)
+		}
+		container = fz.Mutate(container, r)
+	}
+}
+
+func FuzzConform(f *testing.F) {
+	l := newLayout(f)
+	fz := New(l)
+	fz.AddSeeds(f)
+	f.Fuzz(func(t *testing.T, raw uint32) {
+		container := fz.Conform(raw)
+		if !fz.Valid(container) {
+			t.Fatalf("Conform(0x%X) = 0x%X, which does not respect the layout", raw, container)
+		}
+		values := l.DecodeAll(container)
+		if got, err := l.EncodeAll(values); err != nil || got != container {
+			t.Fatalf("EncodeAll(DecodeAll(0x%X)) = 0x%X, %v", container, got, err)
+		}
+	})
+}
+
+func FuzzBinary(f *testing.F) {
+	l := newLayout(f)
+	fz := New(l)
+	fz.AddBinarySeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p := l.Bind(0)
+		if err := p.UnmarshalBinary(data); err != nil {
+			return
+		}
+		if got, err := p.MarshalBinary(); err != nil || !bytes.Equal(got, data) {
+			t.Fatalf("MarshalBinary(UnmarshalBinary(%x)) = %x, %v", data, got, err)
+		}
+	})
+}
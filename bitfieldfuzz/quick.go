@@ -0,0 +1,159 @@
+package bitfieldfuzz
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// LayoutSource supplies the layout of Container values.
+// It is usually an empty struct type whose Layout method returns a package-level layout.
+type LayoutSource[U uint32 | uint64] interface {
+	Layout() *bitfield.Layout[U]
+}
+
+// Container is a container of the layout supplied by S.
+// It implements quick.Generator, so functions checked with testing/quick can
+// take random containers that respect the layout:
+//
+//	type status struct{}
+//
+//	func (status) Layout() *bitfield.Layout[uint32] { return statusLayout }
+//
+//	quick.Check(func(c bitfieldfuzz.Container[uint32, status]) bool {
+//		return Decode(c.Value).Encode() == c.Value
+//	}, nil)
+type Container[U uint32 | uint64, S LayoutSource[U]] struct {
+	Value U
+}
+
+// Generate implements quick.Generator.
+func (Container[U, S]) Generate(r *rand.Rand, size int) reflect.Value {
+	var source S
+	c := Container[U, S]{Value: New(source.Layout()).random(r.Uint64)}
+	return reflect.ValueOf(c)
+}
+
+// Packed returns the container bound to its layout.
+func (c Container[U, S]) Packed() bitfield.Packed[U] {
+	var source S
+	return source.Layout().Bind(c.Value)
+}
+
+// CheckRoundTrip checks that bf obeys the laws of a bit field, for the values 0
+// and bf.Max() and for random values and containers:
+//   - Encode sets no bits outside the mask of the field
+//   - Decode(Encode(v)) == v
+//   - Update(c, v) holds v and leaves the bits of c outside the field unchanged
+//   - Update(c, Decode(c)) == c, when values of type T span the whole field
+//     and Decode(c) is valid
+//   - Clear(c) clears the field and nothing else
+//   - IsValid accepts bf.Max() and rejects the value after it
+//
+// Fields restricted with WithAllowed are checked with their allowed values only.
+//
+// Violations are reported with t.Errorf.
+func CheckRoundTrip[T bitfield.Unsigned, U uint | uint32 | uint64](t testing.TB, bf bitfield.BitField[T, U]) {
+	t.Helper()
+	allowed := bf.Allowed()
+	if allowed == nil && !bf.IsValid(bf.Max()) {
+		t.Errorf("IsValid(%v) = false for the field maximum", bf.Max())
+	}
+	if spansField(bf) && bf.Max() < ^T(0) && bf.IsValid(bf.Max()+1) {
+		t.Errorf("IsValid(%v) = true above the field maximum %v", bf.Max()+1, bf.Max())
+	}
+	values := append([]T{0, bf.Max()}, allowed...)
+	for _, value := range values {
+		if !bf.IsValid(value) {
+			continue
+		}
+		for _, container := range []U{0, ^U(0)} {
+			if err := checkLaws(bf, value, container); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	// valid maps a random value onto a valid one.
+	valid := func(value T) T {
+		if allowed != nil {
+			return allowed[uint64(value)%uint64(len(allowed))]
+		}
+		return value & bf.Max()
+	}
+	law := func(value T, container U) bool {
+		return checkLaws(bf, valid(value), container) == nil
+	}
+	var ce *quick.CheckError
+	if err := quick.Check(law, nil); errors.As(err, &ce) {
+		t.Error(checkLaws(bf, valid(ce.In[0].(T)), ce.In[1].(U)))
+	} else if err != nil {
+		t.Error(err)
+	}
+}
+
+// spansField reports whether values of type T can hold every value of the field.
+func spansField[T bitfield.Unsigned, U uint | uint32 | uint64](bf bitfield.BitField[T, U]) bool {
+	return uint64(bf.Max()) == uint64(bf.Mask>>bf.Shift)
+}
+
+// checkLaws checks the laws of CheckRoundTrip for one value and container.
+func checkLaws[T bitfield.Unsigned, U uint | uint32 | uint64](bf bitfield.BitField[T, U], value T, container U) error {
+	outside := container &^ bf.Mask
+	if encoded := bf.Encode(value); encoded&^bf.Mask != 0 {
+		return fmt.Errorf("Encode(%v) = 0x%X sets bits outside the mask 0x%X", value, encoded, bf.Mask)
+	} else if got := bf.Decode(encoded); got != value {
+		return fmt.Errorf("Decode(Encode(%v)) = %v", value, got)
+	}
+	updated := bf.Update(container, value)
+	if got := bf.Decode(updated); got != value {
+		return fmt.Errorf("Decode(Update(0x%X, %v)) = %v", container, value, got)
+	}
+	if updated&^bf.Mask != outside {
+		return fmt.Errorf("Update(0x%X, %v) = 0x%X changes bits outside the mask 0x%X", container, value, updated, bf.Mask)
+	}
+	if current := bf.Decode(container); spansField(bf) && bf.IsValid(current) {
+		if got := bf.Update(container, current); got != container {
+			return fmt.Errorf("Update(0x%X, Decode(0x%X)) = 0x%X", container, container, got)
+		}
+	}
+	if got := bf.Clear(container); got != outside {
+		return fmt.Errorf("Clear(0x%X) = 0x%X, want 0x%X", container, got, outside)
+	}
+	return nil
+}
+
+// CheckLayoutRoundTrip checks every field of layout with CheckRoundTrip, and
+// checks that EncodeAll(DecodeAll(c)) returns c with its reserved bits cleared
+// for random containers c.
+// Violations are reported with t.Errorf.
+func CheckLayoutRoundTrip[U uint32 | uint64](t testing.TB, layout *bitfield.Layout[U]) {
+	t.Helper()
+	for _, name := range layout.Names() {
+		bf, _ := layout.Field(name)
+		CheckRoundTrip(t, bf)
+	}
+	check := func(container U) error {
+		got, err := layout.EncodeAll(layout.DecodeAll(container))
+		if err != nil {
+			return fmt.Errorf("EncodeAll(DecodeAll(0x%X)): %v", container, err)
+		}
+		if want := layout.Normalize(container); got != want {
+			return fmt.Errorf("EncodeAll(DecodeAll(0x%X)) = 0x%X, want 0x%X", container, got, want)
+		}
+		return nil
+	}
+	law := func(container U) bool {
+		return check(container) == nil
+	}
+	var ce *quick.CheckError
+	if err := quick.Check(law, nil); errors.As(err, &ce) {
+		t.Error(check(ce.In[0].(U)))
+	} else if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,90 @@
+package bitfieldfuzz
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"testing/quick"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// recorder is a testing.TB that records errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+var statusLayout = sync.OnceValue(func() *bitfield.Layout[uint32] {
+	l := bitfield.NewLayout[uint32]()
+	l.Add("enable", 0, 1)
+	l.Add("mode", 4, 3)
+	l.Add("count", 8, 8)
+	l.SetEnum("mode", map[uint64]string{1: "idle", 2: "normal", 5: "turbo"})
+	return l
+})
+
+type status struct{}
+
+func (status) Layout() *bitfield.Layout[uint32] { return statusLayout() }
+
+func TestContainer_Generate(t *testing.T) {
+	fz := New(statusLayout())
+	err := quick.Check(func(c Container[uint32, status]) bool {
+		return fz.Valid(c.Value)
+	}, nil)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestContainer_Packed(t *testing.T) {
+	p := Container[uint32, status]{Value: 0x0000AB51}.Packed()
+	if p.Layout != statusLayout() {
+		t.Error("Packed() is not bound to the layout of the source")
+	}
+	if mode, _ := p.Get("mode"); mode != 5 {
+		t.Errorf("Packed().Get(mode) = %d, want 5", mode)
+	}
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	CheckRoundTrip(t, bitfield.New[uint8, uint32](4, 3))
+	CheckRoundTrip(t, bitfield.New[uint8, uint64](10, 12)) // Values narrower than the field
+	CheckRoundTrip(t, bitfield.New[uint64, uint64](0, 64))
+	CheckRoundTrip(t, bitfield.New[uint16, uint](63, 1))
+	CheckRoundTrip(t, bitfield.New[uint8, uint32](4, 3).WithAllowed(0, 1, 2, 4))
+}
+
+func TestCheckRoundTripReportsViolations(t *testing.T) {
+	// The shift does not match the mask, so Update(c, Decode(c)) clears bit 4.
+	broken := bitfield.BitField[uint8, uint32]{Shift: 5, Size: 3, Mask: 0xF0}
+	r := &recorder{TB: t}
+	CheckRoundTrip(r, broken)
+	if len(r.errors) == 0 {
+		t.Fatal("CheckRoundTrip reported no errors for an inconsistent field")
+	}
+	if !strings.Contains(r.errors[0], "Update(") {
+		t.Errorf("first error = %q, want an Update law violation", r.errors[0])
+	}
+}
+
+func TestCheckLayoutRoundTrip(t *testing.T) {
+	r := &recorder{TB: t}
+	CheckLayoutRoundTrip(r, statusLayout())
+	CheckLayoutRoundTrip(r, newConditionLayout(t))
+	if len(r.errors) != 0 {
+		t.Errorf("CheckLayoutRoundTrip reported errors: %q", r.errors)
+	}
+}
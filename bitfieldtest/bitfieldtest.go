@@ -0,0 +1,139 @@
+// Package bitfieldtest provides test assertions for containers described by a
+// bitfield.Layout.
+//
+// The assertions compare field values rather than raw containers, and their
+// failure messages show every field of the container decoded, with enum names
+// where registered and the mismatching fields marked:
+//
+//	bitfieldtest.AssertField(t, layout, status, "mode", ModeNormal)
+//
+//	field "mode" = idle (1), want normal (2)
+//	container 0x11:
+//	    enable  1
+//	  > mode    idle (1)
+//	    count   0
+//
+// This replaces hexadecimal comparisons of containers, whose failures say little
+// about which field is wrong.
+package bitfieldtest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// AssertField checks that a field of container holds want, and reports an
+// error with t.Errorf if it does not or if the layout has no such field.
+// Returns whether the assertion passed.
+func AssertField[U uint32 | uint64, V bitfield.Unsigned](t testing.TB, layout *bitfield.Layout[U], container U, field string, want V) bool {
+	t.Helper()
+	return AssertFields(t, layout, container, map[string]uint64{field: uint64(want)})
+}
+
+// AssertFields checks that the fields of container hold the values of want,
+// keyed by field name, and reports every mismatch in one error with t.Errorf.
+// Fields missing from want are not checked.
+// Returns whether the assertion passed.
+func AssertFields[U uint32 | uint64](t testing.TB, layout *bitfield.Layout[U], container U, want map[string]uint64) bool {
+	t.Helper()
+	var b strings.Builder
+	marked := make(map[string]bool)
+	for _, name := range sortedFields(layout, want) {
+		bf, ok := layout.Field(name)
+		if !ok {
+			fmt.Fprintf(&b, "unknown field %q\n", name)
+			continue
+		}
+		if got := bf.Decode(container); got != want[name] {
+			fmt.Fprintf(&b, "field %q = %s, want %s\n", name, valueString(bf, got), valueString(bf, want[name]))
+			marked[name] = true
+		}
+	}
+	if b.Len() == 0 {
+		return true
+	}
+	b.WriteString(describe(layout, container, marked))
+	t.Errorf("%s", b.String())
+	return false
+}
+
+// AssertEqual checks that got equals want, and reports an error with t.Errorf
+// listing the fields that differ, the reserved bits that differ, and both
+// containers decoded.
+// Returns whether the assertion passed.
+func AssertEqual[U uint32 | uint64](t testing.TB, layout *bitfield.Layout[U], got, want U) bool {
+	t.Helper()
+	if got == want {
+		return true
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "container = %#x, want %#x\n", got, want)
+	marked := make(map[string]bool)
+	for _, c := range layout.Diff(want, got) {
+		bf, _ := layout.Field(c.Field)
+		fmt.Fprintf(&b, "field %q = %s, want %s\n", c.Field, valueString(bf, c.New), valueString(bf, c.Old))
+		marked[c.Field] = true
+	}
+	if diff := (got ^ want) & layout.Reserved(); diff != 0 {
+		fmt.Fprintf(&b, "reserved bits differ: %#x\n", diff)
+	}
+	b.WriteString("got " + describe(layout, got, marked))
+	b.WriteString("want " + describe(layout, want, marked))
+	t.Errorf("%s", b.String())
+	return false
+}
+
+// sortedFields returns the names of values in layout order, followed by the
+// names the layout does not have.
+func sortedFields[U uint32 | uint64](layout *bitfield.Layout[U], values map[string]uint64) []string {
+	var names, unknown []string
+	for _, name := range layout.Names() {
+		if _, ok := values[name]; ok {
+			names = append(names, name)
+		}
+	}
+	for name := range values {
+		if _, ok := layout.Field(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+	return append(names, unknown...)
+}
+
+// valueString formats a field value as its enum name followed by the number,
+// or as the number alone if no name is registered.
+func valueString[U uint32 | uint64](bf bitfield.BitField[uint64, U], value uint64) string {
+	if name, ok := bf.Name(value); ok {
+		return fmt.Sprintf("%s (%d)", name, value)
+	}
+	return fmt.Sprint(value)
+}
+
+// describe renders container with one line per field, marking the fields in
+// marked with '>', followed by the reserved bits if any is set.
+func describe[U uint32 | uint64](layout *bitfield.Layout[U], container U, marked map[string]bool) string {
+	names := layout.Names()
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "container %#x:\n", container)
+	for _, name := range names {
+		bf, _ := layout.Field(name)
+		mark := " "
+		if marked[name] {
+			mark = ">"
+		}
+		fmt.Fprintf(&b, "  %s %-*s  %s\n", mark, width, name, valueString(bf, bf.Decode(container)))
+	}
+	if reserved := container & layout.Reserved(); reserved != 0 {
+		fmt.Fprintf(&b, "    reserved bits set: %#x\n", reserved)
+	}
+	return b.String()
+}
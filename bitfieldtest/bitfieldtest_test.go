@@ -0,0 +1,125 @@
+package bitfieldtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// recorder is a testing.TB that records errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type mode uint8
+
+const (
+	modeIdle   mode = 1
+	modeNormal mode = 2
+)
+
+func newLayout(t *testing.T) *bitfield.Layout[uint32] {
+	t.Helper()
+	l := bitfield.NewLayout[uint32]()
+	if err := l.Add("enable", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Add("mode", 4, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Add("count", 8, 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetEnum("mode", map[uint64]string{1: "idle", 2: "normal"}); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestAssertField(t *testing.T) {
+	l := newLayout(t)
+
+	r := &recorder{TB: t}
+	if !AssertField(r, l, 0x00000521, "mode", modeNormal) || len(r.errors) != 0 {
+		t.Errorf("AssertField failed for a matching field: %q", r.errors)
+	}
+
+	r = &recorder{TB: t}
+	if AssertField(r, l, 0x00000311, "mode", modeNormal) {
+		t.Error("AssertField passed for a mismatching field")
+	}
+	want := `field "mode" = idle (1), want normal (2)
+container 0x311:
+    enable  1
+  > mode    idle (1)
+    count   3
+`
+	if len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("errors = %q, want %q", r.errors, want)
+	}
+}
+
+func TestAssertFields(t *testing.T) {
+	l := newLayout(t)
+
+	r := &recorder{TB: t}
+	ok := AssertFields(r, l, 0xF0000011, map[string]uint64{
+		"count":  4,
+		"enable": 1,
+		"mode":   uint64(modeIdle),
+		"speed":  2,
+	})
+	if ok {
+		t.Error("AssertFields passed with a mismatching and an unknown field")
+	}
+	want := `field "count" = 0, want 4
+unknown field "speed"
+container 0xf0000011:
+    enable  1
+    mode    idle (1)
+  > count   0
+    reserved bits set: 0xf0000000
+`
+	if len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("errors = %q, want %q", r.errors, want)
+	}
+}
+
+func TestAssertEqual(t *testing.T) {
+	l := newLayout(t)
+
+	r := &recorder{TB: t}
+	if !AssertEqual(r, l, 0x11, 0x11) || len(r.errors) != 0 {
+		t.Errorf("AssertEqual failed for equal containers: %q", r.errors)
+	}
+
+	r = &recorder{TB: t}
+	if AssertEqual(r, l, 0x00010521, 0x00000011) {
+		t.Error("AssertEqual passed for different containers")
+	}
+	want := `container = 0x10521, want 0x11
+field "mode" = normal (2), want idle (1)
+field "count" = 5, want 0
+reserved bits differ: 0x10000
+got container 0x10521:
+    enable  1
+  > mode    normal (2)
+  > count   5
+    reserved bits set: 0x10000
+want container 0x11:
+    enable  1
+  > mode    idle (1)
+  > count   0
+`
+	if len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("errors =\n%s\nwant\n%s", r.errors, want)
+	}
+}
@@ -0,0 +1,402 @@
+// Package bitfieldvet defines an Analyzer that reports misuse of the bitfield
+// package that can be found without running the program.
+//
+// It checks field definitions whose shift and size are constants:
+//
+//   - fields declared in the same var block with the same container type that
+//     overlap, and Layout.Add calls on the same layout whose fields overlap
+//   - constant values passed to Encode or Update that do not fit in the field
+//   - Next, MustNext, SafeNext and NextBitField calls whose new field would
+//     exceed the bit size of its value type
+//
+// Fields are followed through package-level and local variables, so a field
+// defined in one declaration is checked where it is used in another.
+//
+// The analyzer and its command, cmd/bitfieldvet, are a separate module, so that
+// users of bitfield do not depend on golang.org/x/tools:
+//
+//	go install github.com/lnear-dev/bitfield/bitfieldvet/cmd/bitfieldvet@latest
+package bitfieldvet
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// bitfieldPath is the import path of the package whose calls are checked.
+const bitfieldPath = "github.com/lnear-dev/bitfield"
+
+// Analyzer reports overlapping fields, constant values that overflow their
+// field, and Next chains that exceed the value type.
+var Analyzer = &analysis.Analyzer{
+	Name:     "bitfieldvet",
+	Doc:      "report overlapping bit fields, constant values that overflow their field, and Next chains that exceed the value type",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// field is a BitField whose position is known at compile time.
+type field struct {
+	shift, size uint64
+	valueBits   uint64     // Bit size of the value type T
+	container   types.Type // Container type U
+}
+
+// end returns the position just past the most significant bit of the field.
+func (f field) end() uint64 {
+	return f.shift + f.size
+}
+
+// overlaps reports whether two fields share a bit.
+func (f field) overlaps(g field) bool {
+	return f.shift < g.end() && g.shift < f.end()
+}
+
+// bits formats the bit range of the field as datasheets do.
+func (f field) bits() string {
+	if f.size == 1 {
+		return fmt.Sprint(f.shift)
+	}
+	return fmt.Sprintf("%d:%d", f.end()-1, f.shift)
+}
+
+// checker holds the state of one pass.
+type checker struct {
+	pass   *analysis.Pass
+	fields map[types.Object]field // Variables holding known fields
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	c := &checker{pass: pass, fields: make(map[types.Object]field)}
+
+	// Package-level variables may be used before they are declared in the source,
+	// so record them in initialization order first.
+	for _, init := range pass.TypesInfo.InitOrder {
+		if len(init.Lhs) == 1 {
+			if f, ok := c.eval(init.Rhs); ok {
+				c.fields[init.Lhs[0]] = f
+			}
+		}
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	filter := []ast.Node{(*ast.GenDecl)(nil), (*ast.AssignStmt)(nil), (*ast.CallExpr)(nil), (*ast.BlockStmt)(nil)}
+	insp.Preorder(filter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			c.checkDecl(n)
+		case *ast.AssignStmt:
+			c.recordAssign(n)
+		case *ast.CallExpr:
+			c.checkCall(n)
+		case *ast.BlockStmt:
+			c.checkLayoutAdds(n)
+		}
+	})
+	return nil, nil
+}
+
+// recordAssign records local variables defined or assigned from known fields.
+func (c *checker) recordAssign(as *ast.AssignStmt) {
+	if len(as.Lhs) != len(as.Rhs) {
+		return
+	}
+	for i, lhs := range as.Lhs {
+		id, ok := lhs.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		obj := c.pass.TypesInfo.ObjectOf(id)
+		if f, ok := c.eval(as.Rhs[i]); ok && obj != nil {
+			c.fields[obj] = f
+		} else {
+			delete(c.fields, obj)
+		}
+	}
+}
+
+// checkDecl reports overlapping fields declared in the same var block and
+// records local variables initialized with known fields.
+func (c *checker) checkDecl(decl *ast.GenDecl) {
+	if decl.Tok != token.VAR {
+		return
+	}
+	type declared struct {
+		name string
+		f    field
+	}
+	var seen []declared
+	for _, spec := range decl.Specs {
+		vs := spec.(*ast.ValueSpec)
+		if len(vs.Names) != len(vs.Values) {
+			continue
+		}
+		for i, name := range vs.Names {
+			f, ok := c.eval(vs.Values[i])
+			if !ok {
+				continue
+			}
+			if obj := c.pass.TypesInfo.Defs[name]; obj != nil {
+				c.fields[obj] = f
+			}
+			if name.Name == "_" {
+				continue
+			}
+			for _, d := range seen {
+				if types.Identical(d.f.container, f.container) && d.f.overlaps(f) {
+					c.pass.Reportf(name.Pos(), "field %s (bits %s) overlaps field %s (bits %s) in the same %s container",
+						name.Name, f.bits(), d.name, d.f.bits(), types.TypeString(f.container, types.RelativeTo(c.pass.Pkg)))
+					break
+				}
+			}
+			seen = append(seen, declared{name.Name, f})
+		}
+	}
+}
+
+// checkLayoutAdds reports Layout.Add calls in a block whose fields overlap a
+// field added to the same layout earlier in the block.
+func (c *checker) checkLayoutAdds(block *ast.BlockStmt) {
+	type added struct {
+		name string
+		f    field
+	}
+	layouts := make(map[types.Object][]added)
+	for _, stmt := range block.List {
+		call := layoutAdd(stmt)
+		if call == nil {
+			continue
+		}
+		fn, recv := c.method(call)
+		if fn == nil || fn.Name() != "Add" || !isBitfieldType(fn.Signature().Recv().Type(), "Layout") {
+			continue
+		}
+		id, ok := ast.Unparen(recv).(*ast.Ident)
+		if !ok {
+			continue
+		}
+		obj := c.pass.TypesInfo.ObjectOf(id)
+		shift, ok1 := c.constUint(call.Args[1])
+		size, ok2 := c.constUint(call.Args[2])
+		if obj == nil || !ok1 || !ok2 || size == 0 {
+			continue
+		}
+		name := "field"
+		if v, ok := c.constValue(call.Args[0]); ok && v.Kind() == constant.String {
+			name = constant.StringVal(v)
+		}
+		f := field{shift: shift, size: size}
+		for _, a := range layouts[obj] {
+			if a.f.overlaps(f) {
+				c.pass.Reportf(call.Pos(), "layout field %q (bits %s) overlaps field %q (bits %s)", name, f.bits(), a.name, a.f.bits())
+				break
+			}
+		}
+		layouts[obj] = append(layouts[obj], added{name, f})
+	}
+}
+
+// layoutAdd returns the call of a statement that calls a method named Add
+// with three arguments, either alone or as the initializer of an if statement.
+func layoutAdd(stmt ast.Stmt) *ast.CallExpr {
+	var expr ast.Expr
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		expr = s.X
+	case *ast.AssignStmt:
+		if len(s.Rhs) == 1 {
+			expr = s.Rhs[0]
+		}
+	case *ast.IfStmt:
+		if s.Init != nil {
+			return layoutAdd(s.Init)
+		}
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 3 {
+		return nil
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "Add" {
+		return nil
+	}
+	return call
+}
+
+// checkCall reports Encode and Update calls with constant values that overflow
+// the field, and Next calls whose new field exceeds its value type.
+func (c *checker) checkCall(call *ast.CallExpr) {
+	if fn, recv := c.method(call); fn != nil && isBitfieldType(fn.Signature().Recv().Type(), "BitField") {
+		f, ok := c.eval(recv)
+		if !ok {
+			return
+		}
+		switch fn.Name() {
+		case "Encode", "Update":
+			value := call.Args[len(call.Args)-1]
+			if v, ok := c.constUint(value); ok && f.size < 64 && v >= 1<<f.size {
+				c.pass.Reportf(value.Pos(), "value %d overflows %d-bit field (max %d)", v, f.size, uint64(1)<<f.size-1)
+			}
+		case "NextBitField":
+			if size, ok := c.constUint(call.Args[0]); ok {
+				c.checkNext(call, f, size, f.valueBits)
+			}
+		}
+		return
+	}
+
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != bitfieldPath {
+		return
+	}
+	switch fn.Name() {
+	case "Next", "MustNext", "SafeNext":
+		prev, ok1 := c.eval(call.Args[0])
+		size, ok2 := c.constUint(call.Args[1])
+		valueBits, ok3 := c.typeArgBits(call, 0)
+		if ok1 && ok2 && ok3 {
+			c.checkNext(call, prev, size, valueBits)
+		}
+	}
+}
+
+// checkNext reports a field of size bits following prev that exceeds valueBits.
+func (c *checker) checkNext(call *ast.CallExpr, prev field, size, valueBits uint64) {
+	if prev.end()+size > valueBits {
+		c.pass.Reportf(call.Pos(), "next field of %d bits at bit %d exceeds %d-bit value type", size, prev.end(), valueBits)
+	}
+}
+
+// eval returns the field an expression evaluates to, if it is known.
+func (c *checker) eval(expr ast.Expr) (field, bool) {
+	expr = ast.Unparen(expr)
+	if id, ok := expr.(*ast.Ident); ok {
+		f, ok := c.fields[c.pass.TypesInfo.ObjectOf(id)]
+		return f, ok
+	}
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		if obj := c.pass.TypesInfo.ObjectOf(sel.Sel); obj != nil {
+			if f, ok := c.fields[obj]; ok {
+				return f, true
+			}
+		}
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return field{}, false
+	}
+	valueBits, ok1 := c.typeArgBits(call, 0)
+	containerBits, ok2 := c.typeArgBits(call, 1)
+	if !ok1 || !ok2 {
+		return field{}, false
+	}
+	container, _ := c.typeArg(call, 1)
+
+	if fn, recv := c.method(call); fn != nil {
+		if fn.Name() != "NextBitField" || !isBitfieldType(fn.Signature().Recv().Type(), "BitField") {
+			return field{}, false
+		}
+		prev, ok1 := c.eval(recv)
+		size, ok2 := c.constUint(call.Args[0])
+		return field{prev.end(), size, valueBits, container}, ok1 && ok2
+	}
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != bitfieldPath {
+		return field{}, false
+	}
+	switch fn.Name() {
+	case "New", "MustSafe":
+		shift, ok1 := c.constUint(call.Args[0])
+		size, ok2 := c.constUint(call.Args[1])
+		return field{shift, size, valueBits, container}, ok1 && ok2
+	case "NewRange":
+		hi, ok1 := c.constUint(call.Args[0])
+		lo, ok2 := c.constUint(call.Args[1])
+		return field{lo, hi - lo + 1, valueBits, container}, ok1 && ok2 && hi >= lo
+	case "NewMSB0":
+		start, ok1 := c.constUint(call.Args[0])
+		size, ok2 := c.constUint(call.Args[1])
+		return field{containerBits - start - size, size, valueBits, container}, ok1 && ok2 && start+size <= containerBits
+	case "Next", "MustNext":
+		prev, ok1 := c.eval(call.Args[0])
+		size, ok2 := c.constUint(call.Args[1])
+		return field{prev.end(), size, valueBits, container}, ok1 && ok2
+	}
+	return field{}, false
+}
+
+// method returns the method called by call and its receiver expression,
+// or nil if call is not a method call.
+func (c *checker) method(call *ast.CallExpr) (*types.Func, ast.Expr) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, nil
+	}
+	selection, ok := c.pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return nil, nil
+	}
+	fn, _ := selection.Obj().(*types.Func)
+	return fn, sel.X
+}
+
+// typeArg returns type argument i of the BitField type a call returns.
+func (c *checker) typeArg(call *ast.CallExpr, i int) (types.Type, bool) {
+	t := c.pass.TypesInfo.TypeOf(call)
+	if tuple, ok := t.(*types.Tuple); ok && tuple.Len() > 0 {
+		t = tuple.At(0).Type() // SafeNext returns the field and an error
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || !isBitfieldType(named, "BitField") || named.TypeArgs().Len() != 2 {
+		return nil, false
+	}
+	return named.TypeArgs().At(i), true
+}
+
+// typeArgBits returns the bit size of type argument i of the BitField type a call returns.
+func (c *checker) typeArgBits(call *ast.CallExpr, i int) (uint64, bool) {
+	t, ok := c.typeArg(call, i)
+	if !ok {
+		return 0, false
+	}
+	if _, ok := t.Underlying().(*types.Basic); !ok {
+		return 0, false // Type parameter of generic code
+	}
+	return uint64(c.pass.TypesSizes.Sizeof(t)) * 8, true
+}
+
+// constValue returns the constant value of an expression.
+func (c *checker) constValue(expr ast.Expr) (constant.Value, bool) {
+	tv, ok := c.pass.TypesInfo.Types[expr]
+	return tv.Value, ok && tv.Value != nil
+}
+
+// constUint returns the value of a non-negative integer constant expression.
+func (c *checker) constUint(expr ast.Expr) (uint64, bool) {
+	v, ok := c.constValue(expr)
+	if !ok || v.Kind() != constant.Int {
+		return 0, false
+	}
+	return constant.Uint64Val(v)
+}
+
+// isBitfieldType reports whether t, or the type it points to, is the named
+// type name of the bitfield package.
+func isBitfieldType(t types.Type, name string) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == bitfieldPath && obj.Name() == name
+}
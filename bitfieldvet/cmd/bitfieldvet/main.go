@@ -0,0 +1,22 @@
+// Bitfieldvet reports misuse of the bitfield package: overlapping field
+// definitions, constant values that overflow their field, and Next chains
+// that exceed the value type. See the bitfieldvet package for details.
+//
+// Usage:
+//
+//	bitfieldvet [flags] [packages]
+//
+// It can also be run by go vet:
+//
+//	go vet -vettool=$(which bitfieldvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/lnear-dev/bitfield/bitfieldvet"
+)
+
+func main() {
+	singlechecker.Main(bitfieldvet.Analyzer)
+}
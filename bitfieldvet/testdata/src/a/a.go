@@ -0,0 +1,54 @@
+package a
+
+import "github.com/lnear-dev/bitfield"
+
+var (
+	Enable   = bitfield.New[uint8, uint32](0, 1)
+	Mode     = bitfield.New[uint8, uint32](1, 3)
+	Prio     = bitfield.NewRange[uint8, uint32](3, 2) // want `field Prio \(bits 3:2\) overlaps field Mode \(bits 3:1\) in the same uint32 container`
+	Wide     = bitfield.New[uint64, uint64](1, 3)     // Different container, no overlap
+	Status   = bitfield.NewMSB0[uint8, uint32](0, 8)
+	Code     = bitfield.Next[uint8](Mode, 4)
+	TooLarge = bitfield.Next[uint8](Code, 4) // want `next field of 4 bits at bit 8 exceeds 8-bit value type`
+)
+
+var Err = bitfield.MustSafe[uint8, uint32](24, 4) // Separate block, no overlap with Status
+
+func encode(c uint32) uint32 {
+	c = Mode.Update(c, 7)
+	c = Mode.Update(c, 8) // want `value 8 overflows 3-bit field \(max 7\)`
+	c |= Enable.Encode(1)
+	c |= Enable.Encode(2) // want `value 2 overflows 1-bit field \(max 1\)`
+	c |= Status.Encode(255)
+	local := bitfield.New[uint16, uint32](4, 12)
+	c |= local.Encode(0x1000)                        // want `value 4096 overflows 12-bit field \(max 4095\)`
+	c |= bitfield.New[uint8, uint32](0, 2).Encode(4) // want `value 4 overflows 2-bit field \(max 3\)`
+	return c
+}
+
+func next() {
+	f := bitfield.New[uint8, uint32](0, 6)
+	_ = f.NextBitField(2)
+	_ = f.NextBitField(3)                   // want `next field of 3 bits at bit 6 exceeds 8-bit value type`
+	_, _ = bitfield.SafeNext[uint16](f, 10) // Wider value type is fine
+	_ = bitfield.MustNext[uint8](f, 4)      // want `next field of 4 bits at bit 6 exceeds 8-bit value type`
+}
+
+func layout() error {
+	l := bitfield.NewLayout[uint32]()
+	if err := l.Add("enable", 0, 1); err != nil {
+		return err
+	}
+	if err := l.Add("mode", 1, 3); err != nil {
+		return err
+	}
+	if err := l.Add("prio", 3, 2); err != nil { // want `layout field "prio" \(bits 4:3\) overlaps field "mode" \(bits 3:1\)`
+		return err
+	}
+	other := bitfield.NewLayout[uint32]()
+	return other.Add("mode", 1, 3)
+}
+
+func generic[T bitfield.Unsigned, U uint32 | uint64](v T) U {
+	return bitfield.New[T, U](0, 4).Encode(v) // Type parameters are not checked
+}
@@ -0,0 +1,38 @@
+// Package bitfield is a stub of the declarations the analyzer inspects.
+package bitfield
+
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type storageType interface {
+	~uint | ~uint32 | ~uint64
+}
+
+type BitField[T Unsigned, U storageType] struct {
+	Shift, Size uint
+	Mask        U
+}
+
+func New[T Unsigned, U storageType](shift, size uint) BitField[T, U]      { return BitField[T, U]{} }
+func MustSafe[T Unsigned, U storageType](shift, size uint) BitField[T, U] { return BitField[T, U]{} }
+func NewRange[T Unsigned, U storageType](hi, lo uint) BitField[T, U]      { return BitField[T, U]{} }
+func NewMSB0[T Unsigned, U storageType](start, size uint) BitField[T, U]  { return BitField[T, U]{} }
+func Next[T Unsigned, U storageType, Old Unsigned](bf BitField[Old, U], size uint) BitField[T, U] {
+	return BitField[T, U]{}
+}
+func MustNext[T Unsigned, U storageType, Old Unsigned](bf BitField[Old, U], size uint) BitField[T, U] {
+	return BitField[T, U]{}
+}
+func SafeNext[T Unsigned, U storageType, Old Unsigned](bf BitField[Old, U], size uint) (BitField[T, U], error) {
+	return BitField[T, U]{}, nil
+}
+
+func (bf BitField[T, U]) Encode(value T) U                      { return 0 }
+func (bf BitField[T, U]) Update(previous U, value T) U          { return 0 }
+func (bf BitField[T, U]) NextBitField(size uint) BitField[T, U] { return bf }
+
+type Layout[U storageType] struct{}
+
+func NewLayout[U storageType]() *Layout[U]                   { return &Layout[U]{} }
+func (l *Layout[U]) Add(name string, shift, size uint) error { return nil }
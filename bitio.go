@@ -0,0 +1,201 @@
+package bitfield
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// BitReader reads arbitrary numbers of bits sequentially from an underlying byte stream.
+// The BitOrder determines whether bits are consumed from the most or the least
+// significant end of each byte, and in which order they make up the values returned.
+type BitReader struct {
+	r     io.ByteReader
+	order BitOrder
+	cur   byte // Byte currently being consumed
+	avail uint // Number of unread bits left in cur
+	count uint64
+}
+
+// NewBitReader creates a BitReader that reads from r using the given bit order.
+// If r does not implement io.ByteReader it is wrapped in a bufio.Reader,
+// which may read ahead from r.
+func NewBitReader(r io.Reader, order BitOrder) *BitReader {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &BitReader{r: br, order: order}
+}
+
+// NewBitReaderBytes creates a BitReader that reads from buf using the given bit order.
+func NewBitReaderBytes(buf []byte, order BitOrder) *BitReader {
+	return NewBitReader(bytes.NewReader(buf), order)
+}
+
+// ReadBits reads n bits and returns them as the low n bits of the result.
+// n must not exceed 64.
+// Returns io.EOF if no bits could be read and io.ErrUnexpectedEOF if the stream
+// ended in the middle of the value.
+func (br *BitReader) ReadBits(n uint) (uint64, error) {
+	if n > 64 {
+		return 0, fmt.Errorf("cannot read %d bits, max 64", n)
+	}
+	var value uint64
+	done := uint(0)
+	for done < n {
+		if br.avail == 0 {
+			b, err := br.r.ReadByte()
+			if err != nil {
+				if err == io.EOF && done > 0 {
+					err = io.ErrUnexpectedEOF
+				}
+				return 0, err
+			}
+			br.cur, br.avail = b, 8
+		}
+		k := min(br.avail, n-done)
+		mask := byte(1)<<k - 1
+		if br.order == LSBFirst {
+			value |= uint64(br.cur>>(8-br.avail)&mask) << done
+		} else {
+			value = value<<k | uint64(br.cur>>(br.avail-k)&mask)
+		}
+		br.avail -= k
+		done += k
+		br.count += uint64(k)
+	}
+	return value, nil
+}
+
+// ReadBit reads a single bit.
+func (br *BitReader) ReadBit() (bool, error) {
+	v, err := br.ReadBits(1)
+	return v == 1, err
+}
+
+// Align discards the unread bits of the current byte,
+// so that the next read starts at a byte boundary.
+func (br *BitReader) Align() {
+	br.count += uint64(br.avail)
+	br.avail = 0
+}
+
+// BitsRead returns the total number of bits consumed so far, including bits skipped by Align.
+func (br *BitReader) BitsRead() uint64 {
+	return br.count
+}
+
+// DecodeFrom reads bf.Size bits from br and returns them as a value of the field.
+// This allows sequential header formats to be parsed with existing BitField definitions.
+func DecodeFrom[T Unsigned, U storageType](br *BitReader, bf BitField[T, U]) (T, error) {
+	v, err := br.ReadBits(bf.Size)
+	return T(v), err
+}
+
+// BitWriter writes arbitrary numbers of bits sequentially to an underlying io.Writer.
+// Written bits are buffered; call Flush to write out any pending data.
+type BitWriter struct {
+	w     io.Writer
+	order BitOrder
+	buf   []byte // Completed bytes not yet written to w
+	cur   byte   // Byte currently being filled
+	used  uint   // Number of bits already placed in cur
+	count uint64
+}
+
+// bitWriterBufSize is the number of completed bytes buffered before writing to the underlying writer.
+const bitWriterBufSize = 4096
+
+// NewBitWriter creates a BitWriter that writes to w using the given bit order.
+func NewBitWriter(w io.Writer, order BitOrder) *BitWriter {
+	return &BitWriter{w: w, order: order}
+}
+
+// WriteBits writes the low n bits of value.
+// n must not exceed 64 and value must fit in n bits.
+func (bw *BitWriter) WriteBits(value uint64, n uint) error {
+	if n > 64 {
+		return fmt.Errorf("cannot write %d bits, max 64", n)
+	}
+	if value > lowBits(n) {
+		return fmt.Errorf("value %v does not fit in %d bits", value, n)
+	}
+	done := uint(0)
+	for done < n {
+		k := min(8-bw.used, n-done)
+		mask := byte(1)<<k - 1
+		if bw.order == LSBFirst {
+			bw.cur |= byte(value>>done) & mask << bw.used
+		} else {
+			bw.cur |= byte(value>>(n-done-k)) & mask << (8 - bw.used - k)
+		}
+		bw.used += k
+		done += k
+		bw.count += uint64(k)
+		if bw.used == 8 {
+			if err := bw.emit(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteBit writes a single bit.
+func (bw *BitWriter) WriteBit(bit bool) error {
+	var v uint64
+	if bit {
+		v = 1
+	}
+	return bw.WriteBits(v, 1)
+}
+
+// Align pads the current byte with zero bits so that the next write starts at a byte boundary.
+func (bw *BitWriter) Align() error {
+	if bw.used == 0 {
+		return nil
+	}
+	bw.count += uint64(8 - bw.used)
+	return bw.emit()
+}
+
+// Flush aligns the writer to a byte boundary and writes all buffered bytes to the underlying writer.
+func (bw *BitWriter) Flush() error {
+	if err := bw.Align(); err != nil {
+		return err
+	}
+	if len(bw.buf) == 0 {
+		return nil
+	}
+	_, err := bw.w.Write(bw.buf)
+	bw.buf = bw.buf[:0]
+	return err
+}
+
+// BitsWritten returns the total number of bits written so far, including padding added by Align.
+func (bw *BitWriter) BitsWritten() uint64 {
+	return bw.count
+}
+
+// emit moves the current byte to the buffer and writes the buffer out once it is full.
+func (bw *BitWriter) emit() error {
+	bw.buf = append(bw.buf, bw.cur)
+	bw.cur, bw.used = 0, 0
+	if len(bw.buf) < bitWriterBufSize {
+		return nil
+	}
+	_, err := bw.w.Write(bw.buf)
+	bw.buf = bw.buf[:0]
+	return err
+}
+
+// EncodeTo writes value to bw using bf.Size bits.
+// Returns an error if the value is too large for the field.
+func EncodeTo[T Unsigned, U storageType](bw *BitWriter, bf BitField[T, U], value T) error {
+	if !bf.IsValid(value) {
+		return bf.invalidValue(value)
+	}
+	return bw.WriteBits(uint64(value), bf.Size)
+}
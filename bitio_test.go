@@ -0,0 +1,160 @@
+package bitfield
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBitReader_ReadBits(t *testing.T) {
+	data := []byte{0x45, 0xA5, 0x3C}
+	tests := []struct {
+		name  string
+		order BitOrder
+		sizes []uint
+		want  []uint64
+	}{
+		{"msb nibbles", MSBFirst, []uint{4, 4, 4, 4, 4, 4}, []uint64{4, 5, 0xA, 5, 3, 0xC}},
+		{"msb unaligned", MSBFirst, []uint{3, 10, 11}, []uint64{2, 0xB4, 0x53C}},
+		{"msb all", MSBFirst, []uint{24}, []uint64{0x45A53C}},
+		{"lsb nibbles", LSBFirst, []uint{4, 4, 4, 4, 4, 4}, []uint64{5, 4, 5, 0xA, 0xC, 3}},
+		{"lsb unaligned", LSBFirst, []uint{3, 10, 11}, []uint64{5, 0xA8, 0x1E5}},
+		{"lsb all", LSBFirst, []uint{24}, []uint64{0x3CA545}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// OneByteReader hides io.ByteReader, exercising the buffered path.
+			br := NewBitReader(iotest.OneByteReader(bytes.NewReader(data)), tt.order)
+			for i, n := range tt.sizes {
+				got, err := br.ReadBits(n)
+				if err != nil {
+					t.Fatalf("ReadBits(%d): %v", n, err)
+				}
+				if got != tt.want[i] {
+					t.Errorf("read %d: ReadBits(%d) = 0x%X, want 0x%X", i, n, got, tt.want[i])
+				}
+			}
+			if _, err := br.ReadBits(1); err != io.EOF {
+				t.Errorf("ReadBits past end: err = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestBitReader_Errors(t *testing.T) {
+	br := NewBitReaderBytes([]byte{0xFF}, MSBFirst)
+	if _, err := br.ReadBits(65); err == nil {
+		t.Error("ReadBits(65) succeeded, want error")
+	}
+	if _, err := br.ReadBits(12); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadBits(12) on 1 byte: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestBitReader_Align(t *testing.T) {
+	br := NewBitReaderBytes([]byte{0xF0, 0x81}, MSBFirst)
+	if bit, _ := br.ReadBit(); !bit {
+		t.Error("ReadBit() = false, want true")
+	}
+	br.Align()
+	if got, _ := br.ReadBits(8); got != 0x81 {
+		t.Errorf("ReadBits(8) after Align = 0x%X, want 0x81", got)
+	}
+	if got := br.BitsRead(); got != 16 {
+		t.Errorf("BitsRead() = %d, want 16", got)
+	}
+}
+
+func TestBitWriter_RoundTrip(t *testing.T) {
+	values := []struct {
+		v uint64
+		n uint
+	}{
+		{1, 1}, {5, 3}, {0x1FF, 9}, {0, 2}, {0xDEADBEEFCAFEF00D, 64}, {3, 2}, {0x7F, 7},
+	}
+
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		t.Run(order.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			bw := NewBitWriter(&buf, order)
+			for _, v := range values {
+				if err := bw.WriteBits(v.v, v.n); err != nil {
+					t.Fatalf("WriteBits(0x%X, %d): %v", v.v, v.n, err)
+				}
+			}
+			if err := bw.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if got := bw.BitsWritten(); got != 88 {
+				t.Errorf("BitsWritten() = %d, want 88", got)
+			}
+
+			br := NewBitReader(&buf, order)
+			for _, v := range values {
+				got, err := br.ReadBits(v.n)
+				if err != nil {
+					t.Fatalf("ReadBits(%d): %v", v.n, err)
+				}
+				if got != v.v {
+					t.Errorf("ReadBits(%d) = 0x%X, want 0x%X", v.n, got, v.v)
+				}
+			}
+		})
+	}
+}
+
+func TestBitWriter_Layout(t *testing.T) {
+	var buf bytes.Buffer
+	bw := NewBitWriter(&buf, MSBFirst)
+	_ = bw.WriteBits(4, 4)
+	_ = bw.WriteBits(5, 4)
+	_ = bw.WriteBit(true)
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x45, 0x80}; !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("written = % X, want % X", buf.Bytes(), want)
+	}
+}
+
+func TestBitWriter_Errors(t *testing.T) {
+	bw := NewBitWriter(io.Discard, MSBFirst)
+	if err := bw.WriteBits(0, 65); err == nil {
+		t.Error("WriteBits(0, 65) succeeded, want error")
+	}
+	if err := bw.WriteBits(8, 3); err == nil {
+		t.Error("WriteBits(8, 3) succeeded, want error")
+	}
+}
+
+func TestDecodeFrom(t *testing.T) {
+	// IPv4 version and IHL share the first byte of the header.
+	version := New[uint8, uint32](4, 4)
+	ihl := New[uint8, uint32](0, 4)
+
+	var buf bytes.Buffer
+	bw := NewBitWriter(&buf, MSBFirst)
+	if err := EncodeTo(bw, version, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncodeTo(bw, ihl, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncodeTo(bw, ihl, 16); err == nil {
+		t.Error("EncodeTo(16) for 4-bit field succeeded, want error")
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	br := NewBitReader(&buf, MSBFirst)
+	if v, err := DecodeFrom(br, version); err != nil || v != 4 {
+		t.Errorf("DecodeFrom(version) = %v, %v, want 4, nil", v, err)
+	}
+	if v, err := DecodeFrom(br, ihl); err != nil || v != 5 {
+		t.Errorf("DecodeFrom(ihl) = %v, %v, want 5, nil", v, err)
+	}
+}
@@ -0,0 +1,132 @@
+package bitfield
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// BitSet is a vector of bits of arbitrary length, for flag collections that do
+// not fit in a single container, such as interrupt pending bitmaps or
+// allocation maps. Bit i is stored in bit i%64 of word i/64.
+// The zero value is an empty BitSet ready to use; it grows as bits are set.
+// A BitSet is not safe for concurrent use.
+type BitSet struct {
+	words []uint64
+	n     uint // Length in bits
+}
+
+// NewBitSet creates a BitSet of n bits, all cleared.
+func NewBitSet(n uint) *BitSet {
+	return &BitSet{words: make([]uint64, (n+63)/64), n: n}
+}
+
+// BitSetFrom creates a BitSet holding the bits of containers, with container 0
+// holding the lowest bits. The length is the total width of the containers.
+func BitSetFrom[U storageType](containers ...U) *BitSet {
+	width := unsignedSizeOf[U]()
+	b := NewBitSet(uint(len(containers)) * width)
+	for i, c := range containers {
+		pos := uint(i) * width
+		b.words[pos/64] |= uint64(c) << (pos % 64)
+	}
+	return b
+}
+
+// BitSetContainers returns the bits of b as containers of type U, with
+// container 0 holding the lowest bits. The last container is zero-padded.
+func BitSetContainers[U storageType](b *BitSet) []U {
+	width := unsignedSizeOf[U]()
+	containers := make([]U, (b.n+width-1)/width)
+	for i := range containers {
+		pos := uint(i) * width
+		containers[i] = U(b.words[pos/64] >> (pos % 64))
+	}
+	return containers
+}
+
+// grow extends the BitSet to at least n bits.
+func (b *BitSet) grow(n uint) {
+	if n <= b.n {
+		return
+	}
+	if words := int((n + 63) / 64); words > len(b.words) {
+		b.words = slices.Grow(b.words, words-len(b.words))[:words]
+	}
+	b.n = n
+}
+
+// Len returns the length of the BitSet in bits.
+func (b *BitSet) Len() uint {
+	return b.n
+}
+
+// Count returns the number of set bits.
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Set sets bit i, growing the BitSet if i is beyond its length.
+func (b *BitSet) Set(i uint) {
+	b.grow(i + 1)
+	b.words[i/64] |= 1 << (i % 64)
+}
+
+// Clear clears bit i. Bits beyond the length are already clear.
+func (b *BitSet) Clear(i uint) {
+	if i < b.n {
+		b.words[i/64] &^= 1 << (i % 64)
+	}
+}
+
+// Test reports whether bit i is set. Bits beyond the length are clear.
+func (b *BitSet) Test(i uint) bool {
+	return i < b.n && b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Flip inverts bit i, growing the BitSet if i is beyond its length.
+func (b *BitSet) Flip(i uint) {
+	b.grow(i + 1)
+	b.words[i/64] ^= 1 << (i % 64)
+}
+
+// Clone returns a copy of the BitSet.
+func (b *BitSet) Clone() *BitSet {
+	return &BitSet{words: slices.Clone(b.words), n: b.n}
+}
+
+// Ones returns an iterator over the positions of the set bits, in ascending order.
+// The BitSet must not be modified during iteration.
+func (b *BitSet) Ones() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i, w := range b.words {
+			for ; w != 0; w &= w - 1 {
+				if !yield(uint(i)*64 + uint(bits.TrailingZeros64(w))) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Zeros returns an iterator over the positions of the cleared bits below Len,
+// in ascending order. The BitSet must not be modified during iteration.
+func (b *BitSet) Zeros() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i, w := range b.words {
+			w = ^w
+			if base := uint(i) * 64; b.n-base < 64 {
+				w &= lowBits(b.n - base)
+			}
+			for ; w != 0; w &= w - 1 {
+				if !yield(uint(i)*64 + uint(bits.TrailingZeros64(w))) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBitSet(t *testing.T) {
+	var b BitSet
+	if b.Len() != 0 || b.Count() != 0 || b.Test(0) {
+		t.Fatalf("zero BitSet = len %d, count %d", b.Len(), b.Count())
+	}
+
+	b.Set(3)
+	b.Set(64)
+	b.Set(130)
+	if b.Len() != 131 || b.Count() != 3 {
+		t.Errorf("after Set: len %d, count %d, want 131, 3", b.Len(), b.Count())
+	}
+	for _, i := range []uint{3, 64, 130} {
+		if !b.Test(i) {
+			t.Errorf("Test(%d) = false, want true", i)
+		}
+	}
+	if b.Test(4) || b.Test(1000) {
+		t.Error("Test of unset bit = true")
+	}
+
+	b.Clear(64)
+	b.Clear(1000)
+	if b.Test(64) || b.Len() != 131 {
+		t.Errorf("after Clear: Test(64) = %v, len %d, want false, 131", b.Test(64), b.Len())
+	}
+
+	b.Flip(3)
+	b.Flip(200)
+	if b.Test(3) || !b.Test(200) || b.Len() != 201 || b.Count() != 2 {
+		t.Errorf("after Flip: Test(3) = %v, Test(200) = %v, len %d, count %d",
+			b.Test(3), b.Test(200), b.Len(), b.Count())
+	}
+
+	c := b.Clone()
+	c.Set(5)
+	if b.Test(5) {
+		t.Error("Set on clone modified the original")
+	}
+}
+
+func TestNewBitSet(t *testing.T) {
+	b := NewBitSet(100)
+	if b.Len() != 100 || b.Count() != 0 {
+		t.Errorf("NewBitSet(100) = len %d, count %d", b.Len(), b.Count())
+	}
+	b.Set(99)
+	if b.Len() != 100 {
+		t.Errorf("Set within length grew to %d", b.Len())
+	}
+}
+
+func TestBitSetContainers(t *testing.T) {
+	b := BitSetFrom[uint32](0x80000001, 0x12345678, 0x5)
+	if b.Len() != 96 {
+		t.Fatalf("Len() = %d, want 96", b.Len())
+	}
+	if !b.Test(0) || !b.Test(31) || !b.Test(32+3) || !b.Test(64) || b.Test(65) {
+		t.Errorf("bits not at container positions: %X", BitSetContainers[uint64](b))
+	}
+
+	if got, want := BitSetContainers[uint32](b), []uint32{0x80000001, 0x12345678, 0x5}; !slices.Equal(got, want) {
+		t.Errorf("BitSetContainers[uint32] = %X, want %X", got, want)
+	}
+	if got, want := BitSetContainers[uint64](b), []uint64{0x1234567880000001, 0x5}; !slices.Equal(got, want) {
+		t.Errorf("BitSetContainers[uint64] = %X, want %X", got, want)
+	}
+
+	b.Set(100)
+	if got, want := BitSetContainers[uint32](b), []uint32{0x80000001, 0x12345678, 0x5, 0x10}; !slices.Equal(got, want) {
+		t.Errorf("after Set(100): BitSetContainers[uint32] = %X, want %X", got, want)
+	}
+}
+
+func TestBitSet_Ones(t *testing.T) {
+	b := NewBitSet(70)
+	for _, i := range []uint{0, 5, 63, 64, 69} {
+		b.Set(i)
+	}
+	if got, want := slices.Collect(b.Ones()), []uint{0, 5, 63, 64, 69}; !slices.Equal(got, want) {
+		t.Errorf("Ones() = %v, want %v", got, want)
+	}
+
+	zeros := slices.Collect(b.Zeros())
+	if len(zeros) != 65 || zeros[0] != 1 || zeros[len(zeros)-1] != 68 || slices.Contains(zeros, 63) {
+		t.Errorf("Zeros() = %v, want 65 positions from 1 to 68 without set bits", zeros)
+	}
+
+	for i := range b.Ones() {
+		if i > 5 {
+			t.Fatalf("iteration continued to %d after break", i)
+		}
+		if i == 5 {
+			break
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package bitfield
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Kinds of chunks in the compressed BitSet encoding.
+const (
+	chunkZeros   = iota // Run of all-zero words
+	chunkOnes           // Run of all-one words
+	chunkLiteral        // Words stored as they are
+)
+
+// MaxBitSetLen is the largest length in bits that ReadFrom and UnmarshalBinary
+// accept, 2 MiB of words, so that a few bytes of untrusted input cannot
+// declare a set that exhausts memory when its runs are expanded.
+// Use ReadFromLimit to decode larger sets.
+const MaxBitSetLen = 1 << 24
+
+// WriteTo writes a compressed encoding of the BitSet to w, implementing io.WriterTo.
+// Runs of all-zero and all-one words are stored as counts, so sparse and dense
+// sets take little space. The encoding is:
+//
+//	uvarint  length in bits
+//	chunks   until every word is covered, each:
+//	         uvarint count<<2 | kind; kind 0 is count zero words, kind 1 is
+//	         count all-one words and kind 2 is count words that follow as
+//	         8-byte little-endian values
+//
+// The last word counts as all-ones if every bit below the length is set.
+func (b *BitSet) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var buf [binary.MaxVarintLen64]byte
+	var written int64
+	put := func(p []byte) {
+		n, _ := bw.Write(p) // Errors are reported by Flush
+		written += int64(n)
+	}
+	put(binary.AppendUvarint(buf[:0], uint64(b.n)))
+	for i := 0; i < len(b.words); {
+		kind, run := b.chunk(i)
+		put(binary.AppendUvarint(buf[:0], uint64(run)<<2|kind))
+		if kind == chunkLiteral {
+			for _, word := range b.words[i : i+run] {
+				put(binary.LittleEndian.AppendUint64(buf[:0], word))
+			}
+		}
+		i += run
+	}
+	if err := bw.Flush(); err != nil {
+		return written - int64(bw.Buffered()), err
+	}
+	return written, nil
+}
+
+// chunk returns the kind and length of the chunk starting at word i.
+func (b *BitSet) chunk(i int) (uint64, int) {
+	kind := uint64(chunkLiteral)
+	switch b.words[i] {
+	case 0:
+		kind = chunkZeros
+	case b.fullWord(i):
+		kind = chunkOnes
+	}
+	run := 1
+	for j := i + 1; j < len(b.words); j, run = j+1, run+1 {
+		var next uint64 = chunkLiteral
+		switch b.words[j] {
+		case 0:
+			next = chunkZeros
+		case b.fullWord(j):
+			next = chunkOnes
+		}
+		if next != kind {
+			break
+		}
+	}
+	return kind, run
+}
+
+// fullWord returns word i with every bit below the length set.
+func (b *BitSet) fullWord(i int) uint64 {
+	return lowBits(b.n - uint(i)*64)
+}
+
+// ReadFrom replaces the BitSet with one decoded from r in the encoding written
+// by WriteTo, implementing io.ReaderFrom.
+// If r does not implement io.ByteReader, ReadFrom may read past the end of the encoding.
+// Returns an error, leaving b unchanged, if the encoding is malformed or truncated,
+// or declares a length above MaxBitSetLen.
+func (b *BitSet) ReadFrom(r io.Reader) (int64, error) {
+	return b.ReadFromLimit(r, MaxBitSetLen)
+}
+
+// ReadFromLimit is like ReadFrom but accepts lengths up to maxBits bits
+// instead of MaxBitSetLen. The decoded words take up to maxBits/8 bytes,
+// however short the encoding is.
+func (b *BitSet) ReadFromLimit(r io.Reader, maxBits uint) (int64, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	cr := &countingReader{r: br}
+	n, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return cr.n, bitSetReadError(err)
+	}
+	if n > uint64(maxBits) {
+		return cr.n, fmt.Errorf("bitset length %d too large, max %d", n, maxBits)
+	}
+	decoded := BitSet{n: uint(n)}
+	total := int((n + 63) / 64)
+	for len(decoded.words) < total {
+		header, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return cr.n, bitSetReadError(err)
+		}
+		kind, run := header&3, header>>2
+		if run == 0 || run > uint64(total-len(decoded.words)) {
+			return cr.n, fmt.Errorf("invalid bitset chunk of %d words with %d of %d words decoded", run, len(decoded.words), total)
+		}
+		for range run {
+			var word uint64
+			switch kind {
+			case chunkZeros:
+			case chunkOnes:
+				word = decoded.fullWord(len(decoded.words))
+			case chunkLiteral:
+				var buf [8]byte
+				for i := range buf {
+					if buf[i], err = cr.ReadByte(); err != nil {
+						return cr.n, bitSetReadError(err)
+					}
+				}
+				word = binary.LittleEndian.Uint64(buf[:])
+				if word&^decoded.fullWord(len(decoded.words)) != 0 {
+					return cr.n, fmt.Errorf("bitset word %d has bits beyond length %d", len(decoded.words), n)
+				}
+			default:
+				return cr.n, fmt.Errorf("invalid bitset chunk kind %d", kind)
+			}
+			decoded.words = append(decoded.words, word)
+		}
+	}
+	*b = decoded
+	return cr.n, nil
+}
+
+// bitSetReadError reports a premature end of the encoding as io.ErrUnexpectedEOF.
+func bitSetReadError(err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("read bitset: %w", err)
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.ByteReader
+	n int64
+}
+
+func (cr *countingReader) ReadByte() (byte, error) {
+	c, err := cr.r.ReadByte()
+	if err == nil {
+		cr.n++
+	}
+	return c, err
+}
+
+// MarshalBinary encodes the BitSet in the compressed encoding of WriteTo.
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the BitSet from the compressed encoding of WriteTo.
+// Returns an error, leaving b unchanged, if data is malformed or has trailing bytes.
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	var decoded BitSet
+	n, err := decoded.ReadFrom(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if n != int64(len(data)) {
+		return fmt.Errorf("%d trailing bytes after bitset", int64(len(data))-n)
+	}
+	*b = decoded
+	return nil
+}
@@ -0,0 +1,122 @@
+package bitfield
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBitSet_WriteTo(t *testing.T) {
+	sparse := NewBitSet(1 << 20)
+	sparse.Set(5)
+	sparse.Set(700000)
+	dense := NewBitSet(1000)
+	for i := range uint(1000) {
+		dense.Set(i)
+	}
+	dense.Clear(500)
+	mixed := BitSetFrom[uint64](0, 0, 0xDEADBEEF, ^uint64(0), ^uint64(0), 0)
+
+	tests := []struct {
+		name    string
+		b       *BitSet
+		maxSize int
+	}{
+		{"empty", &BitSet{}, 1},
+		{"sparse", sparse, 32},
+		{"dense", dense, 32},
+		{"mixed", mixed, 20},
+		{"ragged", BitSetFrom[uint32](1, 2, 3), 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := tt.b.WriteTo(&buf)
+			if err != nil || n != int64(buf.Len()) {
+				t.Fatalf("WriteTo = %d, %v, wrote %d bytes", n, err, buf.Len())
+			}
+			if buf.Len() > tt.maxSize {
+				t.Errorf("encoding is %d bytes, want at most %d", buf.Len(), tt.maxSize)
+			}
+
+			var got BitSet
+			m, err := got.ReadFrom(iotest.OneByteReader(bytes.NewReader(buf.Bytes())))
+			if err != nil || m != n {
+				t.Fatalf("ReadFrom = %d, %v, want %d", m, err, n)
+			}
+			if got.Len() != tt.b.Len() || !slices.Equal(slices.Collect(got.Ones()), slices.Collect(tt.b.Ones())) {
+				t.Errorf("round trip = len %d with %d bits set, want len %d with %d", got.Len(), got.Count(), tt.b.Len(), tt.b.Count())
+			}
+		})
+	}
+}
+
+func TestBitSet_UnmarshalBinary(t *testing.T) {
+	b := BitSetFrom[uint64](0x5, ^uint64(0), 0)
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got BitSet
+	if err := got.UnmarshalBinary(data); err != nil || got.Count() != 66 || got.Len() != 192 {
+		t.Errorf("UnmarshalBinary = len %d, count %d, %v", got.Len(), got.Count(), err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated literal", data[:4]},
+		{"trailing bytes", append(slices.Clone(data), 0)},
+		{"chunk too long", []byte{64, 2<<2 | chunkZeros}},
+		{"zero-length chunk", []byte{64, 0<<2 | chunkZeros}},
+		{"bad kind", []byte{64, 1<<2 | 3}},
+		{"bits beyond length", []byte{4, 1<<2 | chunkLiteral, 0x10, 0, 0, 0, 0, 0, 0, 0}},
+		{"length too large", binary.AppendUvarint(binary.AppendUvarint(nil, 1<<60), 1<<54<<2|chunkZeros)},
+		{"length above maximum", binary.AppendUvarint(binary.AppendUvarint(nil, MaxBitSetLen+1), (MaxBitSetLen/64+1)<<2|chunkZeros)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := BitSetFrom[uint32](7)
+			if err := orig.UnmarshalBinary(tt.data); err == nil {
+				t.Errorf("UnmarshalBinary(%X) succeeded, want error", tt.data)
+			}
+			if orig.Len() != 32 || orig.Count() != 3 {
+				t.Error("UnmarshalBinary modified the BitSet on error")
+			}
+		})
+	}
+
+	var trunc BitSet
+	if _, err := trunc.ReadFrom(bytes.NewReader(data[:4])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadFrom of truncated data = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestBitSet_ReadFromLimit(t *testing.T) {
+	data, err := BitSetFrom[uint64](0x5, ^uint64(0), 0).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b BitSet
+	if _, err := b.ReadFromLimit(bytes.NewReader(data), 191); err == nil {
+		t.Error("ReadFromLimit(191) of a 192-bit set succeeded, want error")
+	}
+	if _, err := b.ReadFromLimit(bytes.NewReader(data), 192); err != nil || b.Count() != 66 {
+		t.Errorf("ReadFromLimit(192) = count %d, %v", b.Count(), err)
+	}
+
+	large := binary.AppendUvarint(binary.AppendUvarint(nil, MaxBitSetLen+64), (MaxBitSetLen/64+1)<<2|chunkZeros)
+	if _, err := b.ReadFrom(bytes.NewReader(large)); err == nil {
+		t.Error("ReadFrom of a set above MaxBitSetLen succeeded, want error")
+	}
+	if _, err := b.ReadFromLimit(bytes.NewReader(large), MaxBitSetLen+64); err != nil || b.Len() != MaxBitSetLen+64 {
+		t.Errorf("ReadFromLimit(MaxBitSetLen+64) = len %d, %v", b.Len(), err)
+	}
+}
@@ -0,0 +1,129 @@
+// Package bloom provides a Bloom filter backed by a bitfield.BitSet.
+//
+// A Bloom filter answers set membership queries with no false negatives and a
+// tunable rate of false positives, using a fixed number of bits regardless of
+// the size of the elements. Elements are hashed with 64-bit FNV-1a, and the k
+// bit positions of an element are derived from that one hash by double hashing,
+// so filters with equal parameters are compatible across processes.
+package bloom
+
+import (
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"math"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Filter is a Bloom filter of m bits and k hash functions.
+// A Filter is not safe for concurrent use.
+type Filter struct {
+	bits *bitfield.BitSet
+	m, k uint
+}
+
+// New creates an empty Filter of m bits using k hash functions.
+// Panics if m or k is 0.
+func New(m, k uint) *Filter {
+	if m == 0 || k == 0 {
+		panic(fmt.Sprintf("bloom: invalid parameters m = %d, k = %d", m, k))
+	}
+	return &Filter{bits: bitfield.NewBitSet(m), m: m, k: k}
+}
+
+// NewWithEstimates creates a Filter sized for n elements at a false positive rate p.
+// Panics if n is 0 or p is not strictly between 0 and 1.
+func NewWithEstimates(n uint, p float64) *Filter {
+	if n == 0 || !(p > 0 && p < 1) {
+		panic(fmt.Sprintf("bloom: invalid estimates n = %d, p = %v", n, p))
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+	return New(uint(m), uint(max(k, 1)))
+}
+
+// Cap returns the number of bits of the filter.
+func (f *Filter) Cap() uint {
+	return f.m
+}
+
+// K returns the number of hash functions of the filter.
+func (f *Filter) K() uint {
+	return f.k
+}
+
+// BitSet returns the bits of the filter, for example to serialize them.
+// Modifying the BitSet modifies the filter.
+func (f *Filter) BitSet() *bitfield.BitSet {
+	return f.bits
+}
+
+// positions returns the bit positions of an element.
+func (f *Filter) positions(data []byte) iter.Seq[uint] {
+	h := fnv.New64a()
+	h.Write(data)
+	sum := h.Sum64()
+	h1, h2 := sum&0xFFFFFFFF, sum>>32|1 // Odd h2 visits every position for power-of-two m
+	return func(yield func(uint) bool) {
+		for i := range uint64(f.k) {
+			if !yield(uint((h1 + i*h2) % uint64(f.m))) {
+				return
+			}
+		}
+	}
+}
+
+// Add adds an element to the filter.
+func (f *Filter) Add(data []byte) {
+	for i := range f.positions(data) {
+		f.bits.Set(i)
+	}
+}
+
+// AddString adds a string element to the filter.
+func (f *Filter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Test reports whether the element may be in the filter.
+// False means the element was definitely never added.
+func (f *Filter) Test(data []byte) bool {
+	for i := range f.positions(data) {
+		if !f.bits.Test(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString reports whether the string element may be in the filter.
+func (f *Filter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// Union adds all elements of other to the filter, so that the filter then
+// reports every element that was added to either.
+// Returns an error if the filters have different parameters.
+func (f *Filter) Union(other *Filter) error {
+	if f.m != other.m || f.k != other.k {
+		return fmt.Errorf("bloom: cannot union filter of m = %d, k = %d with m = %d, k = %d", f.m, f.k, other.m, other.k)
+	}
+	for i := range other.bits.Ones() {
+		f.bits.Set(i)
+	}
+	return nil
+}
+
+// EstimateCount estimates the number of distinct elements added to the filter
+// from the fraction of set bits. It returns +Inf if every bit is set.
+func (f *Filter) EstimateCount() float64 {
+	m, k := float64(f.m), float64(f.k)
+	return -m / k * math.Log(1-float64(f.bits.Count())/m)
+}
+
+// FalsePositiveRate estimates the probability that Test reports true for an
+// element that was not added, given the current fraction of set bits.
+func (f *Filter) FalsePositiveRate() float64 {
+	return math.Pow(float64(f.bits.Count())/float64(f.m), float64(f.k))
+}
@@ -0,0 +1,79 @@
+package bloom
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+	if f.Cap() != 9586 || f.K() != 7 {
+		t.Errorf("NewWithEstimates(1000, 0.01) = m %d, k %d, want 9586, 7", f.Cap(), f.K())
+	}
+	for i := range 1000 {
+		f.AddString("in-" + strconv.Itoa(i))
+	}
+	for i := range 1000 {
+		if !f.TestString("in-" + strconv.Itoa(i)) {
+			t.Fatalf("added element %d not found", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := range 10000 {
+		if f.TestString("out-" + strconv.Itoa(i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / 10000; rate > 0.02 {
+		t.Errorf("false positive rate = %v, want about 0.01", rate)
+	}
+	if rate := f.FalsePositiveRate(); rate < 0.005 || rate > 0.02 {
+		t.Errorf("FalsePositiveRate() = %v, want about 0.01", rate)
+	}
+	if n := f.EstimateCount(); math.Abs(n-1000) > 50 {
+		t.Errorf("EstimateCount() = %v, want about 1000", n)
+	}
+}
+
+func TestFilter_Union(t *testing.T) {
+	a, b := New(4096, 4), New(4096, 4)
+	for i := range 200 {
+		a.AddString("a" + strconv.Itoa(i))
+		b.AddString("b" + strconv.Itoa(i))
+	}
+	if err := a.Union(b); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 200 {
+		if !a.TestString("a"+strconv.Itoa(i)) || !a.TestString("b"+strconv.Itoa(i)) {
+			t.Fatalf("element %d missing after Union", i)
+		}
+	}
+	if n := a.EstimateCount(); math.Abs(n-400) > 30 {
+		t.Errorf("EstimateCount() after Union = %v, want about 400", n)
+	}
+
+	if err := a.Union(New(4096, 3)); err == nil {
+		t.Error("Union of filters with different k succeeded, want error")
+	}
+}
+
+func TestNew_Panics(t *testing.T) {
+	for _, fn := range []func(){
+		func() { New(0, 3) },
+		func() { New(64, 0) },
+		func() { NewWithEstimates(0, 0.01) },
+		func() { NewWithEstimates(100, 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("invalid parameters did not panic")
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,68 @@
+package bitfield
+
+// DecodeSlice extracts the field from each container of src into dst, like copy:
+// it decodes min(len(dst), len(src)) elements and returns the number decoded.
+// Reusing dst across calls avoids allocating when scanning large record sets.
+// Fields of []uint64 decoded into []uint64 use a vectorized kernel where the CPU supports one.
+func (bf BitField[T, U]) DecodeSlice(dst []T, src []U) int {
+	n := min(len(dst), len(src))
+	dst, src = dst[:n], src[:n]
+	if d, ok := any(dst).([]uint64); ok {
+		if s, ok := any(src).([]uint64); ok {
+			decode64(d, s, uint64(bf.Mask), bf.Shift)
+			return n
+		}
+	}
+	mask, shift := bf.Mask, bf.Shift
+	for i, c := range src {
+		dst[i] = T((c & mask) >> shift)
+	}
+	return n
+}
+
+// decode64Generic extracts a field from each word of src into dst, which must be
+// at least as long as src. It is unrolled so the compiler can drop bounds checks
+// and overlap the iterations.
+func decode64Generic(dst, src []uint64, mask uint64, shift uint) {
+	dst = dst[:len(src)]
+	i := 0
+	for ; i+4 <= len(src); i += 4 {
+		s, d := src[i:i+4:i+4], dst[i:i+4:i+4]
+		d[0] = (s[0] & mask) >> shift
+		d[1] = (s[1] & mask) >> shift
+		d[2] = (s[2] & mask) >> shift
+		d[3] = (s[3] & mask) >> shift
+	}
+	for ; i < len(src); i++ {
+		dst[i] = (src[i] & mask) >> shift
+	}
+}
+
+// UpdateSlice sets the field of each container of dst to the corresponding value
+// of src, preserving all other bits. It updates min(len(dst), len(src)) elements
+// and returns the number updated.
+// Panics, like Update, if a value is too large for the field or is not one of
+// its allowed values; dst is left unmodified then.
+func (bf BitField[T, U]) UpdateSlice(dst []U, src []T) int {
+	n := min(len(dst), len(src))
+	dst, src = dst[:n], src[:n]
+	for _, v := range src {
+		if !bf.IsValid(v) {
+			panic(bf.invalidValue(v).Error())
+		}
+	}
+	mask, shift := bf.Mask, bf.Shift
+	for i, v := range src {
+		dst[i] = dst[i]&^mask | U(v)<<shift
+	}
+	return n
+}
+
+// FillSlice sets the field of every container of dst to value, preserving all other bits.
+// Panics if the value is too large for the field.
+func (bf BitField[T, U]) FillSlice(dst []U, value T) {
+	bits := bf.Encode(value)
+	for i := range dst {
+		dst[i] = dst[i]&^bf.Mask | bits
+	}
+}
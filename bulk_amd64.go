@@ -0,0 +1,57 @@
+//go:build !purego
+
+package bitfield
+
+// hasAVX2 reports whether the CPU and operating system support AVX2.
+var hasAVX2 = detectAVX2()
+
+// detectAVX2 checks the CPUID feature bits for AVX2 and that the operating
+// system saves the YMM registers, as internal/cpu does, so that the core
+// package needs no dependency for it.
+func detectAVX2() bool {
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 7 {
+		return false
+	}
+	const (
+		osxsave = 1 << 27 // CPUID.1:ECX
+		avx     = 1 << 28 // CPUID.1:ECX
+		avx2    = 1 << 5  // CPUID.(7,0):EBX
+		ymm     = 1<<1 | 1<<2
+	)
+	_, _, ecx1, _ := cpuid(1, 0)
+	if ecx1&(osxsave|avx) != osxsave|avx {
+		return false
+	}
+	if xcr0, _ := xgetbv(); xcr0&ymm != ymm {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&avx2 != 0
+}
+
+// cpuid executes the CPUID instruction for the given leaf and subleaf.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv reads the XCR0 extended control register.
+// It must only be called if CPUID reports OSXSAVE.
+func xgetbv() (eax, edx uint32)
+
+// decode64 extracts a field from each word of src into dst, using AVX2 for
+// blocks of 8 words when available.
+func decode64(dst, src []uint64, mask uint64, shift uint) {
+	n := 0
+	if hasAVX2 {
+		n = len(src) &^ 7
+		if n > 0 {
+			decode64AVX2(dst[:n], src[:n], mask, shift)
+		}
+	}
+	decode64Generic(dst[n:], src[n:], mask, shift)
+}
+
+// decode64AVX2 extracts a field from each word of src into dst.
+// len(src) must be a multiple of 8 and dst at least as long.
+//
+//go:noescape
+func decode64AVX2(dst, src []uint64, mask uint64, shift uint)
@@ -0,0 +1,53 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func decode64AVX2(dst, src []uint64, mask uint64, shift uint)
+TEXT ·decode64AVX2(SB), NOSPLIT, $0-64
+	MOVQ dst_base+0(FP), DI
+	MOVQ src_base+24(FP), SI
+	MOVQ src_len+32(FP), CX
+	MOVQ mask+48(FP), AX
+	MOVQ shift+56(FP), BX
+	VMOVQ AX, X0
+	VPBROADCASTQ X0, Y0
+	VMOVQ BX, X1
+	SHRQ $3, CX
+	JZ   done
+
+loop:
+	VMOVDQU (SI), Y2
+	VMOVDQU 32(SI), Y3
+	VPAND   Y0, Y2, Y2
+	VPAND   Y0, Y3, Y3
+	VPSRLQ  X1, Y2, Y2
+	VPSRLQ  X1, Y3, Y3
+	VMOVDQU Y2, (DI)
+	VMOVDQU Y3, 32(DI)
+	ADDQ    $64, SI
+	ADDQ    $64, DI
+	DECQ    CX
+	JNZ     loop
+
+done:
+	VZEROUPPER
+	RET
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
@@ -0,0 +1,8 @@
+//go:build !amd64 || purego
+
+package bitfield
+
+// decode64 extracts a field from each word of src into dst.
+func decode64(dst, src []uint64, mask uint64, shift uint) {
+	decode64Generic(dst, src, mask, shift)
+}
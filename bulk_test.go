@@ -0,0 +1,112 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBitField_DecodeSlice(t *testing.T) {
+	bf := New[uint16, uint64](20, 12)
+	src := []uint64{0, 0xABC00000, 0xFFFFFFFFFFFFFFFF, 0x00100000}
+	dst := make([]uint16, 3)
+	if n := bf.DecodeSlice(dst, src); n != 3 {
+		t.Errorf("DecodeSlice into short dst = %d, want 3", n)
+	}
+	if want := []uint16{0, 0xABC, 0xFFF}; !slices.Equal(dst, want) {
+		t.Errorf("DecodeSlice = %X, want %X", dst, want)
+	}
+
+	dst = make([]uint16, 8)
+	if n := bf.DecodeSlice(dst, src); n != 4 || dst[3] != 1 || dst[4] != 0 {
+		t.Errorf("DecodeSlice into long dst = %d, %X", n, dst)
+	}
+}
+
+func TestBitField_UpdateSlice(t *testing.T) {
+	bf := New[uint8, uint32](4, 4)
+	dst := []uint32{0xFFFFFFFF, 0, 0x12345678}
+	if n := bf.UpdateSlice(dst, []uint8{0, 0xA, 0x3, 0x7}); n != 3 {
+		t.Errorf("UpdateSlice = %d, want 3", n)
+	}
+	if want := []uint32{0xFFFFFF0F, 0xA0, 0x12345638}; !slices.Equal(dst, want) {
+		t.Errorf("UpdateSlice = %X, want %X", dst, want)
+	}
+
+	bf.FillSlice(dst, 0x5)
+	if want := []uint32{0xFFFFFF5F, 0x50, 0x12345658}; !slices.Equal(dst, want) {
+		t.Errorf("FillSlice = %X, want %X", dst, want)
+	}
+
+	before := slices.Clone(dst)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("UpdateSlice with out-of-range value did not panic")
+			}
+		}()
+		bf.UpdateSlice(dst, []uint8{1, 2, 0x10})
+	}()
+	if !slices.Equal(dst, before) {
+		t.Errorf("UpdateSlice modified dst before panicking: %X, want %X", dst, before)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("UpdateSlice with a value that is not allowed did not panic")
+			}
+		}()
+		bf.WithAllowed(1, 2).UpdateSlice(dst, []uint8{1, 2, 3})
+	}()
+	if !slices.Equal(dst, before) {
+		t.Errorf("UpdateSlice modified dst before panicking: %X, want %X", dst, before)
+	}
+}
+
+func TestDecode64(t *testing.T) {
+	src := make([]uint64, 37)
+	for i := range src {
+		src[i] = uint64(i)*0x9E3779B97F4A7C15 + 1
+	}
+	for _, f := range []struct{ shift, size uint }{{0, 1}, {3, 13}, {32, 32}, {63, 1}, {0, 64}} {
+		bf := New[uint64, uint64](f.shift, f.size)
+		for _, n := range []int{0, 1, 7, 8, 9, 16, 37} {
+			got := make([]uint64, n)
+			bf.DecodeSlice(got, src[:n])
+			for i, c := range src[:n] {
+				if want := bf.Decode(c); got[i] != want {
+					t.Fatalf("shift %d size %d len %d: element %d = 0x%X, want 0x%X", f.shift, f.size, n, i, got[i], want)
+				}
+			}
+		}
+	}
+}
+
+func benchmarkDecode64(b *testing.B, decode func(dst, src []uint64, mask uint64, shift uint)) {
+	src := make([]uint64, 4096)
+	for i := range src {
+		src[i] = uint64(i) * 0x9E3779B97F4A7C15
+	}
+	dst := make([]uint64, len(src))
+	bf := New[uint64, uint64](17, 23)
+	b.SetBytes(int64(len(src) * 8))
+	for range b.N {
+		decode(dst, src, bf.Mask, bf.Shift)
+	}
+}
+
+func BenchmarkDecodeSlice(b *testing.B) {
+	benchmarkDecode64(b, decode64)
+}
+
+func BenchmarkDecodeSlice_Generic(b *testing.B) {
+	benchmarkDecode64(b, decode64Generic)
+}
+
+func BenchmarkDecodeSlice_Scalar(b *testing.B) {
+	benchmarkDecode64(b, func(dst, src []uint64, mask uint64, shift uint) {
+		for i, c := range src {
+			dst[i] = (c & mask) >> shift
+		}
+	})
+}
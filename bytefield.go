@@ -0,0 +1,123 @@
+package bitfield
+
+import "fmt"
+
+// BitOrder selects how bit offsets within a byte buffer are numbered.
+type BitOrder int
+
+const (
+	// MSBFirst numbers bits starting at the most significant bit of the first byte,
+	// as in network protocol diagrams. Field values are stored most significant bit first.
+	MSBFirst BitOrder = iota
+	// LSBFirst numbers bits starting at the least significant bit of the first byte,
+	// as in little-endian formats. Field values are stored least significant bit first.
+	LSBFirst
+)
+
+// String returns the name of the bit order.
+func (o BitOrder) String() string {
+	switch o {
+	case MSBFirst:
+		return "MSBFirst"
+	case LSBFirst:
+		return "LSBFirst"
+	}
+	return fmt.Sprintf("BitOrder(%d)", int(o))
+}
+
+// ByteField represents a field of bits at an arbitrary bit offset inside a byte buffer.
+// It allows packet buffers to be read and written in place without first
+// copying them into an unsigned integer container.
+// T represents the type of values that can be stored in the field.
+type ByteField[T Unsigned] struct {
+	Offset uint     // Bit offset of the first bit of the field
+	Size   uint     // Number of bits in the field
+	Order  BitOrder // Bit numbering used for Offset and for the value bits
+}
+
+// NewByteField creates a new ByteField with the given bit offset, size and bit order.
+// Note: This function doesn't perform validation, use SafeByteField for validated creation.
+func NewByteField[T Unsigned](offset, size uint, order BitOrder) ByteField[T] {
+	return ByteField[T]{Offset: offset, Size: size, Order: order}
+}
+
+// SafeByteField creates a new ByteField after validating the parameters.
+// Returns an error if size is 0, size exceeds the bit size of type T,
+// or order is not a known BitOrder.
+func SafeByteField[T Unsigned](offset, size uint, order BitOrder) (ByteField[T], error) {
+	var bf ByteField[T]
+	switch {
+	case size == 0 || size > unsignedSizeOf[T]():
+		return bf, ErrSizeOutOfRange
+	case order != MSBFirst && order != LSBFirst:
+		return bf, fmt.Errorf("invalid bit order %v", order)
+	}
+	return NewByteField[T](offset, size, order), nil
+}
+
+// InBounds reports whether the field lies entirely within buf.
+func (bf ByteField[T]) InBounds(buf []byte) bool {
+	return bf.Offset+bf.Size <= uint(len(buf))*8
+}
+
+// IsValid checks if the value fits within the field.
+func (bf ByteField[T]) IsValid(value T) bool {
+	return bf.Size >= 64 || uint64(value) < uint64(1)<<bf.Size
+}
+
+// Decode extracts the field from buf.
+// Panics if the field does not lie within buf.
+func (bf ByteField[T]) Decode(buf []byte) T {
+	if !bf.InBounds(buf) {
+		panic(fmt.Sprintf("field at bit %d with size %d exceeds buffer of %d bytes", bf.Offset, bf.Size, len(buf)))
+	}
+	var value uint64
+	pos, done := bf.Offset, uint(0)
+	for done < bf.Size {
+		bit := pos % 8
+		n := min(8-bit, bf.Size-done)
+		mask := byte(1)<<n - 1
+		if bf.Order == LSBFirst {
+			value |= uint64(buf[pos/8]>>bit&mask) << done
+		} else {
+			value = value<<n | uint64(buf[pos/8]>>(8-bit-n)&mask)
+		}
+		pos += n
+		done += n
+	}
+	return T(value)
+}
+
+// Update writes value into the field within buf, preserving all other bits.
+// Panics if the value is too large for the field or the field does not lie within buf.
+func (bf ByteField[T]) Update(buf []byte, value T) {
+	if !bf.IsValid(value) {
+		panic(fmt.Sprintf("value %v out of range for %d-bit field", value, bf.Size))
+	}
+	if !bf.InBounds(buf) {
+		panic(fmt.Sprintf("field at bit %d with size %d exceeds buffer of %d bytes", bf.Offset, bf.Size, len(buf)))
+	}
+	v := uint64(value)
+	pos, done := bf.Offset, uint(0)
+	for done < bf.Size {
+		bit := pos % 8
+		n := min(8-bit, bf.Size-done)
+		mask := byte(1)<<n - 1
+		var chunk byte
+		var shift uint
+		if bf.Order == LSBFirst {
+			chunk, shift = byte(v>>done)&mask, bit
+		} else {
+			chunk, shift = byte(v>>(bf.Size-done-n))&mask, 8-bit-n
+		}
+		buf[pos/8] = buf[pos/8]&^(mask<<shift) | chunk<<shift
+		pos += n
+		done += n
+	}
+}
+
+// Clear zeroes out the bits of this field within buf.
+// Panics if the field does not lie within buf.
+func (bf ByteField[T]) Clear(buf []byte) {
+	bf.Update(buf, 0)
+}
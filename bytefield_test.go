@@ -0,0 +1,116 @@
+package bitfield
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSafeByteField(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    uint
+		order   BitOrder
+		wantErr bool
+	}{
+		{"valid", 12, MSBFirst, false},
+		{"full width", 16, LSBFirst, false},
+		{"zero size", 0, MSBFirst, true},
+		{"too wide", 17, MSBFirst, true},
+		{"bad order", 4, BitOrder(7), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SafeByteField[uint16](3, tt.size, tt.order)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SafeByteField(3, %v, %v): err = %v, want err = %v",
+					tt.size, tt.order, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestByteField_Decode(t *testing.T) {
+	buf := []byte{0x45, 0x00, 0x00, 0x54, 0xA5, 0x3C}
+	tests := []struct {
+		name  string
+		field ByteField[uint64]
+		want  uint64
+	}{
+		{"msb nibble", NewByteField[uint64](0, 4, MSBFirst), 4},
+		{"msb second nibble", NewByteField[uint64](4, 4, MSBFirst), 5},
+		{"msb 16-bit aligned", NewByteField[uint64](16, 16, MSBFirst), 0x54},
+		{"msb spanning bytes", NewByteField[uint64](36, 8, MSBFirst), 0x53},
+		{"msb whole buffer", NewByteField[uint64](0, 48, MSBFirst), 0x45000054A53C},
+		{"lsb nibble", NewByteField[uint64](0, 4, LSBFirst), 5},
+		{"lsb second nibble", NewByteField[uint64](4, 4, LSBFirst), 4},
+		{"lsb spanning bytes", NewByteField[uint64](36, 8, LSBFirst), 0xCA},
+		{"lsb whole buffer", NewByteField[uint64](0, 48, LSBFirst), 0x3CA554000045},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Decode(buf); got != tt.want {
+				t.Errorf("Decode() = 0x%X, want 0x%X", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteField_Update(t *testing.T) {
+	tests := []struct {
+		name  string
+		field ByteField[uint16]
+		value uint16
+		want  []byte
+	}{
+		{"msb aligned", NewByteField[uint16](8, 8, MSBFirst), 0xAB, []byte{0xFF, 0xAB, 0xFF}},
+		{"msb spanning", NewByteField[uint16](4, 12, MSBFirst), 0x123, []byte{0xF1, 0x23, 0xFF}},
+		{"msb odd offset", NewByteField[uint16](3, 3, MSBFirst), 0, []byte{0xE3, 0xFF, 0xFF}},
+		{"lsb spanning", NewByteField[uint16](4, 12, LSBFirst), 0x123, []byte{0x3F, 0x12, 0xFF}},
+		{"lsb odd offset", NewByteField[uint16](3, 3, LSBFirst), 0, []byte{0xC7, 0xFF, 0xFF}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := []byte{0xFF, 0xFF, 0xFF}
+			tt.field.Update(buf, tt.value)
+			if !bytes.Equal(buf, tt.want) {
+				t.Errorf("Update(0x%X) = % X, want % X", tt.value, buf, tt.want)
+			}
+			if got := tt.field.Decode(buf); got != tt.value {
+				t.Errorf("Decode() after Update = 0x%X, want 0x%X", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestByteField_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"decode out of bounds", func() { NewByteField[uint8](10, 8, MSBFirst).Decode(make([]byte, 2)) }},
+		{"update out of bounds", func() { NewByteField[uint8](10, 8, LSBFirst).Update(make([]byte, 2), 1) }},
+		{"update value too large", func() { NewByteField[uint8](0, 3, MSBFirst).Update(make([]byte, 2), 8) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", tt.name)
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func TestByteField_Clear(t *testing.T) {
+	buf := []byte{0xFF, 0xFF}
+	NewByteField[uint8](6, 4, MSBFirst).Clear(buf)
+	if want := []byte{0xFC, 0x3F}; !bytes.Equal(buf, want) {
+		t.Errorf("Clear() = % X, want % X", buf, want)
+	}
+}
@@ -0,0 +1,99 @@
+package bitfield
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Alphabet maps the characters of short identifiers, such as ticker symbols or
+// location codes, to fixed-width codes for PackString.
+// Code 0 marks an unused position, so characters are coded from 1 and an
+// alphabet of n characters needs bits.Len(n) bits per character.
+type Alphabet struct {
+	chars string
+	width uint
+	codes [256]uint8 // Code of each byte, 0 if not in the alphabet
+}
+
+// Predefined alphabets. Both list their characters in ASCII order, so packed
+// values sort like the strings they hold.
+var (
+	Upper5 = mustAlphabet("ABCDEFGHIJKLMNOPQRSTUVWXYZ")           // 5 bits per character
+	Alnum6 = mustAlphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ") // 6 bits per character
+)
+
+func mustAlphabet(chars string) *Alphabet {
+	a, err := NewAlphabet(chars)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// NewAlphabet creates an Alphabet of single-byte characters.
+// Returns an error if chars is empty, has more than 255 characters, or repeats a character.
+func NewAlphabet(chars string) (*Alphabet, error) {
+	if len(chars) == 0 || len(chars) > 255 {
+		return nil, fmt.Errorf("alphabet must have 1 to 255 characters, got %d", len(chars))
+	}
+	a := &Alphabet{chars: chars, width: uint(bits.Len(uint(len(chars))))}
+	for i := range len(chars) {
+		if a.codes[chars[i]] != 0 {
+			return nil, fmt.Errorf("duplicate character %q in alphabet", chars[i])
+		}
+		a.codes[chars[i]] = uint8(i + 1)
+	}
+	return a, nil
+}
+
+// Width returns the number of bits per character.
+func (a *Alphabet) Width() uint {
+	return a.width
+}
+
+// MaxLen returns the number of characters that fit in a container of type U.
+func MaxLen[U storageType](a *Alphabet) int {
+	return int(unsignedSizeOf[U]() / a.width)
+}
+
+// charField returns the field of character i in a container of type U.
+// Character 0 is the most significant, so packed values sort like the strings.
+func charField[U storageType](a *Alphabet, i int) BitField[uint8, U] {
+	return New[uint8, U](uint(MaxLen[U](a)-1-i)*a.width, a.width)
+}
+
+// PackString packs s into a container, one character per Alphabet.Width bits,
+// starting at the most significant end. Unused positions are 0.
+// Returns an error if s is longer than MaxLen or has a character outside the alphabet.
+func PackString[U storageType](a *Alphabet, s string) (U, error) {
+	if len(s) > MaxLen[U](a) {
+		return 0, fmt.Errorf("string %q longer than %d characters", s, MaxLen[U](a))
+	}
+	var container U
+	for i := range len(s) {
+		code := a.codes[s[i]]
+		if code == 0 {
+			return 0, fmt.Errorf("character %q at position %d not in alphabet", s[i], i)
+		}
+		container = charField[U](a, i).Update(container, code)
+	}
+	return container, nil
+}
+
+// UnpackString returns the string packed in a container by PackString.
+// Unpacking stops at the first unused position.
+// Returns an error if a position holds a code outside the alphabet.
+func UnpackString[U storageType](a *Alphabet, container U) (string, error) {
+	buf := make([]byte, 0, MaxLen[U](a))
+	for i := range MaxLen[U](a) {
+		code := charField[U](a, i).Decode(container)
+		if code == 0 {
+			break
+		}
+		if int(code) > len(a.chars) {
+			return "", fmt.Errorf("invalid character code %d at position %d", code, i)
+		}
+		buf = append(buf, a.chars[code-1])
+	}
+	return string(buf), nil
+}
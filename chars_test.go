@@ -0,0 +1,72 @@
+package bitfield
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestPackString(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *Alphabet
+		s    string
+		want uint64
+	}{
+		{"empty", Upper5, "", 0},
+		{"one letter", Upper5, "A", 0x0080000000000000},
+		{"ticker", Upper5, "IBM", 0x0489A00000000000},
+		{"full", Upper5, "ZZZZZZZZZZZZ", 0x0D6B5AD6B5AD6B5A},
+		{"alnum", Alnum6, "X1", 0x0882000000000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PackString[uint64](tt.a, tt.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("PackString(%q) = 0x%016X, want 0x%016X", tt.s, got, tt.want)
+			}
+			if back, err := UnpackString(tt.a, got); err != nil || back != tt.s {
+				t.Errorf("UnpackString(0x%016X) = %q, %v, want %q", got, back, err, tt.s)
+			}
+		})
+	}
+}
+
+func TestPackString_Errors(t *testing.T) {
+	if _, err := PackString[uint64](Upper5, "ABCDEFGHIJKLM"); err == nil {
+		t.Error("PackString of 13 characters into 12 succeeded, want error")
+	}
+	if _, err := PackString[uint32](Alnum6, "ab"); err == nil {
+		t.Error("PackString of lower case into Alnum6 succeeded, want error")
+	}
+	if _, err := UnpackString[uint32](Upper5, 0x3F000000); err == nil {
+		t.Error("UnpackString of code 31 in Upper5 succeeded, want error")
+	}
+	for _, chars := range []string{"", "ABCA"} {
+		if _, err := NewAlphabet(chars); err == nil {
+			t.Errorf("NewAlphabet(%q) succeeded, want error", chars)
+		}
+	}
+}
+
+func TestPackString_Order(t *testing.T) {
+	words := []string{"MSFT", "A", "AAPL", "AA", "GOOG", "Z", "IBM"}
+	packed := make([]uint64, len(words))
+	for i, w := range words {
+		packed[i], _ = PackString[uint64](Upper5, w)
+	}
+	slices.Sort(packed)
+	slices.SortFunc(words, cmp.Compare[string])
+	for i, p := range packed {
+		if s, _ := UnpackString(Upper5, p); s != words[i] {
+			t.Errorf("sorted packed[%d] = %q, want %q", i, s, words[i])
+		}
+	}
+	if MaxLen[uint32](Alnum6) != 5 || Alnum6.Width() != 6 {
+		t.Errorf("Alnum6 = width %d, MaxLen[uint32] %d, want 6, 5", Alnum6.Width(), MaxLen[uint32](Alnum6))
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/lnear-dev/bitfield"
+	"github.com/lnear-dev/bitfield/internal/spec"
+)
+
+// decoder decodes values with a layout and writes them in one output format.
+type decoder struct {
+	spec   *spec.Spec
+	layout *bitfield.Layout[uint64]
+	format string
+	w      io.Writer
+	csv    *csv.Writer // Set once the CSV header has been written
+}
+
+// newDecoder returns a decoder for the spec writing to w.
+// Returns an error if format is not table, json or csv.
+func newDecoder(s *spec.Spec, format string, w io.Writer) (*decoder, error) {
+	switch format {
+	case "table", "json", "csv":
+	default:
+		return nil, fmt.Errorf("unknown format %q, want table, json or csv", format)
+	}
+	layout, err := s.Layout()
+	if err != nil {
+		return nil, err
+	}
+	return &decoder{spec: s, layout: layout, format: format, w: w}, nil
+}
+
+// decodeAll decodes every whitespace-separated value read from r.
+func (d *decoder) decodeAll(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		if err := d.decode(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// decode parses a single value and writes its fields.
+func (d *decoder) decode(s string) error {
+	value, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value %q", s)
+	}
+	if width := d.spec.Width(); width < 64 && value>>width != 0 {
+		return fmt.Errorf("value %s exceeds %s", s, d.spec.Container)
+	}
+	switch d.format {
+	case "json":
+		return d.writeJSON(value)
+	case "csv":
+		return d.writeCSV(value)
+	}
+	return d.writeTable(value)
+}
+
+// hex formats value with as many digits as the container has nibbles.
+func (d *decoder) hex(value uint64) string {
+	return fmt.Sprintf("0x%0*X", d.spec.Width()/4, value)
+}
+
+// writeTable writes value followed by a table of its fields.
+func (d *decoder) writeTable(value uint64) error {
+	fmt.Fprintln(d.w, d.hex(value))
+	tw := tabwriter.NewWriter(d.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "  FIELD\tBITS\tVALUE\tHEX")
+	for _, f := range d.spec.Fields {
+		bits := strconv.FormatUint(uint64(f.Shift), 10)
+		if f.Size > 1 {
+			bits = fmt.Sprintf("%d:%d", f.Shift+f.Size-1, f.Shift)
+		}
+		bf, _ := d.layout.Field(f.Name)
+		v := bf.Decode(value)
+		fmt.Fprintf(tw, "  %s\t%s\t%d\t%#x\n", f.Name, bits, v, v)
+	}
+	return tw.Flush()
+}
+
+// writeJSON writes value as one line of JSON holding the value and its fields.
+func (d *decoder) writeJSON(value uint64) error {
+	line, err := json.Marshal(struct {
+		Value  string                  `json:"value"`
+		Fields bitfield.Packed[uint64] `json:"fields"`
+	}{d.hex(value), d.layout.Bind(value)})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(d.w, "%s\n", line)
+	return err
+}
+
+// writeCSV writes value as a CSV record, preceded by a header on the first call.
+func (d *decoder) writeCSV(value uint64) error {
+	if d.csv == nil {
+		d.csv = csv.NewWriter(d.w)
+		if err := d.csv.Write(append([]string{"value"}, d.layout.Names()...)); err != nil {
+			return err
+		}
+	}
+	record := []string{d.hex(value)}
+	for _, name := range d.layout.Names() {
+		bf, _ := d.layout.Field(name)
+		record = append(record, strconv.FormatUint(bf.Decode(value), 10))
+	}
+	return d.csv.Write(record)
+}
+
+// flush writes any buffered output.
+func (d *decoder) flush() error {
+	if d.csv == nil {
+		return nil
+	}
+	d.csv.Flush()
+	return d.csv.Error()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		values []string
+		stdin  string
+		want   string
+	}{
+		{
+			name:   "table",
+			format: "table",
+			values: []string{"0x2A57"},
+			want: "0x00002A57\n" +
+				"  FIELD       BITS   VALUE  HEX\n" +
+				"  active      0      1      0x1\n" +
+				"  priority    3:1    3      0x3\n" +
+				"  error_code  15:8   42     0x2a\n" +
+				"  data        31:16  0      0x0\n",
+		},
+		{
+			name:   "json",
+			format: "json",
+			values: []string{"0x2A57", "0b1"},
+			want: `{"value":"0x00002A57","fields":{"active":true,"priority":3,"error_code":42,"data":0}}` + "\n" +
+				`{"value":"0x00000001","fields":{"active":true,"priority":0,"error_code":0,"data":0}}` + "\n",
+		},
+		{
+			name:   "csv from stdin",
+			format: "csv",
+			stdin:  "0x2A57\n0xFFFF_0000 10\n",
+			want: "value,active,priority,error_code,data\n" +
+				"0x00002A57,1,3,42,0\n" +
+				"0xFFFF0000,0,0,0,65535\n" +
+				"0x0000000A,0,5,0,0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := run("testdata/control.bf", tt.format, tt.values, strings.NewReader(tt.stdin), &out); err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		format string
+		values []string
+	}{
+		{"missing spec", "testdata/missing.bf", "table", []string{"1"}},
+		{"unknown format", "testdata/control.bf", "xml", []string{"1"}},
+		{"invalid value", "testdata/control.bf", "table", []string{"0xZZ"}},
+		{"value too large", "testdata/control.bf", "table", []string{"0x1_0000_0000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := run(tt.path, tt.format, tt.values, strings.NewReader(""), &out); err == nil {
+				t.Errorf("run(%q, %q, %q) succeeded, want error", tt.path, tt.format, tt.values)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Bitfield decodes packed values into named fields.
+//
+// It reads a layout spec (see the internal/spec package for the format) and
+// decodes each value given on the command line, or read from standard input if
+// there are none, into the fields of the layout. Values may be written in
+// decimal or with a 0x, 0o or 0b prefix, and may contain underscores.
+//
+// Usage:
+//
+//	bitfield [-format table|json|csv] spec [value ...]
+//
+// For example:
+//
+//	$ bitfield control.bf 0x2A57
+//	0x00002A57
+//	  FIELD       BITS   VALUE  HEX
+//	  active      0      1      0x1
+//	  priority    3:1    3      0x3
+//	  error_code  15:8   42     0x2a
+//	  data        31:16  0      0x0
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lnear-dev/bitfield/internal/spec"
+)
+
+func main() {
+	format := flag.String("format", "table", "output format: table, json or csv")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: bitfield [flags] spec [value ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *format, flag.Args()[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "bitfield: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run decodes values, or the values read from stdin if there are none,
+// with the spec file at path and writes them to w in the given format.
+func run(path, format string, values []string, stdin io.Reader, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s, err := spec.Parse(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	d, err := newDecoder(s, format, w)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		err = d.decodeAll(stdin)
+	}
+	for _, v := range values {
+		if err = d.decode(v); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return d.flush()
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lnear-dev/bitfield/internal/spec"
+)
+
+// genField is the template view of a single spec field.
+type genField struct {
+	Name      string // Exported Go name, e.g. ErrorCode
+	Source    string // Name as written in the spec, e.g. error_code
+	Shift     uint
+	Size      uint
+	Mask      string // Hex literal of the field mask
+	Max       string // Hex literal of the largest field value
+	ValueType string // Smallest unsigned type that holds the field
+	NeedCheck bool   // Whether setters must range check their argument
+}
+
+// genData is the template view of a whole spec.
+type genData struct {
+	Package string
+	Source  string
+	Type    string
+	Base    string
+	Digits  int // Number of hex digits used for masks
+	Fields  []genField
+}
+
+// newGenData converts a parsed spec into template data.
+func newGenData(s *spec.Spec, pkg, source string) (*genData, error) {
+	if !token.IsIdentifier(s.Type) || !token.IsExported(s.Type) {
+		return nil, fmt.Errorf("type name %q must be an exported identifier", s.Type)
+	}
+	d := &genData{
+		Package: pkg,
+		Source:  source,
+		Type:    s.Type,
+		Base:    s.Container,
+		Digits:  int(s.Width() / 4),
+	}
+	seen := make(map[string]string)
+	for _, f := range s.Fields {
+		name := exportedName(f.Name)
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return nil, fmt.Errorf("line %d: field name %q does not yield an exported identifier", f.Line, f.Name)
+		}
+		if prev, ok := seen[name]; ok {
+			return nil, fmt.Errorf("line %d: field %q collides with %q", f.Line, f.Name, prev)
+		}
+		seen[name] = f.Name
+		valueType := valueTypeFor(f.Size)
+		max := uint64(1)<<f.Size - 1
+		if f.Size == 64 {
+			max = ^uint64(0)
+		}
+		d.Fields = append(d.Fields, genField{
+			Name:      name,
+			Source:    f.Name,
+			Shift:     f.Shift,
+			Size:      f.Size,
+			Mask:      fmt.Sprintf("0x%0*X", d.Digits, max<<f.Shift),
+			Max:       fmt.Sprintf("0x%X", max),
+			ValueType: valueType,
+			NeedCheck: f.Size != typeBits(valueType),
+		})
+	}
+	return d, nil
+}
+
+// exportedName converts a spec field name such as "error_code" into "ErrorCode".
+// The first letter of every part is upper-cased as a rune, so names starting
+// with a multi-byte letter such as "état" stay valid UTF-8.
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		r, size := utf8.DecodeRuneInString(part)
+		b.WriteRune(unicode.ToUpper(r))
+		b.WriteString(part[size:])
+	}
+	return b.String()
+}
+
+// valueTypeFor returns the smallest unsigned Go type that can hold size bits.
+func valueTypeFor(size uint) string {
+	switch {
+	case size <= 8:
+		return "uint8"
+	case size <= 16:
+		return "uint16"
+	case size <= 32:
+		return "uint32"
+	}
+	return "uint64"
+}
+
+// typeBits returns the width of one of the types returned by valueTypeFor.
+func typeBits(t string) uint {
+	switch t {
+	case "uint8":
+		return 8
+	case "uint16":
+		return 16
+	case "uint32":
+		return 32
+	}
+	return 64
+}
+
+// generate renders tmpl with d and formats the result as Go source.
+func generate(tmpl *template.Template, d *genData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+var codeTemplate = template.Must(template.New("code").Parse(`// Code generated by bitfieldgen from {{.Source}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import "fmt"
+
+// {{.Type}} is a packed {{.Base}} container.
+type {{.Type}} {{.Base}}
+
+// Bit positions, sizes and masks of the fields of {{.Type}}.
+const (
+{{- range .Fields}}
+	{{$.Type}}{{.Name}}Shift = {{.Shift}}
+	{{$.Type}}{{.Name}}Size  = {{.Size}}
+	{{$.Type}}{{.Name}}Mask  {{$.Type}} = {{.Mask}}
+{{- end}}
+)
+{{range .Fields}}
+// Get{{.Name}} returns the value of the {{.Source}} field.
+func (c {{$.Type}}) Get{{.Name}}() {{.ValueType}} {
+	return {{.ValueType}}((c & {{$.Type}}{{.Name}}Mask) >> {{$.Type}}{{.Name}}Shift)
+}
+
+// Set{{.Name}} sets the {{.Source}} field to v.
+{{- if .NeedCheck}}
+// Panics if v does not fit in the field.
+{{- end}}
+func (c *{{$.Type}}) Set{{.Name}}(v {{.ValueType}}) {
+{{- if .NeedCheck}}
+	if v > {{.Max}} {
+		panic(fmt.Sprintf("value %v out of range, max %v", v, {{.Max}}))
+	}
+{{- end}}
+	*c = (*c &^ {{$.Type}}{{.Name}}Mask) | {{$.Type}}(v)<<{{$.Type}}{{.Name}}Shift
+}
+{{end}}
+// String returns the field values of c.
+func (c {{$.Type}}) String() string {
+	return fmt.Sprintf("{{range $i, $f := .Fields}}{{if $i}} {{end}}{{$f.Source}}=%d{{end}}"{{range .Fields}}, c.Get{{.Name}}(){{end}})
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`// Code generated by bitfieldgen from {{.Source}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import "testing"
+{{range .Fields}}
+func Test{{$.Type}}_{{.Name}}(t *testing.T) {
+	var c {{$.Type}}
+	c.Set{{.Name}}({{.Max}})
+	if got := c.Get{{.Name}}(); got != {{.Max}} {
+		t.Errorf("Get{{.Name}}() = %v, want %v", got, {{.Max}})
+	}
+	if c != {{$.Type}}{{.Name}}Mask {
+		t.Errorf("container = %#x, want %#x", c, {{$.Type}}{{.Name}}Mask)
+	}
+
+	c = ^{{$.Type}}(0)
+	c.Set{{.Name}}(0)
+	if c != ^{{$.Type}}{{.Name}}Mask {
+		t.Errorf("container = %#x, want %#x", c, ^{{$.Type}}{{.Name}}Mask)
+	}
+}
+{{end}}`))
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield/internal/spec"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerate_Golden(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "control_bitfield.go")
+	if err := run("testdata/control.bf", "regs", out, true); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for _, name := range []string{"control_bitfield.go", "control_bitfield_test.go"} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		golden := filepath.Join("testdata", name+".golden")
+		if *update {
+			if err := os.WriteFile(golden, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s differs from %s, run with -update to regenerate", name, golden)
+		}
+	}
+}
+
+func TestGenerate_CompilesAndPasses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go test of generated code in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not available")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module regs\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run("testdata/control.bf", "regs", filepath.Join(dir, "control_bitfield.go"), true); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	cmd := exec.Command(goTool, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test of generated code failed: %v\n%s", err, out)
+	}
+}
+
+func TestNewGenData_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unexported type", "type control uint32\nfield a 0 1"},
+		{"invalid field name", "type Control uint32\nfield 1a 0 1"},
+		{"field name without upper case", "type Control uint32\nfield 数 0 1"},
+		{"invalid UTF-8 field name", "type Control uint32\nfield \xffa 0 1"},
+		{"colliding names", "type Control uint32\nfield error_code 0 1\nfield errorCode 1 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := spec.Parse(strings.NewReader(tt.src))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if _, err := newGenData(s, "regs", "test.bf"); err == nil {
+				t.Errorf("newGenData(%q) succeeded, want error", tt.src)
+			}
+		})
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"active", "Active"},
+		{"error_code", "ErrorCode"},
+		{"tx-enable", "TxEnable"},
+		{"IRQ", "IRQ"},
+		{"état", "État"},
+		{"rx_ümlaut", "RxÜmlaut"},
+	}
+
+	for _, tt := range tests {
+		if got := exportedName(tt.in); got != tt.want {
+			t.Errorf("exportedName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Bitfieldgen generates typed accessors for packed containers.
+//
+// It reads a layout spec (see the internal/spec package for the format) and
+// writes a Go file declaring the container type, constants for the shift, size
+// and mask of every field, and Get/Set methods for each field. The generated
+// code does not depend on this module and has no generics overhead.
+//
+// Usage:
+//
+//	bitfieldgen [-pkg name] [-out file] [-test] spec
+//
+// It is typically invoked from a go:generate directive:
+//
+//	//go:generate go run github.com/lnear-dev/bitfield/cmd/bitfieldgen -test control.bf
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lnear-dev/bitfield/internal/spec"
+)
+
+func main() {
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name of the generated code (default $GOPACKAGE)")
+	out := flag.String("out", "", "output file (default <spec>_bitfield.go)")
+	withTest := flag.Bool("test", false, "also generate a _test.go file exercising the accessors")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: bitfieldgen [flags] spec\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *pkg, *out, *withTest); err != nil {
+		fmt.Fprintf(os.Stderr, "bitfieldgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the code for the spec file at path.
+func run(path, pkg, out string, withTest bool) error {
+	if pkg == "" {
+		return fmt.Errorf("package name not set, use -pkg")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s, err := spec.Parse(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	d, err := newGenData(s, pkg, filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if out == "" {
+		out = strings.TrimSuffix(path, filepath.Ext(path)) + "_bitfield.go"
+	}
+	code, err := generate(codeTemplate, d)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, code, 0o644); err != nil {
+		return err
+	}
+	if !withTest {
+		return nil
+	}
+	test, err := generate(testTemplate, d)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(strings.TrimSuffix(out, ".go")+"_test.go", test, 0o644)
+}
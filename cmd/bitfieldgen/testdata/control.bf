@@ -0,0 +1,6 @@
+# Control register used by the generator tests
+type Control uint32
+field active     0 1
+field priority   1 3
+field error_code 8 8
+field data       16 16
@@ -0,0 +1,786 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// layout is a register layout recovered from a header.
+type layout struct {
+	Name   string // C name: typedef name, struct tag or define prefix
+	Width  uint   // Size of the register in bits
+	Fields []field
+	Line   int
+}
+
+// field is a single field of a layout.
+type field struct {
+	Name  string
+	Shift uint
+	Size  uint
+	Line  int
+}
+
+// parseHeader extracts the register layouts of a C header: structs made up
+// only of bit fields, and groups of _SHIFT/_MASK style #define constants.
+// Constructs that look like layouts but cannot be imported are reported as
+// warnings rather than errors, since headers usually contain much else.
+func parseHeader(src string) (layouts []*layout, warnings []string, err error) {
+	defines := make(map[string]define)
+	var order []string
+	var code strings.Builder
+	lines := strings.Split(stripComments(src), "\n")
+	for i := 0; i < len(lines); i++ {
+		line, n := lines[i], i+1
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + " " + lines[i]
+			code.WriteByte('\n') // Keep line numbers of the following code
+		}
+		if d, ok := parseDefine(line, n); ok {
+			if _, dup := defines[d.name]; !dup {
+				order = append(order, d.name)
+			}
+			defines[d.name] = d
+			line = ""
+		} else if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			line = ""
+		}
+		code.WriteString(line)
+		code.WriteByte('\n')
+	}
+
+	p := &structParser{toks: tokenize(code.String()), defines: defines}
+	structs, err := p.parse()
+	if err != nil {
+		return nil, nil, err
+	}
+	layouts = append(layouts, structs...)
+	warnings = append(warnings, p.warnings...)
+
+	fromDefines, w := defineLayouts(defines, order)
+	layouts = append(layouts, fromDefines...)
+	warnings = append(warnings, w...)
+	return layouts, warnings, nil
+}
+
+// stripComments replaces the comments of src with spaces, keeping line breaks.
+func stripComments(src string) string {
+	b := []byte(src)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '"':
+			for i++; i < len(b) && b[i] != '"' && b[i] != '\n'; i++ {
+				if b[i] == '\\' {
+					i++
+				}
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				b[i] = ' '
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			for ; i < len(b) && !(b[i] == '*' && i+1 < len(b) && b[i+1] == '/'); i++ {
+				if b[i] != '\n' {
+					b[i] = ' '
+				}
+			}
+			if i < len(b) {
+				b[i] = ' '
+				b[i+1] = ' '
+				i++
+			}
+		}
+	}
+	return string(b)
+}
+
+// define is an object-like macro.
+type define struct {
+	name string
+	expr string
+	line int
+}
+
+// parseDefine parses an object-like #define. Function-like macros are ignored.
+func parseDefine(line string, n int) (define, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+	if !ok {
+		return define{}, false
+	}
+	rest, ok = strings.CutPrefix(strings.TrimSpace(rest), "define")
+	if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+		return define{}, false
+	}
+	rest = strings.TrimSpace(rest)
+	end := 0
+	for end < len(rest) && isIdentByte(rest[end], end > 0) {
+		end++
+	}
+	if end == 0 || end < len(rest) && rest[end] == '(' {
+		return define{}, false
+	}
+	return define{name: rest[:end], expr: strings.TrimSpace(rest[end:]), line: n}, true
+}
+
+func isIdentByte(c byte, digitOK bool) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || digitOK && '0' <= c && c <= '9'
+}
+
+// tok is a token of C source.
+type tok struct {
+	text string
+	line int
+}
+
+// tokenize splits C source into identifiers, numbers and single-character punctuation.
+func tokenize(src string) []tok {
+	var toks []tok
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+		case isIdentByte(c, true):
+			j := i
+			for j < len(src) && isIdentByte(src[j], true) {
+				j++
+			}
+			toks = append(toks, tok{src[i:j], line})
+			i = j
+		case (c == '<' || c == '>') && i+1 < len(src) && src[i+1] == c:
+			toks = append(toks, tok{src[i : i+2], line})
+			i += 2
+		default:
+			toks = append(toks, tok{src[i : i+1], line})
+			i++
+		}
+	}
+	return toks
+}
+
+// structParser finds bit-field structs in a token stream.
+type structParser struct {
+	toks     []tok
+	pos      int
+	defines  map[string]define
+	warnings []string
+}
+
+func (p *structParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos].text
+	}
+	return ""
+}
+
+func (p *structParser) line() int {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos].line
+	}
+	if len(p.toks) > 0 {
+		return p.toks[len(p.toks)-1].line
+	}
+	return 1
+}
+
+func (p *structParser) warnf(line int, format string, args ...any) {
+	p.warnings = append(p.warnings, fmt.Sprintf("line %d: ", line)+fmt.Sprintf(format, args...))
+}
+
+// parse scans the top level for struct and union definitions.
+func (p *structParser) parse() ([]*layout, error) {
+	var layouts []*layout
+	for p.pos < len(p.toks) {
+		switch p.peek() {
+		case "typedef":
+			p.pos++
+			if kw := p.peek(); kw == "struct" || kw == "union" {
+				l, err := p.parseAggregate()
+				if err != nil {
+					return nil, err
+				}
+				name := p.typedefName()
+				if l != nil {
+					if name != "" {
+						l.Name = name
+					}
+					layouts = p.add(layouts, l)
+				}
+			}
+		case "struct", "union":
+			if p.pos+2 < len(p.toks) && p.toks[p.pos+2].text != "{" && p.toks[p.pos+1].text != "{" {
+				p.pos += 2 // Reference to a type, not a definition
+				continue
+			}
+			l, err := p.parseAggregate()
+			if err != nil {
+				return nil, err
+			}
+			if l != nil {
+				layouts = p.add(layouts, l)
+			}
+		default:
+			p.pos++
+		}
+	}
+	return layouts, nil
+}
+
+// add appends l to layouts unless it has no name.
+func (p *structParser) add(layouts []*layout, l *layout) []*layout {
+	if l.Name == "" {
+		p.warnf(l.Line, "skipping anonymous bit-field struct")
+		return layouts
+	}
+	return append(layouts, l)
+}
+
+// typedefName returns the first declarator of a typedef and skips to its end.
+func (p *structParser) typedefName() string {
+	name := ""
+	for p.pos < len(p.toks) && p.peek() != ";" {
+		if t := p.peek(); name == "" && isIdentByte(t[0], false) {
+			name = t
+		}
+		p.pos++
+	}
+	p.pos++
+	return name
+}
+
+// parseAggregate parses a struct or union definition starting at its keyword.
+// It returns the bit-field layout it describes, or nil if it is not a register layout.
+func (p *structParser) parseAggregate() (*layout, error) {
+	kw := p.toks[p.pos]
+	p.pos++
+	tag := ""
+	if t := p.peek(); t != "{" && t != "" {
+		tag = t
+		p.pos++
+	}
+	if p.peek() != "{" {
+		return nil, nil // Forward declaration or use as a type
+	}
+	p.pos++
+
+	var (
+		members []member
+		nested  []*layout
+		plain   bool // Whether there are members other than bit fields
+	)
+	for p.peek() != "}" {
+		if p.pos >= len(p.toks) {
+			return nil, fmt.Errorf("line %d: unterminated %s", kw.line, kw.text)
+		}
+		if t := p.peek(); t == "struct" || t == "union" {
+			l, err := p.parseAggregate()
+			if err != nil {
+				return nil, err
+			}
+			if l != nil {
+				nested = append(nested, l)
+			} else {
+				plain = true
+			}
+			p.skipDeclarators()
+			continue
+		}
+		ms, err := p.parseMembers()
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range ms {
+			if m.width < 0 {
+				plain = true
+			} else {
+				members = append(members, m)
+			}
+		}
+	}
+	p.pos++
+
+	if kw.text == "union" {
+		// A union of a bit-field struct and a raw word is the usual way to
+		// give register structs a whole-value view.
+		if len(nested) != 1 || len(members) > 0 {
+			return nil, nil
+		}
+		nested[0].Name, nested[0].Line = tag, kw.line
+		return nested[0], nil
+	}
+	if plain || len(nested) > 0 || len(members) == 0 {
+		return nil, nil
+	}
+	return p.allocate(tag, kw.line, members)
+}
+
+// skipDeclarators skips the declarators of a nested aggregate up to the ';'.
+func (p *structParser) skipDeclarators() {
+	for p.pos < len(p.toks) && p.peek() != ";" {
+		p.pos++
+	}
+	p.pos++
+}
+
+// member is a struct member. width is -1 for members that are not bit fields.
+type member struct {
+	typ   []string
+	name  string
+	width int
+	line  int
+}
+
+// parseMembers parses a member declaration up to and including its ';'.
+// A declaration may declare several bit fields separated by commas.
+func (p *structParser) parseMembers() ([]member, error) {
+	line := p.line()
+	var (
+		typ     []string
+		members []member
+	)
+	for first := true; ; first = false {
+		var decl []string
+		for t := p.peek(); t != ";" && t != ":" && t != ","; t = p.peek() {
+			if t == "" || t == "}" {
+				return nil, fmt.Errorf("line %d: missing ';'", line)
+			}
+			decl = append(decl, t)
+			p.pos++
+		}
+		if p.peek() != ":" {
+			for p.peek() != ";" && p.peek() != "" {
+				p.pos++
+			}
+			p.pos++
+			return []member{{line: line, width: -1}}, nil
+		}
+		p.pos++
+		m := member{line: p.line()}
+		var expr []string
+		for t := p.peek(); t != ";" && t != ","; t = p.peek() {
+			if t == "" || t == "}" {
+				return nil, fmt.Errorf("line %d: missing ';'", line)
+			}
+			expr = append(expr, t)
+			p.pos++
+		}
+		width, err := evalExpr(strings.Join(expr, " "), p.defines)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bit-field width: %w", m.line, err)
+		}
+		m.width = int(width)
+		if first {
+			typ = decl
+			if n := len(decl); n > 1 {
+				if _, ok := typeWidth(decl[n-1:]); !ok {
+					m.name, typ = decl[n-1], decl[:n-1]
+				}
+			}
+		} else if len(decl) == 1 {
+			m.name = decl[0]
+		} else if len(decl) > 1 {
+			return nil, fmt.Errorf("line %d: invalid declarator %q", m.line, strings.Join(decl, " "))
+		}
+		m.typ = typ
+		members = append(members, m)
+		if p.peek() == ";" {
+			p.pos++
+			return members, nil
+		}
+		p.pos++
+	}
+}
+
+// allocate assigns bit positions to the members of a bit-field struct following
+// the System V ABI used by GCC and Clang on little-endian targets: fields are
+// packed from the least significant bit, and a field that would straddle a
+// storage unit of its declared type starts a new unit.
+func (p *structParser) allocate(name string, line int, members []member) (*layout, error) {
+	l := &layout{Name: name, Line: line}
+	var pos, align uint
+	for _, m := range members {
+		unit, ok := typeWidth(m.typ)
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown bit-field type %q", m.line, strings.Join(m.typ, " "))
+		}
+		w := uint(m.width)
+		if w > unit {
+			return nil, fmt.Errorf("line %d: width %d of %q exceeds its type", m.line, w, m.name)
+		}
+		align = max(align, unit)
+		if w == 0 || pos/unit != (pos+w-1)/unit {
+			pos = (pos + unit - 1) / unit * unit
+		}
+		if m.name != "" {
+			if isSigned(m.typ) {
+				p.warnf(m.line, "signed field %s imported as unsigned", m.name)
+			}
+			l.Fields = append(l.Fields, field{Name: m.name, Shift: pos, Size: w, Line: m.line})
+		}
+		pos += w
+	}
+	l.Width = (pos + align - 1) / align * align
+	if l.Width > 64 {
+		return nil, fmt.Errorf("line %d: %s is %d bits wide, more than a 64-bit register", line, name, l.Width)
+	}
+	return l, nil
+}
+
+// typeWidths maps the integer types allowed in bit fields to their size in bits.
+// long is taken to be 64 bits wide, as on LP64 targets.
+var typeWidths = map[string]uint{
+	"char": 8, "short": 16, "int": 32, "long": 64, "long long": 64,
+	"bool": 8, "_Bool": 8,
+	"uint8_t": 8, "uint16_t": 16, "uint32_t": 32, "uint64_t": 64,
+	"int8_t": 8, "int16_t": 16, "int32_t": 32, "int64_t": 64,
+	"u8": 8, "u16": 16, "u32": 32, "u64": 64,
+	"__u8": 8, "__u16": 16, "__u32": 32, "__u64": 64,
+}
+
+// typeWidth returns the width of a bit-field type, ignoring qualifiers.
+func typeWidth(typ []string) (uint, bool) {
+	var words []string
+	for _, w := range typ {
+		switch w {
+		case "const", "volatile", "signed", "unsigned", "__IO", "__I", "__O", "__IM", "__OM", "__IOM":
+		default:
+			words = append(words, w)
+		}
+	}
+	switch {
+	case len(words) == 0 && slices.ContainsFunc(typ, func(w string) bool { return w == "signed" || w == "unsigned" }):
+		return 32, true
+	case len(words) == 2 && words[1] == "int" && words[0] != "int":
+		words = words[:1] // short int, long int
+	case len(words) == 3 && words[0] == "long" && words[1] == "long" && words[2] == "int":
+		words = words[:2]
+	}
+	n, ok := typeWidths[strings.Join(words, " ")]
+	return n, ok
+}
+
+// isSigned reports whether a bit-field type is signed. Plain int bit fields
+// are signed with GCC and Clang.
+func isSigned(typ []string) bool {
+	if slices.Contains(typ, "unsigned") {
+		return false
+	}
+	for _, w := range typ {
+		if strings.HasPrefix(w, "int") || w == "signed" || w == "short" || w == "long" {
+			return true
+		}
+	}
+	return false
+}
+
+// Suffixes of the #define constants that describe fields.
+var (
+	shiftSuffixes = []string{"_SHIFT", "_Pos", "_POS"}
+	maskSuffixes  = []string{"_MASK", "_Msk", "_MSK"}
+	widthSuffixes = []string{"_WIDTH"}
+)
+
+// defineLayouts groups _SHIFT, _MASK and _WIDTH constants into layouts.
+// The name of a constant without its suffix is split at the last underscore
+// into the layout and field names, so UART_CR_EN_SHIFT describes field EN of UART_CR.
+// Constants that do not describe a field, such as masks with gaps, are skipped
+// with a warning.
+func defineLayouts(defines map[string]define, order []string) ([]*layout, []string) {
+	stems := make(map[string]*parts)
+	var stemOrder []string
+	for _, name := range order {
+		d := defines[name]
+		for _, group := range []struct {
+			suffixes []string
+			slot     func(*parts) **uint64
+		}{
+			{shiftSuffixes, func(p *parts) **uint64 { return &p.shift }},
+			{maskSuffixes, func(p *parts) **uint64 { return &p.mask }},
+			{widthSuffixes, func(p *parts) **uint64 { return &p.width }},
+		} {
+			for _, suffix := range group.suffixes {
+				stem, ok := strings.CutSuffix(name, suffix)
+				if !ok || !strings.Contains(stem, "_") {
+					continue
+				}
+				p, ok := stems[stem]
+				if !ok {
+					p = &parts{line: d.line}
+					stems[stem] = p
+					stemOrder = append(stemOrder, stem)
+				}
+				v, err := evalExpr(d.expr, defines)
+				if err != nil {
+					p.err = fmt.Errorf("%s: %w", name, err)
+					continue
+				}
+				*group.slot(p) = &v
+			}
+		}
+	}
+
+	var layouts []*layout
+	var warnings []string
+	byName := make(map[string]*layout)
+	for _, stem := range stemOrder {
+		p := stems[stem]
+		shift, size, err := p.field()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("line %d: skipping %s: %v", p.line, stem, err))
+			continue
+		}
+		i := strings.LastIndexByte(stem, '_')
+		name, fieldName := stem[:i], stem[i+1:]
+		l, ok := byName[name]
+		if !ok {
+			l = &layout{Name: name, Width: 32, Line: p.line}
+			byName[name] = l
+			layouts = append(layouts, l)
+		}
+		if shift+size > 32 {
+			l.Width = 64
+		}
+		l.Fields = append(l.Fields, field{Name: fieldName, Shift: shift, Size: size, Line: p.line})
+	}
+	return layouts, warnings
+}
+
+// parts holds the constants defined for one field.
+type parts struct {
+	shift, mask, width *uint64
+	line               int
+	err                error // Error evaluating one of the constants
+}
+
+// field returns the position of the field described by the constants.
+// Masks may be given in place or relative to the shift.
+func (p *parts) field() (shift, size uint, err error) {
+	if p.err != nil {
+		return 0, 0, p.err
+	}
+	switch {
+	case p.mask != nil:
+		m := *p.mask
+		if m == 0 {
+			return 0, 0, fmt.Errorf("mask is 0")
+		}
+		if p.shift != nil && uint64(bits.TrailingZeros64(m)) < *p.shift {
+			m <<= *p.shift
+		}
+		shift = uint(bits.TrailingZeros64(m))
+		if v := m >> shift; v&(v+1) != 0 {
+			return 0, 0, fmt.Errorf("mask %#x is not contiguous", m)
+		}
+		size = uint(bits.OnesCount64(m))
+		if p.shift != nil && *p.shift != uint64(shift) {
+			return 0, 0, fmt.Errorf("shift %d does not match mask %#x", *p.shift, m)
+		}
+	case p.shift != nil && p.width != nil:
+		if *p.width == 0 || *p.shift+*p.width > 64 {
+			return 0, 0, fmt.Errorf("field exceeds 64 bits")
+		}
+		shift, size = uint(*p.shift), uint(*p.width)
+	default:
+		return 0, 0, fmt.Errorf("no mask or width")
+	}
+	return shift, size, nil
+}
+
+// evalExpr evaluates an integer constant expression of a macro, as used in
+// shift and mask definitions. It supports literals with U and L suffixes,
+// references to other macros, casts to integer types, the unary operators
+// - and ~, the binary operators * / + - << >> & ^ |, and the BIT and
+// GENMASK macros of the Linux kernel.
+func evalExpr(expr string, defines map[string]define) (uint64, error) {
+	e := &evaluator{toks: tokenize(expr), defines: defines}
+	v, err := e.binary(0)
+	if err != nil {
+		return 0, err
+	}
+	if e.pos != len(e.toks) {
+		return 0, fmt.Errorf("unexpected %q in %q", e.toks[e.pos].text, expr)
+	}
+	return v, nil
+}
+
+// evaluator is a precedence-climbing evaluator over macro tokens.
+type evaluator struct {
+	toks    []tok
+	pos     int
+	defines map[string]define
+	depth   int
+}
+
+// precedence gives the binding strength of the binary operators.
+var precedence = map[string]int{"|": 1, "^": 2, "&": 3, "<<": 4, ">>": 4, "+": 5, "-": 5, "*": 6, "/": 6}
+
+func (e *evaluator) peek() string {
+	if e.pos < len(e.toks) {
+		return e.toks[e.pos].text
+	}
+	return ""
+}
+
+func (e *evaluator) expect(s string) error {
+	if e.peek() != s {
+		return fmt.Errorf("expected %q, found %q", s, e.peek())
+	}
+	e.pos++
+	return nil
+}
+
+func (e *evaluator) binary(minPrec int) (uint64, error) {
+	lhs, err := e.unary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := e.peek()
+		prec, ok := precedence[op]
+		if !ok || prec <= minPrec {
+			return lhs, nil
+		}
+		e.pos++
+		rhs, err := e.binary(prec)
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "|":
+			lhs |= rhs
+		case "^":
+			lhs ^= rhs
+		case "&":
+			lhs &= rhs
+		case "<<":
+			lhs <<= rhs
+		case ">>":
+			lhs >>= rhs
+		case "+":
+			lhs += rhs
+		case "-":
+			lhs -= rhs
+		case "*":
+			lhs *= rhs
+		case "/":
+			if rhs == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			lhs /= rhs
+		}
+	}
+}
+
+func (e *evaluator) unary() (uint64, error) {
+	t := e.peek()
+	e.pos++
+	switch {
+	case t == "":
+		return 0, fmt.Errorf("unexpected end of expression")
+	case t == "~":
+		v, err := e.unary()
+		return ^v, err
+	case t == "-":
+		v, err := e.unary()
+		return -v, err
+	case t == "(":
+		if _, ok := typeWidth([]string{e.peek()}); ok || e.peek() == "unsigned" {
+			for e.peek() != ")" && e.peek() != "" {
+				e.pos++ // Cast to an integer type
+			}
+			if err := e.expect(")"); err != nil {
+				return 0, err
+			}
+			return e.unary()
+		}
+		v, err := e.binary(0)
+		if err != nil {
+			return 0, err
+		}
+		return v, e.expect(")")
+	case t[0] >= '0' && t[0] <= '9':
+		return parseLiteral(t)
+	case t == "BIT" || t == "BIT_ULL" || t == "GENMASK" || t == "GENMASK_ULL":
+		args, err := e.args()
+		if err != nil {
+			return 0, err
+		}
+		if t == "BIT" || t == "BIT_ULL" {
+			if len(args) != 1 || args[0] > 63 {
+				return 0, fmt.Errorf("invalid %s arguments", t)
+			}
+			return 1 << args[0], nil
+		}
+		if len(args) != 2 || args[0] > 63 || args[1] > args[0] {
+			return 0, fmt.Errorf("invalid %s arguments", t)
+		}
+		hi, lo := args[0], args[1]
+		return (^uint64(0) >> (63 - hi)) &^ (1<<lo - 1), nil
+	case isIdentByte(t[0], false):
+		d, ok := e.defines[t]
+		if !ok {
+			return 0, fmt.Errorf("undefined macro %s", t)
+		}
+		if e.depth > 32 {
+			return 0, fmt.Errorf("macro %s nests too deeply", t)
+		}
+		sub := &evaluator{toks: tokenize(d.expr), defines: e.defines, depth: e.depth + 1}
+		v, err := sub.binary(0)
+		if err == nil && sub.pos != len(sub.toks) {
+			err = fmt.Errorf("unexpected %q in %s", sub.toks[sub.pos].text, t)
+		}
+		return v, err
+	}
+	return 0, fmt.Errorf("unexpected %q", t)
+}
+
+// args parses the parenthesized argument list of a macro call.
+func (e *evaluator) args() ([]uint64, error) {
+	if err := e.expect("("); err != nil {
+		return nil, err
+	}
+	var args []uint64
+	for {
+		v, err := e.binary(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+		if e.peek() != "," {
+			break
+		}
+		e.pos++
+	}
+	return args, e.expect(")")
+}
+
+// parseLiteral parses a C integer literal with optional U and L suffixes.
+func parseLiteral(s string) (uint64, error) {
+	lit := strings.TrimRight(s, "uUlL")
+	var (
+		v   uint64
+		err error
+	)
+	switch {
+	case strings.HasPrefix(lit, "0x"), strings.HasPrefix(lit, "0X"):
+		v, err = strconv.ParseUint(lit[2:], 16, 64)
+	case strings.HasPrefix(lit, "0b"), strings.HasPrefix(lit, "0B"):
+		v, err = strconv.ParseUint(lit[2:], 2, 64)
+	case len(lit) > 1 && lit[0] == '0':
+		v, err = strconv.ParseUint(lit[1:], 8, 64)
+	default:
+		v, err = strconv.ParseUint(lit, 10, 64)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer literal %q", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	src, err := os.ReadFile("testdata/regs.h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layouts, warnings, err := parseHeader(string(src))
+	if err != nil {
+		t.Fatalf("parseHeader: %v", err)
+	}
+
+	want := []struct {
+		name   string
+		width  uint
+		fields []field
+	}{
+		{"uart_ctrl_t", 32, []field{{"enable", 0, 1, 14}, {"mode", 1, 2, 15}, {"baud_div", 8, 12, 17}}},
+		{"uart_status", 32, []field{{"rx_ready", 0, 1, 24}, {"tx_empty", 1, 1, 25}, {"level", 2, 4, 26}, {"parity_err", 6, 1, 27}}},
+		{"split_t", 16, []field{{"low", 0, 6, 32}, {"high", 8, 4, 33}}},
+		{"UART_IRQ", 32, []field{{"RXNE", 0, 1, 42}, {"LEVEL", 4, 4, 44}}},
+		{"UART_PSC", 32, []field{{"DIV", 0, 16, 48}, {"EN", 31, 1, 49}, {"MODE", 16, 3, 50}}},
+		{"UART_FIFO", 32, []field{{"THRESH", 8, 5, 55}}},
+	}
+	if len(layouts) != len(want) {
+		t.Fatalf("got %d layouts, want %d", len(layouts), len(want))
+	}
+	for i, w := range want {
+		l := layouts[i]
+		if l.Name != w.name || l.Width != w.width || !reflect.DeepEqual(l.Fields, w.fields) {
+			t.Errorf("layout %d = %s (%d bits) %v, want %s (%d bits) %v", i, l.Name, l.Width, l.Fields, w.name, w.width, w.fields)
+		}
+	}
+
+	wantWarnings := []string{
+		"line 58: skipping UART_BAD_GAP: mask 0x5 is not contiguous",
+		"line 59: skipping UART_BAD_SHIFT_ONLY: no mask or width",
+	}
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("warnings = %q, want %q", warnings, wantWarnings)
+	}
+}
+
+func TestParseHeader_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unknown type", "struct r { reg_t a : 1; };"},
+		{"width exceeds type", "struct r { uint8_t a : 9; };"},
+		{"bad width", "struct r { uint32_t a : WIDTH; };"},
+		{"too wide", "struct r { uint64_t a : 60; uint64_t b : 10; };"},
+		{"unterminated", "struct r { uint32_t a : 1;"},
+		{"missing semicolon", "struct r { uint32_t a : 1 };"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := parseHeader(tt.src); err == nil {
+				t.Errorf("parseHeader(%q) succeeded, want error", tt.src)
+			}
+		})
+	}
+}
+
+func TestParseHeader_Skipped(t *testing.T) {
+	src := `
+struct plain { int a; };
+struct { unsigned a : 1; } anonymous;
+struct uses_plain { struct plain p; };
+union both { struct { unsigned a : 1; } x; unsigned b : 1; };
+extern struct plain *p;
+`
+	layouts, warnings, err := parseHeader(src)
+	if err != nil {
+		t.Fatalf("parseHeader: %v", err)
+	}
+	if len(layouts) != 0 {
+		t.Errorf("got %d layouts, want none", len(layouts))
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "anonymous") {
+		t.Errorf("warnings = %q, want one about the anonymous struct", warnings)
+	}
+}
+
+func TestEvalExpr(t *testing.T) {
+	defines := map[string]define{
+		"POS":   {name: "POS", expr: "(4U)"},
+		"LOOP":  {name: "LOOP", expr: "LOOP + 1"},
+		"WIDTH": {name: "WIDTH", expr: "3"},
+	}
+	tests := []struct {
+		expr    string
+		want    uint64
+		wantErr bool
+	}{
+		{"0x1FUL << POS", 0x1F0, false},
+		{"((uint32_t)0x3 << 2)", 0xC, false},
+		{"(1u << WIDTH) - 1", 7, false},
+		{"~0x0F & 0xFF", 0xF0, false},
+		{"1 | 2 ^ 3 & 6", 1 | 2 ^ 3&6, false},
+		{"2 + 3 * 4", 14, false},
+		{"010", 8, false},
+		{"0b101", 5, false},
+		{"BIT(3)", 8, false},
+		{"GENMASK(7, 4)", 0xF0, false},
+		{"GENMASK_ULL(63, 62)", 0xC000000000000000, false},
+		{"UNDEFINED", 0, true},
+		{"LOOP", 0, true},
+		{"1 / 0", 0, true},
+		{"(1", 0, true},
+		{"1 2", 0, true},
+		{"GENMASK(1, 2)", 0, true},
+		{"0xZZ", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := evalExpr(tt.expr, defines)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("evalExpr(%q) = %#x, %v, want %#x, err = %v", tt.expr, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lnear-dev/bitfield/regcsv"
+)
+
+// csvLayouts imports the registers of a CSV register map.
+func csvLayouts(r io.Reader, f regcsv.Format) ([]*layout, error) {
+	m, err := regcsv.Parse(r, f)
+	if err != nil {
+		return nil, err
+	}
+	var layouts []*layout
+	for _, reg := range m.Registers {
+		if reg.Size > 64 {
+			return nil, fmt.Errorf("row %d: register %s is wider than 64 bits", reg.Row, reg.Name)
+		}
+		l := &layout{Name: reg.Name, Width: reg.Size, Line: reg.Row}
+		for _, f := range reg.Fields {
+			l.Fields = append(l.Fields, field{Name: f.Name, Shift: f.Shift, Size: f.Size, Line: f.Row})
+		}
+		layouts = append(layouts, l)
+	}
+	return layouts, nil
+}
+
+// columnsFlag is a flag.Value setting the column headers of a CSV format
+// from a list such as "register=Reg Name,offset=Address".
+type columnsFlag struct {
+	format *regcsv.Format
+}
+
+func (c columnsFlag) String() string { return "" }
+
+func (c columnsFlag) Set(s string) error {
+	for _, pair := range strings.Split(s, ",") {
+		prop, header, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid column mapping %q, want property=header", pair)
+		}
+		var dst *string
+		switch strings.ToLower(strings.TrimSpace(prop)) {
+		case "register":
+			dst = &c.format.Register
+		case "offset":
+			dst = &c.format.Offset
+		case "field":
+			dst = &c.format.Field
+		case "bits":
+			dst = &c.format.Bits
+		case "shift":
+			dst = &c.format.Shift
+		case "width":
+			dst = &c.format.Width
+		case "size":
+			dst = &c.format.Size
+		case "access":
+			dst = &c.format.Access
+		case "reset":
+			dst = &c.format.Reset
+		case "enum":
+			dst = &c.format.Enum
+		case "description":
+			dst = &c.format.Description
+		default:
+			return fmt.Errorf("unknown column property %q", prop)
+		}
+		*dst = strings.TrimSpace(header)
+	}
+	return nil
+}
+
+// commaFlag is a flag.Value setting the field delimiter of a CSV format.
+type commaFlag struct {
+	format *regcsv.Format
+}
+
+func (c commaFlag) String() string {
+	if c.format == nil || c.format.Comma == 0 {
+		return ","
+	}
+	return string(c.format.Comma)
+}
+
+func (c commaFlag) Set(s string) error {
+	if s == `\t` {
+		s = "\t"
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 || n != len(s) {
+		return fmt.Errorf("delimiter must be a single character")
+	}
+	c.format.Comma = r
+	return nil
+}
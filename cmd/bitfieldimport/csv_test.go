@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield/regcsv"
+)
+
+func TestRun_CSV(t *testing.T) {
+	format := regcsv.DefaultFormat
+	fs := flag.NewFlagSet("bitfieldimport", flag.ContinueOnError)
+	fs.Var(columnsFlag{&format}, "columns", "")
+	fs.Var(commaFlag{&format}, "comma", "")
+	if err := fs.Parse([]string{"-columns", "register=Name, offset=Address", "-comma", ";"}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "regs_bitfield.go")
+	if err := run("testdata/regs.csv", "regs", out, format, io.Discard); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	code, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Fields of CTRL, declared at regs.csv:2.",
+		"CtrlMode = bitfield.New[uint8, uint32](1, 2)",
+		"StatusLevel = bitfield.New[uint8, uint32](4, 4)",
+		"func StatusLayout() *bitfield.Layout[uint32] {",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("generated code does not contain %q", want)
+		}
+	}
+
+	err = run("testdata/regs.csv", "regs", out, regcsv.DefaultFormat, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "regs.csv: regcsv: row 1: missing column") {
+		t.Errorf("run with default format: err = %v, want missing column", err)
+	}
+}
+
+func TestColumnsFlag(t *testing.T) {
+	var f regcsv.Format
+	c := columnsFlag{&f}
+	if err := c.Set("register=Reg, field = Bit Field,DESCRIPTION=Notes"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if f.Register != "Reg" || f.Field != "Bit Field" || f.Description != "Notes" {
+		t.Errorf("format = %+v", f)
+	}
+	for _, bad := range []string{"register", "color=Colour"} {
+		if err := c.Set(bad); err == nil {
+			t.Errorf("Set(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestCommaFlag(t *testing.T) {
+	var f regcsv.Format
+	c := commaFlag{&f}
+	if got := c.String(); got != "," {
+		t.Errorf("default String() = %q, want \",\"", got)
+	}
+	if err := c.Set(`\t`); err != nil || f.Comma != '\t' {
+		t.Errorf("Set(\\t): comma = %q, err = %v", f.Comma, err)
+	}
+	for _, bad := range []string{"", ";;"} {
+		if err := c.Set(bad); err == nil {
+			t.Errorf("Set(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// genField is the template view of a single imported field.
+type genField struct {
+	Name      string // Exported Go name, e.g. CtrlEnable
+	Source    string // Name as written in the header
+	Shift     uint
+	Size      uint
+	ValueType string // Smallest unsigned type that holds the field
+}
+
+// genLayout is the template view of an imported layout.
+type genLayout struct {
+	Name      string // Exported Go name, e.g. Ctrl
+	Source    string // Name as written in the header
+	Line      int
+	Container string
+	Fields    []genField
+}
+
+// genData is the template view of a whole header.
+type genData struct {
+	Package string
+	Source  string
+	Layouts []genLayout
+}
+
+// newGenData converts the imported layouts into template data.
+func newGenData(layouts []*layout, pkg, source string) (*genData, error) {
+	d := &genData{Package: pkg, Source: source}
+	seen := make(map[string]string)
+	declare := func(name, source string, line int) error {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("line %d: %s does not give a valid Go identifier", line, source)
+		}
+		if prev, ok := seen[name]; ok {
+			return fmt.Errorf("line %d: Go name %s of %s collides with %s", line, name, source, prev)
+		}
+		seen[name] = source
+		return nil
+	}
+	for _, l := range layouts {
+		gl := genLayout{Name: goName(strings.TrimSuffix(l.Name, "_t")), Source: l.Name, Line: l.Line, Container: "uint32"}
+		if l.Width > 32 {
+			gl.Container = "uint64"
+		}
+		if gl.Name == "" {
+			return nil, fmt.Errorf("line %d: %s does not give a valid Go identifier", l.Line, l.Name)
+		}
+		if err := declare(gl.Name+"Layout", l.Name, l.Line); err != nil {
+			return nil, err
+		}
+		for _, f := range l.Fields {
+			if goName(f.Name) == "" {
+				return nil, fmt.Errorf("line %d: %s.%s does not give a valid Go identifier", f.Line, l.Name, f.Name)
+			}
+			gf := genField{Name: gl.Name + goName(f.Name), Source: f.Name, Shift: f.Shift, Size: f.Size, ValueType: valueTypeFor(f.Size)}
+			if err := declare(gf.Name, l.Name+"."+f.Name, f.Line); err != nil {
+				return nil, err
+			}
+			gl.Fields = append(gl.Fields, gf)
+		}
+		d.Layouts = append(d.Layouts, gl)
+	}
+	return d, nil
+}
+
+// goName converts a C name such as "UART_CR" or "ctrl_reg" into "UartCr" or "CtrlReg".
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		if strings.ToUpper(part) == part {
+			part = strings.ToLower(part)
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// valueTypeFor returns the smallest unsigned Go type that can hold size bits.
+func valueTypeFor(size uint) string {
+	switch {
+	case size <= 8:
+		return "uint8"
+	case size <= 16:
+		return "uint16"
+	case size <= 32:
+		return "uint32"
+	}
+	return "uint64"
+}
+
+// generate renders the Go source for d.
+func generate(d *genData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := codeTemplate.Execute(&buf, d); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+var codeTemplate = template.Must(template.New("code").Parse(`// Code generated by bitfieldimport from {{.Source}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/lnear-dev/bitfield"
+{{range .Layouts}}{{$c := .Container}}
+// Fields of {{.Source}}, declared at {{$.Source}}:{{.Line}}.
+var (
+{{- range .Fields}}
+	{{.Name}} = bitfield.New[{{.ValueType}}, {{$c}}]({{.Shift}}, {{.Size}})
+{{- end}}
+)
+
+// {{.Name}}Layout returns a Layout with the fields of {{.Source}}.
+func {{.Name}}Layout() *bitfield.Layout[{{.Container}}] {
+	l := bitfield.NewLayout[{{.Container}}]()
+	for _, f := range []struct {
+		name        string
+		shift, size uint
+	}{
+{{- range .Fields}}
+		{"{{.Source}}", {{.Shift}}, {{.Size}}},
+{{- end}}
+	} {
+		if err := l.Add(f.name, f.shift, f.size); err != nil {
+			panic(err)
+		}
+	}
+	return l
+}
+{{end}}`))
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/lnear-dev/bitfield/regcsv"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRun_Golden(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "regs_bitfield.go")
+	if err := run("testdata/regs.h", "regs", out, regcsv.DefaultFormat, io.Discard); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "regs_bitfield.go.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("generated code differs from %s, run with -update to regenerate", golden)
+	}
+}
+
+func TestRun_Compiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go build of generated code in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not available")
+	}
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	mod := "module regs\n\ngo 1.23\n\nrequire github.com/lnear-dev/bitfield v0.0.0\n\nreplace github.com/lnear-dev/bitfield => " + root + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(mod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run("testdata/regs.h", "regs", filepath.Join(dir, "regs_bitfield.go"), regcsv.DefaultFormat, io.Discard); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	// Each generated layout function must build its layout without panicking.
+	test := `package regs
+
+import "testing"
+
+func TestLayouts(t *testing.T) {
+	for _, l := range []interface{ Names() []string }{
+		UartCtrlLayout(), UartStatusLayout(), SplitLayout(), UartIrqLayout(), UartPscLayout(), UartFifoLayout(),
+	} {
+		if len(l.Names()) == 0 {
+			t.Error("layout has no fields")
+		}
+	}
+	if UartPscEn.Mask != 1<<31 {
+		t.Errorf("UartPscEn.Mask = %#x", UartPscEn.Mask)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "regs_test.go"), []byte(test), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(goTool, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off", "GOPROXY=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test of generated code failed: %v\n%s", err, out)
+	}
+}
+
+func TestNewGenData_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		layouts []*layout
+	}{
+		{"colliding fields", []*layout{{Name: "r", Fields: []field{{Name: "a_b"}, {Name: "A_B"}}}}},
+		{"colliding layouts", []*layout{{Name: "ctrl"}, {Name: "CTRL"}}},
+		{"invalid layout name", []*layout{{Name: "_"}}},
+		{"invalid field name", []*layout{{Name: "r", Fields: []field{{Name: "__"}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newGenData(tt.layouts, "regs", "regs.h"); err == nil {
+				t.Error("newGenData succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"UART_CR", "UartCr"},
+		{"baud_div", "BaudDiv"},
+		{"rxReady", "RxReady"},
+		{"EN", "En"},
+		{"__reserved_1", "Reserved1"},
+	}
+	for _, tt := range tests {
+		if got := goName(tt.in); got != tt.want {
+			t.Errorf("goName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
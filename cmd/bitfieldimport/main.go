@@ -0,0 +1,100 @@
+// Bitfieldimport converts the register layouts of existing C headers into Go
+// BitField and Layout definitions, for porting legacy driver code.
+//
+// Two kinds of declarations are imported:
+//
+//   - structs whose members are all bit fields, optionally wrapped in a union
+//     with a raw word, with bits allocated as GCC and Clang do on
+//     little-endian targets;
+//   - groups of #define constants ending in _SHIFT, _Pos or _POS, _MASK, _Msk
+//     or _MSK, and _WIDTH, such as UART_CR_EN_SHIFT and UART_CR_EN_MASK, which
+//     become field EN of layout UART_CR.
+//
+// Register maps kept in spreadsheets can be imported from CSV files instead,
+// in the format of the regcsv package. The -columns flag maps properties to the
+// headers of the file, for example -columns 'register=Reg Name,offset=Address'.
+//
+// For each layout the generated file declares a BitField variable per field
+// and a function returning the whole Layout. Declarations that cannot be
+// imported are reported as warnings.
+//
+// Usage:
+//
+//	bitfieldimport [-pkg name] [-out file] header.h
+//	bitfieldimport [-pkg name] [-out file] [-columns map] [-comma c] regs.csv
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lnear-dev/bitfield/regcsv"
+)
+
+func main() {
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name of the generated code (default $GOPACKAGE)")
+	out := flag.String("out", "", "output file (default <input>_bitfield.go)")
+	format := regcsv.DefaultFormat
+	flag.Var(columnsFlag{&format}, "columns", "CSV column headers as property=header pairs separated by commas")
+	flag.Var(commaFlag{&format}, "comma", "CSV field delimiter")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: bitfieldimport [flags] header.h|regs.csv\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *pkg, *out, format, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "bitfieldimport: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run imports the header or CSV file at path, writing warnings to stderr.
+// CSV files are read using format.
+func run(path, pkg, out string, format regcsv.Format, stderr io.Writer) error {
+	if pkg == "" {
+		return fmt.Errorf("package name not set, use -pkg")
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var (
+		layouts  []*layout
+		warnings []string
+	)
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		layouts, err = csvLayouts(bytes.NewReader(src), format)
+	} else {
+		layouts, warnings, err = parseHeader(string(src))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(stderr, "bitfieldimport: %s: %s\n", path, w)
+	}
+	if len(layouts) == 0 {
+		return fmt.Errorf("%s: no layouts found", path)
+	}
+	d, err := newGenData(layouts, pkg, filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	code, err := generate(d)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		out = strings.TrimSuffix(path, filepath.Ext(path)) + "_bitfield.go"
+	}
+	return os.WriteFile(out, code, 0o644)
+}
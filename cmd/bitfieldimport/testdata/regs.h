@@ -0,0 +1,61 @@
+/*
+ * Register definitions of a legacy UART driver.
+ */
+#ifndef REGS_H
+#define REGS_H
+
+#include <stdint.h>
+
+#define UART_BASE 0x40001000UL
+
+/* Control register */
+typedef union {
+    struct {
+        uint32_t enable   : 1;  /* Enable the UART */
+        uint32_t mode     : 2;
+        uint32_t          : 5;  /* Reserved */
+        uint32_t baud_div : 12;
+        uint32_t          : 0;
+    } bits;
+    uint32_t raw;
+} uart_ctrl_t;
+
+struct uart_status {
+    volatile unsigned int rx_ready : 1,
+                          tx_empty : 1;
+    unsigned int level : 4;
+    unsigned int parity_err : 1;
+};
+
+/* Fields straddling a byte are moved to the next storage unit. */
+typedef struct {
+    uint8_t low  : 6;
+    uint8_t high : 4;
+} split_t;
+
+struct not_a_register {
+    uint32_t count;
+    const char *name;
+};
+
+/* Interrupt register, CMSIS style */
+#define UART_IRQ_RXNE_Pos   (0U)
+#define UART_IRQ_RXNE_Msk   (0x1UL << UART_IRQ_RXNE_Pos)
+#define UART_IRQ_LEVEL_Pos  (4U)
+#define UART_IRQ_LEVEL_Msk  (0xFUL << UART_IRQ_LEVEL_Pos)
+
+/* Prescaler register, Linux style */
+#define UART_PSC_DIV_MASK   GENMASK(15, 0)
+#define UART_PSC_EN_MASK    BIT(31)
+#define UART_PSC_MODE_SHIFT 16
+#define UART_PSC_MODE_WIDTH \
+	3
+
+/* Masks given relative to the shift */
+#define UART_FIFO_THRESH_SHIFT 8
+#define UART_FIFO_THRESH_MASK  0x1f
+
+#define UART_BAD_GAP_MASK   0x5
+#define UART_BAD_SHIFT_ONLY_SHIFT 3
+
+#endif /* REGS_H */
@@ -0,0 +1,54 @@
+package codegen
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// CHeader writes a C header describing the layout as the type name.
+// The header defines SHIFT, SIZE and MASK macros for every field, a macro for
+// every enum value, and a union of the raw container with a struct of bitfield
+// members. The order of C bitfield members is implementation-defined; the struct
+// assumes the least significant bit is allocated first, as GCC and Clang do on
+// little-endian targets. The macros do not depend on this.
+func CHeader[U uint32 | uint64](w io.Writer, name string, l *bitfield.Layout[U]) error {
+	v, err := newLayoutView(name, l)
+	if err != nil {
+		return err
+	}
+	return cTemplate.Execute(w, v)
+}
+
+var cTemplate = template.Must(template.New("c").Funcs(funcs).Parse(`/* Code generated by bitfield codegen; DO NOT EDIT. */
+{{- $t := upperSnake .Name}}{{$d := digits .Width}}
+
+#ifndef {{$t}}_H
+#define {{$t}}_H
+
+#include <stdint.h>
+{{range .Fields}}
+/* {{.Name}}: bits {{msb .}}:{{.Shift}} */
+#define {{$t}}_{{upperSnake .Name}}_SHIFT {{.Shift}}u
+#define {{$t}}_{{upperSnake .Name}}_SIZE {{.Size}}u
+#define {{$t}}_{{upperSnake .Name}}_MASK {{hex .Mask $d}}u{{if eq $.Width 64}}ll{{end}}
+{{- $f := .}}{{range .Enum}}
+#define {{$t}}_{{upperSnake $f.Name}}_{{upperSnake .Name}} {{.Value}}u
+{{- end}}
+{{end}}
+typedef union {
+	uint{{.Width}}_t raw;
+	struct {
+{{- range .Slots}}
+{{- if .Reserved}}
+		uint{{$.Width}}_t : {{.Size}};
+{{- else}}
+		uint{{$.Width}}_t {{snake .Name}} : {{.Size}};
+{{- end}}
+{{- end}}
+	} bits;
+} {{snake .Name}}_t;
+
+#endif /* {{$t}}_H */
+`))
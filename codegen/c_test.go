@@ -0,0 +1,53 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCHeader(t *testing.T) {
+	var buf bytes.Buffer
+	if err := CHeader(&buf, "Control", newControlLayout(t)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "control.h", buf.Bytes())
+}
+
+func TestCHeader_Compiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping C compilation in short mode")
+	}
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("C compiler not available")
+	}
+	dir := t.TempDir()
+	var header bytes.Buffer
+	if err := CHeader(&header, "Control", newControlLayout(t)); err != nil {
+		t.Fatal(err)
+	}
+	program := `#include "control.h"
+_Static_assert(sizeof(control_t) == 4, "control_t must be 32 bits");
+int main(void) {
+	control_t c = {.raw = 0x2A57};
+	if (c.bits.priority != CONTROL_PRIORITY_HIGH || c.bits.error_code != 42) return 1;
+	if (((c.raw & CONTROL_ERROR_CODE_MASK) >> CONTROL_ERROR_CODE_SHIFT) != 42) return 2;
+	return 0;
+}
+`
+	for name, data := range map[string][]byte{"control.h": header.Bytes(), "main.c": []byte(program)} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bin := filepath.Join(dir, "main")
+	if out, err := exec.Command(cc, "-std=c11", "-Wall", "-Werror", "-o", bin, filepath.Join(dir, "main.c")).CombinedOutput(); err != nil {
+		t.Fatalf("cc: %v\n%s", err, out)
+	}
+	if out, err := exec.Command(bin).CombinedOutput(); err != nil {
+		t.Errorf("generated header gives wrong values: %v\n%s", err, out)
+	}
+}
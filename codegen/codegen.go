@@ -0,0 +1,242 @@
+// Package codegen renders bitfield layouts as source code for other languages,
+// so that firmware, RTL and host tooling can share the Go definitions as their
+// single source of truth. Register maps can also be rendered as Markdown or
+// HTML reference documentation.
+//
+// Every generator takes a name for the rendered type or package and a Layout or
+// RegisterMap, and writes the generated code to an io.Writer. Names are converted to the conventions of the
+// target language: a field named "error_code" or "errorCode" becomes ERROR_CODE
+// in C macros and error_code in C struct members. Enum names registered with
+// Layout.SetEnum are rendered as named constants.
+package codegen
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+	"unsafe"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// field is the template view of a single layout field or of a run of reserved bits.
+type field struct {
+	Name     string // Name as in the layout, empty for reserved bits
+	Shift    uint
+	Size     uint
+	Mask     uint64
+	Max      uint64 // Largest value of the field
+	Enum     []enumValue
+	Reserved bool
+	Access   string // Access type of a register field, such as RW; empty for layouts
+	Effect   string // Side effect of a register field, such as W1C; empty if none
+	Reset    uint64 // Reset value of a register field
+
+	Description string // Set with bitfield.WithDescription
+	Unit        string // Set with bitfield.WithUnit
+}
+
+// enumValue is a named value of a field.
+type enumValue struct {
+	Name  string
+	Value uint64
+}
+
+// layoutView is the template view of a layout.
+type layoutView struct {
+	Name   string
+	Width  uint    // Size of the container in bits
+	Fields []field // Fields in the order they were added to the layout
+	Slots  []field // Fields and reserved bits in ascending bit order, covering the container
+}
+
+// newLayoutView converts a layout into template data.
+// Returns an error if name or a field name cannot be converted into an identifier,
+// or two field names collide once converted.
+func newLayoutView[U uint32 | uint64](name string, l *bitfield.Layout[U]) (*layoutView, error) {
+	if len(words(name)) == 0 {
+		return nil, fmt.Errorf("invalid type name %q", name)
+	}
+	v := &layoutView{Name: name, Width: uint(unsafe.Sizeof(U(0))) * 8}
+	seen := make(map[string]string)
+	for _, n := range l.Names() {
+		key := snake(n)
+		if key == "" {
+			return nil, fmt.Errorf("invalid field name %q", n)
+		}
+		if prev, ok := seen[key]; ok {
+			return nil, fmt.Errorf("field %q collides with %q", n, prev)
+		}
+		seen[key] = n
+		bf, _ := l.Field(n)
+		f := field{Name: n, Shift: bf.Shift, Size: bf.Size, Mask: uint64(bf.Mask), Max: bf.Max(), Description: bf.Description(), Unit: bf.Unit()}
+		for value, label := range bf.Enum() {
+			if snake(label) == "" {
+				return nil, fmt.Errorf("invalid enum name %q of field %q", label, n)
+			}
+			f.Enum = append(f.Enum, enumValue{Name: label, Value: value})
+		}
+		slices.SortFunc(f.Enum, func(a, b enumValue) int { return cmp.Compare(a.Value, b.Value) })
+		v.Fields = append(v.Fields, f)
+	}
+
+	sorted := slices.Clone(v.Fields)
+	slices.SortFunc(sorted, func(a, b field) int { return cmp.Compare(a.Shift, b.Shift) })
+	var next uint
+	for _, f := range append(sorted, field{Shift: v.Width}) {
+		if f.Shift > next {
+			size := f.Shift - next
+			v.Slots = append(v.Slots, field{Shift: next, Size: size, Mask: lowBits(size) << next, Max: lowBits(size), Reserved: true})
+		}
+		if f.Size > 0 {
+			v.Slots = append(v.Slots, f)
+		}
+		next = f.Shift + f.Size
+	}
+	return v, nil
+}
+
+// registerView is the template view of a register of a RegisterMap.
+type registerView struct {
+	*layoutView
+	Offset uint64
+	Reset  uint64
+}
+
+// mapView is the template view of a RegisterMap.
+type mapView struct {
+	Name      string
+	Width     uint // Size of the registers in bits
+	Registers []registerView
+}
+
+// newMapView converts a register map into template data.
+// Returns an error if a name cannot be converted into an identifier.
+func newMapView[U uint32 | uint64](name string, m *bitfield.RegisterMap[U]) (*mapView, error) {
+	if snake(name) == "" {
+		return nil, fmt.Errorf("invalid package name %q", name)
+	}
+	v := &mapView{Name: name, Width: uint(unsafe.Sizeof(U(0))) * 8}
+	for _, reg := range m.Registers() {
+		lv, err := newLayoutView(reg.Name, reg.Layout)
+		if err != nil {
+			return nil, fmt.Errorf("register %s: %w", reg.Name, err)
+		}
+		for _, fields := range [][]field{lv.Fields, lv.Slots} {
+			for i := range fields {
+				f := &fields[i]
+				f.Reset = (uint64(reg.ResetValue) & f.Mask) >> f.Shift
+				if f.Reserved {
+					continue
+				}
+				f.Access = reg.Access(f.Name).String()
+				if e := reg.SideEffect(f.Name); e != bitfield.NoSideEffect {
+					f.Effect = e.String()
+				}
+			}
+		}
+		v.Registers = append(v.Registers, registerView{layoutView: lv, Offset: reg.Offset, Reset: uint64(reg.ResetValue)})
+	}
+	return v, nil
+}
+
+// lowBits returns a mask of the n least significant bits.
+func lowBits(n uint) uint64 {
+	if n >= 64 {
+		return ^uint64(0)
+	}
+	return 1<<n - 1
+}
+
+// words splits a name into lower-case words at underscores, hyphens, spaces,
+// other punctuation and lower-to-upper case transitions, so that "errorCode",
+// "error_code" and "ERROR-CODE" all give "error", "code".
+func words(name string) []string {
+	var out []string
+	var cur []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(cur) > 0 {
+				out = append(out, string(cur))
+				cur = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(cur) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				out = append(out, string(cur))
+				cur = nil
+			}
+		}
+		cur = append(cur, unicode.ToLower(r))
+	}
+	if len(cur) > 0 {
+		out = append(out, string(cur))
+	}
+	return out
+}
+
+// snake converts a name into lower_snake_case, prefixing an underscore if it
+// would start with a digit.
+func snake(name string) string {
+	s := strings.Join(words(name), "_")
+	if s != "" && unicode.IsDigit(rune(s[0])) {
+		s = "_" + s
+	}
+	return s
+}
+
+// upperSnake converts a name into UPPER_SNAKE_CASE.
+func upperSnake(name string) string {
+	return strings.ToUpper(snake(name))
+}
+
+// pascal converts a name into PascalCase.
+func pascal(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	s := b.String()
+	if s != "" && unicode.IsDigit(rune(s[0])) {
+		s = "_" + s
+	}
+	return s
+}
+
+// camel converts a name into camelCase.
+func camel(name string) string {
+	s := pascal(name)
+	if s == "" || s[0] == '_' {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// hex formats v as a hexadecimal literal with the given number of digits.
+func hex(v uint64, digits uint) string {
+	return fmt.Sprintf("0x%0*X", digits, v)
+}
+
+// funcs are the helpers available to all templates.
+var funcs = template.FuncMap{
+	"snake":      snake,
+	"upperSnake": upperSnake,
+	"pascal":     pascal,
+	"camel":      camel,
+	"hex":        hex,
+	"digits":     func(width uint) uint { return width / 4 },
+	"msb":        func(f field) uint { return f.Shift + f.Size - 1 },
+	// msbFirst returns the slots of a layout from the most significant bit down.
+	"msbFirst": func(slots []field) []field {
+		s := slices.Clone(slots)
+		slices.Reverse(s)
+		return s
+	},
+}
@@ -0,0 +1,98 @@
+package codegen
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// newControlLayout returns the layout used by the golden tests: an active flag,
+// a 3-bit priority with enum names, an error code and a 16-bit data field,
+// with bits 4-7 reserved.
+func newControlLayout(t *testing.T) *bitfield.Layout[uint32] {
+	t.Helper()
+	l := bitfield.NewLayout[uint32]()
+	for _, err := range []error{
+		l.Add("active", 0, 1),
+		l.Add("priority", 1, 3),
+		l.Add("errorCode", 8, 8),
+		l.Add("data", 16, 16),
+		l.SetEnum("priority", map[uint64]string{0: "Low", 1: "Medium", 3: "High"}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+// checkGolden compares got with the golden file testdata/name, or rewrites it with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run with -update to regenerate:\n%s", golden, got)
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"error_code", []string{"error", "code"}},
+		{"errorCode", []string{"error", "code"}},
+		{"ERROR-CODE", []string{"error", "code"}},
+		{"HTTPStatus", []string{"http", "status"}},
+		{"irq2Enable", []string{"irq2", "enable"}},
+		{"CTRL", []string{"ctrl"}},
+		{"--", nil},
+	}
+
+	for _, tt := range tests {
+		if got := words(tt.name); !slices.Equal(got, tt.want) {
+			t.Errorf("words(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewLayoutView(t *testing.T) {
+	v, err := newLayoutView("Control", newControlLayout(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range v.Slots {
+		got = append(got, s.Name)
+	}
+	if want := []string{"active", "priority", "", "errorCode", "data"}; !slices.Equal(got, want) {
+		t.Errorf("slots = %q, want %q", got, want)
+	}
+
+	l := bitfield.NewLayout[uint32]()
+	_ = l.Add("error_code", 0, 1)
+	_ = l.Add("errorCode", 1, 1)
+	if _, err := newLayoutView("Control", l); err == nil {
+		t.Error("newLayoutView with colliding names succeeded, want error")
+	}
+	if _, err := newLayoutView("", newControlLayout(t)); err == nil {
+		t.Error("newLayoutView with empty type name succeeded, want error")
+	}
+}
@@ -0,0 +1,144 @@
+package codegen
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Markdown writes reference documentation for the registers of the map as
+// Markdown: a summary table of the registers, then a table per register
+// listing every field and reserved range from the most significant bit down,
+// with its access type, side effect, reset value, and description, unit and
+// enum meanings.
+func Markdown[U uint32 | uint64](w io.Writer, name string, m *bitfield.RegisterMap[U]) error {
+	v, err := newMapView(name, m)
+	if err != nil {
+		return err
+	}
+	return markdownTemplate.Execute(w, v)
+}
+
+// HTML writes the documentation produced by Markdown as a standalone HTML page.
+func HTML[U uint32 | uint64](w io.Writer, name string, m *bitfield.RegisterMap[U]) error {
+	v, err := newMapView(name, m)
+	if err != nil {
+		return err
+	}
+	return htmlTemplate.Execute(w, v)
+}
+
+// docFuncs are the helpers available to the documentation templates.
+var docFuncs = template.FuncMap{
+	// bits formats the bit range of a field.
+	"bits": func(f field) string {
+		if f.Size == 1 {
+			return fmt.Sprint(f.Shift)
+		}
+		return fmt.Sprintf("%d:%d", f.Shift+f.Size-1, f.Shift)
+	},
+	// access describes the access type and side effect of a field.
+	"access": func(f field) string {
+		if f.Effect != "" {
+			return f.Access + ", " + f.Effect
+		}
+		return f.Access
+	},
+	// values lists the description, unit and enum meanings of a field.
+	"values": func(f field) string {
+		var parts, enum []string
+		if f.Description != "" {
+			parts = append(parts, f.Description)
+		}
+		if f.Unit != "" {
+			parts = append(parts, "unit: "+f.Unit)
+		}
+		for _, e := range f.Enum {
+			enum = append(enum, hexValue(e.Value, f.Size)+": "+e.Name)
+		}
+		if len(enum) > 0 {
+			parts = append(parts, strings.Join(enum, ", "))
+		}
+		return strings.Join(parts, "; ")
+	},
+	"fieldHex": func(f field, v uint64) string { return hexValue(v, f.Size) },
+	// anchor returns the link target of a register heading.
+	"anchor": func(name string) string { return strings.ReplaceAll(strings.ToLower(name), " ", "-") },
+	// md escapes the characters that would break a Markdown table cell.
+	"md": strings.NewReplacer(`|`, `\|`, `*`, `\*`, `_`, `\_`, "`", "\\`").Replace,
+}
+
+// hexValue formats a field value as a hex literal with enough digits for size bits.
+func hexValue(v uint64, size uint) string {
+	return hex(v, (size+3)/4)
+}
+
+var markdownTemplate = template.Must(template.New("md").Funcs(funcs).Funcs(docFuncs).Parse(`<!-- Code generated by bitfield codegen; DO NOT EDIT. -->
+
+# {{md .Name}} registers
+{{- $w := .Width}}
+
+| Offset | Register | Reset |
+|--------|----------|-------|
+{{- range .Registers}}
+| {{hex .Offset 4}} | [{{md .Name}}](#{{anchor .Name}}) | {{hex .Reset (digits $w)}} |
+{{- end}}
+{{range .Registers}}
+## {{md .Name}}
+
+Offset {{hex .Offset 4}}, reset value {{hex .Reset (digits $w)}}.
+
+| Bits | Field | Access | Reset | Values |
+|------|-------|--------|-------|--------|
+{{- range msbFirst .Slots}}
+{{- if .Reserved}}
+| {{bits .}} | *reserved* | | | |
+{{- else}}
+| {{bits .}} | {{md .Name}} | {{access .}} | {{fieldHex . .Reset}} | {{md (values .)}} |
+{{- end}}
+{{- end}}
+{{end -}}
+`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(htmltemplate.FuncMap(funcs)).Funcs(htmltemplate.FuncMap(docFuncs)).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}} registers</title>
+<style>
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #999; padding: 0.2em 0.6em; text-align: left; }
+td.reserved { color: #888; font-style: italic; }
+code { font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>{{.Name}} registers</h1>
+{{- $w := .Width}}
+<table>
+<tr><th>Offset</th><th>Register</th><th>Reset</th></tr>
+{{- range .Registers}}
+<tr><td><code>{{hex .Offset 4}}</code></td><td><a href="#{{anchor .Name}}">{{.Name}}</a></td><td><code>{{hex .Reset (digits $w)}}</code></td></tr>
+{{- end}}
+</table>
+{{range .Registers}}
+<h2 id="{{anchor .Name}}">{{.Name}}</h2>
+<p>Offset <code>{{hex .Offset 4}}</code>, reset value <code>{{hex .Reset (digits $w)}}</code>.</p>
+<table>
+<tr><th>Bits</th><th>Field</th><th>Access</th><th>Reset</th><th>Values</th></tr>
+{{- range msbFirst .Slots}}
+{{- if .Reserved}}
+<tr><td>{{bits .}}</td><td class="reserved" colspan="4">reserved</td></tr>
+{{- else}}
+<tr><td>{{bits .}}</td><td>{{.Name}}</td><td>{{access .}}</td><td><code>{{fieldHex . .Reset}}</code></td><td>{{values .}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+{{end -}}
+</body>
+</html>
+`))
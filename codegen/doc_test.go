@@ -0,0 +1,92 @@
+package codegen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// newDocMap returns the UART map with a read-to-clear error counter, so the
+// documentation shows a side effect.
+func newDocMap(t *testing.T) *bitfield.RegisterMap[uint32] {
+	t.Helper()
+	m := newUARTMap(t)
+	status, _ := m.ByName("STATUS")
+	if err := status.SetSideEffect("errors", bitfield.ReadToClear); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Markdown(&buf, "uart", newDocMap(t)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "uart.md", buf.Bytes())
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := HTML(&buf, "uart", newDocMap(t)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "uart.html", buf.Bytes())
+}
+
+func TestHTML_Escapes(t *testing.T) {
+	l := bitfield.NewLayout[uint32]()
+	if err := l.Add("a", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetEnum("a", map[uint64]string{1: "<on>"}); err != nil {
+		t.Fatal(err)
+	}
+	m := bitfield.NewRegisterMap[uint32]()
+	if err := m.Add(0, bitfield.NewRegister("R", l, 0)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := HTML(&buf, "x", m); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<on>") || !strings.Contains(buf.String(), "&lt;on&gt;") {
+		t.Errorf("enum name not escaped:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := Markdown(&buf, "x", m); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "| 0x1: <on> |") {
+		t.Errorf("Markdown enum cell not found:\n%s", buf.String())
+	}
+}
+
+func TestMarkdown_Description(t *testing.T) {
+	l := bitfield.NewLayout[uint32]()
+	if err := l.Add("timeout", 0, 8, bitfield.WithDescription("Idle timeout"), bitfield.WithUnit("ms")); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Add("mode", 8, 1, bitfield.WithEnum(map[uint8]string{0: "off", 1: "on"})); err != nil {
+		t.Fatal(err)
+	}
+	m := bitfield.NewRegisterMap[uint32]()
+	if err := m.Add(0, bitfield.NewRegister("CTRL", l, 0)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Markdown(&buf, "x", m); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range []string{
+		"| 7:0 | timeout | RW | 0x00 | Idle timeout; unit: ms |",
+		"| 8 | mode | RW | 0x0 | 0x0: off, 0x1: on |",
+	} {
+		if !strings.Contains(buf.String(), row) {
+			t.Errorf("Markdown() is missing row %q:\n%s", row, buf.String())
+		}
+	}
+}
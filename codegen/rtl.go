@@ -0,0 +1,125 @@
+package codegen
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// SystemVerilog writes a SystemVerilog package named <name>_pkg describing the
+// registers of the map. For every register it declares localparams for the
+// offset and reset value, the shift, width and mask of every field and every
+// enum value, and a struct packed typedef of the register with reserved bits
+// filled in, so that RTL and Go drivers share one definition.
+func SystemVerilog[U uint32 | uint64](w io.Writer, name string, m *bitfield.RegisterMap[U]) error {
+	v, err := newMapView(name, m)
+	if err != nil {
+		return err
+	}
+	return svTemplate.Execute(w, v)
+}
+
+// VHDL writes a VHDL package named <name>_pkg describing the registers of the map,
+// with the same constants as SystemVerilog and a record type for every register
+// with the same members, reserved bits included. Offsets are std_logic_vector
+// constants, since natural cannot hold offsets of 2^31 and above.
+func VHDL[U uint32 | uint64](w io.Writer, name string, m *bitfield.RegisterMap[U]) error {
+	v, err := newMapView(name, m)
+	if err != nil {
+		return err
+	}
+	return vhdlTemplate.Execute(w, v)
+}
+
+// rtlFuncs are the helpers available to the SystemVerilog and VHDL templates.
+var rtlFuncs = template.FuncMap{
+	"dec": func(n uint) uint { return n - 1 },
+	// svHex formats v as a sized SystemVerilog hex literal.
+	"svHex": func(width uint, v uint64) string {
+		return fmt.Sprintf("%d'h%0*X", width, (width+3)/4, v)
+	},
+	// addrWidth returns the width of the literals used for register offsets.
+	"addrWidth": func(offset uint64) uint {
+		if offset>>32 != 0 {
+			return 64
+		}
+		return 32
+	},
+	// vhdlHex formats v as a VHDL hex bit string literal.
+	"vhdlHex": func(width uint, v uint64) string {
+		return fmt.Sprintf("x\"%0*X\"", width/4, v)
+	},
+	// vhdlBits formats v as a VHDL literal of width bits.
+	"vhdlBits": func(width uint, v uint64) string {
+		if width == 1 {
+			return fmt.Sprintf("'%d'", v&1)
+		}
+		return fmt.Sprintf("\"%0*b\"", width, v)
+	},
+	// member returns the struct member name of a slot.
+	"member": func(f field) string {
+		if f.Reserved {
+			return fmt.Sprintf("reserved_%d", f.Shift)
+		}
+		return snake(f.Name)
+	},
+}
+
+var svTemplate = template.Must(template.New("sv").Funcs(funcs).Funcs(rtlFuncs).Parse(`// Code generated by bitfield codegen; DO NOT EDIT.
+
+package {{snake .Name}}_pkg;
+{{- $w := .Width}}
+{{range .Registers}}{{$r := upperSnake .Name}}
+  // {{.Name}} register
+  localparam logic [{{dec (addrWidth .Offset)}}:0] {{$r}}_OFFSET = {{svHex (addrWidth .Offset) .Offset}};
+  localparam logic [{{dec $w}}:0] {{$r}}_RESET = {{svHex $w .Reset}};
+{{- range .Fields}}{{$f := .}}
+  // {{.Name}}: bits {{msb .}}:{{.Shift}}, {{.Access}}
+  localparam int {{$r}}_{{upperSnake .Name}}_SHIFT = {{.Shift}};
+  localparam int {{$r}}_{{upperSnake .Name}}_WIDTH = {{.Size}};
+  localparam logic [{{dec $w}}:0] {{$r}}_{{upperSnake .Name}}_MASK = {{svHex $w .Mask}};
+{{- range .Enum}}
+  localparam logic {{if gt $f.Size 1}}[{{dec $f.Size}}:0] {{end}}{{$r}}_{{upperSnake $f.Name}}_{{upperSnake .Name}} = {{svHex $f.Size .Value}};
+{{- end}}
+{{- end}}
+
+  typedef struct packed {
+{{- range msbFirst .Slots}}
+    logic {{if gt .Size 1}}[{{dec .Size}}:0] {{end}}{{member .}};
+{{- end}}
+  } {{snake .Name}}_t;
+{{end}}
+endpackage
+`))
+
+var vhdlTemplate = template.Must(template.New("vhdl").Funcs(funcs).Funcs(rtlFuncs).Parse(`-- Code generated by bitfield codegen; DO NOT EDIT.
+
+library ieee;
+use ieee.std_logic_1164.all;
+
+package {{snake .Name}}_pkg is
+{{- $w := .Width}}
+{{range .Registers}}{{$r := upperSnake .Name}}
+  -- {{.Name}} register
+  constant {{$r}}_OFFSET : std_logic_vector({{dec (addrWidth .Offset)}} downto 0) := {{vhdlHex (addrWidth .Offset) .Offset}};
+  constant {{$r}}_RESET : std_logic_vector({{dec $w}} downto 0) := {{vhdlHex $w .Reset}};
+{{- range .Fields}}{{$f := .}}
+  -- {{.Name}}: bits {{msb .}}:{{.Shift}}, {{.Access}}
+  constant {{$r}}_{{upperSnake .Name}}_SHIFT : natural := {{.Shift}};
+  constant {{$r}}_{{upperSnake .Name}}_WIDTH : natural := {{.Size}};
+  constant {{$r}}_{{upperSnake .Name}}_MASK : std_logic_vector({{dec $w}} downto 0) := {{vhdlHex $w .Mask}};
+{{- range .Enum}}
+  constant {{$r}}_{{upperSnake $f.Name}}_{{upperSnake .Name}} : {{if eq $f.Size 1}}std_logic{{else}}std_logic_vector({{dec $f.Size}} downto 0){{end}} := {{vhdlBits $f.Size .Value}};
+{{- end}}
+{{- end}}
+
+  type {{snake .Name}}_t is record
+{{- range msbFirst .Slots}}
+    {{member .}} : {{if eq .Size 1}}std_logic{{else}}std_logic_vector({{dec .Size}} downto 0){{end}};
+{{- end}}
+  end record;
+{{end}}
+end package;
+`))
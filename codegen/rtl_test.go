@@ -0,0 +1,96 @@
+package codegen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// newUARTMap returns the register map used by the RTL golden tests: a CTRL
+// register with an enum mode and a write-only command, and a read-only STATUS.
+func newUARTMap(t *testing.T) *bitfield.RegisterMap[uint32] {
+	t.Helper()
+	ctrl := bitfield.NewLayout[uint32]()
+	status := bitfield.NewLayout[uint32]()
+	for _, err := range []error{
+		ctrl.Add("enable", 0, 1),
+		ctrl.Add("mode", 1, 2),
+		ctrl.Add("command", 16, 8),
+		ctrl.SetEnum("mode", map[uint64]string{0: "Off", 1: "Normal", 2: "LowPower"}),
+		status.Add("ready", 0, 1),
+		status.Add("errors", 4, 4),
+		status.SetEnum("ready", map[uint64]string{0: "Busy", 1: "Ready"}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctrlReg := bitfield.NewRegister("CTRL", ctrl, 0x00000002)
+	statusReg := bitfield.NewRegister("STATUS", status, 0x00000001)
+	m := bitfield.NewRegisterMap[uint32]()
+	for _, err := range []error{
+		ctrlReg.SetAccess("command", bitfield.WriteOnly),
+		statusReg.SetAccess("ready", bitfield.ReadOnly),
+		statusReg.SetAccess("errors", bitfield.ReadOnly),
+		m.Add(0x0, ctrlReg),
+		m.Add(0x4, statusReg),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+func TestSystemVerilog(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SystemVerilog(&buf, "uart", newUARTMap(t)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "uart_pkg.sv", buf.Bytes())
+}
+
+func TestVHDL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := VHDL(&buf, "uart", newUARTMap(t)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "uart_pkg.vhd", buf.Bytes())
+}
+
+func TestSystemVerilog_InvalidName(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SystemVerilog(&buf, "--", newUARTMap(t)); err == nil {
+		t.Error("SystemVerilog with invalid package name succeeded, want error")
+	}
+}
+
+func TestVHDL_HighOffset(t *testing.T) {
+	l := bitfield.NewLayout[uint32]()
+	if err := l.Add("data", 0, 8); err != nil {
+		t.Fatal(err)
+	}
+	m := bitfield.NewRegisterMap[uint32]()
+	for _, err := range []error{
+		m.Add(0x80000000, bitfield.NewRegister("HIGH", l, 0)),
+		m.Add(0x100000000, bitfield.NewRegister("WIDE", l, 0)),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := VHDL(&buf, "soc", m); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`constant HIGH_OFFSET : std_logic_vector(31 downto 0) := x"80000000";`,
+		`constant WIDE_OFFSET : std_logic_vector(63 downto 0) := x"0000000100000000";`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("VHDL() is missing %q:\n%s", want, buf.String())
+		}
+	}
+}
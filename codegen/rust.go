@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Rust writes a Rust module describing the layout as the type name.
+// The type is a tuple struct wrapping the raw container, with associated SHIFT,
+// MASK and MAX constants for every field, a constant for every enum value, and
+// a getter and a setter for every field. Like the flags of the bitflags crate,
+// the MASK constants of 1-bit fields can be combined with | to test and set flags.
+// Setters panic if the value does not fit in the field. The code has no dependencies.
+// Getters of fields named after Rust keywords, such as type, are raw identifiers
+// like r#type. Returns an error for fields named self, super, crate or Self,
+// which cannot be raw identifiers.
+func Rust[U uint32 | uint64](w io.Writer, name string, l *bitfield.Layout[U]) error {
+	v, err := newLayoutView(name, l)
+	if err != nil {
+		return err
+	}
+	for _, f := range v.Fields {
+		if s := snake(f.Name); rustPathKeywords[s] {
+			return fmt.Errorf("field %q: %q cannot be a Rust identifier", f.Name, s)
+		}
+	}
+	return rustTemplate.Execute(w, v)
+}
+
+// rustKeywords are the strict and reserved keywords of Rust 2021 and later,
+// which must be written as raw identifiers to be used as names.
+var rustKeywords = map[string]bool{
+	"as": true, "async": true, "await": true, "break": true, "const": true,
+	"continue": true, "dyn": true, "else": true, "enum": true, "extern": true,
+	"false": true, "fn": true, "for": true, "gen": true, "if": true, "impl": true,
+	"in": true, "let": true, "loop": true, "match": true, "mod": true, "move": true,
+	"mut": true, "pub": true, "ref": true, "return": true, "static": true,
+	"struct": true, "trait": true, "true": true, "try": true, "type": true,
+	"unsafe": true, "use": true, "where": true, "while": true, "abstract": true,
+	"become": true, "box": true, "do": true, "final": true, "macro": true,
+	"override": true, "priv": true, "typeof": true, "unsized": true,
+	"virtual": true, "yield": true,
+}
+
+// rustPathKeywords are the keywords that cannot be raw identifiers.
+var rustPathKeywords = map[string]bool{"self": true, "Self": true, "super": true, "crate": true}
+
+// rustFuncs are the helpers available to the Rust template.
+var rustFuncs = template.FuncMap{
+	// rustIdent returns the snake_case name of a field as a Rust identifier.
+	"rustIdent": func(name string) string {
+		s := snake(name)
+		if rustKeywords[s] {
+			return "r#" + s
+		}
+		return s
+	},
+}
+
+var rustTemplate = template.Must(template.New("rust").Funcs(funcs).Funcs(rustFuncs).Parse(`// Code generated by bitfield codegen; DO NOT EDIT.
+{{- $t := pascal .Name}}{{$u := printf "u%d" .Width}}{{$d := digits .Width}}
+
+/// Packed {{$u}} container with the fields of {{.Name}}.
+#[derive(Clone, Copy, Debug, Default, PartialEq, Eq, Hash)]
+pub struct {{$t}}(pub {{$u}});
+
+#[allow(dead_code)]
+impl {{$t}} {
+{{- range .Fields}}
+    /// {{.Name}}: bits {{msb .}}:{{.Shift}}
+    pub const {{upperSnake .Name}}_SHIFT: u32 = {{.Shift}};
+    pub const {{upperSnake .Name}}_MASK: {{$u}} = {{hex .Mask $d}};
+    pub const {{upperSnake .Name}}_MAX: {{$u}} = {{hex .Max 0}};
+{{- $f := .}}{{range .Enum}}
+    pub const {{upperSnake $f.Name}}_{{upperSnake .Name}}: {{$u}} = {{.Value}};
+{{- end}}
+{{end}}
+{{- range .Fields}}
+    /// Returns the value of the {{.Name}} field.
+    pub const fn {{rustIdent .Name}}(self) -> {{$u}} {
+        (self.0 & Self::{{upperSnake .Name}}_MASK) >> Self::{{upperSnake .Name}}_SHIFT
+    }
+
+    /// Sets the {{.Name}} field to v. Panics if v does not fit in the field.
+    pub fn set_{{snake .Name}}(&mut self, v: {{$u}}) {
+        assert!(v <= Self::{{upperSnake .Name}}_MAX, "value out of range for field {{.Name}}");
+        self.0 = (self.0 & !Self::{{upperSnake .Name}}_MASK) | (v << Self::{{upperSnake .Name}}_SHIFT);
+    }
+{{end -}}
+}
+`))
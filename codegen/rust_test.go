@@ -0,0 +1,121 @@
+package codegen
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+func TestRust(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Rust(&buf, "Control", newControlLayout(t)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "control.rs", buf.Bytes())
+}
+
+func TestRust_Compiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping Rust compilation in short mode")
+	}
+	rustc, err := exec.LookPath("rustc")
+	if err != nil {
+		t.Skip("rustc not available")
+	}
+	dir := t.TempDir()
+	var module bytes.Buffer
+	if err := Rust(&module, "Control", newControlLayout(t)); err != nil {
+		t.Fatal(err)
+	}
+	program := `mod control;
+use control::Control;
+
+fn main() {
+    let mut c = Control(0x2A57);
+    assert_eq!(c.priority(), Control::PRIORITY_HIGH);
+    assert_eq!(c.error_code(), 42);
+    c.set_data(0xBEEF);
+    c.set_priority(Control::PRIORITY_LOW);
+    assert_eq!(c.0, 0xBEEF2A51);
+    assert!(std::panic::catch_unwind(|| Control(0).set_priority(8)).is_err());
+}
+`
+	for name, data := range map[string][]byte{"control.rs": module.Bytes(), "main.rs": []byte(program)} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bin := filepath.Join(dir, "main")
+	if out, err := exec.Command(rustc, "--edition", "2021", "-D", "warnings", "-o", bin, filepath.Join(dir, "main.rs")).CombinedOutput(); err != nil {
+		t.Fatalf("rustc: %v\n%s", err, out)
+	}
+	if out, err := exec.Command(bin).CombinedOutput(); err != nil {
+		t.Errorf("generated module gives wrong values: %v\n%s", err, out)
+	}
+}
+
+func TestRust_Keywords(t *testing.T) {
+	l := bitfield.NewLayout[uint32]()
+	for _, err := range []error{
+		l.Add("type", 0, 2),
+		l.Add("match", 2, 1),
+		l.Add("loop", 3, 1),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := Rust(&buf, "Frame", l); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"pub const fn r#type(self)", "pub fn set_type(", "pub const fn r#match(self)", "pub const TYPE_MASK"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Rust() is missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	self := bitfield.NewLayout[uint32]()
+	if err := self.Add("self", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := Rust(io.Discard, "Frame", self); err == nil {
+		t.Error("Rust() of a field named self succeeded")
+	}
+
+	rustc, err := exec.LookPath("rustc")
+	if err != nil || testing.Short() {
+		return
+	}
+	dir := t.TempDir()
+	program := `mod frame;
+use frame::Frame;
+
+fn main() {
+    let mut f = Frame(0);
+    f.set_type(2);
+    f.set_loop(1);
+    assert_eq!(f.r#type(), 2);
+    assert_eq!(f.r#match(), 0);
+    assert_eq!(f.r#loop(), 1);
+}
+`
+	for name, data := range map[string][]byte{"frame.rs": buf.Bytes(), "main.rs": []byte(program)} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bin := filepath.Join(dir, "main")
+	if out, err := exec.Command(rustc, "--edition", "2021", "-D", "warnings", "-o", bin, filepath.Join(dir, "main.rs")).CombinedOutput(); err != nil {
+		t.Fatalf("rustc: %v\n%s", err, out)
+	}
+	if out, err := exec.Command(bin).CombinedOutput(); err != nil {
+		t.Errorf("generated module gives wrong values: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,42 @@
+/* Code generated by bitfield codegen; DO NOT EDIT. */
+
+#ifndef CONTROL_H
+#define CONTROL_H
+
+#include <stdint.h>
+
+/* active: bits 0:0 */
+#define CONTROL_ACTIVE_SHIFT 0u
+#define CONTROL_ACTIVE_SIZE 1u
+#define CONTROL_ACTIVE_MASK 0x00000001u
+
+/* priority: bits 3:1 */
+#define CONTROL_PRIORITY_SHIFT 1u
+#define CONTROL_PRIORITY_SIZE 3u
+#define CONTROL_PRIORITY_MASK 0x0000000Eu
+#define CONTROL_PRIORITY_LOW 0u
+#define CONTROL_PRIORITY_MEDIUM 1u
+#define CONTROL_PRIORITY_HIGH 3u
+
+/* errorCode: bits 15:8 */
+#define CONTROL_ERROR_CODE_SHIFT 8u
+#define CONTROL_ERROR_CODE_SIZE 8u
+#define CONTROL_ERROR_CODE_MASK 0x0000FF00u
+
+/* data: bits 31:16 */
+#define CONTROL_DATA_SHIFT 16u
+#define CONTROL_DATA_SIZE 16u
+#define CONTROL_DATA_MASK 0xFFFF0000u
+
+typedef union {
+	uint32_t raw;
+	struct {
+		uint32_t active : 1;
+		uint32_t priority : 3;
+		uint32_t : 4;
+		uint32_t error_code : 8;
+		uint32_t data : 16;
+	} bits;
+} control_t;
+
+#endif /* CONTROL_H */
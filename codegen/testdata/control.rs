@@ -0,0 +1,75 @@
+// Code generated by bitfield codegen; DO NOT EDIT.
+
+/// Packed u32 container with the fields of Control.
+#[derive(Clone, Copy, Debug, Default, PartialEq, Eq, Hash)]
+pub struct Control(pub u32);
+
+#[allow(dead_code)]
+impl Control {
+    /// active: bits 0:0
+    pub const ACTIVE_SHIFT: u32 = 0;
+    pub const ACTIVE_MASK: u32 = 0x00000001;
+    pub const ACTIVE_MAX: u32 = 0x1;
+
+    /// priority: bits 3:1
+    pub const PRIORITY_SHIFT: u32 = 1;
+    pub const PRIORITY_MASK: u32 = 0x0000000E;
+    pub const PRIORITY_MAX: u32 = 0x7;
+    pub const PRIORITY_LOW: u32 = 0;
+    pub const PRIORITY_MEDIUM: u32 = 1;
+    pub const PRIORITY_HIGH: u32 = 3;
+
+    /// errorCode: bits 15:8
+    pub const ERROR_CODE_SHIFT: u32 = 8;
+    pub const ERROR_CODE_MASK: u32 = 0x0000FF00;
+    pub const ERROR_CODE_MAX: u32 = 0xFF;
+
+    /// data: bits 31:16
+    pub const DATA_SHIFT: u32 = 16;
+    pub const DATA_MASK: u32 = 0xFFFF0000;
+    pub const DATA_MAX: u32 = 0xFFFF;
+
+    /// Returns the value of the active field.
+    pub const fn active(self) -> u32 {
+        (self.0 & Self::ACTIVE_MASK) >> Self::ACTIVE_SHIFT
+    }
+
+    /// Sets the active field to v. Panics if v does not fit in the field.
+    pub fn set_active(&mut self, v: u32) {
+        assert!(v <= Self::ACTIVE_MAX, "value out of range for field active");
+        self.0 = (self.0 & !Self::ACTIVE_MASK) | (v << Self::ACTIVE_SHIFT);
+    }
+
+    /// Returns the value of the priority field.
+    pub const fn priority(self) -> u32 {
+        (self.0 & Self::PRIORITY_MASK) >> Self::PRIORITY_SHIFT
+    }
+
+    /// Sets the priority field to v. Panics if v does not fit in the field.
+    pub fn set_priority(&mut self, v: u32) {
+        assert!(v <= Self::PRIORITY_MAX, "value out of range for field priority");
+        self.0 = (self.0 & !Self::PRIORITY_MASK) | (v << Self::PRIORITY_SHIFT);
+    }
+
+    /// Returns the value of the errorCode field.
+    pub const fn error_code(self) -> u32 {
+        (self.0 & Self::ERROR_CODE_MASK) >> Self::ERROR_CODE_SHIFT
+    }
+
+    /// Sets the errorCode field to v. Panics if v does not fit in the field.
+    pub fn set_error_code(&mut self, v: u32) {
+        assert!(v <= Self::ERROR_CODE_MAX, "value out of range for field errorCode");
+        self.0 = (self.0 & !Self::ERROR_CODE_MASK) | (v << Self::ERROR_CODE_SHIFT);
+    }
+
+    /// Returns the value of the data field.
+    pub const fn data(self) -> u32 {
+        (self.0 & Self::DATA_MASK) >> Self::DATA_SHIFT
+    }
+
+    /// Sets the data field to v. Panics if v does not fit in the field.
+    pub fn set_data(&mut self, v: u32) {
+        assert!(v <= Self::DATA_MAX, "value out of range for field data");
+        self.0 = (self.0 & !Self::DATA_MASK) | (v << Self::DATA_SHIFT);
+    }
+}
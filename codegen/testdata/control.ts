@@ -0,0 +1,77 @@
+// Code generated by bitfield codegen; DO NOT EDIT.
+
+export enum ControlPriority {
+  Low = 0,
+  Medium = 1,
+  High = 3,
+}
+
+/** Packed 32-bit container with the fields of Control. */
+export class Control {
+  /** active: bits 0:0 */
+  static readonly ACTIVE_SHIFT = 0;
+  static readonly ACTIVE_MASK = 0x00000001;
+  static readonly ACTIVE_MAX = 0x1;
+  /** priority: bits 3:1 */
+  static readonly PRIORITY_SHIFT = 1;
+  static readonly PRIORITY_MASK = 0x0000000E;
+  static readonly PRIORITY_MAX = 0x7;
+  /** errorCode: bits 15:8 */
+  static readonly ERROR_CODE_SHIFT = 8;
+  static readonly ERROR_CODE_MASK = 0x0000FF00;
+  static readonly ERROR_CODE_MAX = 0xFF;
+  /** data: bits 31:16 */
+  static readonly DATA_SHIFT = 16;
+  static readonly DATA_MASK = 0xFFFF0000;
+  static readonly DATA_MAX = 0xFFFF;
+
+  constructor(public value: number = 0) {}
+
+  /** The value of the active field. */
+  get active(): number {
+    return (this.value & Control.ACTIVE_MASK) >>> Control.ACTIVE_SHIFT;
+  }
+
+  set active(v: number) {
+    if (!Number.isInteger(v) || v < 0 || v > Control.ACTIVE_MAX) {
+      throw new RangeError(`value ${v} out of range for field active`);
+    }
+    this.value = ((this.value & ~Control.ACTIVE_MASK) | (v << Control.ACTIVE_SHIFT)) >>> 0;
+  }
+
+  /** The value of the priority field. */
+  get priority(): number {
+    return (this.value & Control.PRIORITY_MASK) >>> Control.PRIORITY_SHIFT;
+  }
+
+  set priority(v: number) {
+    if (!Number.isInteger(v) || v < 0 || v > Control.PRIORITY_MAX) {
+      throw new RangeError(`value ${v} out of range for field priority`);
+    }
+    this.value = ((this.value & ~Control.PRIORITY_MASK) | (v << Control.PRIORITY_SHIFT)) >>> 0;
+  }
+
+  /** The value of the errorCode field. */
+  get errorCode(): number {
+    return (this.value & Control.ERROR_CODE_MASK) >>> Control.ERROR_CODE_SHIFT;
+  }
+
+  set errorCode(v: number) {
+    if (!Number.isInteger(v) || v < 0 || v > Control.ERROR_CODE_MAX) {
+      throw new RangeError(`value ${v} out of range for field errorCode`);
+    }
+    this.value = ((this.value & ~Control.ERROR_CODE_MASK) | (v << Control.ERROR_CODE_SHIFT)) >>> 0;
+  }
+
+  /** The value of the data field. */
+  get data(): number {
+    return (this.value & Control.DATA_MASK) >>> Control.DATA_SHIFT;
+  }
+
+  set data(v: number) {
+    if (!Number.isInteger(v) || v < 0 || v > Control.DATA_MAX) {
+      throw new RangeError(`value ${v} out of range for field data`);
+    }
+    this.value = ((this.value & ~Control.DATA_MASK) | (v << Control.DATA_SHIFT)) >>> 0;
+  }
+}
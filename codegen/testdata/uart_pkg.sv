@@ -0,0 +1,53 @@
+// Code generated by bitfield codegen; DO NOT EDIT.
+
+package uart_pkg;
+
+  // CTRL register
+  localparam logic [31:0] CTRL_OFFSET = 32'h00000000;
+  localparam logic [31:0] CTRL_RESET = 32'h00000002;
+  // enable: bits 0:0, RW
+  localparam int CTRL_ENABLE_SHIFT = 0;
+  localparam int CTRL_ENABLE_WIDTH = 1;
+  localparam logic [31:0] CTRL_ENABLE_MASK = 32'h00000001;
+  // mode: bits 2:1, RW
+  localparam int CTRL_MODE_SHIFT = 1;
+  localparam int CTRL_MODE_WIDTH = 2;
+  localparam logic [31:0] CTRL_MODE_MASK = 32'h00000006;
+  localparam logic [1:0] CTRL_MODE_OFF = 2'h0;
+  localparam logic [1:0] CTRL_MODE_NORMAL = 2'h1;
+  localparam logic [1:0] CTRL_MODE_LOW_POWER = 2'h2;
+  // command: bits 23:16, WO
+  localparam int CTRL_COMMAND_SHIFT = 16;
+  localparam int CTRL_COMMAND_WIDTH = 8;
+  localparam logic [31:0] CTRL_COMMAND_MASK = 32'h00FF0000;
+
+  typedef struct packed {
+    logic [7:0] reserved_24;
+    logic [7:0] command;
+    logic [12:0] reserved_3;
+    logic [1:0] mode;
+    logic enable;
+  } ctrl_t;
+
+  // STATUS register
+  localparam logic [31:0] STATUS_OFFSET = 32'h00000004;
+  localparam logic [31:0] STATUS_RESET = 32'h00000001;
+  // ready: bits 0:0, RO
+  localparam int STATUS_READY_SHIFT = 0;
+  localparam int STATUS_READY_WIDTH = 1;
+  localparam logic [31:0] STATUS_READY_MASK = 32'h00000001;
+  localparam logic STATUS_READY_BUSY = 1'h0;
+  localparam logic STATUS_READY_READY = 1'h1;
+  // errors: bits 7:4, RO
+  localparam int STATUS_ERRORS_SHIFT = 4;
+  localparam int STATUS_ERRORS_WIDTH = 4;
+  localparam logic [31:0] STATUS_ERRORS_MASK = 32'h000000F0;
+
+  typedef struct packed {
+    logic [23:0] reserved_8;
+    logic [3:0] errors;
+    logic [2:0] reserved_1;
+    logic ready;
+  } status_t;
+
+endpackage
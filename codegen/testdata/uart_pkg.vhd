@@ -0,0 +1,56 @@
+-- Code generated by bitfield codegen; DO NOT EDIT.
+
+library ieee;
+use ieee.std_logic_1164.all;
+
+package uart_pkg is
+
+  -- CTRL register
+  constant CTRL_OFFSET : std_logic_vector(31 downto 0) := x"00000000";
+  constant CTRL_RESET : std_logic_vector(31 downto 0) := x"00000002";
+  -- enable: bits 0:0, RW
+  constant CTRL_ENABLE_SHIFT : natural := 0;
+  constant CTRL_ENABLE_WIDTH : natural := 1;
+  constant CTRL_ENABLE_MASK : std_logic_vector(31 downto 0) := x"00000001";
+  -- mode: bits 2:1, RW
+  constant CTRL_MODE_SHIFT : natural := 1;
+  constant CTRL_MODE_WIDTH : natural := 2;
+  constant CTRL_MODE_MASK : std_logic_vector(31 downto 0) := x"00000006";
+  constant CTRL_MODE_OFF : std_logic_vector(1 downto 0) := "00";
+  constant CTRL_MODE_NORMAL : std_logic_vector(1 downto 0) := "01";
+  constant CTRL_MODE_LOW_POWER : std_logic_vector(1 downto 0) := "10";
+  -- command: bits 23:16, WO
+  constant CTRL_COMMAND_SHIFT : natural := 16;
+  constant CTRL_COMMAND_WIDTH : natural := 8;
+  constant CTRL_COMMAND_MASK : std_logic_vector(31 downto 0) := x"00FF0000";
+
+  type ctrl_t is record
+    reserved_24 : std_logic_vector(7 downto 0);
+    command : std_logic_vector(7 downto 0);
+    reserved_3 : std_logic_vector(12 downto 0);
+    mode : std_logic_vector(1 downto 0);
+    enable : std_logic;
+  end record;
+
+  -- STATUS register
+  constant STATUS_OFFSET : std_logic_vector(31 downto 0) := x"00000004";
+  constant STATUS_RESET : std_logic_vector(31 downto 0) := x"00000001";
+  -- ready: bits 0:0, RO
+  constant STATUS_READY_SHIFT : natural := 0;
+  constant STATUS_READY_WIDTH : natural := 1;
+  constant STATUS_READY_MASK : std_logic_vector(31 downto 0) := x"00000001";
+  constant STATUS_READY_BUSY : std_logic := '0';
+  constant STATUS_READY_READY : std_logic := '1';
+  -- errors: bits 7:4, RO
+  constant STATUS_ERRORS_SHIFT : natural := 4;
+  constant STATUS_ERRORS_WIDTH : natural := 4;
+  constant STATUS_ERRORS_MASK : std_logic_vector(31 downto 0) := x"000000F0";
+
+  type status_t is record
+    reserved_8 : std_logic_vector(23 downto 0);
+    errors : std_logic_vector(3 downto 0);
+    reserved_1 : std_logic_vector(2 downto 0);
+    ready : std_logic;
+  end record;
+
+end package;
@@ -0,0 +1,39 @@
+// Code generated by bitfield codegen; DO NOT EDIT.
+
+/** Packed 64-bit container with the fields of Wide. */
+export class Wide {
+  /** lo: bits 7:0 */
+  static readonly LO_SHIFT = 0n;
+  static readonly LO_MASK = 0x00000000000000FFn;
+  static readonly LO_MAX = 0xFFn;
+  /** hi: bits 63:56 */
+  static readonly HI_SHIFT = 56n;
+  static readonly HI_MASK = 0xFF00000000000000n;
+  static readonly HI_MAX = 0xFFn;
+
+  constructor(public value: bigint = 0n) {}
+
+  /** The value of the lo field. */
+  get lo(): bigint {
+    return (this.value & Wide.LO_MASK) >> Wide.LO_SHIFT;
+  }
+
+  set lo(v: bigint) {
+    if (v < 0n || v > Wide.LO_MAX) {
+      throw new RangeError(`value ${v} out of range for field lo`);
+    }
+    this.value = (this.value & ~Wide.LO_MASK) | (v << Wide.LO_SHIFT);
+  }
+
+  /** The value of the hi field. */
+  get hi(): bigint {
+    return (this.value & Wide.HI_MASK) >> Wide.HI_SHIFT;
+  }
+
+  set hi(v: bigint) {
+    if (v < 0n || v > Wide.HI_MAX) {
+      throw new RangeError(`value ${v} out of range for field hi`);
+    }
+    this.value = (this.value & ~Wide.HI_MASK) | (v << Wide.HI_SHIFT);
+  }
+}
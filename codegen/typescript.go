@@ -0,0 +1,69 @@
+package codegen
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// TypeScript writes a TypeScript module describing the layout as the class name.
+// The class wraps the raw container with static SHIFT, MASK and MAX constants
+// for every field, and a getter and a setter property for every field. Fields
+// with enum names also get an exported enum. 32-bit containers are represented
+// as numbers and 64-bit containers as bigints. Setters throw a RangeError if
+// the value does not fit in the field.
+func TypeScript[U uint32 | uint64](w io.Writer, name string, l *bitfield.Layout[U]) error {
+	v, err := newLayoutView(name, l)
+	if err != nil {
+		return err
+	}
+	return tsTemplate.Execute(w, v)
+}
+
+var tsTemplate = template.Must(template.New("ts").Funcs(funcs).Parse(`// Code generated by bitfield codegen; DO NOT EDIT.
+{{- $t := pascal .Name}}{{$d := digits .Width}}{{$big := eq .Width 64}}
+{{- $n := "number"}}{{$s := ""}}{{if $big}}{{$n = "bigint"}}{{$s = "n"}}{{end}}
+{{range .Fields}}{{if .Enum}}
+export enum {{$t}}{{pascal .Name}} {
+{{- range .Enum}}
+  {{pascal .Name}} = {{.Value}},
+{{- end}}
+}
+{{end}}{{end}}
+/** Packed {{.Width}}-bit container with the fields of {{.Name}}. */
+export class {{$t}} {
+{{- range .Fields}}
+  /** {{.Name}}: bits {{msb .}}:{{.Shift}} */
+  static readonly {{upperSnake .Name}}_SHIFT = {{.Shift}}{{$s}};
+  static readonly {{upperSnake .Name}}_MASK = {{hex .Mask $d}}{{$s}};
+  static readonly {{upperSnake .Name}}_MAX = {{hex .Max 0}}{{$s}};
+{{- end}}
+
+  constructor(public value: {{$n}} = 0{{$s}}) {}
+{{range .Fields}}
+  /** The value of the {{.Name}} field. */
+  get {{camel .Name}}(): {{$n}} {
+{{- if $big}}
+    return (this.value & {{$t}}.{{upperSnake .Name}}_MASK) >> {{$t}}.{{upperSnake .Name}}_SHIFT;
+{{- else}}
+    return (this.value & {{$t}}.{{upperSnake .Name}}_MASK) >>> {{$t}}.{{upperSnake .Name}}_SHIFT;
+{{- end}}
+  }
+
+  set {{camel .Name}}(v: {{$n}}) {
+{{- if $big}}
+    if (v < 0n || v > {{$t}}.{{upperSnake .Name}}_MAX) {
+      throw new RangeError(` + "`" + `value ${v} out of range for field {{.Name}}` + "`" + `);
+    }
+    this.value = (this.value & ~{{$t}}.{{upperSnake .Name}}_MASK) | (v << {{$t}}.{{upperSnake .Name}}_SHIFT);
+{{- else}}
+    if (!Number.isInteger(v) || v < 0 || v > {{$t}}.{{upperSnake .Name}}_MAX) {
+      throw new RangeError(` + "`" + `value ${v} out of range for field {{.Name}}` + "`" + `);
+    }
+    this.value = ((this.value & ~{{$t}}.{{upperSnake .Name}}_MASK) | (v << {{$t}}.{{upperSnake .Name}}_SHIFT)) >>> 0;
+{{- end}}
+  }
+{{end -}}
+}
+`))
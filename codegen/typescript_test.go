@@ -0,0 +1,27 @@
+package codegen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+func TestTypeScript(t *testing.T) {
+	var buf bytes.Buffer
+	if err := TypeScript(&buf, "Control", newControlLayout(t)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "control.ts", buf.Bytes())
+}
+
+func TestTypeScript64(t *testing.T) {
+	l := bitfield.NewLayout[uint64]()
+	_ = l.Add("lo", 0, 8)
+	_ = l.Add("hi", 56, 8)
+	var buf bytes.Buffer
+	if err := TypeScript(&buf, "Wide", l); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "wide.ts", buf.Bytes())
+}
@@ -0,0 +1,45 @@
+package bitfield
+
+import "fmt"
+
+// FieldValue pairs a field with the value to store in it, for Compose.
+type FieldValue[U storageType] struct {
+	Field Setter[U]
+	Value uint64
+}
+
+// Compose builds a container from field/value pairs, all of which are validated
+// before the container is returned:
+//
+//	status, err := bitfield.Compose(
+//		bitfield.FieldValue[uint32]{Field: active, Value: 1},
+//		bitfield.FieldValue[uint32]{Field: priority, Value: uint64(High)},
+//	)
+//
+// Bits that belong to none of the fields are 0.
+// Returns an error, naming the position of the offending pair, if a value does
+// not fit in its field or is not one of its allowed values, or if two pairs
+// assign the same field or overlapping fields.
+func Compose[U storageType](pairs ...FieldValue[U]) (U, error) {
+	var container, assigned U
+	for i, p := range pairs {
+		if err := p.Field.wordCheck(p.Value); err != nil {
+			return 0, fmt.Errorf("pair %d: %w", i, err)
+		}
+		mask := p.Field.wordMask()
+		if overlap := assigned & mask; overlap != 0 {
+			for j := range i {
+				if pairs[j].Field.wordMask()&overlap == 0 {
+					continue
+				}
+				if pairs[j].Field.wordMask() == mask {
+					return 0, fmt.Errorf("pair %d assigns the same field as pair %d", i, j)
+				}
+				return 0, fmt.Errorf("pair %d overlaps pair %d in bits 0x%X", i, j, pairs[j].Field.wordMask()&mask)
+			}
+		}
+		assigned |= mask
+		container = p.Field.wordSet(container, p.Value)
+	}
+	return container, nil
+}
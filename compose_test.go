@@ -0,0 +1,61 @@
+package bitfield
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	active := NewFlag[uint32](0)
+	priority := New[Priority, uint32](1, 3)
+	category := New[uint16, uint32](4, 4).WithAllowed(1, 2, 9)
+	wide := New[uint8, uint32](2, 4)
+
+	tests := []struct {
+		name  string
+		pairs []FieldValue[uint32]
+		want  uint32
+		err   string
+	}{
+		{"empty", nil, 0, ""},
+		{"all fields", []FieldValue[uint32]{
+			{Field: active, Value: 1},
+			{Field: priority, Value: uint64(High)},
+			{Field: category, Value: 9},
+		}, 0x95, ""},
+		{"out of range", []FieldValue[uint32]{
+			{Field: active, Value: 1},
+			{Field: priority, Value: 8},
+		}, 0, "pair 1: value 8 out of range"},
+		{"flag out of range", []FieldValue[uint32]{{Field: active, Value: 2}}, 0, "pair 0: value 2 out of range"},
+		{"not allowed", []FieldValue[uint32]{{Field: category, Value: 3}}, 0, "pair 0: value 3: value not allowed"},
+		{"duplicate", []FieldValue[uint32]{
+			{Field: priority, Value: 1},
+			{Field: active, Value: 1},
+			{Field: priority, Value: 2},
+		}, 0, "pair 2 assigns the same field as pair 0"},
+		{"overlap", []FieldValue[uint32]{
+			{Field: priority, Value: 1},
+			{Field: wide, Value: 1},
+		}, 0, "pair 1 overlaps pair 0 in bits 0xC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compose(tt.pairs...)
+			if tt.err == "" {
+				if err != nil || got != tt.want {
+					t.Errorf("Compose() = 0x%X, %v, want 0x%X", got, err, tt.want)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Compose() = 0x%X, %v, want error %q", got, err, tt.err)
+			}
+		})
+	}
+
+	if _, err := Compose(FieldValue[uint32]{Field: category, Value: 4}); !errors.Is(err, ErrValueNotAllowed) {
+		t.Errorf("Compose() error = %v, want %v", err, ErrValueNotAllowed)
+	}
+}
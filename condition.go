@@ -0,0 +1,91 @@
+package bitfield
+
+import (
+	"fmt"
+	"iter"
+)
+
+// fieldCondition makes a field of a Layout present only when another field holds a value.
+type fieldCondition struct {
+	field int // Index into Layout.fields of the field the condition is on
+	value uint64
+}
+
+// SetCondition declares that the field name is present only when the field on
+// holds value, as in wire formats where an extended address is present only when
+// an EXT flag is set. If on is itself conditional, name is present only when on
+// is present too.
+// Fields that are not present are left out of DecodeAll, MarshalText, MarshalJSON
+// and LogValue, EncodeAll, UnmarshalText and UnmarshalJSON reject values for
+// them, their bits must be zero for Validate, and they are shown as unused bits
+// in the diagrams of containers.
+// Returns an error if either field does not exist, on was not added before name,
+// or value does not fit in on.
+func (l *Layout[U]) SetCondition(name, on string, value uint64) error {
+	i, ok := l.index[name]
+	if !ok {
+		return fmt.Errorf("unknown field %q", name)
+	}
+	j, ok := l.index[on]
+	if !ok {
+		return fmt.Errorf("unknown field %q", on)
+	}
+	if j >= i {
+		return fmt.Errorf("field %q: condition on field %q, which was not added before it", name, on)
+	}
+	bf := l.fields[j].field
+	if !bf.IsValid(value) {
+		return fmt.Errorf("field %q: condition on field %q: %w", name, on, bf.invalidValue(value))
+	}
+	l.fields[i].cond = &fieldCondition{field: j, value: value}
+	return nil
+}
+
+// Present reports whether the field name is present in container, that is
+// whether it exists and its condition, if any, holds.
+func (l *Layout[U]) Present(name string, container U) bool {
+	i, ok := l.index[name]
+	return ok && l.present(i, container)
+}
+
+// present reports whether the field at index i is present in container.
+func (l *Layout[U]) present(i int, container U) bool {
+	for c := l.fields[i].cond; c != nil; c = l.fields[c.field].cond {
+		if l.fields[c.field].field.Decode(container) != c.value {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPresent returns an error if one of the named fields is not present in container.
+func (l *Layout[U]) checkPresent(container U, names iter.Seq[string]) error {
+	for name := range names {
+		if i := l.index[name]; !l.present(i, container) {
+			return fmt.Errorf("field %q is only %s", name, l.conditionString(i))
+		}
+	}
+	return nil
+}
+
+// presentMask returns the union of the masks of the fields present in container.
+func (l *Layout[U]) presentMask(container U) U {
+	mask := l.used
+	for i, f := range l.fields {
+		if f.cond != nil && !l.present(i, container) {
+			mask &^= f.field.Mask
+		}
+	}
+	return mask
+}
+
+// conditionString describes the condition of the field at index i, for example
+// `present when "ext" = 1`, with the enum name of the value if it has one.
+func (l *Layout[U]) conditionString(i int) string {
+	c := l.fields[i].cond
+	on := l.fields[c.field]
+	if name, ok := on.field.Name(c.value); ok {
+		return fmt.Sprintf("present when %q = %s", on.name, name)
+	}
+	return fmt.Sprintf("present when %q = %d", on.name, c.value)
+}
@@ -0,0 +1,183 @@
+package bitfield
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+)
+
+// newFrameLayout returns the header of a frame whose extended address is present
+// only when ext is set, whose opcode is present only for control frames, and
+// whose port is present only for the broadcast extended address.
+func newFrameLayout(t *testing.T) *Layout[uint32] {
+	t.Helper()
+	l := NewLayout[uint32]()
+	for _, err := range []error{
+		l.Add("ext", 0, 1),
+		l.Add("kind", 1, 2),
+		l.Add("addr", 8, 8),
+		l.Add("extaddr", 16, 8),
+		l.Add("opcode", 24, 4),
+		l.Add("port", 28, 4),
+		l.SetEnum("kind", map[uint64]string{0: "data", 1: "ctrl"}),
+		l.SetCondition("extaddr", "ext", 1),
+		l.SetCondition("opcode", "kind", 1),
+		l.SetCondition("port", "extaddr", 0xFF),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func TestLayout_Condition(t *testing.T) {
+	l := newFrameLayout(t)
+
+	tests := []struct {
+		name      string
+		container uint32
+		want      map[string]uint64
+	}{
+		{"plain", 0x00000012, map[string]uint64{"ext": 0, "kind": 1, "addr": 0, "opcode": 0}},
+		{"extended", 0x00343401, map[string]uint64{"ext": 1, "kind": 0, "addr": 0x34, "extaddr": 0x34}},
+		{"port", 0x50FF0001, map[string]uint64{"ext": 1, "kind": 0, "addr": 0, "extaddr": 0xFF, "port": 5}},
+		{"port without ext", 0x00FF0000, map[string]uint64{"ext": 0, "kind": 0, "addr": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := l.DecodeAll(tt.container)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("DecodeAll(0x%X) = %v, want %v", tt.container, got, tt.want)
+			}
+			for name := range got {
+				if !l.Present(name, tt.container) {
+					t.Errorf("Present(%q, 0x%X) = false", name, tt.container)
+				}
+			}
+			if container, err := l.EncodeAll(got); err != nil || !maps.Equal(l.DecodeAll(container), got) {
+				t.Errorf("EncodeAll(%v) = 0x%X, %v, which does not decode to the same fields", got, container, err)
+			}
+		})
+	}
+	if l.Present("speed", 0) {
+		t.Error("Present() of an unknown field = true")
+	}
+}
+
+func TestLayout_ConditionEncodeValidate(t *testing.T) {
+	l := newFrameLayout(t)
+
+	if got, err := l.EncodeAll(map[string]uint64{"ext": 1, "extaddr": 0x12}); err != nil || got != 0x00120001 {
+		t.Errorf("EncodeAll() = 0x%X, %v, want 0x120001", got, err)
+	}
+	if _, err := l.EncodeAll(map[string]uint64{"extaddr": 0x12}); err == nil || !strings.Contains(err.Error(), `present when "ext" = 1`) {
+		t.Errorf("EncodeAll() of an absent field error = %v", err)
+	}
+
+	tests := []struct {
+		container uint32
+		want      error
+		normal    uint32
+	}{
+		{0x00120001, nil, 0x00120001},
+		{0x00120000, ErrReservedBits, 0x00000000}, // extaddr is not present
+		{0x50FF0001, nil, 0x50FF0001},
+		{0x50FE0001, ErrReservedBits, 0x00FE0001}, // port is not present
+		{0xFFFFFFFA, ErrReservedBits, 0x0F00FF02}, // Only opcode is conditional and present
+	}
+	for _, tt := range tests {
+		if err := l.Validate(tt.container); !errors.Is(err, tt.want) {
+			t.Errorf("Validate(0x%X) = %v, want %v", tt.container, err, tt.want)
+		}
+		if got := l.Normalize(tt.container); got != tt.normal {
+			t.Errorf("Normalize(0x%X) = 0x%X, want 0x%X", tt.container, got, tt.normal)
+		}
+		if err := l.Validate(l.Normalize(tt.container)); err != nil {
+			t.Errorf("Validate(Normalize(0x%X)) = %v", tt.container, err)
+		}
+	}
+}
+
+func TestLayout_ConditionRender(t *testing.T) {
+	l := newFrameLayout(t)
+	p := l.Bind(0x00340012)
+
+	if got, want := p.String(), "ext=0,kind=ctrl,addr=0,opcode=0"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"ext":false,"kind":"ctrl","addr":0,"opcode":0}`; got != want {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+
+	diagram := l.Diagram()
+	for _, line := range []string{
+		`extaddr present when "ext" = 1`,
+		`opcode present when "kind" = ctrl`,
+		`port present when "extaddr" = 255`,
+	} {
+		if !strings.Contains(diagram, line+"\n") {
+			t.Errorf("Diagram() is missing %q:\n%s", line, diagram)
+		}
+	}
+	if got := p.Diagram(); strings.Contains(got, "extaddr") || !strings.Contains(got, "opcode") {
+		t.Errorf("Packed.Diagram() shows absent fields:\n%s", got)
+	}
+}
+
+func TestLayout_ConditionUnmarshal(t *testing.T) {
+	l := newFrameLayout(t)
+	tests := []struct {
+		json, text string
+		want       uint32
+		wantErr    bool
+	}{
+		{`{"ext":1,"extaddr":5}`, "ext=1,extaddr=5", 0x00050001, false},
+		{`{"extaddr":5}`, "extaddr=5", 0, true},
+		{`{"kind":"data","opcode":2}`, "kind=data,opcode=2", 0, true},
+	}
+	for _, tt := range tests {
+		p := l.Bind(0)
+		err := json.Unmarshal([]byte(tt.json), &p)
+		if (err != nil) != tt.wantErr || p.Value != tt.want {
+			t.Errorf("Unmarshal(%s) = 0x%08X, %v, want 0x%08X, err = %v", tt.json, p.Value, err, tt.want, tt.wantErr)
+		}
+		p = l.Bind(0)
+		err = p.UnmarshalText([]byte(tt.text))
+		if (err != nil) != tt.wantErr || p.Value != tt.want {
+			t.Errorf("UnmarshalText(%q) = 0x%08X, %v, want 0x%08X, err = %v", tt.text, p.Value, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLayout_SetCondition(t *testing.T) {
+	l := newFrameLayout(t)
+
+	tests := []struct {
+		name, field, on string
+		value           uint64
+	}{
+		{"unknown field", "speed", "ext", 1},
+		{"unknown condition field", "addr", "speed", 1},
+		{"condition on itself", "addr", "addr", 1},
+		{"condition on a later field", "addr", "extaddr", 1},
+		{"value out of range", "addr", "ext", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := l.SetCondition(tt.field, tt.on, tt.value); err == nil {
+				t.Error("SetCondition() succeeded")
+			}
+		})
+	}
+
+	if !strings.Contains(l.Signature(), `field "extaddr" 16 8 if "ext"=1`) {
+		t.Errorf("Signature() does not include the condition:\n%s", l.Signature())
+	}
+}
@@ -0,0 +1,200 @@
+// Package configstore persists a set of containers described by bitfield
+// layouts as a blob suitable for EEPROM or flash, in the pattern common to
+// embedded configuration stores: a header identifying the data and its layout
+// version, the containers, and a CRC over everything.
+//
+// A blob is laid out as follows, with header fields and the CRC big-endian:
+//
+//	offset  size  content
+//	0       4     magic number
+//	4       2     layout version
+//	6       2     number of containers n
+//	8       n*w   containers of w bytes each, in the byte order of the store
+//	8+n*w   4     CRC-32 (IEEE) of the preceding bytes
+//
+// Blobs written by an older version of the firmware are upgraded on load by
+// the migrations registered with AddMigration, one version at a time:
+//
+//	store := configstore.New(0xC0F16000, 3, uartLayout, pinLayout)
+//	store.AddMigration(2, func(values []uint32) ([]uint32, error) {
+//		return append(values, defaultPins), nil // Version 3 added the pin register
+//	})
+//	values, err := store.Load(blob)
+package configstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// headerSize and crcSize are the sizes in bytes of the header and CRC of a blob.
+const (
+	headerSize = 8
+	crcSize    = 4
+)
+
+// Errors returned by Store.Load. A blob with the wrong magic number usually
+// means the storage was never written, as erased flash reads as all ones, and
+// callers typically fall back to defaults. A CRC mismatch is reported with
+// bitfield.ErrChecksum.
+var (
+	ErrBadMagic = errors.New("configstore: bad magic number")
+	ErrVersion  = errors.New("configstore: unsupported version")
+)
+
+// Migration upgrades the containers of a blob from one version to the next.
+// It receives the containers as stored and returns those of the next version,
+// which may differ in number.
+type Migration[U uint32 | uint64] func(values []U) ([]U, error)
+
+// Store serializes the containers of a fixed list of layouts.
+// The configuration of a Store must not be changed while it is in use.
+type Store[U uint32 | uint64] struct {
+	magic      uint32
+	version    uint16
+	order      binary.ByteOrder
+	layouts    []*bitfield.Layout[U]
+	migrations map[uint16]Migration[U]
+}
+
+// New creates a Store for blobs identified by magic, holding one container per
+// layout, in order, at the given layout version.
+// Containers are stored big-endian; use SetByteOrder to change it.
+func New[U uint32 | uint64](magic uint32, version uint16, layouts ...*bitfield.Layout[U]) *Store[U] {
+	return &Store[U]{
+		magic:      magic,
+		version:    version,
+		order:      binary.BigEndian,
+		layouts:    layouts,
+		migrations: make(map[uint16]Migration[U]),
+	}
+}
+
+// SetByteOrder sets the byte order of the containers in the blob.
+// The header and the CRC are always big-endian.
+func (s *Store[U]) SetByteOrder(order binary.ByteOrder) {
+	s.order = order
+}
+
+// Version returns the layout version of the blobs written by the store.
+func (s *Store[U]) Version() uint16 {
+	return s.version
+}
+
+// Size returns the size in bytes of the blobs written by the store.
+func (s *Store[U]) Size() int {
+	return headerSize + len(s.layouts)*containerSize[U]() + crcSize
+}
+
+// containerSize returns the size in bytes of a container of type U.
+func containerSize[U uint32 | uint64]() int {
+	return len(bitfield.AppendBinary(nil, U(0), binary.BigEndian))
+}
+
+// AddMigration registers the migration of blobs of version from to version from+1.
+// Returns an error if from is not older than the version of the store or a
+// migration is already registered for it.
+func (s *Store[U]) AddMigration(from uint16, m Migration[U]) error {
+	if from >= s.version {
+		return fmt.Errorf("configstore: migration from version %d, store is at version %d", from, s.version)
+	}
+	if _, ok := s.migrations[from]; ok {
+		return fmt.Errorf("configstore: migration from version %d already registered", from)
+	}
+	s.migrations[from] = m
+	return nil
+}
+
+// Save encodes values, one container per layout of the store, into a blob.
+// Returns an error if the number of values does not match the layouts or a
+// value does not validate against its layout.
+func (s *Store[U]) Save(values []U) ([]byte, error) {
+	if err := s.validate(values); err != nil {
+		return nil, err
+	}
+	b := make([]byte, 0, s.Size())
+	b = binary.BigEndian.AppendUint32(b, s.magic)
+	b = binary.BigEndian.AppendUint16(b, s.version)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(values)))
+	for _, value := range values {
+		b = bitfield.AppendBinary(b, value, s.order)
+	}
+	return binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(b)), nil
+}
+
+// Load decodes a blob, migrating it to the version of the store if it was
+// written at an older version, and returns the containers bound to their layouts.
+// Bytes after the CRC are ignored, so a whole storage page can be passed.
+// Returns an error wrapping ErrBadMagic, bitfield.ErrChecksum or ErrVersion if
+// the blob is not for this store, is corrupted, or has a version that is newer
+// than the store or has no migration path; and an error if a container does not
+// validate against its layout after migration.
+func (s *Store[U]) Load(blob []byte) ([]bitfield.Packed[U], error) {
+	if len(blob) < headerSize+crcSize {
+		return nil, fmt.Errorf("configstore: blob of %d bytes too short for header", len(blob))
+	}
+	if magic := binary.BigEndian.Uint32(blob); magic != s.magic {
+		return nil, fmt.Errorf("%w %#08x, want %#08x", ErrBadMagic, magic, s.magic)
+	}
+	version := binary.BigEndian.Uint16(blob[4:])
+	count := int(binary.BigEndian.Uint16(blob[6:]))
+	size := containerSize[U]()
+	end := headerSize + count*size
+	if len(blob) < end+crcSize {
+		return nil, fmt.Errorf("configstore: blob of %d bytes too short for %d containers", len(blob), count)
+	}
+	if sum, want := crc32.ChecksumIEEE(blob[:end]), binary.BigEndian.Uint32(blob[end:]); sum != want {
+		return nil, fmt.Errorf("configstore: CRC %#08x, stored %#08x: %w", sum, want, bitfield.ErrChecksum)
+	}
+	values := make([]U, count)
+	for i := range values {
+		values[i], _ = bitfield.UnmarshalBinary[U](blob[headerSize+i*size:headerSize+(i+1)*size], s.order)
+	}
+	values, err := s.migrate(version, values)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(values); err != nil {
+		return nil, err
+	}
+	packed := make([]bitfield.Packed[U], len(values))
+	for i, value := range values {
+		packed[i] = s.layouts[i].Bind(value)
+	}
+	return packed, nil
+}
+
+// migrate applies the migrations from version to the version of the store.
+func (s *Store[U]) migrate(version uint16, values []U) ([]U, error) {
+	if version > s.version {
+		return nil, fmt.Errorf("%w %d, newer than %d", ErrVersion, version, s.version)
+	}
+	for ; version < s.version; version++ {
+		m, ok := s.migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("%w %d, no migration to version %d", ErrVersion, version, version+1)
+		}
+		var err error
+		if values, err = m(values); err != nil {
+			return nil, fmt.Errorf("configstore: migration from version %d: %w", version, err)
+		}
+	}
+	return values, nil
+}
+
+// validate checks that values has one valid container per layout.
+func (s *Store[U]) validate(values []U) error {
+	if len(values) != len(s.layouts) {
+		return fmt.Errorf("configstore: %d containers, want %d", len(values), len(s.layouts))
+	}
+	for i, value := range values {
+		if err := s.layouts[i].Validate(value); err != nil {
+			return fmt.Errorf("configstore: container %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package configstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+func newLayouts(t *testing.T) (uart, pins *bitfield.Layout[uint32]) {
+	t.Helper()
+	uart = bitfield.NewLayout[uint32]()
+	if err := uart.Add("enable", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := uart.Add("baud", 4, 4); err != nil {
+		t.Fatal(err)
+	}
+	pins = bitfield.NewLayout[uint32]()
+	if err := pins.Add("tx", 0, 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := pins.Add("rx", 8, 8); err != nil {
+		t.Fatal(err)
+	}
+	return uart, pins
+}
+
+func TestStore_SaveLoad(t *testing.T) {
+	uart, pins := newLayouts(t)
+	s := New(0xC0F16000, 2, uart, pins)
+
+	blob, err := s.Save([]uint32{0x31, 0x0504})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		0xC0, 0xF1, 0x60, 0x00, // Magic
+		0x00, 0x02, // Version
+		0x00, 0x02, // Count
+		0x00, 0x00, 0x00, 0x31,
+		0x00, 0x00, 0x05, 0x04,
+	}
+	if len(blob) != s.Size() || !bytes.Equal(blob[:len(want)], want) {
+		t.Fatalf("Save() = %x, want %x followed by the CRC", blob, want)
+	}
+
+	got, err := s.Load(append(blob, 0xFF, 0xFF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Value != 0x31 || got[1].Value != 0x0504 {
+		t.Fatalf("Load() = %v", got)
+	}
+	if baud, _ := got[0].Get("baud"); baud != 3 {
+		t.Errorf("baud = %d, want 3", baud)
+	}
+	if rx, _ := got[1].Get("rx"); rx != 5 {
+		t.Errorf("rx = %d, want 5", rx)
+	}
+}
+
+func TestStore_ByteOrder(t *testing.T) {
+	uart, _ := newLayouts(t)
+	s := New(1, 1, uart)
+	s.SetByteOrder(binary.LittleEndian)
+
+	blob, err := s.Save([]uint32{0x31})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x31, 0, 0, 0}; !bytes.Equal(blob[headerSize:headerSize+4], want) {
+		t.Errorf("container bytes = %x, want %x", blob[headerSize:headerSize+4], want)
+	}
+	if got, err := s.Load(blob); err != nil || got[0].Value != 0x31 {
+		t.Errorf("Load() = %v, %v", got, err)
+	}
+}
+
+func TestStore_LoadErrors(t *testing.T) {
+	uart, pins := newLayouts(t)
+	s := New(0xC0F16000, 2, uart, pins)
+	blob, err := s.Save([]uint32{0x31, 0x0504})
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupt := bytes.Clone(blob)
+	corrupt[9] ^= 0x10
+	erased := bytes.Repeat([]byte{0xFF}, s.Size())
+	newer, _ := New(0xC0F16000, 3, uart, pins).Save([]uint32{0x31, 0x0504})
+	older, _ := New(0xC0F16000, 1, uart, pins).Save([]uint32{0x31, 0x0504})
+
+	tests := []struct {
+		name string
+		blob []byte
+		want error
+	}{
+		{"erased", erased, ErrBadMagic},
+		{"corrupt", corrupt, bitfield.ErrChecksum},
+		{"newer version", newer, ErrVersion},
+		{"no migration", older, ErrVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Load(tt.blob); !errors.Is(err, tt.want) {
+				t.Errorf("Load() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	for _, n := range []int{0, headerSize + 3, len(blob) - 1} {
+		if _, err := s.Load(blob[:n]); err == nil {
+			t.Errorf("Load() of %d bytes succeeded", n)
+		}
+	}
+}
+
+func TestStore_Migration(t *testing.T) {
+	uart, pins := newLayouts(t)
+	v1 := New(0xC0F16000, 1, uart)
+	blob, err := v1.Save([]uint32{0x21})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(0xC0F16000, 3, uart, pins)
+	if err := s.AddMigration(1, func(values []uint32) ([]uint32, error) {
+		return append(values, 0x0201), nil // Version 2 added the pins
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddMigration(2, func(values []uint32) ([]uint32, error) {
+		values[0] |= 1 // Version 3 enables the UART by default
+		return values, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Load(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Value != 0x21 || got[1].Value != 0x0201 {
+		t.Errorf("Load() = %v, want [0x21 0x201]", got)
+	}
+
+	if err := s.AddMigration(2, nil); err == nil {
+		t.Error("AddMigration() of a registered version succeeded")
+	}
+	if err := s.AddMigration(3, nil); err == nil {
+		t.Error("AddMigration() from the current version succeeded")
+	}
+}
+
+func TestStore_MigrationError(t *testing.T) {
+	uart, _ := newLayouts(t)
+	blob, err := New(1, 1, uart).Save([]uint32{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(1, 2, uart)
+	fail := errors.New("unsupported setting")
+	if err := s.AddMigration(1, func([]uint32) ([]uint32, error) { return nil, fail }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load(blob); !errors.Is(err, fail) {
+		t.Errorf("Load() error = %v, want %v", err, fail)
+	}
+}
+
+func TestStore_Validate(t *testing.T) {
+	uart, pins := newLayouts(t)
+	s := New(1, 1, uart, pins)
+
+	if _, err := s.Save([]uint32{0x31}); err == nil {
+		t.Error("Save() with a missing container succeeded")
+	}
+	if _, err := s.Save([]uint32{0x31, 0x10000}); !errors.Is(err, bitfield.ErrReservedBits) {
+		t.Errorf("Save() error = %v, want %v", err, bitfield.ErrReservedBits)
+	}
+
+	// A blob with a valid CRC but fewer containers than layouts is rejected
+	blob, err := New(1, 1, uart).Save([]uint32{0x31})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load(blob); err == nil {
+		t.Error("Load() with a missing container succeeded")
+	}
+}
@@ -0,0 +1,53 @@
+package bitfield
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// CountOnes returns the number of set bits within the field, ignoring all other
+// bits of the container. Fields of a Layout are counted the same way, as in
+// l.Field("PENDING") followed by CountOnes.
+func (bf BitField[T, U]) CountOnes(container U) int {
+	return bits.OnesCount64(uint64(container & bf.Mask))
+}
+
+// FirstSet returns the position of the lowest set bit within the field,
+// counted from the field's least significant bit.
+// The second return value is false if no bit of the field is set.
+func (bf BitField[T, U]) FirstSet(container U) (uint, bool) {
+	return firstOne(uint64(container&bf.Mask), bf.Shift)
+}
+
+// FirstZero returns the position of the lowest cleared bit within the field,
+// counted from the field's least significant bit.
+// The second return value is false if every bit of the field is set.
+func (bf BitField[T, U]) FirstZero(container U) (uint, bool) {
+	return firstOne(uint64(^container&bf.Mask), bf.Shift)
+}
+
+// firstOne returns the position of the lowest set bit of masked relative to shift.
+func firstOne(masked uint64, shift uint) (uint, bool) {
+	if masked == 0 {
+		return 0, false
+	}
+	return uint(bits.TrailingZeros64(masked)) - shift, true
+}
+
+// Ones returns an iterator over the positions of the set bits of the container,
+// in ascending order.
+func Ones[U storageType](container U) iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for w := uint64(container); w != 0; w &= w - 1 {
+			if !yield(uint(bits.TrailingZeros64(w))) {
+				return
+			}
+		}
+	}
+}
+
+// Zeros returns an iterator over the positions of the cleared bits of the container,
+// in ascending order.
+func Zeros[U storageType](container U) iter.Seq[uint] {
+	return Ones(^container)
+}
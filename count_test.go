@@ -0,0 +1,70 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBitField_CountOnes(t *testing.T) {
+	bf := New[uint8, uint32](8, 8)
+	tests := []struct {
+		container uint32
+		want      int
+	}{
+		{0x00000000, 0},
+		{0xFFFF00FF, 0},
+		{0x00000100, 1},
+		{0x0000A500, 4},
+		{0xFFFFFFFF, 8},
+	}
+
+	for _, tt := range tests {
+		if got := bf.CountOnes(tt.container); got != tt.want {
+			t.Errorf("CountOnes(0x%08X) = %d, want %d", tt.container, got, tt.want)
+		}
+	}
+}
+
+func TestBitField_FirstSet(t *testing.T) {
+	bf := New[uint8, uint64](60, 4)
+	tests := []struct {
+		name      string
+		container uint64
+		wantSet   uint
+		okSet     bool
+		wantZero  uint
+		okZero    bool
+	}{
+		{"empty field", 0x0FFFFFFFFFFFFFFF, 0, false, 0, true},
+		{"full field", 0xF000000000000000, 0, true, 0, false},
+		{"lowest bit", 0x1000000000000000, 0, true, 1, true},
+		{"top bit", 0x8000000000000001, 3, true, 0, true},
+		{"mixed", 0xB000000000000000, 0, true, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := bf.FirstSet(tt.container); got != tt.wantSet || ok != tt.okSet {
+				t.Errorf("FirstSet(0x%X) = %d, %v, want %d, %v", tt.container, got, ok, tt.wantSet, tt.okSet)
+			}
+			if got, ok := bf.FirstZero(tt.container); got != tt.wantZero || ok != tt.okZero {
+				t.Errorf("FirstZero(0x%X) = %d, %v, want %d, %v", tt.container, got, ok, tt.wantZero, tt.okZero)
+			}
+		})
+	}
+}
+
+func TestOnes(t *testing.T) {
+	if got, want := slices.Collect(Ones[uint32](0x80000412)), []uint{1, 4, 10, 31}; !slices.Equal(got, want) {
+		t.Errorf("Ones(0x80000412) = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(Zeros[uint64](^uint64(0x9))), []uint{0, 3}; !slices.Equal(got, want) {
+		t.Errorf("Zeros(^0x9) = %v, want %v", got, want)
+	}
+	if got := slices.Collect(Ones[uint32](0)); len(got) != 0 {
+		t.Errorf("Ones(0) = %v, want none", got)
+	}
+	if got := len(slices.Collect(Zeros[uint32](0))); got != 32 {
+		t.Errorf("Zeros[uint32](0) yielded %d positions, want 32", got)
+	}
+}
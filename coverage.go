@@ -0,0 +1,216 @@
+package bitfield
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxCoverageValueBits is the widest field whose default coverage has one bin per value.
+const maxCoverageValueBits = 4
+
+// CoverageBin is a range of values of a field that tests are expected to exercise,
+// in the style of the coverage bins of constrained-random hardware verification.
+type CoverageBin struct {
+	Name   string // Name used in reports
+	Lo, Hi uint64 // Inclusive range of values that hit the bin
+}
+
+// CoverageHole is a bin that was never hit.
+type CoverageHole struct {
+	Field string
+	Bin   CoverageBin
+}
+
+// Coverage records which values of the fields of a Layout were observed during
+// tests, and reports the bins that were never hit. It helps driver test suites
+// show that they exercised every mode of a device.
+// A Coverage is safe for concurrent use.
+type Coverage[U storageType] struct {
+	mu       sync.Mutex
+	layout   *Layout[U]
+	bins     [][]CoverageBin // Bins of each field, in layout order
+	hits     [][]int         // Hit counts of each bin
+	observed int
+}
+
+// NewCoverage creates a Coverage for the fields of layout with default bins:
+// one bin per named value for enum fields, one bin per allowed value for fields
+// restricted with SetAllowed, one bin per value for other fields of up to 4 bits,
+// and bins for 0, the maximum and the values in between for wider fields.
+// Fields added to the layout after the Coverage is created are not tracked.
+func NewCoverage[U storageType](layout *Layout[U]) *Coverage[U] {
+	c := &Coverage[U]{layout: layout}
+	for _, f := range layout.fields {
+		bins := defaultCoverageBins(f.field)
+		c.bins = append(c.bins, bins)
+		c.hits = append(c.hits, make([]int, len(bins)))
+	}
+	return c
+}
+
+// defaultCoverageBins returns the default bins of a field.
+func defaultCoverageBins[U storageType](bf BitField[uint64, U]) []CoverageBin {
+	var bins []CoverageBin
+	if names := bf.Enum(); len(names) > 0 {
+		for _, value := range slices.Sorted(maps.Keys(names)) {
+			bins = append(bins, CoverageBin{Name: names[value], Lo: value, Hi: value})
+		}
+		return bins
+	}
+	if allowed := bf.Allowed(); allowed != nil {
+		for _, value := range allowed {
+			bins = append(bins, CoverageBin{Name: fmt.Sprint(value), Lo: value, Hi: value})
+		}
+		return bins
+	}
+	hi := bf.Max()
+	if bf.Size <= maxCoverageValueBits {
+		for value := range hi + 1 {
+			bins = append(bins, CoverageBin{Name: fmt.Sprint(value), Lo: value, Hi: value})
+		}
+		return bins
+	}
+	return []CoverageBin{
+		{Name: "0", Lo: 0, Hi: 0},
+		{Name: fmt.Sprintf("1..%d", hi-1), Lo: 1, Hi: hi - 1},
+		{Name: fmt.Sprint(hi), Lo: hi, Hi: hi},
+	}
+}
+
+// SetBins replaces the bins of a field, discarding the hits recorded for it.
+// Bins may overlap; an observed value hits every bin that contains it.
+// Returns an error if the field does not exist, bins is empty, or a bin is
+// empty or does not fit in the field.
+func (c *Coverage[U]) SetBins(field string, bins []CoverageBin) error {
+	i, ok := c.layout.index[field]
+	if !ok {
+		return fmt.Errorf("unknown field %q", field)
+	}
+	if len(bins) == 0 {
+		return fmt.Errorf("field %q: no coverage bins", field)
+	}
+	bf := c.layout.fields[i].field
+	for _, bin := range bins {
+		if bin.Lo > bin.Hi || bin.Hi > bf.Max() {
+			return fmt.Errorf("field %q: coverage bin %q [%d, %d] is empty or out of range, max %v", field, bin.Name, bin.Lo, bin.Hi, bf.Max())
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bins[i] = append([]CoverageBin(nil), bins...)
+	c.hits[i] = make([]int, len(bins))
+	return nil
+}
+
+// Observe records the values of every field of container.
+func (c *Coverage[U]) Observe(container U) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observed++
+	for i, f := range c.layout.fields[:len(c.bins)] {
+		value := f.field.Decode(container)
+		for j, bin := range c.bins[i] {
+			if value >= bin.Lo && value <= bin.Hi {
+				c.hits[i][j]++
+			}
+		}
+	}
+}
+
+// Hits returns the number of observed containers that hit each bin of a field,
+// keyed by bin name. Returns nil if the field does not exist.
+func (c *Coverage[U]) Hits(field string) map[string]int {
+	i, ok := c.layout.index[field]
+	if !ok || i >= len(c.bins) {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hits := make(map[string]int, len(c.bins[i]))
+	for j, bin := range c.bins[i] {
+		hits[bin.Name] += c.hits[i][j]
+	}
+	return hits
+}
+
+// Holes returns the bins that were never hit, in layout and bin order.
+func (c *Coverage[U]) Holes() []CoverageHole {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var holes []CoverageHole
+	for i, bins := range c.bins {
+		for j, bin := range bins {
+			if c.hits[i][j] == 0 {
+				holes = append(holes, CoverageHole{Field: c.layout.fields[i].name, Bin: bin})
+			}
+		}
+	}
+	return holes
+}
+
+// Percent returns the percentage of bins that were hit, from 0 to 100.
+// A layout without fields is fully covered.
+func (c *Coverage[U]) Percent() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hit, total := c.count()
+	return percent(hit, total)
+}
+
+// count returns the number of bins that were hit and the total number of bins.
+// The caller must hold c.mu.
+func (c *Coverage[U]) count() (hit, total int) {
+	for i := range c.bins {
+		for _, n := range c.hits[i] {
+			total++
+			if n > 0 {
+				hit++
+			}
+		}
+	}
+	return hit, total
+}
+
+// percent returns hit as a percentage of total, or 100 if total is 0.
+func percent(hit, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return 100 * float64(hit) / float64(total)
+}
+
+// Report returns a human-readable summary of the coverage with one line per
+// field, listing the bins that were never hit:
+//
+//	coverage: 6/8 bins (75.0%) over 2 containers
+//	  enable  2/2
+//	  mode    2/3  missing: turbo
+//	  count   2/3  missing: 255
+func (c *Coverage[U]) Report() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	width := 0
+	for i := range c.bins {
+		width = max(width, len(c.layout.fields[i].name))
+	}
+	hit, total := c.count()
+	var b strings.Builder
+	fmt.Fprintf(&b, "coverage: %d/%d bins (%.1f%%) over %d containers\n", hit, total, percent(hit, total), c.observed)
+	for i, bins := range c.bins {
+		var missing []string
+		for j, bin := range bins {
+			if c.hits[i][j] == 0 {
+				missing = append(missing, bin.Name)
+			}
+		}
+		fmt.Fprintf(&b, "  %-*s  %d/%d", width, c.layout.fields[i].name, len(bins)-len(missing), len(bins))
+		if len(missing) > 0 {
+			fmt.Fprintf(&b, "  missing: %s", strings.Join(missing, ", "))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package bitfield
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestCoverage_DefaultBins(t *testing.T) {
+	c := NewCoverage(newStatusLayout(t))
+
+	category := make([]CoverageBin, 16)
+	for v := range category {
+		category[v] = CoverageBin{fmt.Sprint(v), uint64(v), uint64(v)}
+	}
+	want := [][]CoverageBin{
+		{{"0", 0, 0}, {"1", 1, 1}},
+		{{"Low", 0, 0}, {"Medium", 1, 1}, {"High", 3, 3}},
+		category,
+		{{"0", 0, 0}, {"1..254", 1, 254}, {"255", 255, 255}},
+	}
+	if !reflect.DeepEqual(c.bins, want) {
+		t.Errorf("bins = %v, want %v", c.bins, want)
+	}
+}
+
+func TestCoverage_Observe(t *testing.T) {
+	c := NewCoverage(newStatusLayout(t))
+	c.Observe(0x00000000) // active 0, Low, category 0, error 0
+	c.Observe(0x00000523) // active 1, Medium, category 2, error 5
+	c.Observe(0x00000723) // active 1, Medium, category 2, error 7
+
+	if got, want := c.Hits("error"), map[string]int{"0": 1, "1..254": 2, "255": 0}; !maps.Equal(got, want) {
+		t.Errorf("Hits(error) = %v, want %v", got, want)
+	}
+	if got := c.Hits("missing"); got != nil {
+		t.Errorf("Hits(missing) = %v, want nil", got)
+	}
+
+	want := []CoverageHole{{Field: "priority", Bin: CoverageBin{"High", 3, 3}}}
+	for v := range uint64(16) {
+		if v != 0 && v != 2 {
+			want = append(want, CoverageHole{Field: "category", Bin: CoverageBin{fmt.Sprint(v), v, v}})
+		}
+	}
+	want = append(want, CoverageHole{Field: "error", Bin: CoverageBin{"255", 255, 255}})
+	if got := c.Holes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Holes() = %v, want %v", got, want)
+	}
+	if got, want := c.Percent(), 100*8/24.0; got != want {
+		t.Errorf("Percent() = %v, want %v", got, want)
+	}
+
+	for v := range uint32(16) {
+		c.Observe(0x0000FF07 | v<<4) // active 1, High, category v, error 255
+	}
+	if holes := c.Holes(); len(holes) != 0 {
+		t.Errorf("Holes() = %v after covering every bin, want none", holes)
+	}
+}
+
+func TestCoverage_SetBins(t *testing.T) {
+	c := NewCoverage(newStatusLayout(t))
+	c.Observe(0x00000100)
+
+	bins := []CoverageBin{{"small", 0, 15}, {"one", 1, 1}, {"large", 16, 255}}
+	if err := c.SetBins("error", bins); err != nil {
+		t.Fatalf("SetBins: %v", err)
+	}
+	if got, want := c.Hits("error"), map[string]int{"small": 0, "one": 0, "large": 0}; !maps.Equal(got, want) {
+		t.Errorf("Hits(error) after SetBins = %v, want %v", got, want)
+	}
+	c.Observe(0x00000100)
+	if got, want := c.Hits("error"), map[string]int{"small": 1, "one": 1, "large": 0}; !maps.Equal(got, want) {
+		t.Errorf("Hits(error) = %v, want %v", got, want)
+	}
+
+	errTests := []struct {
+		name  string
+		field string
+		bins  []CoverageBin
+	}{
+		{"unknown field", "missing", bins},
+		{"no bins", "error", nil},
+		{"empty bin", "error", []CoverageBin{{"empty", 5, 4}}},
+		{"out of range", "error", []CoverageBin{{"big", 0, 256}}},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.SetBins(tt.field, tt.bins); err == nil {
+				t.Error("SetBins() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestCoverage_Report(t *testing.T) {
+	c := NewCoverage(newStatusLayout(t))
+	c.Observe(0x00000000)
+	c.Observe(0x00000523)
+
+	want := "coverage: 8/24 bins (33.3%) over 2 containers\n" +
+		"  active    2/2\n" +
+		"  priority  2/3  missing: High\n" +
+		"  category  2/16  missing: 1, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15\n" +
+		"  error     2/3  missing: 255\n"
+	if got := c.Report(); got != want {
+		t.Errorf("Report() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCoverage_Empty(t *testing.T) {
+	c := NewCoverage(NewLayout[uint64]())
+	c.Observe(0xFF)
+	if got := c.Percent(); got != 100 {
+		t.Errorf("Percent() = %v, want 100", got)
+	}
+}
+
+func TestCoverage_AllowedBins(t *testing.T) {
+	l := newStatusLayout(t)
+	if err := l.SetAllowed("error", 0, 16, 255); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCoverage(l)
+
+	want := []CoverageBin{{"0", 0, 0}, {"16", 16, 16}, {"255", 255, 255}}
+	if !reflect.DeepEqual(c.bins[3], want) {
+		t.Errorf("bins of error = %v, want %v", c.bins[3], want)
+	}
+}
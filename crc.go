@@ -0,0 +1,105 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math/bits"
+)
+
+// CRC describes a cyclic redundancy check in the parameter model of the CRC catalogue,
+// so custom checksums used by device and frame formats can be defined by their parameters.
+type CRC struct {
+	Width   uint   // Number of bits of the checksum, from 1 to 64
+	Poly    uint64 // Generator polynomial, without the leading term
+	Init    uint64 // Initial register value
+	Reflect bool   // Process input bytes least significant bit first and reflect the result
+	XorOut  uint64 // Value XORed into the final checksum
+}
+
+// Common CRC definitions.
+var (
+	CRC8SMBus       = CRC{Width: 8, Poly: 0x07}
+	CRC8Maxim       = CRC{Width: 8, Poly: 0x31, Reflect: true}
+	CRC16CCITTFalse = CRC{Width: 16, Poly: 0x1021, Init: 0xFFFF}
+	CRC16Modbus     = CRC{Width: 16, Poly: 0x8005, Init: 0xFFFF, Reflect: true}
+)
+
+// Checksum computes the CRC of data.
+// Panics if Width is not between 1 and 64.
+func (c CRC) Checksum(data []byte) uint64 {
+	if c.Width == 0 || c.Width > 64 {
+		panic(fmt.Sprintf("invalid CRC width %d", c.Width))
+	}
+	mask := lowBits(c.Width)
+	top := uint64(1) << (c.Width - 1)
+	crc := c.Init & mask
+	for _, b := range data {
+		if c.Reflect {
+			b = bits.Reverse8(b)
+		}
+		for i := 7; i >= 0; i-- {
+			feedback := crc&top != 0
+			if b>>i&1 != 0 {
+				feedback = !feedback
+			}
+			crc = crc << 1 & mask
+			if feedback {
+				crc ^= c.Poly & mask
+			}
+		}
+	}
+	if c.Reflect {
+		crc = bits.Reverse64(crc) >> (64 - c.Width)
+	}
+	return (crc ^ c.XorOut) & mask
+}
+
+// CRC32 returns a checksum function computing the CRC-32 of data with a table
+// from hash/crc32, such as crc32.IEEETable or crc32.MakeTable(crc32.Castagnoli).
+func CRC32(table *crc32.Table) func(data []byte) uint64 {
+	return func(data []byte) uint64 {
+		return uint64(crc32.Checksum(data, table))
+	}
+}
+
+// CRCField is a field holding a checksum of the rest of its container, as in
+// frames and records that protect their payload with a CRC.
+// The checksum is computed over the container with the field cleared, encoded
+// in the byte order Order; checksums wider than the field are truncated to
+// their low bits.
+type CRCField[U storageType] struct {
+	Field BitField[uint64, U]      // Field holding the checksum
+	Sum   func(data []byte) uint64 // Checksum function, such as CRC.Checksum or CRC32
+	Order binary.ByteOrder         // Byte order of the checksummed data, nil for big-endian
+}
+
+// NewCRCField creates a CRCField storing the checksum computed by sum in field.
+func NewCRCField[U storageType](field BitField[uint64, U], sum func(data []byte) uint64) CRCField[U] {
+	return CRCField[U]{Field: field, Sum: sum}
+}
+
+// Compute returns the checksum of container, ignoring the current value of the field.
+func (cf CRCField[U]) Compute(container U) uint64 {
+	order := cf.Order
+	if order == nil {
+		order = binary.BigEndian
+	}
+	var buf [8]byte
+	data := AppendBinary(buf[:0], cf.Field.Clear(container), order)
+	return cf.Sum(data) & cf.Field.Max()
+}
+
+// Seal returns container with the field set to its checksum.
+func (cf CRCField[U]) Seal(container U) U {
+	return cf.Field.Update(container, cf.Compute(container))
+}
+
+// Verify checks the checksum held by container.
+// Returns an error wrapping ErrChecksum if it does not match the rest of the container.
+func (cf CRCField[U]) Verify(container U) error {
+	if got, want := cf.Field.Decode(container), cf.Compute(container); got != want {
+		return fmt.Errorf("%w: got 0x%X, want 0x%X", ErrChecksum, got, want)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+)
+
+func TestCRC_Checksum(t *testing.T) {
+	check := []byte("123456789")
+	tests := []struct {
+		name string
+		crc  CRC
+		want uint64
+	}{
+		{"CRC-8/SMBUS", CRC8SMBus, 0xF4},
+		{"CRC-8/MAXIM", CRC8Maxim, 0xA1},
+		{"CRC-16/CCITT-FALSE", CRC16CCITTFalse, 0x29B1},
+		{"CRC-16/MODBUS", CRC16Modbus, 0x4B37},
+		{"CRC-3/GSM", CRC{Width: 3, Poly: 0x3, XorOut: 0x7}, 0x4},
+		{"CRC-32/ISO-HDLC", CRC{Width: 32, Poly: 0x04C11DB7, Init: 0xFFFFFFFF, Reflect: true, XorOut: 0xFFFFFFFF}, 0xCBF43926},
+		{"CRC-64/XZ", CRC{Width: 64, Poly: 0x42F0E1EBA9EA3693, Init: ^uint64(0), Reflect: true, XorOut: ^uint64(0)}, 0x995DC9BBDF1939FA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.crc.Checksum(check); got != tt.want {
+				t.Errorf("Checksum(%q) = 0x%X, want 0x%X", check, got, tt.want)
+			}
+		})
+	}
+	if got := CRC32(crc32.IEEETable)(check); got != 0xCBF43926 {
+		t.Errorf("CRC32(IEEE)(%q) = 0x%X, want 0xCBF43926", check, got)
+	}
+}
+
+func TestCRCField_Seal(t *testing.T) {
+	// A 32-bit frame with 24 bits of payload and a CRC-8 in the low byte.
+	cf := NewCRCField(New[uint64, uint32](0, 8), CRC8SMBus.Checksum)
+
+	frame := cf.Seal(0x12345600)
+	if want := CRC8SMBus.Checksum([]byte{0x12, 0x34, 0x56, 0x00}); uint64(frame&0xFF) != want {
+		t.Errorf("Seal(0x12345600) = 0x%08X, want CRC 0x%02X", frame, want)
+	}
+	if frame&^0xFF != 0x12345600 {
+		t.Errorf("Seal(0x12345600) = 0x%08X changed the payload", frame)
+	}
+	if err := cf.Verify(frame); err != nil {
+		t.Errorf("Verify(0x%08X) = %v", frame, err)
+	}
+	if resealed := cf.Seal(frame); resealed != frame {
+		t.Errorf("Seal(0x%08X) = 0x%08X, want unchanged", frame, resealed)
+	}
+
+	for bit := range uint(32) {
+		corrupted := frame ^ 1<<bit
+		if err := cf.Verify(corrupted); !errors.Is(err, ErrChecksum) {
+			t.Errorf("Verify(0x%08X) = %v with bit %d flipped, want ErrChecksum", corrupted, err, bit)
+		}
+	}
+}
+
+func TestCRCField_Truncated(t *testing.T) {
+	// The low 12 bits of a CRC-32 over a little-endian 64-bit container.
+	cf := NewCRCField(New[uint64, uint64](52, 12), CRC32(crc32.IEEETable))
+	cf.Order = binary.LittleEndian
+
+	container := uint64(0x000123456789ABCD)
+	data := AppendBinary(nil, container, binary.LittleEndian)
+	want := uint64(crc32.ChecksumIEEE(data)) & 0xFFF
+	if got := cf.Compute(container); got != want {
+		t.Errorf("Compute(0x%X) = 0x%X, want 0x%X", container, got, want)
+	}
+	if got := cf.Seal(container) >> 52; got != want {
+		t.Errorf("Seal(0x%X) stored 0x%X, want 0x%X", container, got, want)
+	}
+}
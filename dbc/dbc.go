@@ -0,0 +1,324 @@
+// Package dbc decodes and encodes CAN frames described by Vector DBC files.
+//
+// Parse reads the messages and signals of a DBC file into a Database. Every
+// signal is located in the frame payload with a bitfield.BitField over the
+// payload loaded as a uint64 in the signal's byte order, so Intel
+// (little-endian) and Motorola (big-endian) signals are both plain bit fields,
+// and Message.Layout gives the signals of a message as a bitfield.Layout.
+// Raw values are converted to physical values with the signal's scale and
+// offset, and value tables (VAL_) name the raw values of enumerated signals.
+//
+// Only classic CAN payloads of up to 8 bytes can be decoded and encoded.
+// Multiplexed messages are supported with a single multiplexor signal.
+package dbc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Database is the content of a DBC file.
+type Database struct {
+	Version  string
+	Nodes    []string   // Names of the network nodes (BU_)
+	Messages []*Message // Messages in file order
+}
+
+// Message is a CAN message.
+type Message struct {
+	ID       uint32 // Identifier, without the extended frame flag
+	Extended bool   // Whether the identifier is a 29-bit extended identifier
+	Name     string
+	Length   int // Payload length in bytes
+	Sender   string
+	Comment  string
+	Signals  []*Signal // Signals in file order
+}
+
+// Signal is a signal of a message.
+type Signal struct {
+	Name      string
+	StartBit  uint // Start bit as written in the DBC file: the LSB for Intel, the MSB for Motorola signals
+	Length    uint // Length in bits
+	ByteOrder binary.ByteOrder
+	Signed    bool
+	Float     bool // Whether the raw value is an IEEE 754 float of 32 or 64 bits (SIG_VALTYPE_)
+	Scale     float64
+	Offset    float64
+	Min, Max  float64 // Physical range declared in the file
+	Unit      string
+	Receivers []string
+	Comment   string
+	Values    map[uint64]string // Value table names of raw values, nil if there are none
+
+	Multiplexor bool   // Whether the signal selects which multiplexed signals are present
+	Multiplexed bool   // Whether the signal is only present for one multiplexor value
+	MuxValue    uint64 // Multiplexor value selecting the signal, if Multiplexed
+
+	// Field locates the raw value in the payload loaded as a uint64 in ByteOrder.
+	Field bitfield.BitField[uint64, uint64]
+}
+
+// Message returns the message with the given identifier, or nil if there is none.
+func (db *Database) Message(id uint32) *Message {
+	for _, m := range db.Messages {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// MessageByName returns the message with the given name, or nil if there is none.
+func (db *Database) MessageByName(name string) *Message {
+	for _, m := range db.Messages {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// Signal returns the signal with the given name, or nil if there is none.
+func (m *Message) Signal(name string) *Signal {
+	for _, s := range m.Signals {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Value is the decoded value of a signal.
+type Value struct {
+	Signal   *Signal
+	Raw      uint64  // Raw bits of the signal
+	Physical float64 // Scaled value in the signal's unit
+	Label    string  // Value table name of the raw value, empty if there is none
+}
+
+// String returns the value table name of the value if it has one, and
+// otherwise the physical value followed by the unit.
+func (v Value) String() string {
+	if v.Label != "" {
+		return v.Label
+	}
+	s := strconv.FormatFloat(v.Physical, 'g', -1, 64)
+	if v.Signal.Unit != "" {
+		s += " " + v.Signal.Unit
+	}
+	return s
+}
+
+// Decode decodes the signals present in a payload. For multiplexed messages
+// only the multiplexor and the signals it selects are returned.
+// Returns an error if the payload is shorter than the message.
+func (m *Message) Decode(data []byte) ([]Value, error) {
+	if err := m.checkPayload(data); err != nil {
+		return nil, err
+	}
+	mux, hasMux := m.muxValue(data)
+	var values []Value
+	for _, s := range m.Signals {
+		if s.Multiplexed && (!hasMux || s.MuxValue != mux) {
+			continue
+		}
+		raw := s.Raw(data)
+		values = append(values, Value{Signal: s, Raw: raw, Physical: s.physical(raw), Label: s.Values[raw]})
+	}
+	return values, nil
+}
+
+// Encode builds the payload of the message from physical signal values.
+// Signals without a value are encoded as raw 0; for multiplexed messages the
+// multiplexor value must be given and selects which multiplexed signals may be.
+// Returns an error if a signal does not exist, is not selected by the
+// multiplexor, or its value is out of range.
+func (m *Message) Encode(values map[string]float64) ([]byte, error) {
+	if m.Length > 8 {
+		return nil, fmt.Errorf("message %s: payloads of %d bytes are not supported", m.Name, m.Length)
+	}
+	data := make([]byte, m.Length)
+	var mux *Signal
+	for _, s := range m.Signals {
+		if s.Multiplexor {
+			mux = s
+		}
+	}
+	var muxRaw uint64
+	if mux != nil {
+		if v, ok := values[mux.Name]; ok {
+			if err := mux.Encode(data, v); err != nil {
+				return nil, fmt.Errorf("message %s: %w", m.Name, err)
+			}
+			muxRaw = mux.Raw(data)
+		}
+	}
+	for name, v := range values {
+		s := m.Signal(name)
+		if s == nil {
+			return nil, fmt.Errorf("message %s: unknown signal %q", m.Name, name)
+		}
+		if s == mux {
+			continue
+		}
+		if s.Multiplexed && (mux == nil || s.MuxValue != muxRaw) {
+			return nil, fmt.Errorf("message %s: signal %s is not selected by multiplexor value %d", m.Name, name, muxRaw)
+		}
+		if err := s.Encode(data, v); err != nil {
+			return nil, fmt.Errorf("message %s: %w", m.Name, err)
+		}
+	}
+	return data, nil
+}
+
+// Layout returns the signals of the message as a Layout over the payload
+// loaded as a uint64, with value tables registered as enums and the byte
+// order of the signals. The layout decodes raw values only.
+// Returns an error if the signals do not share a byte order or overlap, as
+// the signals of multiplexed messages do.
+func (m *Message) Layout() (*bitfield.Layout[uint64], error) {
+	l := bitfield.NewLayout[uint64]()
+	var order binary.ByteOrder
+	for _, s := range m.Signals {
+		if order != nil && s.ByteOrder != order {
+			return nil, fmt.Errorf("message %s: signals use both byte orders", m.Name)
+		}
+		order = s.ByteOrder
+		if err := l.Add(s.Name, s.Field.Shift, s.Field.Size); err != nil {
+			return nil, fmt.Errorf("message %s: %w", m.Name, err)
+		}
+		if s.Values != nil {
+			if err := l.SetEnum(s.Name, s.Values); err != nil {
+				return nil, fmt.Errorf("message %s: %w", m.Name, err)
+			}
+		}
+	}
+	if order != nil {
+		l.SetByteOrder(order)
+	}
+	return l, nil
+}
+
+// checkPayload checks that data holds a payload of the message.
+func (m *Message) checkPayload(data []byte) error {
+	if m.Length > 8 {
+		return fmt.Errorf("message %s: payloads of %d bytes are not supported", m.Name, m.Length)
+	}
+	if len(data) < m.Length {
+		return fmt.Errorf("message %s: payload of %d bytes, want %d", m.Name, len(data), m.Length)
+	}
+	return nil
+}
+
+// muxValue returns the raw value of the multiplexor signal, if the message has one.
+func (m *Message) muxValue(data []byte) (uint64, bool) {
+	for _, s := range m.Signals {
+		if s.Multiplexor {
+			return s.Raw(data), true
+		}
+	}
+	return 0, false
+}
+
+// load returns the first 8 bytes of a payload as a uint64 in the given byte
+// order, padding short payloads with zeros.
+func load(data []byte, order binary.ByteOrder) uint64 {
+	var buf [8]byte
+	copy(buf[:], data)
+	return order.Uint64(buf[:])
+}
+
+// Raw returns the raw bits of the signal in a payload.
+func (s *Signal) Raw(data []byte) uint64 {
+	return s.Field.Decode(load(data, s.ByteOrder))
+}
+
+// Decode returns the physical value of the signal in a payload.
+func (s *Signal) Decode(data []byte) float64 {
+	return s.physical(s.Raw(data))
+}
+
+// Encode sets the signal in a payload to a physical value, rounding to the
+// nearest raw value. Other signals are preserved.
+// Returns an error if the payload is too short for the signal or the value is
+// not finite or out of the range of the raw field.
+func (s *Signal) Encode(data []byte, value float64) error {
+	raw, err := s.rawValue(value)
+	if err != nil {
+		return fmt.Errorf("signal %s: %w", s.Name, err)
+	}
+	if s.Field.Mask&^mask(len(data), s.ByteOrder) != 0 {
+		return fmt.Errorf("signal %s: payload of %d bytes is too short", s.Name, len(data))
+	}
+	var buf [8]byte
+	copy(buf[:], data)
+	word := s.Field.Update(s.ByteOrder.Uint64(buf[:]), raw)
+	s.ByteOrder.PutUint64(buf[:], word)
+	copy(data, buf[:])
+	return nil
+}
+
+// mask returns the bits of a loaded payload covered by its first n bytes.
+func mask(n int, order binary.ByteOrder) uint64 {
+	var buf [8]byte
+	for i := range min(n, 8) {
+		buf[i] = 0xFF
+	}
+	return order.Uint64(buf[:])
+}
+
+// physical converts a raw value to a physical value.
+func (s *Signal) physical(raw uint64) float64 {
+	var v float64
+	switch {
+	case s.Float && s.Length == 32:
+		v = float64(math.Float32frombits(uint32(raw)))
+	case s.Float:
+		v = math.Float64frombits(raw)
+	case s.Signed:
+		v = float64(signExtend(raw, s.Length))
+	default:
+		v = float64(raw)
+	}
+	return v*s.Scale + s.Offset
+}
+
+// rawValue converts a physical value to raw bits.
+func (s *Signal) rawValue(value float64) (uint64, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("value %v is not finite", value)
+	}
+	if s.Scale == 0 {
+		return 0, fmt.Errorf("scale must not be 0")
+	}
+	x := (value - s.Offset) / s.Scale
+	switch {
+	case s.Float && s.Length == 32:
+		return uint64(math.Float32bits(float32(x))), nil
+	case s.Float:
+		return math.Float64bits(x), nil
+	}
+	x = math.Round(x)
+	if s.Signed {
+		limit := math.Ldexp(1, int(s.Length)-1)
+		if x < -limit || x >= limit {
+			return 0, fmt.Errorf("value %v out of range", value)
+		}
+		return uint64(int64(x)) & s.Field.Max(), nil
+	}
+	if x < 0 || x >= math.Ldexp(1, int(s.Length)) {
+		return 0, fmt.Errorf("value %v out of range", value)
+	}
+	return uint64(x), nil
+}
+
+// signExtend interprets the low size bits of raw as a two's complement number.
+func signExtend(raw uint64, size uint) int64 {
+	shift := 64 - size
+	return int64(raw<<shift) >> shift
+}
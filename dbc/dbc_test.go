@@ -0,0 +1,281 @@
+package dbc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func parseExample(t *testing.T) *Database {
+	t.Helper()
+	f, err := os.Open("testdata/example.dbc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	db, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return db
+}
+
+func TestParse(t *testing.T) {
+	db := parseExample(t)
+	if db.Version != "1.0" || len(db.Messages) != 3 {
+		t.Fatalf("database = version %q with %d messages, want 1.0 with 3", db.Version, len(db.Messages))
+	}
+	if want := []string{"ECU", "Gateway", "Dash"}; !slices.Equal(db.Nodes, want) {
+		t.Errorf("nodes = %v, want %v", db.Nodes, want)
+	}
+
+	engine := db.Message(100)
+	if engine == nil {
+		t.Fatal("Message(100) = nil")
+	}
+	if engine.Name != "EngineData" || engine.Length != 8 || engine.Sender != "ECU" || engine.Comment != "Engine state." {
+		t.Errorf("EngineData = %+v", engine)
+	}
+	speed := engine.Signal("EngineSpeed")
+	if speed.StartBit != 0 || speed.Length != 16 || speed.ByteOrder != binary.LittleEndian || speed.Signed ||
+		speed.Scale != 0.25 || speed.Max != 16383.75 || speed.Unit != "rpm" || speed.Comment != "Crankshaft speed." {
+		t.Errorf("EngineSpeed = %+v", speed)
+	}
+	if want := []string{"Dash", "Gateway"}; !slices.Equal(speed.Receivers, want) {
+		t.Errorf("EngineSpeed receivers = %v, want %v", speed.Receivers, want)
+	}
+	if torque := engine.Signal("Torque"); !torque.Signed || torque.Field.Shift != 24 || torque.Field.Size != 12 {
+		t.Errorf("Torque = signed %v, shift %d, size %d", torque.Signed, torque.Field.Shift, torque.Field.Size)
+	}
+	wantValues := map[uint64]string{0: "Park", 1: "Reverse", 2: "Neutral", 3: "Drive"}
+	if gear := engine.Signal("Gear"); !maps.Equal(gear.Values, wantValues) {
+		t.Errorf("Gear values = %v, want %v", gear.Values, wantValues)
+	}
+
+	brake := db.MessageByName("BrakeStatus")
+	if brake == nil || brake.ID != 0x200 || brake.Extended {
+		t.Fatalf("BrakeStatus = %+v", brake)
+	}
+	if p := brake.Signal("Pressure"); p.ByteOrder != binary.BigEndian || p.Field.Shift != 48 || p.Comment != "Brake line\npressure." {
+		t.Errorf("Pressure = order %v, shift %d, comment %q", p.ByteOrder, p.Field.Shift, p.Comment)
+	}
+	if l := brake.Signal("Level"); l.Field.Shift != 40 || l.Field.Size != 6 {
+		t.Errorf("Level = shift %d, size %d, want shift 40, size 6", l.Field.Shift, l.Field.Size)
+	}
+
+	diag := db.MessageByName("Diagnostics")
+	if diag.ID != 0x18FEF1FE || !diag.Extended {
+		t.Errorf("Diagnostics = ID 0x%X, extended %v, want 0x18FEF1FE, extended", diag.ID, diag.Extended)
+	}
+	if !diag.Signal("Mode").Multiplexor {
+		t.Error("Mode is not the multiplexor")
+	}
+	if c := diag.Signal("Current"); !c.Multiplexed || c.MuxValue != 2 {
+		t.Errorf("Current = multiplexed %v, value %d, want multiplexed by 2", c.Multiplexed, c.MuxValue)
+	}
+	if !diag.Signal("Ratio").Float {
+		t.Error("Ratio is not a float signal")
+	}
+}
+
+var frameTests = []struct {
+	name    string
+	message string
+	data    []byte
+	values  map[string]float64
+	strings []string
+}{
+	{
+		"intel", "EngineData",
+		[]byte{0xE0, 0x2E, 0x82, 0x38, 0x3F, 0, 0, 0},
+		map[string]float64{"EngineSpeed": 3000, "CoolantTemp": 90, "Torque": -100, "Gear": 3},
+		[]string{"3000 rpm", "90 degC", "-100 Nm", "Drive"},
+	},
+	{
+		"motorola", "BrakeStatus",
+		[]byte{0x04, 0xD2, 0x85, 0x00},
+		map[string]float64{"Pressure": 123.4, "Active": 1, "Level": 5},
+		[]string{"123.4 bar", "1", "5 %"},
+	},
+	{
+		"multiplexed float", "Diagnostics",
+		[]byte{0x01, 0xD4, 0x30, 0x00, 0x00, 0x40, 0x3F, 0},
+		map[string]float64{"Mode": 1, "Voltage": 12.5, "Ratio": 0.75},
+		[]string{"Power", "12.5 V", "0.75"},
+	},
+	{
+		"multiplexed signed", "Diagnostics",
+		[]byte{0x02, 0x18, 0xFC, 0, 0, 0, 0, 0},
+		map[string]float64{"Mode": 2, "Current": -10},
+		[]string{"Load", "-10 A"},
+	},
+}
+
+func TestMessage_Decode(t *testing.T) {
+	db := parseExample(t)
+	for _, tt := range frameTests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := db.MessageByName(tt.message).Decode(tt.data)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			var got []string
+			for _, v := range values {
+				got = append(got, v.String())
+			}
+			if !slices.Equal(got, tt.strings) {
+				t.Errorf("Decode = %v, want %v", got, tt.strings)
+			}
+		})
+	}
+}
+
+func TestMessage_Encode(t *testing.T) {
+	db := parseExample(t)
+	for _, tt := range frameTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.MessageByName(tt.message).Encode(tt.values)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if !bytes.Equal(got, tt.data) {
+				t.Errorf("Encode = % X, want % X", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestMessage_EncodeErrors(t *testing.T) {
+	db := parseExample(t)
+	tests := []struct {
+		name    string
+		message string
+		values  map[string]float64
+	}{
+		{"unknown signal", "EngineData", map[string]float64{"Speed": 1}},
+		{"too large", "EngineData", map[string]float64{"CoolantTemp": 300}},
+		{"below offset", "EngineData", map[string]float64{"CoolantTemp": -41}},
+		{"signed too small", "EngineData", map[string]float64{"Torque": -1025}},
+		{"not selected", "Diagnostics", map[string]float64{"Mode": 1, "Current": 1}},
+		{"no multiplexor", "Diagnostics", map[string]float64{"Voltage": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := db.MessageByName(tt.message).Encode(tt.values); err == nil {
+				t.Errorf("Encode(%v) succeeded, want error", tt.values)
+			}
+		})
+	}
+}
+
+func TestSignal_Encode(t *testing.T) {
+	db := parseExample(t)
+	s := db.MessageByName("BrakeStatus").Signal("Level")
+	data := []byte{0x04, 0xD2, 0x80, 0xFF}
+	if err := s.Encode(data, 63); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if want := []byte{0x04, 0xD2, 0xBF, 0xFF}; !bytes.Equal(data, want) {
+		t.Errorf("payload = % X, want % X", data, want)
+	}
+	if got := s.Decode(data); got != 63 {
+		t.Errorf("Decode = %v, want 63", got)
+	}
+	short := []byte{0x04, 0xD2}
+	if err := s.Encode(short, 1); err == nil {
+		t.Error("Encode into 2-byte payload succeeded, want error")
+	}
+	if !bytes.Equal(short, []byte{0x04, 0xD2}) {
+		t.Errorf("failed Encode modified payload to % X", short)
+	}
+}
+
+func TestMessage_Layout(t *testing.T) {
+	db := parseExample(t)
+	l, err := db.Message(100).Layout()
+	if err != nil {
+		t.Fatalf("Layout: %v", err)
+	}
+	if l.ByteOrder() != binary.LittleEndian {
+		t.Errorf("ByteOrder = %v, want little-endian", l.ByteOrder())
+	}
+	payload := binary.LittleEndian.Uint64(frameTests[0].data)
+	want := map[string]uint64{"EngineSpeed": 12000, "CoolantTemp": 130, "Torque": 0xF38, "Gear": 3}
+	if got := l.DecodeAll(payload); !maps.Equal(got, want) {
+		t.Errorf("DecodeAll = %v, want %v", got, want)
+	}
+	gear, _ := l.Field("Gear")
+	if got := gear.ValueString(3); got != "Drive" {
+		t.Errorf("Gear.ValueString(3) = %q, want Drive", got)
+	}
+
+	if _, err := db.MessageByName("Diagnostics").Layout(); err == nil {
+		t.Error("Layout of multiplexed message succeeded, want error")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	msg := "BO_ 1 M: 8 N\n"
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"signal outside message", ` SG_ S : 0|8@1+ (1,0) [0|0] "" N`},
+		{"bad identifier", "BO_ 4096 M: 8 N\n"},
+		{"duplicate message", msg + msg},
+		{"missing colon", "BO_ 1 M 8 N\n"},
+		{"bad byte order", msg + ` SG_ S : 0|8@2+ (1,0) [0|0] "" N`},
+		{"bad sign", msg + ` SG_ S : 0|8@1* (1,0) [0|0] "" N`},
+		{"zero length", msg + ` SG_ S : 0|0@1+ (1,0) [0|0] "" N`},
+		{"intel overflow", msg + ` SG_ S : 60|8@1+ (1,0) [0|0] "" N`},
+		{"motorola overflow", msg + ` SG_ S : 58|8@0+ (1,0) [0|0] "" N`},
+		{"exceeds message", "BO_ 1 M: 2 N\n" + ` SG_ S : 16|8@1+ (1,0) [0|0] "" N`},
+		{"duplicate signal", msg + ` SG_ S : 0|8@1+ (1,0) [0|0] "" N` + "\n" + ` SG_ S : 8|8@1+ (1,0) [0|0] "" N`},
+		{"bad multiplexer", msg + ` SG_ S x : 0|8@1+ (1,0) [0|0] "" N`},
+		{"two multiplexors", msg + ` SG_ A M : 0|8@1+ (1,0) [0|0] "" N` + "\n" + ` SG_ B M : 8|8@1+ (1,0) [0|0] "" N`},
+		{"unterminated string", `VERSION "1.0`},
+		{"unknown comment message", `CM_ BO_ 2 "x";`},
+		{"unknown value signal", msg + `VAL_ 1 S 0 "x" ;`},
+		{"bad value", msg + ` SG_ S : 0|8@1+ (1,0) [0|0] "" N` + "\n" + `VAL_ 1 S x "x" ;`},
+		{"missing semicolon", `BA_DEF_ BO_ "x" INT 0 1`},
+		{"float length", msg + ` SG_ S : 0|8@1+ (1,0) [0|0] "" N` + "\n" + `SIG_VALTYPE_ 1 S : 1;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.src)); err == nil {
+				t.Errorf("Parse(%q) succeeded, want error", tt.src)
+			}
+		})
+	}
+}
+
+func TestSignalField(t *testing.T) {
+	tests := []struct {
+		start, length uint
+		order         binary.ByteOrder
+		wantShift     uint
+	}{
+		{0, 8, binary.LittleEndian, 0},
+		{12, 4, binary.LittleEndian, 12},
+		{7, 8, binary.BigEndian, 56},
+		{7, 16, binary.BigEndian, 48},
+		{3, 4, binary.BigEndian, 56},
+		{15, 12, binary.BigEndian, 44},
+		{63, 1, binary.BigEndian, 7},
+		{7, 64, binary.BigEndian, 0},
+	}
+
+	for _, tt := range tests {
+		bf, err := signalField(tt.start, tt.length, tt.order)
+		if err != nil || bf.Shift != tt.wantShift || bf.Size != tt.length {
+			t.Errorf("signalField(%d, %d, %v) = shift %d, size %d, %v, want shift %d",
+				tt.start, tt.length, tt.order, bf.Shift, bf.Size, err, tt.wantShift)
+		}
+	}
+}
@@ -0,0 +1,563 @@
+package dbc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// extendedFlag marks extended identifiers in BO_ statements.
+const extendedFlag = 0x80000000
+
+// token is a lexical token of a DBC file.
+type token struct {
+	text  string
+	str   bool // Whether the token is a quoted string
+	line  int
+	first bool // Whether the token is the first on its line
+}
+
+// lex splits a DBC file into tokens. The indented keyword list following NS_
+// is dropped, as it would otherwise be mistaken for statements.
+func lex(r io.Reader) ([]token, error) {
+	var toks []token
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	inNS := false
+	var open *token // Unterminated string continuing on the next line
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if open != nil {
+			end := strings.IndexByte(text, '"')
+			if end < 0 {
+				open.text += "\n" + text
+				continue
+			}
+			open.text += "\n" + text[:end]
+			toks = append(toks, *open)
+			open = nil
+			text = strings.Repeat(" ", end+1) + text[end+1:]
+		} else if inNS {
+			if strings.TrimSpace(text) == "" || text[0] == ' ' || text[0] == '\t' {
+				continue
+			}
+			inNS = false
+		}
+		first, lineStart := true, len(toks)
+		for i := 0; i < len(text); {
+			c := text[i]
+			switch {
+			case c == ' ' || c == '\t' || c == '\r':
+				i++
+				continue
+			case c == '"':
+				end := strings.IndexByte(text[i+1:], '"')
+				if end < 0 {
+					open = &token{text: text[i+1:], str: true, line: line, first: first}
+					i = len(text)
+					continue
+				}
+				toks = append(toks, token{text: text[i+1 : i+1+end], str: true, line: line, first: first})
+				i += end + 2
+			case strings.IndexByte(":;|@()[],", c) >= 0:
+				toks = append(toks, token{text: text[i : i+1], line: line, first: first})
+				i++
+			case (c == '+' || c == '-') && (i+1 >= len(text) || !isNumberByte(text[i+1])):
+				toks = append(toks, token{text: text[i : i+1], line: line, first: first})
+				i++
+			default:
+				j := i + 1
+				for j < len(text) && strings.IndexByte(" \t\r\":;|@()[],", text[j]) < 0 &&
+					!((text[j] == '+' || text[j] == '-') && text[j-1] != 'e' && text[j-1] != 'E') {
+					j++
+				}
+				toks = append(toks, token{text: text[i:j], line: line, first: first})
+				i = j
+			}
+			first = false
+		}
+		if lineStart < len(toks) && toks[lineStart].first && toks[lineStart].text == "NS_" {
+			inNS = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, fmt.Errorf("line %d: unterminated string", open.line)
+	}
+	return toks, nil
+}
+
+func isNumberByte(c byte) bool {
+	return '0' <= c && c <= '9' || c == '.'
+}
+
+// parser parses the statements of a DBC file.
+type parser struct {
+	toks []token
+	pos  int
+	db   *Database
+	// floats records SIG_VALTYPE_ declarations, applied once all messages are known.
+	floats []sigRef
+}
+
+// sigRef refers to a signal of a message from a statement following the messages.
+type sigRef struct {
+	id     uint32
+	signal string
+	line   int
+	value  string
+}
+
+func (p *parser) peek() token {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	line := 1
+	if len(p.toks) > 0 {
+		line = p.toks[len(p.toks)-1].line
+	}
+	return token{line: line}
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) done() bool { return p.pos >= len(p.toks) }
+
+func (p *parser) expect(s string) error {
+	if t := p.next(); t.str || t.text != s {
+		return fmt.Errorf("line %d: expected %q, found %q", t.line, s, t.text)
+	}
+	return nil
+}
+
+func (p *parser) word() (token, error) {
+	t := p.next()
+	if t.str || t.text == "" || strings.IndexByte(":;|@()[],", t.text[0]) >= 0 {
+		return t, fmt.Errorf("line %d: expected name, found %q", t.line, t.text)
+	}
+	return t, nil
+}
+
+func (p *parser) string() (string, error) {
+	t := p.next()
+	if !t.str {
+		return "", fmt.Errorf("line %d: expected string, found %q", t.line, t.text)
+	}
+	return t.text, nil
+}
+
+func (p *parser) uint() (uint64, int, error) {
+	t := p.next()
+	n, err := strconv.ParseUint(t.text, 10, 64)
+	if t.str || err != nil {
+		return 0, t.line, fmt.Errorf("line %d: expected unsigned integer, found %q", t.line, t.text)
+	}
+	return n, t.line, nil
+}
+
+func (p *parser) float() (float64, error) {
+	t := p.next()
+	f, err := strconv.ParseFloat(t.text, 64)
+	if t.str || err != nil {
+		return 0, fmt.Errorf("line %d: expected number, found %q", t.line, t.text)
+	}
+	return f, nil
+}
+
+// skipLine skips the tokens of a statement that ends with its line.
+func (p *parser) skipLine() {
+	for !p.done() && !p.peek().first {
+		p.pos++
+	}
+}
+
+// skipStatement skips a statement terminated by ';'.
+func (p *parser) skipStatement() error {
+	start := p.peek().line
+	for !p.done() {
+		if t := p.next(); !t.str && t.text == ";" {
+			return nil
+		}
+	}
+	return fmt.Errorf("line %d: missing ';'", start)
+}
+
+// Parse reads a DBC file from r.
+func Parse(r io.Reader) (*Database, error) {
+	toks, err := lex(r)
+	if err != nil {
+		return nil, fmt.Errorf("dbc: %w", err)
+	}
+	p := &parser{toks: toks, db: &Database{}}
+	if err := p.parse(); err != nil {
+		return nil, fmt.Errorf("dbc: %w", err)
+	}
+	return p.db, nil
+}
+
+func (p *parser) parse() error {
+	var msg *Message
+	for !p.done() {
+		kw := p.next()
+		if kw.str || !kw.first {
+			return fmt.Errorf("line %d: unexpected %q", kw.line, kw.text)
+		}
+		var err error
+		switch kw.text {
+		case "VERSION":
+			p.db.Version, err = p.string()
+		case "NS_", "BS_":
+			p.skipLine()
+		case "BU_":
+			if err = p.expect(":"); err == nil {
+				for !p.done() && !p.peek().first {
+					p.db.Nodes = append(p.db.Nodes, p.next().text)
+				}
+			}
+		case "BO_":
+			msg, err = p.parseMessage()
+		case "SG_":
+			if msg == nil {
+				return fmt.Errorf("line %d: signal outside of a message", kw.line)
+			}
+			err = p.parseSignal(msg)
+		case "CM_":
+			err = p.parseComment()
+		case "VAL_":
+			err = p.parseValues()
+		case "SIG_VALTYPE_":
+			err = p.parseValueType()
+		default:
+			err = p.skipStatement()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, ref := range p.floats {
+		s, err := p.signal(ref)
+		if err != nil {
+			return err
+		}
+		switch ref.value {
+		case "1":
+			if s.Length != 32 {
+				return fmt.Errorf("line %d: float signal %s must be 32 bits long", ref.line, s.Name)
+			}
+		case "2":
+			if s.Length != 64 {
+				return fmt.Errorf("line %d: double signal %s must be 64 bits long", ref.line, s.Name)
+			}
+		case "0":
+			continue
+		default:
+			return fmt.Errorf("line %d: invalid value type %q", ref.line, ref.value)
+		}
+		s.Float = true
+	}
+	return nil
+}
+
+// parseMessage parses "BO_ id name: length sender".
+func (p *parser) parseMessage() (*Message, error) {
+	id, line, err := p.uint()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.word()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	length, _, err := p.uint()
+	if err != nil {
+		return nil, err
+	}
+	sender, err := p.word()
+	if err != nil {
+		return nil, err
+	}
+	m := &Message{Name: name.text, Length: int(length), Sender: sender.text}
+	if id&extendedFlag != 0 {
+		m.Extended = true
+		id &^= extendedFlag
+	}
+	if id > 0x1FFFFFFF || !m.Extended && id > 0x7FF {
+		return nil, fmt.Errorf("line %d: invalid message identifier %d", line, id)
+	}
+	m.ID = uint32(id)
+	for _, prev := range p.db.Messages {
+		if prev.ID == m.ID && prev.Extended == m.Extended {
+			return nil, fmt.Errorf("line %d: duplicate message identifier %d", line, id)
+		}
+	}
+	p.db.Messages = append(p.db.Messages, m)
+	return m, nil
+}
+
+// parseSignal parses
+// "SG_ name [M|mN] : start|length@order sign (scale,offset) [min|max] "unit" receivers".
+func (p *parser) parseSignal(m *Message) error {
+	name, err := p.word()
+	if err != nil {
+		return err
+	}
+	s := &Signal{Name: name.text}
+	if t := p.peek(); !t.str && t.text != ":" {
+		p.next()
+		switch {
+		case t.text == "M":
+			s.Multiplexor = true
+		case strings.HasPrefix(t.text, "m"):
+			v, err := strconv.ParseUint(strings.TrimSuffix(t.text[1:], "M"), 10, 64)
+			if err != nil || strings.HasSuffix(t.text, "M") {
+				return fmt.Errorf("line %d: unsupported multiplexer indicator %q", t.line, t.text)
+			}
+			s.Multiplexed, s.MuxValue = true, v
+		default:
+			return fmt.Errorf("line %d: invalid multiplexer indicator %q", t.line, t.text)
+		}
+	}
+	if err := p.expect(":"); err != nil {
+		return err
+	}
+	start, line, err := p.uint()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("|"); err != nil {
+		return err
+	}
+	length, _, err := p.uint()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("@"); err != nil {
+		return err
+	}
+	switch t := p.next(); t.text {
+	case "0":
+		s.ByteOrder = binary.BigEndian
+	case "1":
+		s.ByteOrder = binary.LittleEndian
+	default:
+		return fmt.Errorf("line %d: invalid byte order %q", t.line, t.text)
+	}
+	switch t := p.next(); t.text {
+	case "+":
+	case "-":
+		s.Signed = true
+	default:
+		return fmt.Errorf("line %d: invalid sign %q", t.line, t.text)
+	}
+	s.StartBit, s.Length = uint(start), uint(length)
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	if s.Scale, err = p.float(); err != nil {
+		return err
+	}
+	if err := p.expect(","); err != nil {
+		return err
+	}
+	if s.Offset, err = p.float(); err != nil {
+		return err
+	}
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+	if err := p.expect("["); err != nil {
+		return err
+	}
+	if s.Min, err = p.float(); err != nil {
+		return err
+	}
+	if err := p.expect("|"); err != nil {
+		return err
+	}
+	if s.Max, err = p.float(); err != nil {
+		return err
+	}
+	if err := p.expect("]"); err != nil {
+		return err
+	}
+	if s.Unit, err = p.string(); err != nil {
+		return err
+	}
+	for !p.done() && !p.peek().first {
+		if t := p.next(); t.text != "," {
+			s.Receivers = append(s.Receivers, t.text)
+		}
+	}
+
+	if s.Field, err = signalField(s.StartBit, s.Length, s.ByteOrder); err != nil {
+		return fmt.Errorf("line %d: signal %s: %w", line, s.Name, err)
+	}
+	if m.Length <= 8 && s.Field.Mask&^mask(m.Length, s.ByteOrder) != 0 {
+		return fmt.Errorf("line %d: signal %s exceeds the %d-byte payload", line, s.Name, m.Length)
+	}
+	if m.Signal(s.Name) != nil {
+		return fmt.Errorf("line %d: duplicate signal %s", line, s.Name)
+	}
+	if s.Multiplexor {
+		for _, other := range m.Signals {
+			if other.Multiplexor {
+				return fmt.Errorf("line %d: message %s has more than one multiplexor", line, m.Name)
+			}
+		}
+	}
+	m.Signals = append(m.Signals, s)
+	return nil
+}
+
+// signalField returns the position of a signal in the payload loaded as a
+// uint64 in the signal's byte order. The start bit of a Motorola signal is its
+// most significant bit, numbered from the least significant bit of byte 0.
+func signalField(start, length uint, order binary.ByteOrder) (bitfield.BitField[uint64, uint64], error) {
+	if length == 0 || length > 64 {
+		return bitfield.BitField[uint64, uint64]{}, fmt.Errorf("invalid length %d", length)
+	}
+	shift := start
+	if order == binary.BigEndian {
+		msb := (7-start/8)*8 + start%8
+		if start >= 64 || msb+1 < length {
+			return bitfield.BitField[uint64, uint64]{}, fmt.Errorf("start bit %d and length %d exceed 8 bytes", start, length)
+		}
+		shift = msb + 1 - length
+	}
+	if shift+length > 64 {
+		return bitfield.BitField[uint64, uint64]{}, fmt.Errorf("start bit %d and length %d exceed 8 bytes", start, length)
+	}
+	return bitfield.New[uint64, uint64](shift, length), nil
+}
+
+// parseComment parses the CM_ statements of messages and signals. Comments of
+// other objects are skipped.
+func (p *parser) parseComment() error {
+	t := p.peek()
+	switch {
+	case t.str:
+		p.next() // Comment of the database
+	case t.text == "BO_":
+		p.next()
+		id, line, err := p.uint()
+		if err != nil {
+			return err
+		}
+		m := p.message(uint32(id))
+		if m == nil {
+			return fmt.Errorf("line %d: comment of unknown message %d", line, id)
+		}
+		if m.Comment, err = p.string(); err != nil {
+			return err
+		}
+	case t.text == "SG_":
+		p.next()
+		id, line, err := p.uint()
+		if err != nil {
+			return err
+		}
+		name, err := p.word()
+		if err != nil {
+			return err
+		}
+		s, err := p.signal(sigRef{id: uint32(id), signal: name.text, line: line})
+		if err != nil {
+			return err
+		}
+		if s.Comment, err = p.string(); err != nil {
+			return err
+		}
+	}
+	return p.skipStatement()
+}
+
+// parseValues parses "VAL_ id signal value "name" ... ;".
+// Value tables of environment variables are skipped.
+func (p *parser) parseValues() error {
+	t := p.peek()
+	id, err := strconv.ParseUint(t.text, 10, 64)
+	if t.str || err != nil {
+		return p.skipStatement()
+	}
+	p.next()
+	name, err := p.word()
+	if err != nil {
+		return err
+	}
+	s, err := p.signal(sigRef{id: uint32(id), signal: name.text, line: t.line})
+	if err != nil {
+		return err
+	}
+	s.Values = make(map[uint64]string)
+	for !p.done() && p.peek().text != ";" {
+		vt := p.next()
+		v, err := strconv.ParseInt(vt.text, 10, 64)
+		if vt.str || err != nil {
+			return fmt.Errorf("line %d: invalid value %q", vt.line, vt.text)
+		}
+		label, err := p.string()
+		if err != nil {
+			return err
+		}
+		s.Values[uint64(v)&s.Field.Max()] = label
+	}
+	return p.expect(";")
+}
+
+// parseValueType records a "SIG_VALTYPE_ id signal : type ;" statement.
+func (p *parser) parseValueType() error {
+	id, line, err := p.uint()
+	if err != nil {
+		return err
+	}
+	name, err := p.word()
+	if err != nil {
+		return err
+	}
+	if p.peek().text == ":" {
+		p.next()
+	}
+	value := p.next()
+	p.floats = append(p.floats, sigRef{id: uint32(id), signal: name.text, line: line, value: value.text})
+	return p.expect(";")
+}
+
+// message returns the message with an identifier as written in the file,
+// with the extended frame flag.
+func (p *parser) message(id uint32) *Message {
+	for _, m := range p.db.Messages {
+		if m.ID == id&^extendedFlag && m.Extended == (id&extendedFlag != 0) {
+			return m
+		}
+	}
+	return nil
+}
+
+// signal resolves a signal reference.
+func (p *parser) signal(ref sigRef) (*Signal, error) {
+	m := p.message(ref.id)
+	if m == nil {
+		return nil, fmt.Errorf("line %d: unknown message %d", ref.line, ref.id)
+	}
+	s := m.Signal(ref.signal)
+	if s == nil {
+		return nil, fmt.Errorf("line %d: unknown signal %s of message %s", ref.line, ref.signal, m.Name)
+	}
+	return s, nil
+}
@@ -0,0 +1,57 @@
+package bitfield
+
+import "fmt"
+
+// WithDefault returns a copy of the field with a default value, such as the
+// reset value of a register field or the default of a protocol field.
+// Layout.NewContainer starts from the defaults of the fields.
+// Panics if the value does not fit in the field or is not one of its allowed values.
+func (bf BitField[T, U]) WithDefault(value T) BitField[T, U] {
+	if !bf.IsValid(value) {
+		panic("default " + bf.invalidValue(value).Error())
+	}
+	meta := bf.meta.clone()
+	meta.def = value
+	bf.meta = meta
+	return bf
+}
+
+// Default returns the default value of the field, 0 unless set with WithDefault.
+func (bf BitField[T, U]) Default() T {
+	if bf.meta == nil {
+		return 0
+	}
+	return bf.meta.def
+}
+
+// SetDefault sets the default value of a field of the layout, as WithDefault does.
+// Returns an error if the layout has no such field, or the value does not fit in
+// the field or is not one of its allowed values.
+func (l *Layout[U]) SetDefault(name string, value uint64) error {
+	i, ok := l.index[name]
+	if !ok {
+		return fmt.Errorf("unknown field %q", name)
+	}
+	bf := l.fields[i].field
+	if !bf.IsValid(value) {
+		return fmt.Errorf("field %q: default %w", name, bf.invalidValue(value))
+	}
+	l.fields[i].field = bf.WithDefault(value)
+	return nil
+}
+
+// NewContainer returns a container with every field at its default value and
+// the reserved bits zero, mirroring the reset value of a register.
+// Fields declared with SetCondition get their default only if they are present
+// given the defaults of the fields they depend on.
+// A default that is no longer allowed because of a later SetAllowed is still
+// applied, and reported by Validate.
+func (l *Layout[U]) NewContainer() U {
+	var container U
+	for i, f := range l.fields {
+		if l.present(i, container) {
+			container = container&^f.field.Mask | U(f.field.Default())<<f.field.Shift
+		}
+	}
+	return container
+}
@@ -0,0 +1,98 @@
+package bitfield
+
+import "testing"
+
+func TestBitField_WithDefault(t *testing.T) {
+	bf := New[uint8, uint32](4, 4)
+	if got := bf.Default(); got != 0 {
+		t.Errorf("Default() = %d, want 0", got)
+	}
+	withDefault := bf.WithDefault(9)
+	if got := withDefault.Default(); got != 9 {
+		t.Errorf("Default() = %d, want 9", got)
+	}
+	if got := bf.Default(); got != 0 {
+		t.Errorf("Default() of the original field = %d, want 0", got)
+	}
+	if got := withDefault.WithEnum(map[uint8]string{9: "nine"}).Default(); got != 9 {
+		t.Errorf("Default() after WithEnum = %d, want 9", got)
+	}
+}
+
+func TestBitField_WithDefaultPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		bf   BitField[uint8, uint32]
+	}{
+		{"out of range", New[uint8, uint32](0, 3)},
+		{"not allowed", New[uint8, uint32](0, 4).WithAllowed(1, 2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("WithDefault(8) did not panic")
+				}
+			}()
+			tt.bf.WithDefault(8)
+		})
+	}
+}
+
+func TestLayout_NewContainer(t *testing.T) {
+	l := newStatusLayout(t)
+	if got := l.NewContainer(); got != 0 {
+		t.Errorf("NewContainer() without defaults = 0x%X, want 0", got)
+	}
+	for _, err := range []error{
+		l.SetDefault("active", 1),
+		l.SetDefault("priority", 3),
+		l.SetDefault("error", 0xAB),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := l.NewContainer(); got != 0xAB07 {
+		t.Errorf("NewContainer() = 0x%X, want 0xAB07", got)
+	}
+
+	if err := l.SetDefault("speed", 1); err == nil {
+		t.Error("SetDefault() of an unknown field succeeded")
+	}
+	if err := l.SetDefault("category", 16); err == nil {
+		t.Error("SetDefault() of a value out of range succeeded")
+	}
+	if err := l.SetAllowed("category", 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetDefault("category", 3); err == nil {
+		t.Error("SetDefault() of a value that is not allowed succeeded")
+	}
+}
+
+func TestLayout_NewContainerCondition(t *testing.T) {
+	l := newFrameLayout(t)
+	for _, err := range []error{
+		l.SetDefault("extaddr", 0xFF),
+		l.SetDefault("port", 3),
+		l.SetDefault("opcode", 2),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := l.NewContainer(); got != 0 {
+		t.Errorf("NewContainer() = 0x%X, want 0 as no conditional field is present", got)
+	}
+
+	if err := l.SetDefault("ext", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.NewContainer(); got != 0x30FF0001 {
+		t.Errorf("NewContainer() = 0x%X, want 0x30FF0001", got)
+	}
+	if err := l.Validate(l.NewContainer()); err != nil {
+		t.Errorf("Validate(NewContainer()) = %v", err)
+	}
+}
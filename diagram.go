@@ -0,0 +1,153 @@
+package bitfield
+
+import "strings"
+
+// diagramRowBits is the number of bits shown on one row of a diagram.
+const diagramRowBits = 32
+
+// Diagram renders the layout as an RFC-style ASCII box diagram, with bit numbers
+// on top and field names inside the boxes. The most significant bit is on the left,
+// and 64-bit containers are split into rows of 32 bits. Bits that belong to no field
+// are shown as empty boxes, and names that do not fit in their box are truncated.
+// Fields declared with SetCondition are listed under the diagram with their
+// condition, followed by the fields that have a description or unit, one per line
+// as in "timeout: Receive timeout, in ms".
+//
+//	 3 3                   2                   1                   0
+//	 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|                               |     error     |categor|prior|a|
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+func (l *Layout[U]) Diagram() string {
+	return l.diagram(nil)
+}
+
+// Diagram renders the layout of the container like Layout.Diagram, with an extra
+// line under the field names showing the value of each field.
+// Values with a registered enum name are shown as the name.
+// Fields that are not present in the container, as declared with
+// Layout.SetCondition, are shown as empty boxes.
+// A container without a layout is rendered as its raw value, as by String.
+func (p Packed[U]) Diagram() string {
+	if p.Layout == nil {
+		return p.String() + "\n"
+	}
+	return p.Layout.diagram(&p.Value)
+}
+
+// diagramCell is a run of adjacent bits on one diagram row that belong to the same field.
+type diagramCell struct {
+	bits  uint
+	field int // Index into Layout.fields, -1 for unused bits
+}
+
+// diagram renders the layout, annotated with the field values of container if it is not nil.
+func (l *Layout[U]) diagram(container *U) string {
+	width := unsignedSizeOf[U]()
+	present := l.used
+	if container != nil {
+		present = l.presentMask(*container)
+	}
+	var b strings.Builder
+	for hi := int(width) - 1; hi >= 0; hi -= diagramRowBits {
+		lo := max(hi-diagramRowBits+1, 0)
+		border := "+" + strings.Repeat("-+", hi-lo+1) + "\n"
+
+		// Bit numbers: tens digits above every multiple of ten, units below.
+		tens := []byte(strings.Repeat(" ", 2*(hi-lo+1)))
+		units := []byte(strings.Repeat(" ", 2*(hi-lo+1)))
+		for bit := hi; bit >= lo; bit-- {
+			col := 2*(hi-bit) + 1
+			if bit%10 == 0 || bit == hi {
+				tens[col] = byte('0' + bit/10%10)
+			}
+			units[col] = byte('0' + bit%10)
+		}
+		b.WriteString(strings.TrimRight(string(tens), " ") + "\n")
+		b.WriteString(string(units) + "\n")
+		b.WriteString(border)
+
+		cells := l.diagramCells(uint(hi), uint(lo), present)
+		b.WriteString(diagramLine(cells, func(c diagramCell) string {
+			if c.field < 0 {
+				return ""
+			}
+			return l.fields[c.field].name
+		}))
+		if container != nil {
+			b.WriteString(diagramLine(cells, func(c diagramCell) string {
+				if c.field < 0 {
+					return ""
+				}
+				return l.fields[c.field].field.DecodeString(*container)
+			}))
+		}
+		b.WriteString(border)
+	}
+	if container == nil {
+		for i, f := range l.fields {
+			if f.cond != nil {
+				b.WriteString(f.name + " " + l.conditionString(i) + "\n")
+			}
+		}
+		for _, f := range l.fields {
+			if legend := fieldLegend(f.field); legend != "" {
+				b.WriteString(f.name + ": " + legend + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// fieldLegend describes a field by its description and unit, or returns "" if it has neither.
+func fieldLegend[T Unsigned, U storageType](bf BitField[T, U]) string {
+	switch desc, unit := bf.Description(), bf.Unit(); {
+	case unit == "":
+		return desc
+	case desc == "":
+		return "in " + unit
+	default:
+		return desc + ", in " + unit
+	}
+}
+
+// diagramCells splits the bits hi down to lo into runs belonging to the same field.
+// Only the bits in present are attributed to fields.
+func (l *Layout[U]) diagramCells(hi, lo uint, present U) []diagramCell {
+	var cells []diagramCell
+	for bit := int(hi); bit >= int(lo); bit-- {
+		field := -1
+		for i, f := range l.fields {
+			if f.field.Mask&present&(U(1)<<bit) != 0 {
+				field = i
+				break
+			}
+		}
+		if n := len(cells); n > 0 && cells[n-1].field == field {
+			cells[n-1].bits++
+			continue
+		}
+		cells = append(cells, diagramCell{bits: 1, field: field})
+	}
+	return cells
+}
+
+// diagramLine renders one line of boxes, with the text of each cell centered and truncated to fit.
+func diagramLine(cells []diagramCell, text func(diagramCell) string) string {
+	var b strings.Builder
+	b.WriteByte('|')
+	for _, c := range cells {
+		room := int(2*c.bits - 1)
+		s := text(c)
+		if len(s) > room {
+			s = s[:room]
+		}
+		left := (room - len(s)) / 2
+		b.WriteString(strings.Repeat(" ", left))
+		b.WriteString(s)
+		b.WriteString(strings.Repeat(" ", room-left-len(s)))
+		b.WriteByte('|')
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
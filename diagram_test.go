@@ -0,0 +1,53 @@
+package bitfield
+
+import "testing"
+
+func TestLayout_Diagram(t *testing.T) {
+	got := newStatusLayout(t).Diagram()
+	want := "" +
+		" 3 3                   2                   1                   0\n" +
+		" 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0\n" +
+		"+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+\n" +
+		"|                               |     error     |categor|prior|a|\n" +
+		"+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+\n"
+	if got != want {
+		t.Errorf("Diagram() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPacked_Diagram(t *testing.T) {
+	got := newStatusLayout(t).Bind(0x00002A57).Diagram()
+	want := "" +
+		" 3 3                   2                   1                   0\n" +
+		" 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0\n" +
+		"+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+\n" +
+		"|                               |     error     |categor|prior|a|\n" +
+		"|                               |      42       |   5   |High |1|\n" +
+		"+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+\n"
+	if got != want {
+		t.Errorf("Diagram() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLayout_Diagram64(t *testing.T) {
+	l := NewLayout[uint64]()
+	_ = l.Add("lo", 0, 8)
+	_ = l.Add("span", 28, 8) // Crosses the row boundary at bit 32
+	_ = l.Add("top", 60, 4)
+
+	got := l.Diagram()
+	want := "" +
+		" 6     6                   5                   4\n" +
+		" 3 2 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2\n" +
+		"+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+\n" +
+		"|  top  |                                               | span  |\n" +
+		"+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+\n" +
+		" 3 3                   2                   1                   0\n" +
+		" 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0\n" +
+		"+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+\n" +
+		"| span  |                                       |      lo       |\n" +
+		"+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+\n"
+	if got != want {
+		t.Errorf("Diagram() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package bitfield
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// FieldChange describes a field whose value differs between two container values.
+type FieldChange struct {
+	Register         string // Name of the register, empty for changes reported by Layout.Diff
+	Field            string
+	Old, New         uint64
+	OldName, NewName string // Enum names of Old and New, empty if none is registered
+	Unit             string // Unit of the field set with WithUnit, empty if none
+}
+
+// String formats the change as "REGISTER.field: old -> new", using enum names
+// where registered and hexadecimal values otherwise, followed by the unit of
+// the field in parentheses if it has one.
+// The register prefix is omitted if Register is empty.
+func (c FieldChange) String() string {
+	name := c.Field
+	if c.Register != "" {
+		name = c.Register + "." + name
+	}
+	s := fmt.Sprintf("%s: %s -> %s", name, changeValue(c.Old, c.OldName), changeValue(c.New, c.NewName))
+	if c.Unit != "" {
+		s += " (" + c.Unit + ")"
+	}
+	return s
+}
+
+// changeValue formats one side of a FieldChange.
+func changeValue(value uint64, name string) string {
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("%#x", value)
+}
+
+// Diff returns the fields whose values differ between two containers,
+// in the order the fields were added to the layout.
+// Each change carries the enum names of the old and new values if they are
+// registered, and the unit of the field if it has one.
+// Bits outside of all fields are not compared.
+func (l *Layout[U]) Diff(from, to U) []FieldChange {
+	if (from^to)&l.used == 0 {
+		return nil
+	}
+	var changes []FieldChange
+	for _, f := range l.fields {
+		a, b := f.field.Decode(from), f.field.Decode(to)
+		if a == b {
+			continue
+		}
+		oldName, _ := f.field.Name(a)
+		newName, _ := f.field.Name(b)
+		changes = append(changes, FieldChange{Field: f.name, Old: a, New: b, OldName: oldName, NewName: newName, Unit: f.field.Unit()})
+	}
+	return changes
+}
+
+// ChangedFields returns the names of the fields whose values differ between two
+// containers, in the order the fields were added to the layout. Unlike Diff it
+// decodes no values, so it is cheap enough to call on every sample of a status word.
+// Bits outside of all fields are not compared.
+func (l *Layout[U]) ChangedFields(from, to U) []string {
+	changed := (from ^ to) & l.used
+	if changed == 0 {
+		return nil
+	}
+	var names []string
+	for _, f := range l.fields {
+		if changed&f.field.Mask != 0 {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// HammingDistance returns the number of bit positions in which a and b differ.
+func HammingDistance[U storageType](a, b U) int {
+	return bits.OnesCount64(uint64(a ^ b))
+}
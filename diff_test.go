@@ -0,0 +1,94 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLayout_Diff(t *testing.T) {
+	l := newStatusLayout(t)
+	tests := []struct {
+		name     string
+		from, to uint32
+		want     []FieldChange
+	}{
+		{"no change", 0x00002A57, 0x00002A57, nil},
+		{"unused bits only", 0x00002A57, 0xFFFF2A57, nil},
+		{"enum names", 0x00000002, 0x00000006, []FieldChange{
+			{Field: "priority", Old: 1, New: 3, OldName: "Medium", NewName: "High"},
+		}},
+		{"several fields", 0x00002A57, 0x00000A50, []FieldChange{
+			{Field: "active", Old: 1, New: 0},
+			{Field: "priority", Old: 3, New: 0, OldName: "High", NewName: "Low"},
+			{Field: "error", Old: 42, New: 10},
+		}},
+		{"value without name", 0x00000006, 0x00000004, []FieldChange{
+			{Field: "priority", Old: 3, New: 2, OldName: "High"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.Diff(tt.from, tt.to); !slices.Equal(got, tt.want) {
+				t.Errorf("Diff(0x%08X, 0x%08X) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldChange_String(t *testing.T) {
+	tests := []struct {
+		change FieldChange
+		want   string
+	}{
+		{FieldChange{Field: "mode", Old: 1, New: 2, OldName: "Normal", NewName: "LowPower"}, "mode: Normal -> LowPower"},
+		{FieldChange{Field: "error", Old: 42, New: 0}, "error: 0x2a -> 0x0"},
+		{FieldChange{Register: "CTRL", Field: "mode", Old: 3, New: 1, NewName: "Normal"}, "CTRL.mode: 0x3 -> Normal"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.change.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestLayout_ChangedFields(t *testing.T) {
+	l := newStatusLayout(t)
+	tests := []struct {
+		from, to uint32
+		want     []string
+	}{
+		{0x00002A57, 0x00002A57, nil},
+		{0x00002A57, 0xFFFF2A57, nil},
+		{0x00000002, 0x00000006, []string{"priority"}},
+		{0x00002A57, 0x00000A50, []string{"active", "priority", "error"}},
+	}
+
+	for _, tt := range tests {
+		if got := l.ChangedFields(tt.from, tt.to); !slices.Equal(got, tt.want) {
+			t.Errorf("ChangedFields(0x%08X, 0x%08X) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0xFF, 0x0F, 4},
+		{0, ^uint64(0), 64},
+		{0x8000000000000001, 0x1, 1},
+	}
+
+	for _, tt := range tests {
+		if got := HammingDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("HammingDistance(0x%X, 0x%X) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if got := HammingDistance[uint32](0xFFFF0000, 0x0000FFFF); got != 32 {
+		t.Errorf("HammingDistance[uint32] = %d, want 32", got)
+	}
+}
@@ -0,0 +1,165 @@
+package bitfield
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// SECDED is an extended Hamming code that corrects single-bit errors and detects
+// double-bit errors (SECDED) in up to 64 data bits, as used by ECC memories and
+// robust storage formats. With 64 data bits it is the common (72, 64) code.
+//
+// The check bits hold the Hamming bits in their low bits, bit j covering the data
+// bits whose codeword position has bit j set, and the parity of the whole
+// codeword in the bit above them.
+type SECDED struct {
+	dataBits    uint
+	hammingBits uint
+}
+
+// NewSECDED creates a SECDED code over dataBits data bits.
+// Panics if dataBits is not between 1 and 64.
+func NewSECDED(dataBits uint) SECDED {
+	if dataBits == 0 || dataBits > 64 {
+		panic(fmt.Sprintf("invalid SECDED data size %d", dataBits))
+	}
+	r := uint(2)
+	for 1<<r < dataBits+r+1 {
+		r++
+	}
+	return SECDED{dataBits: dataBits, hammingBits: r}
+}
+
+// DataBits returns the number of data bits protected by the code.
+func (c SECDED) DataBits() uint {
+	return c.dataBits
+}
+
+// CheckBits returns the number of check bits of the code.
+func (c SECDED) CheckBits() uint {
+	return c.hammingBits + 1
+}
+
+// hamming returns the Hamming bits of data: the XOR of the codeword positions of its set bits.
+func (c SECDED) hamming(data uint64) uint64 {
+	var syndrome uint64
+	pos := uint64(2)
+	for i := range c.dataBits {
+		pos++
+		if pos&(pos-1) == 0 { // Skip the positions of the Hamming bits
+			pos++
+		}
+		if data>>i&1 != 0 {
+			syndrome ^= pos
+		}
+	}
+	return syndrome
+}
+
+// Check returns the check bits of data.
+// Bits of data above DataBits are ignored.
+func (c SECDED) Check(data uint64) uint64 {
+	data &= lowBits(c.dataBits)
+	h := c.hamming(data)
+	parity := uint64(bits.OnesCount64(data)+bits.OnesCount64(h)) & 1
+	return h | parity<<c.hammingBits
+}
+
+// Correct checks data against its check bits and corrects a single-bit error
+// in either of them. It returns the corrected data and reports whether an error
+// was corrected; the check bits of the corrected data are given by Check.
+// Returns the data unchanged and an error wrapping ErrUncorrectable if a
+// double-bit error, or another error that cannot be located, is detected.
+func (c SECDED) Correct(data, check uint64) (uint64, bool, error) {
+	data &= lowBits(c.dataBits)
+	check &= lowBits(c.CheckBits())
+	diff := c.Check(data) ^ check
+	syndrome := diff & lowBits(c.hammingBits)
+	// The stored check bits carry the overall parity, so a single flipped bit anywhere
+	// leaves the parity of data and check bits odd.
+	odd := (bits.OnesCount64(data)+bits.OnesCount64(check))&1 == 1
+	switch {
+	case diff == 0:
+		return data, false, nil
+	case !odd:
+		return data, false, fmt.Errorf("%w: double-bit error, syndrome 0x%X", ErrUncorrectable, syndrome)
+	case syndrome == 0 || syndrome&(syndrome-1) == 0:
+		return data, true, nil // Error in a check bit
+	}
+	i := syndrome - uint64(bits.Len64(syndrome)) - 1 // Data index of codeword position syndrome
+	if i >= uint64(c.dataBits) {
+		return data, false, fmt.Errorf("%w: syndrome 0x%X outside the codeword", ErrUncorrectable, syndrome)
+	}
+	return data ^ 1<<i, true, nil
+}
+
+// ECCField protects a data field of a container with SECDED check bits held in
+// another field of the same container.
+type ECCField[U storageType] struct {
+	Data  BitField[uint64, U] // Protected data bits
+	Check BitField[uint64, U] // Check bits, at least Code.CheckBits() wide
+	Code  SECDED              // Code over the data bits
+}
+
+// NewECCField creates an ECCField protecting data with check bits stored in check.
+// Panics if check is too narrow for the code or overlaps data;
+// use SafeECCField to get an error instead.
+func NewECCField[U storageType](data, check BitField[uint64, U]) ECCField[U] {
+	ef, err := SafeECCField(data, check)
+	if err != nil {
+		panic(err.Error())
+	}
+	return ef
+}
+
+// SafeECCField creates an ECCField after validating the fields.
+// Returns an error if check is narrower than the check bits of the code or overlaps data.
+func SafeECCField[U storageType](data, check BitField[uint64, U]) (ECCField[U], error) {
+	if data.Size == 0 || data.Size > 64 {
+		return ECCField[U]{}, fmt.Errorf("data field of %d bits: %w", data.Size, ErrSizeOutOfRange)
+	}
+	code := NewSECDED(data.Size)
+	if check.Size < code.CheckBits() {
+		return ECCField[U]{}, fmt.Errorf("check field of %d bits, need %d for %d data bits", check.Size, code.CheckBits(), data.Size)
+	}
+	if data.Mask&check.Mask != 0 {
+		return ECCField[U]{}, fmt.Errorf("check field overlaps the data field")
+	}
+	return ECCField[U]{Data: data, Check: check, Code: code}, nil
+}
+
+// Encode encodes a value into the data field together with its check bits.
+// Panics if the value is too large for the data field.
+func (ef ECCField[U]) Encode(value uint64) U {
+	return ef.Seal(ef.Data.Encode(value))
+}
+
+// Update sets the data field within an existing container and recomputes the check bits.
+// Panics if the new value is too large for the data field.
+func (ef ECCField[U]) Update(previous U, value uint64) U {
+	return ef.Seal(ef.Data.Update(previous, value))
+}
+
+// Seal returns container with the check bits recomputed from its data field.
+func (ef ECCField[U]) Seal(container U) U {
+	return ef.Check.Update(container, ef.Code.Check(ef.Data.Decode(container)))
+}
+
+// Correct returns container with a single-bit error in its data or check bits
+// corrected, and reports whether an error was corrected.
+// Returns the container unchanged and an error wrapping ErrUncorrectable if the
+// error cannot be corrected.
+func (ef ECCField[U]) Correct(container U) (U, bool, error) {
+	data, corrected, err := ef.Code.Correct(ef.Data.Decode(container), ef.Check.Decode(container))
+	if err != nil || !corrected {
+		return container, false, err
+	}
+	return ef.Seal(ef.Data.Update(container, data)), true, nil
+}
+
+// Decode extracts the data field, correcting a single-bit error if there is one.
+// Returns an error wrapping ErrUncorrectable if the error cannot be corrected.
+func (ef ECCField[U]) Decode(container U) (uint64, error) {
+	data, _, err := ef.Code.Correct(ef.Data.Decode(container), ef.Check.Decode(container))
+	return data, err
+}
@@ -0,0 +1,123 @@
+package bitfield
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestSECDED_CheckBits(t *testing.T) {
+	tests := []struct {
+		dataBits, checkBits uint
+	}{
+		{1, 3},
+		{4, 4},
+		{8, 5},
+		{11, 5},
+		{16, 6},
+		{26, 6},
+		{32, 7},
+		{57, 7},
+		{64, 8},
+	}
+	for _, tt := range tests {
+		if got := NewSECDED(tt.dataBits).CheckBits(); got != tt.checkBits {
+			t.Errorf("NewSECDED(%d).CheckBits() = %d, want %d", tt.dataBits, got, tt.checkBits)
+		}
+	}
+}
+
+func TestSECDED_Hamming74(t *testing.T) {
+	// The data bits 1, 0, 1, 1, least significant first, sit at codeword positions
+	// 3, 5, 6 and 7, so the Hamming bits are 3^6^7 = 0b010 and the codeword has
+	// four ones, giving an overall parity of 0.
+	code := NewSECDED(4)
+	if got := code.Check(0b1101); got != 0b0010 {
+		t.Errorf("Check(0b1101) = 0b%04b, want 0b0010", got)
+	}
+}
+
+func TestSECDED_Correct(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, dataBits := range []uint{1, 4, 8, 13, 32, 57, 64} {
+		code := NewSECDED(dataBits)
+		n := dataBits + code.CheckBits()
+		for range 20 {
+			data := r.Uint64() & lowBits(dataBits)
+			check := code.Check(data)
+
+			if got, corrected, err := code.Correct(data, check); err != nil || corrected || got != data {
+				t.Fatalf("%d bits: Correct(0x%X, 0x%X) = 0x%X, %v, %v, want no error", dataBits, data, check, got, corrected, err)
+			}
+
+			// Every single-bit error is corrected.
+			for bit := range n {
+				d, c := flip(data, check, dataBits, bit)
+				got, corrected, err := code.Correct(d, c)
+				if err != nil || !corrected || got != data {
+					t.Fatalf("%d bits: bit %d flipped: Correct = 0x%X, %v, %v, want 0x%X corrected", dataBits, bit, got, corrected, err, data)
+				}
+			}
+
+			// Every double-bit error is detected.
+			a, b := r.UintN(n), r.UintN(n-1)
+			if b >= a {
+				b++
+			}
+			d, c := flip(data, check, dataBits, a)
+			d, c = flip(d, c, dataBits, b)
+			if _, _, err := code.Correct(d, c); !errors.Is(err, ErrUncorrectable) {
+				t.Fatalf("%d bits: bits %d and %d flipped: err = %v, want ErrUncorrectable", dataBits, a, b, err)
+			}
+		}
+	}
+}
+
+// flip flips bit i of the codeword formed by data followed by check.
+func flip(data, check uint64, dataBits, i uint) (uint64, uint64) {
+	if i < dataBits {
+		return data ^ 1<<i, check
+	}
+	return data, check ^ 1<<(i-dataBits)
+}
+
+func TestECCField(t *testing.T) {
+	// 57 data bits and 7 check bits fill a uint64.
+	ef := NewECCField(New[uint64, uint64](0, 57), New[uint64, uint64](57, 7))
+	container := ef.Encode(0x0123456789ABCDE)
+
+	if value, err := ef.Decode(container); err != nil || value != 0x0123456789ABCDE {
+		t.Errorf("Decode(0x%X) = 0x%X, %v", container, value, err)
+	}
+	for bit := range uint(64) {
+		corrupted := container ^ 1<<bit
+		fixed, corrected, err := ef.Correct(corrupted)
+		if err != nil || !corrected || fixed != container {
+			t.Errorf("Correct(0x%X) = 0x%X, %v, %v, want 0x%X corrected", corrupted, fixed, corrected, err, container)
+		}
+		if value, err := ef.Decode(corrupted); err != nil || value != 0x0123456789ABCDE {
+			t.Errorf("Decode(0x%X) = 0x%X, %v", corrupted, value, err)
+		}
+	}
+	if _, _, err := ef.Correct(container ^ 0b101); !errors.Is(err, ErrUncorrectable) {
+		t.Errorf("Correct with two bits flipped = %v, want ErrUncorrectable", err)
+	}
+
+	updated := ef.Update(container, 42)
+	if fixed, corrected, err := ef.Correct(updated); err != nil || corrected || fixed != updated {
+		t.Errorf("Correct(Update(42)) = 0x%X, %v, %v, want unchanged", fixed, corrected, err)
+	}
+}
+
+func TestSafeECCField(t *testing.T) {
+	data := New[uint64, uint32](0, 16)
+	if _, err := SafeECCField(data, New[uint64, uint32](16, 6)); err != nil {
+		t.Errorf("SafeECCField(16 data, 6 check) = %v", err)
+	}
+	if _, err := SafeECCField(data, New[uint64, uint32](16, 5)); err == nil {
+		t.Error("SafeECCField(16 data, 5 check) succeeded, want error")
+	}
+	if _, err := SafeECCField(data, New[uint64, uint32](10, 6)); err == nil {
+		t.Error("SafeECCField with overlapping fields succeeded, want error")
+	}
+}
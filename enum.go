@@ -0,0 +1,122 @@
+package bitfield
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+)
+
+// fieldMeta holds optional metadata attached to a BitField.
+// It is shared between copies of a field and must not be modified once attached.
+type fieldMeta[T Unsigned] struct {
+	names  map[T]string // Registered enum names by value
+	values map[string]T // Registered enum values by name
+
+	allowed map[T]bool // Legal values, nil if every value that fits is legal
+
+	def T // Default value, set with WithDefault
+
+	name        string // Set with WithName or by Layout.Add
+	description string
+	unit        string
+}
+
+// clone returns a copy of the metadata that can be modified safely.
+func (m *fieldMeta[T]) clone() *fieldMeta[T] {
+	if m == nil {
+		return &fieldMeta[T]{}
+	}
+	c := *m
+	return &c
+}
+
+// WithEnum returns a copy of the field with names registered for its values,
+// so decoded values can be rendered as names and parsed from them.
+// Panics if a value does not fit in the field or a name is used for more than one value.
+func (bf BitField[T, U]) WithEnum(names map[T]string) BitField[T, U] {
+	if err := checkEnum(bf, names); err != nil {
+		panic(err.Error())
+	}
+	meta := bf.meta.clone()
+	meta.names = maps.Clone(names)
+	meta.values = make(map[string]T, len(names))
+	for value, name := range names {
+		meta.values[name] = value
+	}
+	bf.meta = meta
+	return bf
+}
+
+// checkEnum returns an error if a value of names is not a valid value of bf or
+// a name is used for more than one value. Values are of any unsigned type, so
+// that values too large for T are reported rather than truncated.
+func checkEnum[V, T Unsigned, U storageType](bf BitField[T, U], names map[V]string) error {
+	seen := make(map[string]V, len(names))
+	for value, name := range names {
+		if uint64(value) > uint64(bf.Max()) {
+			return fmt.Errorf("enum value %v out of range, max %v", value, bf.Max())
+		}
+		if !bf.IsValid(T(value)) {
+			return fmt.Errorf("enum %w", bf.invalidValue(T(value)))
+		}
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("enum name %q used for %v and %v", name, min(other, value), max(other, value))
+		}
+		seen[name] = value
+	}
+	return nil
+}
+
+// Enum returns a copy of the value names registered with WithEnum, or nil if there are none.
+func (bf BitField[T, U]) Enum() map[T]string {
+	if bf.meta == nil {
+		return nil
+	}
+	return maps.Clone(bf.meta.names)
+}
+
+// Name returns the name registered for value.
+// The second return value reports whether a name is registered.
+func (bf BitField[T, U]) Name(value T) (string, bool) {
+	if bf.meta == nil {
+		return "", false
+	}
+	name, ok := bf.meta.names[value]
+	return name, ok
+}
+
+// ValueString returns the registered name of value, or its decimal representation
+// if no name is registered.
+func (bf BitField[T, U]) ValueString(value T) string {
+	if name, ok := bf.Name(value); ok {
+		return name
+	}
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+// DecodeString extracts the field from a container and renders it like ValueString.
+func (bf BitField[T, U]) DecodeString(container U) string {
+	return bf.ValueString(bf.Decode(container))
+}
+
+// Parse converts a registered name or a numeric literal into a field value.
+// Numeric literals may use the 0x, 0o and 0b prefixes.
+// Returns an error if s is neither a registered name nor a number that fits in the field.
+func (bf BitField[T, U]) Parse(s string) (T, error) {
+	if bf.meta != nil {
+		if value, ok := bf.meta.values[s]; ok {
+			return value, nil
+		}
+	}
+	n, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if uint64(T(n)) != n {
+		return 0, fmt.Errorf("value %v out of range, max %v", n, bf.Max())
+	}
+	if !bf.IsValid(T(n)) {
+		return 0, bf.invalidValue(T(n))
+	}
+	return T(n), nil
+}
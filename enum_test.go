@@ -0,0 +1,107 @@
+package bitfield
+
+import (
+	"maps"
+	"testing"
+)
+
+var colorNames = map[Color]string{
+	Red:    "Red",
+	Green:  "Green",
+	Blue:   "Blue",
+	Yellow: "Yellow",
+}
+
+func TestBitField_WithEnum(t *testing.T) {
+	plain := New[Color, uint32](4, 3)
+	bf := plain.WithEnum(colorNames)
+
+	if got := plain.Enum(); got != nil {
+		t.Errorf("Enum() on plain field = %v, want nil", got)
+	}
+	if got := bf.Enum(); !maps.Equal(got, colorNames) {
+		t.Errorf("Enum() = %v, want %v", got, colorNames)
+	}
+	if bf.Shift != plain.Shift || bf.Size != plain.Size || bf.Mask != plain.Mask {
+		t.Errorf("WithEnum changed the field geometry: %+v", bf)
+	}
+
+	// The registry must not alias the caller's map.
+	names := map[Color]string{Red: "Red"}
+	aliased := plain.WithEnum(names)
+	names[Red] = "Changed"
+	if name, _ := aliased.Name(Red); name != "Red" {
+		t.Errorf("Name(Red) = %q after modifying input map, want %q", name, "Red")
+	}
+}
+
+func TestBitField_WithEnumPanics(t *testing.T) {
+	tests := []struct {
+		name  string
+		names map[Color]string
+	}{
+		{"value out of range", map[Color]string{Color(8): "Eight"}},
+		{"duplicate name", map[Color]string{Red: "Same", Blue: "Same"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WithEnum(%v) did not panic", tt.names)
+				}
+			}()
+			New[Color, uint32](0, 3).WithEnum(tt.names)
+		})
+	}
+}
+
+func TestBitField_DecodeString(t *testing.T) {
+	bf := New[Color, uint32](4, 3).WithEnum(colorNames)
+	tests := []struct {
+		container uint32
+		want      string
+	}{
+		{0x00, "Red"},
+		{0x20, "Blue"},
+		{0x70, "7"},
+	}
+
+	for _, tt := range tests {
+		if got := bf.DecodeString(tt.container); got != tt.want {
+			t.Errorf("DecodeString(0x%X) = %q, want %q", tt.container, got, tt.want)
+		}
+	}
+	if got := New[Color, uint32](0, 3).ValueString(Blue); got != "2" {
+		t.Errorf("ValueString(Blue) on plain field = %q, want %q", got, "2")
+	}
+}
+
+func TestBitField_Parse(t *testing.T) {
+	bf := New[Color, uint32](0, 3).WithEnum(colorNames)
+	tests := []struct {
+		in      string
+		want    Color
+		wantErr bool
+	}{
+		{"Blue", Blue, false},
+		{"Yellow", Yellow, false},
+		{"6", 6, false},
+		{"0b111", 7, false},
+		{"8", 0, true},
+		{"Purple", 0, true},
+		{"-1", 0, true},
+		{"0x100000000", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := bf.Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q): err = %v, want err = %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package bitfield
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the Safe constructors and Layout.Add, for use with errors.Is.
+// Errors that carry more detail wrap one of these.
+var (
+	ErrShiftOutOfRange = errors.New("invalid shift parameter")
+	ErrSizeOutOfRange  = errors.New("invalid size parameter")
+	ErrFieldOverflow   = errors.New("field exceeds bounds")
+)
+
+// Errors returned by Layout.Validate for containers that do not follow the layout.
+// ErrValueNotAllowed is also returned for values rejected by WithAllowed restrictions.
+var (
+	ErrReservedBits    = errors.New("reserved bits set")
+	ErrValueNotAllowed = errors.New("value not allowed")
+)
+
+// Errors returned by ParityField.Decode, CRCField.Verify and the SECDED helpers
+// for data whose check bits do not match.
+var (
+	ErrParity        = errors.New("parity error")
+	ErrChecksum      = errors.New("checksum mismatch")
+	ErrUncorrectable = errors.New("uncorrectable error")
+)
+
+// FieldOverflowError reports a field that extends past the bits available to it,
+// either in the container or in the value type.
+// It wraps ErrFieldOverflow.
+type FieldOverflowError struct {
+	Shift uint // Position of the least significant bit of the field
+	Size  uint // Number of bits in the field
+	Width uint // Number of bits available
+}
+
+// Error implements the error interface.
+func (e *FieldOverflowError) Error() string {
+	return fmt.Sprintf("field of %d bits at bit %d exceeds %d bits", e.Size, e.Shift, e.Width)
+}
+
+// Unwrap returns ErrFieldOverflow.
+func (e *FieldOverflowError) Unwrap() error {
+	return ErrFieldOverflow
+}
@@ -0,0 +1,64 @@
+package bitfield
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	layoutErr := func(shift, size uint) error {
+		return NewLayout[uint32]().Add("f", shift, size)
+	}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"Safe shift", second(Safe[uint32, uint32](32, 1)), ErrShiftOutOfRange},
+		{"Safe size", second(Safe[uint32, uint32](0, 0)), ErrSizeOutOfRange},
+		{"Safe overflow", second(Safe[uint32, uint32](30, 4)), ErrFieldOverflow},
+		{"SafeNext", second(SafeNext[uint8, uint32](New[uint8, uint32](4, 3), 2)), ErrFieldOverflow},
+		{"SafeMSB0 size", second(SafeMSB0[uint8, uint32](0, 9)), ErrSizeOutOfRange},
+		{"SafeMSB0 overflow", second(SafeMSB0[uint8, uint32](30, 4)), ErrFieldOverflow},
+		{"SafeRange reversed", second(SafeRange[uint8, uint32](2, 5)), ErrSizeOutOfRange},
+		{"SafeRange overflow", second(SafeRange[uint8, uint32](33, 30)), ErrFieldOverflow},
+		{"NewFromMask", second(NewFromMask[uint8, uint32](0xFFF)), ErrSizeOutOfRange},
+		{"SafeFieldArray", second(SafeFieldArray[uint8, uint32](4, 2, 15)), ErrFieldOverflow},
+		{"SafeSplit size", second(SafeSplit[uint32, uint32](Segment{Size: 0})), ErrSizeOutOfRange},
+		{"SafeSplit overflow", second(SafeSplit[uint32, uint32](Segment{Shift: 30, Size: 4})), ErrFieldOverflow},
+		{"SafeFlag", second(SafeFlag[uint32](32)), ErrShiftOutOfRange},
+		{"SafeWordField", second(SafeWordField[uint8, uint32](0, 9)), ErrSizeOutOfRange},
+		{"SafeByteField", second(SafeByteField[uint8](0, 0, MSBFirst)), ErrSizeOutOfRange},
+		{"Layout.Add size", layoutErr(0, 0), ErrSizeOutOfRange},
+		{"Layout.Add overflow", layoutErr(30, 4), ErrFieldOverflow},
+		{"Layout.Validate", newStatusLayout(t).Validate(0x10000), ErrReservedBits},
+		{"Parse not allowed", second(New[uint8, uint32](0, 3).WithAllowed(1).Parse("2")), ErrValueNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.want) {
+				t.Errorf("err = %v, want %v", tt.err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldOverflowError(t *testing.T) {
+	err := NewLayout[uint32]().Add("wide", 30, 4)
+	var overflow *FieldOverflowError
+	if !errors.As(err, &overflow) {
+		t.Fatalf("errors.As(%v) failed", err)
+	}
+	if overflow.Shift != 30 || overflow.Size != 4 || overflow.Width != 32 {
+		t.Errorf("FieldOverflowError = %+v, want shift 30, size 4, width 32", *overflow)
+	}
+	if got, want := err.Error(), `field "wide": field of 4 bits at bit 30 exceeds 32 bits`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+// second returns the error of a constructor result.
+func second[T any](_ T, err error) error {
+	return err
+}
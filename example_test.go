@@ -1,6 +1,7 @@
 package bitfield
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 )
@@ -360,3 +361,56 @@ func ExampleBitField_deviceRegister() {
 	// After re-enable: 0x000000AA
 	// After power mode change: 0x000000A9
 }
+
+func ExampleLayout() {
+	// Describe a status register once instead of juggling separate fields
+	status := NewLayout[uint32]()
+	_ = status.Add("active", 0, 1)
+	_ = status.Add("priority", 1, 3)
+	_ = status.Add("category", 4, 4)
+	_ = status.Add("error", 8, 8)
+
+	// Build a container from named values
+	reg, err := status.EncodeAll(map[string]uint64{
+		"active":   1,
+		"priority": 3,
+		"category": 5,
+		"error":    42,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Register: 0x%08X\n", reg)
+
+	// Decode every field at once
+	values := status.DecodeAll(reg)
+	fmt.Printf("Priority: %d\n", values["priority"])
+	fmt.Printf("Error: %d\n", values["error"])
+
+	// Output:
+	// Register: 0x00002A57
+	// Priority: 3
+	// Error: 42
+}
+
+func ExamplePacked_MarshalJSON() {
+	status := NewLayout[uint32]()
+	_ = status.Add("active", 0, 1)
+	_ = status.Add("priority", 1, 3)
+	_ = status.Add("category", 4, 4)
+	_ = status.SetEnum("priority", map[uint64]string{0: "Low", 1: "Medium", 3: "High"})
+
+	// Render the packed word as a readable object
+	data, _ := json.Marshal(status.Bind(0x57))
+	fmt.Println(string(data))
+
+	// Parse it back, using enum names or numbers
+	p := status.Bind(0)
+	_ = json.Unmarshal([]byte(`{"active":true,"priority":"Medium","category":2}`), &p)
+	fmt.Printf("Register: 0x%02X\n", p.Value)
+
+	// Output:
+	// {"active":true,"priority":"High","category":5}
+	// Register: 0x23
+}
@@ -0,0 +1,106 @@
+package bitfield
+
+import "slices"
+
+// FilterSlice returns the containers whose field value satisfies pred, in their
+// original order. Only the one field is decoded for each container.
+func FilterSlice[T Unsigned, U storageType](containers []U, field BitField[T, U], pred func(T) bool) []U {
+	var matched []U
+	for _, c := range containers {
+		if pred(field.Decode(c)) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// FilterIndices returns the indices of the containers whose field value satisfies pred,
+// in ascending order.
+func FilterIndices[T Unsigned, U storageType](containers []U, field BitField[T, U], pred func(T) bool) []int {
+	var indices []int
+	for i, c := range containers {
+		if pred(field.Decode(c)) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// Query matches containers against several field conditions that must all hold,
+// such as "MODE == 2 AND STATE in {1, 3}". Conditions are compared on the raw
+// masked bits, so matching a container decodes no fields.
+// The zero value is not usable; create queries with NewQuery.
+type Query[U storageType] struct {
+	mask, value U // Combined equality conditions: c&mask == value
+	never       bool
+	sets        []setCondition[U]
+}
+
+// setCondition matches a field against a set of values, kept in encoded form.
+type setCondition[U storageType] struct {
+	mask   U
+	values []U
+}
+
+// NewQuery creates a Query without conditions, which matches every container.
+func NewQuery[U storageType]() *Query[U] {
+	return &Query[U]{}
+}
+
+// Where adds the condition that field equals value and returns q for chaining.
+// Panics if the value is too large for the field.
+func (q *Query[U]) Where(field BitField[uint64, U], value uint64) *Query[U] {
+	bits := field.Encode(value)
+	if q.value&field.Mask != q.mask&field.Mask&bits {
+		q.never = true // Conflicts with an earlier condition on overlapping bits
+	}
+	q.mask |= field.Mask
+	q.value = q.value&^field.Mask | bits
+	return q
+}
+
+// In adds the condition that field equals one of values and returns q for chaining.
+// Panics if a value is too large for the field.
+func (q *Query[U]) In(field BitField[uint64, U], values ...uint64) *Query[U] {
+	cond := setCondition[U]{mask: field.Mask, values: make([]U, len(values))}
+	for i, v := range values {
+		cond.values[i] = field.Encode(v)
+	}
+	q.sets = append(q.sets, cond)
+	return q
+}
+
+// Matches reports whether the container satisfies every condition of the query.
+func (q *Query[U]) Matches(container U) bool {
+	if q.never || container&q.mask != q.value {
+		return false
+	}
+	for _, s := range q.sets {
+		if !slices.Contains(s.values, container&s.mask) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the containers that match the query, in their original order.
+func (q *Query[U]) Filter(containers []U) []U {
+	var matched []U
+	for _, c := range containers {
+		if q.Matches(c) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// Indices returns the indices of the containers that match the query, in ascending order.
+func (q *Query[U]) Indices(containers []U) []int {
+	var indices []int
+	for i, c := range containers {
+		if q.Matches(c) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
@@ -0,0 +1,55 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+// Status words: MODE in bits 0-1, STATE in bits 2-4, ERR in bit 7.
+var filterRecords = []uint32{0x01, 0x06, 0x86, 0x0D, 0x02, 0x8E, 0x11}
+
+func TestFilterSlice(t *testing.T) {
+	errFlag := New[uint8, uint32](7, 1)
+	if got, want := FilterSlice(filterRecords, errFlag, func(v uint8) bool { return v == 1 }), []uint32{0x86, 0x8E}; !slices.Equal(got, want) {
+		t.Errorf("FilterSlice(ERR) = %X, want %X", got, want)
+	}
+	state := New[uint8, uint32](2, 3)
+	if got, want := FilterIndices(filterRecords, state, func(v uint8) bool { return v >= 3 }), []int{3, 5, 6}; !slices.Equal(got, want) {
+		t.Errorf("FilterIndices(STATE >= 3) = %v, want %v", got, want)
+	}
+	if got := FilterSlice(filterRecords, state, func(uint8) bool { return false }); got != nil {
+		t.Errorf("FilterSlice with no matches = %X, want nil", got)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	mode := New[uint64, uint32](0, 2)
+	state := New[uint64, uint32](2, 3)
+	errFlag := New[uint64, uint32](7, 1)
+
+	tests := []struct {
+		name string
+		q    *Query[uint32]
+		want []int
+	}{
+		{"no conditions", NewQuery[uint32](), []int{0, 1, 2, 3, 4, 5, 6}},
+		{"equal", NewQuery[uint32]().Where(mode, 2), []int{1, 2, 4, 5}},
+		{"equal and equal", NewQuery[uint32]().Where(mode, 2).Where(errFlag, 0), []int{1, 4}},
+		{"equal and in", NewQuery[uint32]().Where(mode, 2).In(state, 1, 3), []int{1, 2, 5}},
+		{"in", NewQuery[uint32]().In(state, 0, 4), []int{0, 4, 6}},
+		{"conflicting", NewQuery[uint32]().Where(mode, 2).Where(mode, 1), nil},
+		{"repeated", NewQuery[uint32]().Where(mode, 1).Where(mode, 1), []int{0, 3, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.q.Indices(filterRecords)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Indices = %v, want %v", got, tt.want)
+			}
+			if filtered := tt.q.Filter(filterRecords); len(filtered) != len(tt.want) {
+				t.Errorf("Filter returned %d containers, want %d", len(filtered), len(tt.want))
+			}
+		})
+	}
+}
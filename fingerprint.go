@@ -0,0 +1,55 @@
+package bitfield
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Signature returns a canonical description of the layout covering the size of
+// the container and the name, shift, size, enum names and condition of every
+// field, one line per field in ascending order of shift:
+//
+//	bits 32
+//	field "active" 0 1
+//	field "priority" 1 3 enum 0="Low" 1="Medium" 3="High"
+//
+// Two layouts have the same signature if and only if they decode containers the
+// same way, regardless of the order in which their fields were added.
+// Byte order, allowed values and other metadata are not part of the signature.
+func (l *Layout[U]) Signature() string {
+	fields := slices.Clone(l.fields)
+	slices.SortFunc(fields, func(a, b layoutField[U]) int {
+		return cmp.Compare(a.field.Shift, b.field.Shift)
+	})
+	var b strings.Builder
+	fmt.Fprintf(&b, "bits %d\n", unsignedSizeOf[U]())
+	for _, f := range fields {
+		fmt.Fprintf(&b, "field %q %d %d", f.name, f.field.Shift, f.field.Size)
+		if names := f.field.Enum(); len(names) > 0 {
+			b.WriteString(" enum")
+			for _, value := range slices.Sorted(maps.Keys(names)) {
+				fmt.Fprintf(&b, " %d=%q", value, names[value])
+			}
+		}
+		if c := f.cond; c != nil {
+			fmt.Fprintf(&b, " if %q=%d", l.fields[c.field].name, c.value)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Fingerprint returns a stable 64-bit hash of the signature of the layout:
+// the first 8 bytes, big-endian, of its SHA-256 digest.
+// Services exchanging containers can compare fingerprints at handshake time to
+// check that both sides use the same layout, and compare signatures to find out
+// how they differ when they do not.
+func (l *Layout[U]) Fingerprint() uint64 {
+	sum := sha256.Sum256([]byte(l.Signature()))
+	return binary.BigEndian.Uint64(sum[:])
+}
@@ -0,0 +1,86 @@
+package bitfield
+
+import "testing"
+
+func TestLayout_Signature(t *testing.T) {
+	l := newStatusLayout(t)
+
+	want := `bits 32
+field "active" 0 1
+field "priority" 1 3 enum 0="Low" 1="Medium" 3="High"
+field "category" 4 4
+field "error" 8 8
+`
+	if got := l.Signature(); got != want {
+		t.Errorf("Signature() = %q, want %q", got, want)
+	}
+}
+
+func TestLayout_Fingerprint(t *testing.T) {
+	base := newStatusLayout(t)
+
+	// The fingerprint must not change across releases
+	if got, want := base.Fingerprint(), uint64(0xE8822523115E1CDD); got != want {
+		t.Errorf("Fingerprint() = %#x, want %#x", got, want)
+	}
+
+	reordered := NewLayout[uint32]()
+	for _, err := range []error{
+		reordered.Add("error", 8, 8),
+		reordered.Add("category", 4, 4),
+		reordered.Add("priority", 1, 3),
+		reordered.Add("active", 0, 1),
+		reordered.SetEnum("priority", map[uint64]string{3: "High", 1: "Medium", 0: "Low"}),
+		reordered.SetAllowed("category", 1, 2),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reordered.Fingerprint() != base.Fingerprint() {
+		t.Errorf("Fingerprint() differs for the same fields added in another order")
+	}
+
+	tests := []struct {
+		name   string
+		change func(l *Layout[uint32]) error
+	}{
+		{"added field", func(l *Layout[uint32]) error { return l.Add("spare", 16, 1) }},
+		{"renamed enum value", func(l *Layout[uint32]) error {
+			return l.SetEnum("priority", map[uint64]string{0: "Low", 1: "Normal", 3: "High"})
+		}},
+		{"added enum", func(l *Layout[uint32]) error {
+			return l.SetEnum("category", map[uint64]string{0: "none"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newStatusLayout(t)
+			if err := tt.change(l); err != nil {
+				t.Fatal(err)
+			}
+			if l.Fingerprint() == base.Fingerprint() {
+				t.Errorf("Fingerprint() did not change")
+			}
+		})
+	}
+
+	moved := mustLayout(t, testField{"active", 1, 1})
+	resized := mustLayout(t, testField{"active", 0, 2})
+	renamed := mustLayout(t, testField{"enabled", 0, 1})
+	wider := NewLayout[uint64]()
+	if err := wider.Add("active", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	one := mustLayout(t, testField{"active", 0, 1})
+	for name, fp := range map[string]uint64{
+		"moved":   moved.Fingerprint(),
+		"resized": resized.Fingerprint(),
+		"renamed": renamed.Fingerprint(),
+		"wider":   wider.Fingerprint(),
+	} {
+		if fp == one.Fingerprint() {
+			t.Errorf("%s: Fingerprint() did not change", name)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package bitfield
+
+import "math"
+
+// FixedField interprets a BitField as a Qm.n fixed-point number, as used by DSP
+// and motor-control registers. The field stores value * 2^Frac as an integer,
+// in two's complement if Signed is true.
+type FixedField[T Unsigned, U storageType] struct {
+	Field  BitField[T, U] // Field holding the raw fixed-point value
+	Frac   uint           // Number of fractional bits (n)
+	Signed bool           // Whether the raw value is two's complement
+}
+
+// NewQ creates a signed Qm.n field at the given shift.
+// The field occupies 1 + m + n bits: a sign bit, m integer bits and n fractional bits.
+func NewQ[T Unsigned, U storageType](shift, m, n uint) FixedField[T, U] {
+	return FixedField[T, U]{Field: New[T, U](shift, 1+m+n), Frac: n, Signed: true}
+}
+
+// NewUQ creates an unsigned UQm.n field at the given shift.
+// The field occupies m + n bits: m integer bits and n fractional bits.
+func NewUQ[T Unsigned, U storageType](shift, m, n uint) FixedField[T, U] {
+	return FixedField[T, U]{Field: New[T, U](shift, m+n), Frac: n}
+}
+
+// Decode extracts the field from the container and converts it to a float64.
+func (ff FixedField[T, U]) Decode(container U) float64 {
+	return float64(ff.toInt(uint64(ff.Field.Decode(container)))) / ff.scale()
+}
+
+// Encode converts value to the fixed-point representation and encodes it into the field.
+// Values are rounded to the nearest representable step and saturate at Min and Max.
+// NaN encodes as 0.
+func (ff FixedField[T, U]) Encode(value float64) U {
+	return ff.Field.Encode(ff.Raw(value))
+}
+
+// Update converts value to the fixed-point representation and sets the field within
+// an existing container, saturating like Encode.
+func (ff FixedField[T, U]) Update(previous U, value float64) U {
+	return ff.Field.Update(previous, ff.Raw(value))
+}
+
+// Raw returns the raw field value for value, rounding and saturating like Encode.
+func (ff FixedField[T, U]) Raw(value float64) T {
+	if math.IsNaN(value) {
+		return 0
+	}
+	lo, hi := ff.intRange()
+	scaled := math.Round(value * ff.scale())
+	var raw int64
+	switch {
+	case scaled <= float64(lo):
+		raw = lo
+	case scaled >= float64(hi):
+		raw = hi
+	default:
+		raw = int64(scaled)
+	}
+	return T(uint64(raw) & lowBits(ff.Field.Size))
+}
+
+// Min returns the smallest value the field can represent.
+func (ff FixedField[T, U]) Min() float64 {
+	lo, _ := ff.intRange()
+	return float64(lo) / ff.scale()
+}
+
+// Max returns the largest value the field can represent.
+func (ff FixedField[T, U]) Max() float64 {
+	_, hi := ff.intRange()
+	return float64(hi) / ff.scale()
+}
+
+// Resolution returns the difference between two adjacent representable values.
+func (ff FixedField[T, U]) Resolution() float64 {
+	return 1 / ff.scale()
+}
+
+// scale returns 2^Frac.
+func (ff FixedField[T, U]) scale() float64 {
+	return math.Ldexp(1, int(ff.Frac))
+}
+
+// intRange returns the smallest and largest raw values as integers.
+func (ff FixedField[T, U]) intRange() (lo, hi int64) {
+	size := ff.Field.Size
+	if ff.Signed {
+		return -1 << (size - 1), 1<<(size-1) - 1
+	}
+	if size >= 63 {
+		return 0, math.MaxInt64
+	}
+	return 0, 1<<size - 1
+}
+
+// toInt interprets a raw field value as an integer, sign extending it if needed.
+func (ff FixedField[T, U]) toInt(raw uint64) int64 {
+	if !ff.Signed || ff.Field.Size >= 64 {
+		return int64(raw)
+	}
+	shift := 64 - ff.Field.Size
+	return int64(raw<<shift) >> shift
+}
@@ -0,0 +1,77 @@
+package bitfield
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFixedField_Signed(t *testing.T) {
+	// Q1.14 in the upper half of a 32-bit register: range [-2, 2).
+	q := NewQ[uint16, uint32](16, 1, 14)
+	if q.Field.Size != 16 {
+		t.Fatalf("size = %d, want 16", q.Field.Size)
+	}
+	tests := []struct {
+		name  string
+		value float64
+		raw   uint16
+		back  float64
+	}{
+		{"zero", 0, 0x0000, 0},
+		{"one", 1, 0x4000, 1},
+		{"minus one", -1, 0xC000, -1},
+		{"half step rounds", 0.5 + 1.0/(1<<15), 0x2000 + 1, 0.5 + 1.0/(1<<14)},
+		{"saturate high", 5, 0x7FFF, 2 - 1.0/(1<<14)},
+		{"saturate low", -5, 0x8000, -2},
+		{"nan", math.NaN(), 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := q.Encode(tt.value)
+			if got := q.Field.Decode(c); got != tt.raw {
+				t.Errorf("Encode(%v) raw = 0x%04X, want 0x%04X", tt.value, got, tt.raw)
+			}
+			if got := q.Decode(c); got != tt.back {
+				t.Errorf("Decode(Encode(%v)) = %v, want %v", tt.value, got, tt.back)
+			}
+		})
+	}
+	if q.Min() != -2 || q.Max() != 2-1.0/(1<<14) {
+		t.Errorf("range = [%v, %v], want [-2, %v]", q.Min(), q.Max(), 2-1.0/(1<<14))
+	}
+}
+
+func TestFixedField_Unsigned(t *testing.T) {
+	// UQ4.4 in the low byte.
+	uq := NewUQ[uint8, uint32](0, 4, 4)
+	tests := []struct {
+		value float64
+		want  uint32
+	}{
+		{0, 0x00},
+		{1.5, 0x18},
+		{15.9375, 0xFF},
+		{100, 0xFF},
+		{-1, 0x00},
+	}
+
+	for _, tt := range tests {
+		if got := uq.Update(0xFFFFFF00, tt.value); got != 0xFFFFFF00|tt.want {
+			t.Errorf("Update(%v) = 0x%08X, want 0x%08X", tt.value, got, 0xFFFFFF00|tt.want)
+		}
+	}
+	if got := uq.Resolution(); got != 0.0625 {
+		t.Errorf("Resolution() = %v, want 0.0625", got)
+	}
+}
+
+func TestFixedField_FullWidth(t *testing.T) {
+	q := NewQ[uint64, uint64](0, 31, 32)
+	if got := q.Decode(q.Encode(-1.25)); got != -1.25 {
+		t.Errorf("Decode(Encode(-1.25)) = %v, want -1.25", got)
+	}
+	if got := q.Decode(q.Encode(math.Inf(-1))); got != q.Min() {
+		t.Errorf("Decode(Encode(-Inf)) = %v, want %v", got, q.Min())
+	}
+}
@@ -0,0 +1,85 @@
+package bitfield
+
+import "fmt"
+
+// Flag represents a single-bit boolean field within a container.
+// It avoids the Update(c, 1) / Decode(c) == 1 boilerplate of a 1-bit BitField.
+// U represents the container type where the flag will be stored.
+type Flag[U storageType] struct {
+	Bit  uint // Position of the flag bit
+	Mask U    // Mask with a 1 in the flag position
+}
+
+// NewFlag creates a new Flag at the given bit position.
+// Note: This function doesn't perform validation, use SafeFlag for validated creation.
+func NewFlag[U storageType](bit uint) Flag[U] {
+	return Flag[U]{Bit: bit, Mask: U(1) << bit}
+}
+
+// SafeFlag creates a new Flag at the given bit position, after validating it.
+// Returns an error if bit is outside the container type U.
+func SafeFlag[U storageType](bit uint) (Flag[U], error) {
+	if bit >= unsignedSizeOf[U]() {
+		return Flag[U]{}, fmt.Errorf("bit %d: %w", bit, ErrShiftOutOfRange)
+	}
+	return NewFlag[U](bit), nil
+}
+
+// Set returns the container with the flag set.
+func (f Flag[U]) Set(container U) U {
+	return container | f.Mask
+}
+
+// ClearFlag returns the container with the flag cleared.
+func (f Flag[U]) ClearFlag(container U) U {
+	return container &^ f.Mask
+}
+
+// IsSet reports whether the flag is set in the container.
+func (f Flag[U]) IsSet(container U) bool {
+	return container&f.Mask != 0
+}
+
+// Toggle returns the container with the flag inverted.
+func (f Flag[U]) Toggle(container U) U {
+	return container ^ f.Mask
+}
+
+// SetTo returns the container with the flag set if on is true and cleared otherwise.
+func (f Flag[U]) SetTo(container U, on bool) U {
+	if on {
+		return f.Set(container)
+	}
+	return f.ClearFlag(container)
+}
+
+// HasAll reports whether all of flags are set in the container.
+// It reports true if no flags are given.
+func HasAll[U storageType](container U, flags ...Flag[U]) bool {
+	mask := flagMask(flags)
+	return container&mask == mask
+}
+
+// HasAny reports whether any of flags is set in the container.
+func HasAny[U storageType](container U, flags ...Flag[U]) bool {
+	return container&flagMask(flags) != 0
+}
+
+// SetFlags returns the container with all of flags set.
+func SetFlags[U storageType](container U, flags ...Flag[U]) U {
+	return container | flagMask(flags)
+}
+
+// ClearFlags returns the container with all of flags cleared.
+func ClearFlags[U storageType](container U, flags ...Flag[U]) U {
+	return container &^ flagMask(flags)
+}
+
+// flagMask returns the combined mask of flags.
+func flagMask[U storageType](flags []Flag[U]) U {
+	var mask U
+	for _, f := range flags {
+		mask |= f.Mask
+	}
+	return mask
+}
@@ -0,0 +1,48 @@
+package bitfield
+
+import "testing"
+
+func TestSafeFlag(t *testing.T) {
+	if _, err := SafeFlag[uint32](31); err != nil {
+		t.Errorf("SafeFlag(31): %v", err)
+	}
+	if _, err := SafeFlag[uint32](32); err == nil {
+		t.Error("SafeFlag(32) succeeded, want error")
+	}
+}
+
+func TestFlag(t *testing.T) {
+	f := NewFlag[uint32](4)
+	tests := []struct {
+		name string
+		fn   func(uint32) uint32
+		in   uint32
+		want uint32
+	}{
+		{"set", f.Set, 0x00, 0x10},
+		{"set already set", f.Set, 0x10, 0x10},
+		{"clear", f.ClearFlag, 0xFF, 0xEF},
+		{"toggle on", f.Toggle, 0x01, 0x11},
+		{"toggle off", f.Toggle, 0x11, 0x01},
+		{"set to true", func(c uint32) uint32 { return f.SetTo(c, true) }, 0x00, 0x10},
+		{"set to false", func(c uint32) uint32 { return f.SetTo(c, false) }, 0x10, 0x00},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.in); got != tt.want {
+				t.Errorf("%s(0x%X) = 0x%X, want 0x%X", tt.name, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlag_IsSet(t *testing.T) {
+	f := NewFlag[uint64](63)
+	if f.IsSet(0x7FFFFFFFFFFFFFFF) {
+		t.Error("IsSet(0x7FFFFFFFFFFFFFFF) = true, want false")
+	}
+	if !f.IsSet(0x8000000000000000) {
+		t.Error("IsSet(0x8000000000000000) = false, want true")
+	}
+}
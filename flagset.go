@@ -0,0 +1,156 @@
+package bitfield
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ParseMode selects how strictly ParseFlags treats its input.
+type ParseMode int
+
+const (
+	ParseStrict  ParseMode = iota // Exact names only; unknown names and empty parts are errors
+	ParseLenient                  // Names match case-insensitively; unknown names and empty parts are skipped
+)
+
+// FlagSet names the flags of an options bitmask, a container where each bit is
+// an independent boolean, such as open(2) flags or interrupt enable registers.
+// Flags are kept in bit order.
+type FlagSet[U storageType] struct {
+	names []string
+	flags []Flag[U]
+}
+
+// NewFlagSet creates an empty FlagSet.
+func NewFlagSet[U storageType]() *FlagSet[U] {
+	return &FlagSet[U]{}
+}
+
+// Add registers a flag at the given bit position and returns it.
+// Returns an error if the name is empty or already used, the bit is outside
+// the container type U, or the bit already has a name.
+func (s *FlagSet[U]) Add(name string, bit uint) (Flag[U], error) {
+	if name == "" {
+		return Flag[U]{}, fmt.Errorf("flag name must not be empty")
+	}
+	if slices.Contains(s.names, name) {
+		return Flag[U]{}, fmt.Errorf("duplicate flag %q", name)
+	}
+	f, err := SafeFlag[U](bit)
+	if err != nil {
+		return Flag[U]{}, err
+	}
+	i, found := slices.BinarySearchFunc(s.flags, bit, func(f Flag[U], bit uint) int {
+		return int(f.Bit) - int(bit)
+	})
+	if found {
+		return Flag[U]{}, fmt.Errorf("flag %q overlaps flag %q at bit %d", name, s.names[i], bit)
+	}
+	s.names = slices.Insert(s.names, i, name)
+	s.flags = slices.Insert(s.flags, i, f)
+	return f, nil
+}
+
+// Flag returns the flag with the given name.
+// The second return value reports whether the flag exists.
+func (s *FlagSet[U]) Flag(name string) (Flag[U], bool) {
+	i := slices.Index(s.names, name)
+	if i < 0 {
+		return Flag[U]{}, false
+	}
+	return s.flags[i], true
+}
+
+// Names returns the names of all registered flags in bit order.
+func (s *FlagSet[U]) Names() []string {
+	return slices.Clone(s.names)
+}
+
+// Mask returns the combined mask of all registered flags.
+func (s *FlagSet[U]) Mask() U {
+	return flagMask(s.flags)
+}
+
+// All returns an iterator over the names and flags that are set in the container,
+// in bit order. Set bits without a registered name are skipped.
+func (s *FlagSet[U]) All(container U) iter.Seq2[string, Flag[U]] {
+	return func(yield func(string, Flag[U]) bool) {
+		for i, f := range s.flags {
+			if f.IsSet(container) && !yield(s.names[i], f) {
+				return
+			}
+		}
+	}
+}
+
+// Set returns the names of the flags that are set in the container, in bit order.
+func (s *FlagSet[U]) Set(container U) []string {
+	var names []string
+	for name := range s.All(container) {
+		names = append(names, name)
+	}
+	return names
+}
+
+// FormatFlags returns the names of the flags set in the container joined by "|",
+// as in "ACTIVE|HIGH_PRIORITY". Set bits without a registered name are appended
+// as a hexadecimal number, and a container without set bits formats as "0".
+func (s *FlagSet[U]) FormatFlags(container U) string {
+	names := s.Set(container)
+	if rest := container &^ s.Mask(); rest != 0 {
+		names = append(names, fmt.Sprintf("0x%X", rest))
+	}
+	if len(names) == 0 {
+		return "0"
+	}
+	return strings.Join(names, "|")
+}
+
+// ParseFlags parses flag names joined by "|" into a container, the inverse of FormatFlags.
+// Whitespace around names is ignored, and numbers with an optional 0x, 0o or 0b
+// prefix are accepted for bits without a name.
+// Returns an error if a number does not fit in U, and in ParseStrict mode
+// if a name is unknown or a part is empty.
+func (s *FlagSet[U]) ParseFlags(str string, mode ParseMode) (U, error) {
+	var container U
+	for _, part := range strings.Split(str, "|") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if mode == ParseStrict {
+				return 0, fmt.Errorf("empty flag in %q", str)
+			}
+			continue
+		}
+		if f, ok := s.lookup(part, mode); ok {
+			container |= f.Mask
+			continue
+		}
+		v, err := strconv.ParseUint(part, 0, int(unsignedSizeOf[U]()))
+		switch {
+		case err == nil:
+			container |= U(v)
+		case errors.Is(err, strconv.ErrRange):
+			return 0, fmt.Errorf("flag value %s exceeds container width", part)
+		case mode == ParseStrict:
+			return 0, fmt.Errorf("unknown flag %q", part)
+		}
+	}
+	return container, nil
+}
+
+// lookup finds a flag by name, ignoring case in ParseLenient mode.
+func (s *FlagSet[U]) lookup(name string, mode ParseMode) (Flag[U], bool) {
+	if f, ok := s.Flag(name); ok || mode != ParseLenient {
+		return f, ok
+	}
+	for i, n := range s.names {
+		if strings.EqualFold(n, name) {
+			return s.flags[i], true
+		}
+	}
+	return Flag[U]{}, false
+}
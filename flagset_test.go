@@ -0,0 +1,129 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func openFlags(t *testing.T) *FlagSet[uint32] {
+	t.Helper()
+	s := NewFlagSet[uint32]()
+	for _, f := range []struct {
+		name string
+		bit  uint
+	}{{"WRITE", 1}, {"READ", 0}, {"CREATE", 6}, {"APPEND", 10}} {
+		if _, err := s.Add(f.name, f.bit); err != nil {
+			t.Fatalf("Add(%s): %v", f.name, err)
+		}
+	}
+	return s
+}
+
+func TestFlagHelpers(t *testing.T) {
+	read, write, exec := NewFlag[uint32](0), NewFlag[uint32](1), NewFlag[uint32](2)
+	c := SetFlags(0, read, write)
+	if c != 0x3 {
+		t.Errorf("SetFlags = 0x%X, want 0x3", c)
+	}
+	if !HasAll(c, read, write) || HasAll(c, read, exec) || !HasAll(c) {
+		t.Error("HasAll reported wrong result")
+	}
+	if !HasAny(c, exec, write) || HasAny(c, exec) || HasAny(c) {
+		t.Error("HasAny reported wrong result")
+	}
+	if got := ClearFlags(0xFF, read, exec); got != 0xFA {
+		t.Errorf("ClearFlags(0xFF) = 0x%X, want 0xFA", got)
+	}
+}
+
+func TestFlagSet(t *testing.T) {
+	s := openFlags(t)
+	if got, want := s.Names(), []string{"READ", "WRITE", "CREATE", "APPEND"}; !slices.Equal(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if s.Mask() != 0x443 {
+		t.Errorf("Mask() = 0x%X, want 0x443", s.Mask())
+	}
+	if f, ok := s.Flag("CREATE"); !ok || f.Bit != 6 {
+		t.Errorf("Flag(CREATE) = %+v, %v", f, ok)
+	}
+
+	c := uint32(0x441)
+	if got, want := s.Set(c), []string{"READ", "CREATE", "APPEND"}; !slices.Equal(got, want) {
+		t.Errorf("Set(0x%X) = %v, want %v", c, got, want)
+	}
+	var bits []uint
+	for _, f := range s.All(c) {
+		bits = append(bits, f.Bit)
+		break
+	}
+	if !slices.Equal(bits, []uint{0}) {
+		t.Errorf("All stopped early yielded bits %v, want [0]", bits)
+	}
+}
+
+func TestFlagSet_Errors(t *testing.T) {
+	s := openFlags(t)
+	adds := []struct {
+		name string
+		bit  uint
+	}{{"", 3}, {"READ", 3}, {"SYNC", 1}, {"SYNC", 32}}
+	for _, a := range adds {
+		if _, err := s.Add(a.name, a.bit); err == nil {
+			t.Errorf("Add(%q, %d) succeeded, want error", a.name, a.bit)
+		}
+	}
+}
+
+func TestFlagSet_FormatFlags(t *testing.T) {
+	s := openFlags(t)
+	tests := []struct {
+		container uint32
+		want      string
+	}{
+		{0, "0"},
+		{0x3, "READ|WRITE"},
+		{0x401, "READ|APPEND"},
+		{0x8002, "WRITE|0x8000"},
+		{0x30, "0x30"},
+	}
+
+	for _, tt := range tests {
+		got := s.FormatFlags(tt.container)
+		if got != tt.want {
+			t.Errorf("FormatFlags(0x%X) = %q, want %q", tt.container, got, tt.want)
+		}
+		if back, err := s.ParseFlags(got, ParseStrict); err != nil || back != tt.container {
+			t.Errorf("ParseFlags(%q) = 0x%X, %v, want 0x%X", got, back, err, tt.container)
+		}
+	}
+}
+
+func TestFlagSet_ParseFlags(t *testing.T) {
+	s := openFlags(t)
+	tests := []struct {
+		in      string
+		mode    ParseMode
+		want    uint32
+		wantErr bool
+	}{
+		{"READ|CREATE", ParseStrict, 0x41, false},
+		{" WRITE | 0b100 ", ParseStrict, 0x6, false},
+		{"read", ParseStrict, 0, true},
+		{"EXEC", ParseStrict, 0, true},
+		{"READ|", ParseStrict, 0, true},
+		{"", ParseStrict, 0, true},
+		{"0x100000000", ParseStrict, 0, true},
+		{"read|Append", ParseLenient, 0x401, false},
+		{"READ|EXEC||WRITE", ParseLenient, 0x3, false},
+		{"", ParseLenient, 0, false},
+		{"0x100000000", ParseLenient, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := s.ParseFlags(tt.in, tt.mode)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFlags(%q, %d) = 0x%X, %v, want 0x%X, err = %v", tt.in, tt.mode, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
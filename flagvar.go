@@ -0,0 +1,53 @@
+package bitfield
+
+import (
+	"flag"
+	"strconv"
+)
+
+// fieldFlag implements flag.Value for a single field of a container.
+type fieldFlag[T Unsigned, U storageType] struct {
+	container *U
+	field     BitField[T, U]
+}
+
+// String returns the current value of the field, using its enum name if one is registered.
+func (f *fieldFlag[T, U]) String() string {
+	if f.container == nil {
+		return "" // Zero value, used by flag to detect defaults
+	}
+	return f.field.DecodeString(*f.container)
+}
+
+// Set parses an enum name or a number into the field.
+// Boolean flags also accept the values understood by strconv.ParseBool.
+func (f *fieldFlag[T, U]) Set(s string) error {
+	if f.IsBoolFlag() {
+		if b, err := strconv.ParseBool(s); err == nil {
+			s = "0"
+			if b {
+				s = "1"
+			}
+		}
+	}
+	value, err := f.field.Parse(s)
+	if err != nil {
+		return err
+	}
+	*f.container = f.field.Update(*f.container, value)
+	return nil
+}
+
+// IsBoolFlag reports whether the field is a 1-bit field without enum names,
+// which the flag package then accepts without a value, as in -verbose.
+func (f *fieldFlag[T, U]) IsBoolFlag() bool {
+	return f.field.Size == 1 && f.field.Enum() == nil
+}
+
+// FieldVar defines a flag with the given name and usage that sets field within *container.
+// The flag accepts enum names registered with WithEnum and numbers with an optional
+// 0x, 0o or 0b prefix. Flags for 1-bit fields without enum names are boolean flags.
+// The default shown in the usage message is the value of the field when FieldVar is called.
+func FieldVar[T Unsigned, U storageType](fs *flag.FlagSet, container *U, field BitField[T, U], name, usage string) {
+	fs.Var(&fieldFlag[T, U]{container: container, field: field}, name, usage)
+}
@@ -0,0 +1,61 @@
+package bitfield
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFieldVar(t *testing.T) {
+	color := New[Color, uint32](0, 3).WithEnum(colorNames)
+	level := New[uint8, uint32](3, 4)
+	verbose := New[uint8, uint32](7, 1)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    uint32
+		wantErr bool
+	}{
+		{"defaults", nil, 0x0002, false},
+		{"enum name", []string{"-color", "Yellow"}, 0x0003, false},
+		{"number", []string{"-color=1", "-level", "0xA"}, 0x0051, false},
+		{"bool flag", []string{"-verbose"}, 0x0082, false},
+		{"bool flag false", []string{"-verbose=false"}, 0x0002, false},
+		{"bool flag number", []string{"-verbose=1"}, 0x0082, false},
+		{"unknown name", []string{"-color", "Pink"}, 0x0002, true},
+		{"value too large", []string{"-level", "16"}, 0x0002, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			options := color.Encode(Blue)
+			FieldVar(fs, &options, color, "color", "LED color")
+			FieldVar(fs, &options, level, "level", "log level")
+			FieldVar(fs, &options, verbose, "verbose", "verbose output")
+
+			err := fs.Parse(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q): err = %v, want err = %v", tt.args, err, tt.wantErr)
+			}
+			if options != tt.want {
+				t.Errorf("Parse(%q): options = 0x%08X, want 0x%08X", tt.args, options, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldVar_Usage(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var buf strings.Builder
+	fs.SetOutput(&buf)
+	options := uint32(0x0002)
+	FieldVar(fs, &options, New[Color, uint32](0, 3).WithEnum(colorNames), "color", "LED color")
+	fs.PrintDefaults()
+	if got := buf.String(); !strings.Contains(got, `(default Blue)`) {
+		t.Errorf("PrintDefaults() = %q, want default Blue", got)
+	}
+}
@@ -0,0 +1,61 @@
+package bitfield
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String returns the container in the field=value form of MarshalText.
+func (p Packed[U]) String() string {
+	text, err := p.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("Packed(%#x)", uint64(p.Value))
+	}
+	return string(text)
+}
+
+// Format implements fmt.Formatter:
+//   - %v and %s print the fields in field=value form, as String does
+//   - %q prints the same as a quoted string
+//   - %b prints every bit of the container, most significant first, with '_'
+//     between field boundaries; the '#' flag adds a 0b prefix
+//   - %d, %o, %x and %X print the raw container value, honoring all flags
+//
+// Other verbs, and containers without a layout, are formatted as the raw value.
+func (p Packed[U]) Format(f fmt.State, verb rune) {
+	if p.Layout == nil {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), p.Value)
+		return
+	}
+	switch verb {
+	case 'v', 's':
+		fmt.Fprint(f, p.String())
+	case 'q':
+		fmt.Fprintf(f, "%q", p.String())
+	case 'b':
+		if f.Flag('#') {
+			fmt.Fprint(f, "0b")
+		}
+		fmt.Fprint(f, p.groupedBits())
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), p.Value)
+	}
+}
+
+// groupedBits renders the bits of the container, most significant first,
+// with '_' at each boundary between fields or between a field and unused bits.
+func (p Packed[U]) groupedBits() string {
+	var b strings.Builder
+	cells := p.Layout.diagramCells(unsignedSizeOf[U]()-1, 0, p.Layout.used)
+	bit := int(unsignedSizeOf[U]()) - 1
+	for i, c := range cells {
+		if i > 0 {
+			b.WriteByte('_')
+		}
+		for range c.bits {
+			b.WriteByte('0' + byte(p.Value>>bit&1))
+			bit--
+		}
+	}
+	return b.String()
+}
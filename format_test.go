@@ -0,0 +1,50 @@
+package bitfield
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPacked_Format(t *testing.T) {
+	p := newStatusLayout(t).Bind(0x00002A57)
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%v", "active=1,priority=High,category=5,error=42"},
+		{"%s", "active=1,priority=High,category=5,error=42"},
+		{"%q", `"active=1,priority=High,category=5,error=42"`},
+		{"%b", "0000000000000000_00101010_0101_011_1"},
+		{"%#b", "0b0000000000000000_00101010_0101_011_1"},
+		{"%x", "2a57"},
+		{"%#08X", "0X00002A57"},
+		{"%d", "10839"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, p); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestPacked_FormatWithoutLayout(t *testing.T) {
+	p := Packed[uint32]{Value: 0x2A}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%v", "42"},
+		{"%b", "101010"},
+		{"%#x", "0x2a"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, p); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+	if got := p.String(); got != "Packed(0x2a)" {
+		t.Errorf("String() = %q, want Packed(0x2a)", got)
+	}
+}
@@ -0,0 +1,41 @@
+package bitfield
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SortByField sorts the containers in ascending order of a field's value.
+// The sort is stable, so containers with equal field values keep their order,
+// and sorting by several fields in turn orders by the last one first.
+func SortByField[T Unsigned, U storageType](containers []U, field BitField[T, U]) {
+	SortByFieldFunc(containers, field, cmp.Compare[T])
+}
+
+// SortByFieldFunc sorts the containers by a field's value using the comparison
+// function compare, as in slices.SortStableFunc.
+func SortByFieldFunc[T Unsigned, U storageType](containers []U, field BitField[T, U], compare func(a, b T) int) {
+	slices.SortStableFunc(containers, func(a, b U) int {
+		return compare(field.Decode(a), field.Decode(b))
+	})
+}
+
+// GroupByField returns the indices of the containers grouped by a field's value.
+// The indices within each group are in ascending order.
+func GroupByField[T Unsigned, U storageType](containers []U, field BitField[T, U]) map[T][]int {
+	groups := make(map[T][]int)
+	for i, c := range containers {
+		v := field.Decode(c)
+		groups[v] = append(groups[v], i)
+	}
+	return groups
+}
+
+// CountByField returns the number of containers holding each value of a field.
+func CountByField[T Unsigned, U storageType](containers []U, field BitField[T, U]) map[T]int {
+	counts := make(map[T]int)
+	for _, c := range containers {
+		counts[field.Decode(c)]++
+	}
+	return counts
+}
@@ -0,0 +1,36 @@
+package bitfield
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestSortByField(t *testing.T) {
+	state := New[uint8, uint32](2, 3)
+	records := slices.Clone(filterRecords)
+	SortByField(records, state)
+	if want := []uint32{0x01, 0x02, 0x06, 0x86, 0x0D, 0x8E, 0x11}; !slices.Equal(records, want) {
+		t.Errorf("SortByField(STATE) = %X, want %X", records, want)
+	}
+
+	SortByFieldFunc(records, New[uint8, uint32](0, 2), func(a, b uint8) int { return cmp.Compare(b, a) })
+	if want := []uint32{0x02, 0x06, 0x86, 0x8E, 0x01, 0x0D, 0x11}; !slices.Equal(records, want) {
+		t.Errorf("SortByFieldFunc(MODE, descending) = %X, want %X", records, want)
+	}
+}
+
+func TestGroupByField(t *testing.T) {
+	mode := New[uint8, uint32](0, 2)
+	groups := GroupByField(filterRecords, mode)
+	want := map[uint8][]int{1: {0, 3, 6}, 2: {1, 2, 4, 5}}
+	if !maps.EqualFunc(groups, want, slices.Equal) {
+		t.Errorf("GroupByField(MODE) = %v, want %v", groups, want)
+	}
+
+	counts := CountByField(filterRecords, New[uint8, uint32](7, 1))
+	if wantCounts := map[uint8]int{0: 5, 1: 2}; !maps.Equal(counts, wantCounts) {
+		t.Errorf("CountByField(ERR) = %v, want %v", counts, wantCounts)
+	}
+}
@@ -0,0 +1,104 @@
+package bitfield
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// StructLayout is a Layout inferred from a Go struct type by InferLayout,
+// with the mapping between the struct fields and the fields of the layout.
+type StructLayout[U storageType] struct {
+	layout *Layout[U]
+	typ    reflect.Type
+	fields []structField
+}
+
+// InferLayout derives a packed Layout from the struct type of v, so that an
+// existing configuration struct can be stored in a container without tagging
+// every field. v must be a struct or a pointer to a struct; only its type is used.
+//
+// The exported unsigned integer and bool fields of the struct are packed in
+// declaration order from bit 0, each taking the width of its type, or 1 bit for
+// bools. A field can be narrowed with a hint of the form `bits:"4"`, and left
+// out with `bits:"-"`. Fields of other types and unexported fields are ignored.
+// Layout fields are named after the struct fields.
+// Returns an error if a hint is invalid or the fields do not fit in U.
+func InferLayout[U storageType](v any) (*StructLayout[U], error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot infer layout of %T, want struct", v)
+	}
+	s := &StructLayout[U]{layout: NewLayout[U](), typ: t}
+	var shift uint
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		hint, hinted := f.Tag.Lookup("bits")
+		if hint == "-" || !f.IsExported() {
+			continue
+		}
+		var size uint
+		switch f.Type.Kind() {
+		case reflect.Bool:
+			size = 1
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			size = uint(f.Type.Bits())
+		default:
+			if hinted {
+				return nil, fmt.Errorf("field %s: unsupported type %v", f.Name, f.Type)
+			}
+			continue
+		}
+		if hinted {
+			n, err := strconv.ParseUint(hint, 10, 0)
+			if err != nil || n == 0 || uint(n) > size {
+				return nil, fmt.Errorf("field %s: invalid bits hint %q for %v", f.Name, hint, f.Type)
+			}
+			size = uint(n)
+		}
+		if width := unsignedSizeOf[U](); shift+size > width {
+			return nil, fmt.Errorf("field %s: %w", f.Name, &FieldOverflowError{Shift: shift, Size: size, Width: width})
+		}
+		if err := s.layout.Add(f.Name, shift, size); err != nil {
+			return nil, err
+		}
+		s.fields = append(s.fields, structField{index: i, name: f.Name})
+		shift += size
+	}
+	return s, nil
+}
+
+// Layout returns the inferred layout.
+func (s *StructLayout[U]) Layout() *Layout[U] {
+	return s.layout
+}
+
+// Pack packs the fields of v, a value of the struct type or a pointer to one,
+// into a container.
+// Returns an error if v has another type or a value does not fit in a field
+// narrowed with a bits hint.
+func (s *StructLayout[U]) Pack(v any) (U, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Type() != s.typ {
+		return 0, fmt.Errorf("cannot pack %T, want %v", v, s.typ)
+	}
+	return packStruct(s.layout, s.fields, rv)
+}
+
+// Unpack decodes a container into the fields of the struct pointed to by v.
+// Struct fields that are not in the layout are left untouched.
+// Returns an error if v is not a non-nil pointer to the struct type.
+func (s *StructLayout[U]) Unpack(container U, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Type() != s.typ {
+		return fmt.Errorf("cannot unpack into %T, want non-nil *%v", v, s.typ)
+	}
+	unpackStruct(s.layout, s.fields, container, rv.Elem())
+	return nil
+}
@@ -0,0 +1,116 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+type inferConfig struct {
+	Enabled  bool
+	Mode     uint8 `bits:"3"`
+	Channel  uint8
+	Label    string // Not packed
+	Timeout  uint16 `bits:"12"`
+	Internal uint32 `bits:"-"`
+	secret   uint8
+}
+
+func TestInferLayout(t *testing.T) {
+	s, err := InferLayout[uint32](inferConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := s.Layout()
+	if got, want := l.Names(), []string{"Enabled", "Mode", "Channel", "Timeout"}; !slices.Equal(got, want) {
+		t.Fatalf("Names() = %v, want %v", got, want)
+	}
+	var shifts, sizes []uint
+	for _, bf := range l.Fields() {
+		shifts = append(shifts, bf.Shift)
+		sizes = append(sizes, bf.Size)
+	}
+	if !slices.Equal(shifts, []uint{0, 1, 4, 12}) || !slices.Equal(sizes, []uint{1, 3, 8, 12}) {
+		t.Errorf("shifts %v, sizes %v", shifts, sizes)
+	}
+
+	in := inferConfig{Enabled: true, Mode: 5, Channel: 0xA7, Label: "x", Timeout: 0xFED, Internal: 9, secret: 1}
+	container, err := s.Pack(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if container != 0xFEDA7B {
+		t.Errorf("Pack() = 0x%X, want 0xFEDA7B", container)
+	}
+	if got, err := s.Pack(&in); err != nil || got != container {
+		t.Errorf("Pack(&v) = 0x%X, %v, want 0x%X", got, err, container)
+	}
+
+	out := inferConfig{Label: "kept", Internal: 3}
+	if err := s.Unpack(container, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := inferConfig{Enabled: true, Mode: 5, Channel: 0xA7, Label: "kept", Timeout: 0xFED, Internal: 3}
+	if out != want {
+		t.Errorf("Unpack() = %+v, want %+v", out, want)
+	}
+}
+
+func TestInferLayout_Errors(t *testing.T) {
+	type tooWide struct {
+		A uint32
+		B uint8 `bits:"1"`
+	}
+	type badHint struct {
+		A uint8 `bits:"9"`
+	}
+	type zeroHint struct {
+		A uint8 `bits:"0"`
+	}
+	type hintedString struct {
+		A string `bits:"4"`
+	}
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"not a struct", 42},
+		{"nil", nil},
+		{"too wide", tooWide{}},
+		{"hint wider than type", badHint{}},
+		{"zero hint", zeroHint{}},
+		{"hint on unsupported type", hintedString{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := InferLayout[uint32](tt.v); err == nil {
+				t.Error("InferLayout() succeeded")
+			}
+		})
+	}
+
+	if _, err := InferLayout[uint64](&tooWide{}); err != nil {
+		t.Errorf("InferLayout() of a pointer in a wider container: %v", err)
+	}
+}
+
+func TestStructLayout_PackErrors(t *testing.T) {
+	s, err := InferLayout[uint32](inferConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Pack(inferConfig{Mode: 8}); err == nil {
+		t.Error("Pack() of a value too wide for its hint succeeded")
+	}
+	if _, err := s.Pack(struct{ A uint8 }{}); err == nil {
+		t.Error("Pack() of another type succeeded")
+	}
+	if _, err := s.Pack(nil); err == nil {
+		t.Error("Pack(nil) succeeded")
+	}
+	if err := s.Unpack(0, inferConfig{}); err == nil {
+		t.Error("Unpack() into a non-pointer succeeded")
+	}
+	if err := s.Unpack(0, (*inferConfig)(nil)); err == nil {
+		t.Error("Unpack() into a nil pointer succeeded")
+	}
+}
@@ -0,0 +1,265 @@
+// Package insn encodes and decodes machine instructions described as bit
+// patterns, for emulators, assemblers and disassemblers.
+//
+// A Format declares the fixed opcode bits of an instruction with a pattern
+// string, written most significant bit first, and its operands as bit fields.
+// Operands may be split across several ranges of the instruction word, as the
+// immediates of RISC-V branches are, and may be sign-extended. A Table holds
+// the formats of an instruction set and decodes words by matching the fixed
+// bits, preferring the most specific format.
+package insn
+
+import (
+	"cmp"
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Operand is a named operand field of an instruction format.
+type Operand[U uint32 | uint64] struct {
+	Name   string
+	Field  bitfield.SplitField[uint64, U]
+	Signed bool // Whether the value is sign-extended from its most significant covered bit
+}
+
+// Field returns an unsigned operand of contiguous bits.
+func Field[U uint32 | uint64](name string, shift, size uint) Operand[U] {
+	return Operand[U]{Name: name, Field: bitfield.NewSplit[uint64, U](bitfield.Segment{Shift: shift, Size: size})}
+}
+
+// Split returns an unsigned operand made of several ranges of bits.
+// Value bits not covered by a segment are 0, like the lowest bit of branch offsets.
+func Split[U uint32 | uint64](name string, segments ...bitfield.Segment) Operand[U] {
+	return Operand[U]{Name: name, Field: bitfield.NewSplit[uint64, U](segments...)}
+}
+
+// AsSigned returns a copy of the operand that is sign-extended.
+func (o Operand[U]) AsSigned() Operand[U] {
+	o.Signed = true
+	return o
+}
+
+// width returns the number of value bits up to the most significant covered bit.
+func (o Operand[U]) width() int {
+	return bits.Len64(o.Field.ValueMask)
+}
+
+// decode extracts the operand value from an instruction word.
+func (o Operand[U]) decode(word U) int64 {
+	v := o.Field.Decode(word)
+	if o.Signed {
+		shift := 64 - o.width()
+		return int64(v<<shift) >> shift
+	}
+	return int64(v)
+}
+
+// raw converts an operand value to the value bits of its field.
+func (o Operand[U]) raw(value int64) (uint64, error) {
+	w := o.width()
+	var raw uint64
+	if o.Signed {
+		shift := 64 - w
+		if int64(uint64(value)<<shift)>>shift != value {
+			return 0, fmt.Errorf("operand %s: value %d out of %d-bit signed range", o.Name, value, w)
+		}
+		raw = uint64(value) & (1<<w - 1)
+	} else {
+		if value < 0 || w < 64 && uint64(value) >= 1<<w {
+			return 0, fmt.Errorf("operand %s: value %d out of %d-bit range", o.Name, value, w)
+		}
+		raw = uint64(value)
+	}
+	if !o.Field.IsValid(raw) {
+		return 0, fmt.Errorf("operand %s: value %d has bits not encoded by the field, encoded bits 0x%X", o.Name, value, o.Field.ValueMask)
+	}
+	return raw, nil
+}
+
+// Operands maps operand names to values.
+type Operands map[string]int64
+
+// Format is an instruction format: fixed opcode bits plus operand fields.
+type Format[U uint32 | uint64] struct {
+	Name     string
+	Size     uint // Instruction size in bits, the length of the pattern
+	Mask     U    // Bits fixed by the pattern
+	Match    U    // Values of the fixed bits
+	Operands []Operand[U]
+}
+
+// NewFormat creates an instruction format.
+// The pattern gives the bits of the instruction, most significant first, as
+// '0' and '1' for fixed bits and any other character for operand bits;
+// spaces and underscores are ignored. A pattern shorter than U describes the
+// low bits of the word, and the other bits are ignored when matching.
+// Returns an error if the pattern is longer than U, an operand name is empty
+// or duplicated, or an operand overlaps a fixed bit, another operand, or the
+// bits above the pattern.
+func NewFormat[U uint32 | uint64](name, pattern string, operands ...Operand[U]) (*Format[U], error) {
+	f := &Format[U]{Name: name, Operands: operands}
+	for _, c := range pattern {
+		if c == ' ' || c == '_' {
+			continue
+		}
+		if f.Size == uint(bits.Len64(uint64(^U(0)))) {
+			return nil, fmt.Errorf("insn: format %s: pattern longer than %d bits", name, f.Size)
+		}
+		f.Mask <<= 1
+		f.Match <<= 1
+		switch c {
+		case '0':
+			f.Mask |= 1
+		case '1':
+			f.Mask |= 1
+			f.Match |= 1
+		}
+		f.Size++
+	}
+	used := f.Mask
+	names := make(map[string]bool)
+	for _, o := range operands {
+		switch {
+		case o.Name == "":
+			return nil, fmt.Errorf("insn: format %s: operand name must not be empty", name)
+		case names[o.Name]:
+			return nil, fmt.Errorf("insn: format %s: duplicate operand %s", name, o.Name)
+		case o.Field.Mask&used != 0:
+			return nil, fmt.Errorf("insn: format %s: operand %s overlaps fixed bits or another operand", name, o.Name)
+		case f.Size < 64 && uint64(o.Field.Mask)>>f.Size != 0:
+			return nil, fmt.Errorf("insn: format %s: operand %s exceeds the %d-bit pattern", name, o.Name, f.Size)
+		}
+		names[o.Name] = true
+		used |= o.Field.Mask
+	}
+	return f, nil
+}
+
+// Matches reports whether the fixed bits of the word match the format.
+func (f *Format[U]) Matches(word U) bool {
+	return word&f.Mask == f.Match
+}
+
+// Decode extracts the operands of an instruction word of the format.
+// The fixed bits are not checked; use Matches or a Table for that.
+func (f *Format[U]) Decode(word U) Operands {
+	ops := make(Operands, len(f.Operands))
+	for _, o := range f.Operands {
+		ops[o.Name] = o.decode(word)
+	}
+	return ops
+}
+
+// Encode builds an instruction word of the format from its operands.
+// Returns an error if an operand is missing, unknown, or out of range.
+func (f *Format[U]) Encode(ops Operands) (U, error) {
+	word := f.Match
+	for _, o := range f.Operands {
+		v, ok := ops[o.Name]
+		if !ok {
+			return 0, fmt.Errorf("insn: %s: missing operand %s", f.Name, o.Name)
+		}
+		raw, err := o.raw(v)
+		if err != nil {
+			return 0, fmt.Errorf("insn: %s: %w", f.Name, err)
+		}
+		word |= o.Field.Encode(raw)
+	}
+	if len(ops) != len(f.Operands) {
+		for name := range ops {
+			if !slices.ContainsFunc(f.Operands, func(o Operand[U]) bool { return o.Name == name }) {
+				return 0, fmt.Errorf("insn: %s: unknown operand %s", f.Name, name)
+			}
+		}
+	}
+	return word, nil
+}
+
+// String formats the operands of a decoded instruction in the order of its
+// format, for disassembly listings.
+func (f *Format[U]) String(ops Operands) string {
+	var b strings.Builder
+	b.WriteString(f.Name)
+	for i, o := range f.Operands {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s=%d", o.Name, ops[o.Name])
+	}
+	return b.String()
+}
+
+// Table is a decoder table of instruction formats.
+type Table[U uint32 | uint64] struct {
+	formats []*Format[U] // Sorted by decreasing number of fixed bits
+	byName  map[string]*Format[U]
+}
+
+// NewTable creates an empty Table.
+func NewTable[U uint32 | uint64]() *Table[U] {
+	return &Table[U]{byName: make(map[string]*Format[U])}
+}
+
+// Add adds a format to the table. Formats may overlap if one fixes more bits
+// than the other, such as a NOP encoding of an arithmetic instruction; the
+// format fixing more bits takes precedence.
+// Returns an error if the name is already used, or the format matches some
+// word that another format with the same number of fixed bits also matches.
+func (t *Table[U]) Add(f *Format[U]) error {
+	if _, ok := t.byName[f.Name]; ok {
+		return fmt.Errorf("insn: duplicate format %s", f.Name)
+	}
+	n := bits.OnesCount64(uint64(f.Mask))
+	for _, other := range t.formats {
+		common := f.Mask & other.Mask
+		if bits.OnesCount64(uint64(other.Mask)) == n && f.Match&common == other.Match&common {
+			return fmt.Errorf("insn: format %s is ambiguous with %s", f.Name, other.Name)
+		}
+	}
+	i, _ := slices.BinarySearchFunc(t.formats, n, func(g *Format[U], n int) int {
+		return cmp.Compare(n, bits.OnesCount64(uint64(g.Mask)))
+	})
+	for i < len(t.formats) && bits.OnesCount64(uint64(t.formats[i].Mask)) == n {
+		i++
+	}
+	t.formats = slices.Insert(t.formats, i, f)
+	t.byName[f.Name] = f
+	return nil
+}
+
+// Format returns the format with the given name, or nil if there is none.
+func (t *Table[U]) Format(name string) *Format[U] {
+	return t.byName[name]
+}
+
+// Formats returns the formats of the table, the most specific first.
+func (t *Table[U]) Formats() []*Format[U] {
+	return slices.Clone(t.formats)
+}
+
+// Match decodes an instruction word with the most specific matching format.
+// Returns a nil format if no format matches.
+func (t *Table[U]) Match(word U) (*Format[U], Operands) {
+	for _, f := range t.formats {
+		if f.Matches(word) {
+			return f, f.Decode(word)
+		}
+	}
+	return nil, nil
+}
+
+// Encode builds an instruction word of the named format.
+// Returns an error if the format does not exist or Format.Encode fails.
+func (t *Table[U]) Encode(name string, ops Operands) (U, error) {
+	f, ok := t.byName[name]
+	if !ok {
+		return 0, fmt.Errorf("insn: unknown format %s", name)
+	}
+	return f.Encode(ops)
+}
@@ -0,0 +1,193 @@
+package insn
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+var (
+	rd  = Field[uint32]("rd", 7, 5)
+	rs1 = Field[uint32]("rs1", 15, 5)
+	rs2 = Field[uint32]("rs2", 20, 5)
+)
+
+// riscv returns a table with a few RV32I instructions.
+func riscv(t *testing.T) *Table[uint32] {
+	t.Helper()
+	formats := []struct {
+		name     string
+		pattern  string
+		operands []Operand[uint32]
+	}{
+		{"add", "0000000 ----- ----- 000 ----- 0110011", []Operand[uint32]{rd, rs1, rs2}},
+		{"sub", "0100000 ----- ----- 000 ----- 0110011", []Operand[uint32]{rd, rs1, rs2}},
+		{"addi", "------------ ----- 000 ----- 0010011", []Operand[uint32]{rd, rs1, Field[uint32]("imm", 20, 12).AsSigned()}},
+		{"nop", "000000000000 00000 000 00000 0010011", nil},
+		{"lui", "-------------------- ----- 0110111", []Operand[uint32]{rd, Split[uint32]("imm", bitfield.Segment{Shift: 12, Size: 20, ValueShift: 12})}},
+		{"beq", "------- ----- ----- 000 ----- 1100011", []Operand[uint32]{rs1, rs2, Split[uint32]("offset",
+			bitfield.Segment{Shift: 31, Size: 1, ValueShift: 12},
+			bitfield.Segment{Shift: 25, Size: 6, ValueShift: 5},
+			bitfield.Segment{Shift: 8, Size: 4, ValueShift: 1},
+			bitfield.Segment{Shift: 7, Size: 1, ValueShift: 11},
+		).AsSigned()}},
+		{"jal", "-------------------- ----- 1101111", []Operand[uint32]{rd, Split[uint32]("offset",
+			bitfield.Segment{Shift: 31, Size: 1, ValueShift: 20},
+			bitfield.Segment{Shift: 21, Size: 10, ValueShift: 1},
+			bitfield.Segment{Shift: 20, Size: 1, ValueShift: 11},
+			bitfield.Segment{Shift: 12, Size: 8, ValueShift: 12},
+		).AsSigned()}},
+	}
+	table := NewTable[uint32]()
+	for _, f := range formats {
+		format, err := NewFormat(f.name, f.pattern, f.operands...)
+		if err != nil {
+			t.Fatalf("NewFormat(%s): %v", f.name, err)
+		}
+		if err := table.Add(format); err != nil {
+			t.Fatalf("Add(%s): %v", f.name, err)
+		}
+	}
+	return table
+}
+
+var riscvTests = []struct {
+	word uint32
+	name string
+	ops  Operands
+}{
+	{0x002081B3, "add", Operands{"rd": 3, "rs1": 1, "rs2": 2}},
+	{0x40B50533, "sub", Operands{"rd": 10, "rs1": 10, "rs2": 11}},
+	{0xFFF00093, "addi", Operands{"rd": 1, "rs1": 0, "imm": -1}},
+	{0x7FF28293, "addi", Operands{"rd": 5, "rs1": 5, "imm": 2047}},
+	{0x00000013, "nop", Operands{}},
+	{0x123452B7, "lui", Operands{"rd": 5, "imm": 0x12345000}},
+	{0xFE208CE3, "beq", Operands{"rs1": 1, "rs2": 2, "offset": -8}},
+	{0x00B50863, "beq", Operands{"rs1": 10, "rs2": 11, "offset": 16}},
+	{0x001000EF, "jal", Operands{"rd": 1, "offset": 2048}},
+	{0xFFDFF06F, "jal", Operands{"rd": 0, "offset": -4}},
+}
+
+func TestTable_Match(t *testing.T) {
+	table := riscv(t)
+	for _, tt := range riscvTests {
+		f, ops := table.Match(tt.word)
+		if f == nil || f.Name != tt.name || !maps.Equal(ops, tt.ops) {
+			t.Errorf("Match(0x%08X) = %v, %v, want %s %v", tt.word, f, ops, tt.name, tt.ops)
+		}
+	}
+	if f, ops := table.Match(0xFFFFFFFF); f != nil || ops != nil {
+		t.Errorf("Match(0xFFFFFFFF) = %s, %v, want no match", f.Name, ops)
+	}
+}
+
+func TestTable_Encode(t *testing.T) {
+	table := riscv(t)
+	for _, tt := range riscvTests {
+		got, err := table.Encode(tt.name, tt.ops)
+		if err != nil || got != tt.word {
+			t.Errorf("Encode(%s, %v) = 0x%08X, %v, want 0x%08X", tt.name, tt.ops, got, err, tt.word)
+		}
+	}
+}
+
+func TestTable_EncodeErrors(t *testing.T) {
+	table := riscv(t)
+	tests := []struct {
+		name   string
+		format string
+		ops    Operands
+	}{
+		{"unknown format", "mul", Operands{}},
+		{"missing operand", "add", Operands{"rd": 1, "rs1": 2}},
+		{"unknown operand", "add", Operands{"rd": 1, "rs1": 2, "rs2": 3, "rs3": 4}},
+		{"register out of range", "add", Operands{"rd": 32, "rs1": 2, "rs2": 3}},
+		{"negative unsigned", "add", Operands{"rd": -1, "rs1": 2, "rs2": 3}},
+		{"immediate too large", "addi", Operands{"rd": 1, "rs1": 0, "imm": 2048}},
+		{"immediate too small", "addi", Operands{"rd": 1, "rs1": 0, "imm": -2049}},
+		{"odd offset", "beq", Operands{"rs1": 1, "rs2": 2, "offset": 3}},
+		{"unaligned upper immediate", "lui", Operands{"rd": 1, "imm": 0x1001}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := table.Encode(tt.format, tt.ops); err == nil {
+				t.Errorf("Encode(%s, %v) succeeded, want error", tt.format, tt.ops)
+			}
+		})
+	}
+}
+
+func TestNewFormat(t *testing.T) {
+	f, err := NewFormat[uint32]("c.li", "010 - ----- ----- 01", Field[uint32]("rd", 7, 5))
+	if err != nil {
+		t.Fatalf("NewFormat: %v", err)
+	}
+	if f.Size != 16 || f.Mask != 0xE003 || f.Match != 0x4001 {
+		t.Errorf("c.li = size %d, mask 0x%X, match 0x%X, want 16, 0xE003, 0x4001", f.Size, f.Mask, f.Match)
+	}
+	if !f.Matches(0xABCD4081) {
+		t.Error("Matches(0xABCD4081) = false, want true for bits above the pattern")
+	}
+	if got := f.String(f.Decode(0x4081)); got != "c.li rd=1" {
+		t.Errorf("String = %q, want %q", got, "c.li rd=1")
+	}
+}
+
+func TestNewFormat_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		operands []Operand[uint32]
+	}{
+		{"pattern too long", "000000000000000000000000000000000", nil},
+		{"empty operand name", "--------", []Operand[uint32]{Field[uint32]("", 0, 4)}},
+		{"duplicate operand", "--------", []Operand[uint32]{Field[uint32]("a", 0, 4), Field[uint32]("a", 4, 4)}},
+		{"operand on fixed bits", "0000----", []Operand[uint32]{Field[uint32]("a", 2, 4)}},
+		{"overlapping operands", "--------", []Operand[uint32]{Field[uint32]("a", 0, 4), Field[uint32]("b", 3, 4)}},
+		{"operand above pattern", "--------", []Operand[uint32]{Field[uint32]("a", 6, 4)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFormat(tt.name, tt.pattern, tt.operands...); err == nil {
+				t.Error("NewFormat succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestTable_AddErrors(t *testing.T) {
+	table := riscv(t)
+	dup, _ := NewFormat[uint32]("add", "1111111 ----- ----- 000 ----- 0110011")
+	if err := table.Add(dup); err == nil {
+		t.Error("Add of duplicate name succeeded, want error")
+	}
+	ambiguous, _ := NewFormat[uint32]("slti", "------------ ----- 000 ----- 0010011")
+	if err := table.Add(ambiguous); err == nil {
+		t.Error("Add of format ambiguous with addi succeeded, want error")
+	}
+	if got := table.Formats()[0].Name; got != "nop" {
+		t.Errorf("most specific format = %s, want nop", got)
+	}
+	if table.Format("beq") == nil {
+		t.Error("Format(beq) = nil")
+	}
+}
+
+func TestFormat_Uint64(t *testing.T) {
+	f, err := NewFormat("wide", "1111"+strings.Repeat("-", 60),
+		Field[uint64]("imm", 0, 60).AsSigned())
+	if err != nil {
+		t.Fatalf("NewFormat: %v", err)
+	}
+	word, err := f.Encode(Operands{"imm": -2})
+	if err != nil || word != 0xFFFFFFFFFFFFFFFE {
+		t.Errorf("Encode = 0x%X, %v, want 0xFFFFFFFFFFFFFFFE", word, err)
+	}
+	if got := f.Decode(word)["imm"]; got != -2 {
+		t.Errorf("Decode imm = %d, want -2", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Package spec parses the small layout description language shared by the
+// bitfield command line tools.
+//
+// A spec file declares a single container type followed by its fields:
+//
+//	# Comments start with '#'
+//	type Control uint32
+//	field active   0 1
+//	field priority 1 3
+//
+// Each field line gives the field name, its shift and its size in bits.
+package spec
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Spec is a parsed layout description.
+type Spec struct {
+	Type      string  // Name of the container type
+	Container string  // Underlying unsigned type: uint32 or uint64
+	Fields    []Field // Fields in declaration order
+}
+
+// Field is a single field declaration of a Spec.
+type Field struct {
+	Name  string
+	Shift uint
+	Size  uint
+	Line  int // Line number of the declaration, for error messages
+}
+
+// Width returns the size in bits of the container type.
+func (s *Spec) Width() uint {
+	if s.Container == "uint32" {
+		return 32
+	}
+	return 64
+}
+
+// Layout builds a bitfield.Layout from the spec.
+func (s *Spec) Layout() (*bitfield.Layout[uint64], error) {
+	layout := bitfield.NewLayout[uint64]()
+	for _, f := range s.Fields {
+		if f.Shift+f.Size > s.Width() {
+			return nil, fmt.Errorf("line %d: field %q exceeds %s", f.Line, f.Name, s.Container)
+		}
+		if err := layout.Add(f.Name, f.Shift, f.Size); err != nil {
+			return nil, fmt.Errorf("line %d: %w", f.Line, err)
+		}
+	}
+	return layout, nil
+}
+
+// Parse reads a spec from r and validates it.
+func Parse(r io.Reader) (*Spec, error) {
+	var s Spec
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		words := strings.Fields(text)
+		if len(words) == 0 {
+			continue
+		}
+		switch words[0] {
+		case "type":
+			if len(words) != 3 {
+				return nil, fmt.Errorf("line %d: want \"type <name> <container>\"", line)
+			}
+			if s.Type != "" {
+				return nil, fmt.Errorf("line %d: duplicate type declaration", line)
+			}
+			switch words[2] {
+			case "uint32", "uint64":
+			case "uint":
+				// The size of uint depends on the platform the generated code is built for.
+				return nil, fmt.Errorf("line %d: container type uint has no fixed size, use uint32 or uint64", line)
+			default:
+				return nil, fmt.Errorf("line %d: unsupported container type %q", line, words[2])
+			}
+			s.Type, s.Container = words[1], words[2]
+		case "field":
+			if len(words) != 4 {
+				return nil, fmt.Errorf("line %d: want \"field <name> <shift> <size>\"", line)
+			}
+			shift, err := strconv.ParseUint(words[2], 0, 8)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid shift %q", line, words[2])
+			}
+			size, err := strconv.ParseUint(words[3], 0, 8)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid size %q", line, words[3])
+			}
+			s.Fields = append(s.Fields, Field{Name: words[1], Shift: uint(shift), Size: uint(size), Line: line})
+		default:
+			return nil, fmt.Errorf("line %d: unknown directive %q", line, words[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if s.Type == "" {
+		return nil, fmt.Errorf("missing type declaration")
+	}
+	if _, err := s.Layout(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
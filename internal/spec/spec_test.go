@@ -0,0 +1,63 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	src := `
+# Control register
+type Control uint32
+field active   0 1
+field priority 1 3 # trailing comment
+field code     0x8 8
+`
+	s, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if s.Type != "Control" || s.Container != "uint32" {
+		t.Errorf("type = %s %s, want Control uint32", s.Type, s.Container)
+	}
+	want := []Field{
+		{Name: "active", Shift: 0, Size: 1, Line: 4},
+		{Name: "priority", Shift: 1, Size: 3, Line: 5},
+		{Name: "code", Shift: 8, Size: 8, Line: 6},
+	}
+	if len(s.Fields) != len(want) {
+		t.Fatalf("got %d fields, want %d", len(s.Fields), len(want))
+	}
+	for i, f := range s.Fields {
+		if f != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, f, want[i])
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"missing type", "field a 0 1"},
+		{"duplicate type", "type A uint32\ntype B uint32"},
+		{"bad container", "type A uint16"},
+		{"unsized container", "type A uint\nfield a 0 1"},
+		{"short field", "type A uint32\nfield a 0"},
+		{"bad shift", "type A uint32\nfield a x 1"},
+		{"bad size", "type A uint32\nfield a 0 y"},
+		{"unknown directive", "type A uint32\nenum a"},
+		{"overlap", "type A uint32\nfield a 0 4\nfield b 2 4"},
+		{"exceeds container", "type A uint32\nfield a 30 4"},
+		{"duplicate field", "type A uint32\nfield a 0 1\nfield a 1 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.src)); err == nil {
+				t.Errorf("Parse(%q) succeeded, want error", tt.src)
+			}
+		})
+	}
+}
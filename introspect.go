@@ -0,0 +1,101 @@
+package bitfield
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// Gap is a run of adjacent bits of a container that belong to no field of a Layout.
+type Gap struct {
+	Shift uint // Position of the lowest bit of the run
+	Size  uint // Number of bits in the run
+}
+
+// Len returns the number of fields of the layout.
+func (l *Layout[U]) Len() int {
+	return len(l.fields)
+}
+
+// Fields returns an iterator over the names and definitions of the fields of the
+// layout, in the order they were added.
+func (l *Layout[U]) Fields() iter.Seq2[string, BitField[uint64, U]] {
+	return func(yield func(string, BitField[uint64, U]) bool) {
+		for _, f := range l.fields {
+			if !yield(f.name, f.field) {
+				return
+			}
+		}
+	}
+}
+
+// UsedBits returns the number of bits of the container that belong to a field.
+func (l *Layout[U]) UsedBits() uint {
+	return uint(bits.OnesCount64(uint64(l.used)))
+}
+
+// Gaps returns the runs of bits that belong to no field, in ascending order of shift,
+// including the run above the highest field.
+func (l *Layout[U]) Gaps() []Gap {
+	var gaps []Gap
+	width := unsignedSizeOf[U]()
+	for bit := uint(0); bit < width; {
+		if l.used&(U(1)<<bit) != 0 {
+			bit++
+			continue
+		}
+		gap := Gap{Shift: bit}
+		for bit < width && l.used&(U(1)<<bit) == 0 {
+			bit++
+		}
+		gap.Size = bit - gap.Shift
+		gaps = append(gaps, gap)
+	}
+	return gaps
+}
+
+// UsageReport returns a human-readable map of the container with one line per
+// field and per gap, from the lowest bit, under a summary of the bits in use:
+//
+//	32 bits, 16 used, 16 unused in 1 gap
+//	  bits   0      active
+//	  bits   1-3    priority
+//	  bits   4-7    category
+//	  bits   8-15   error
+//	  bits  16-31   (unused)
+func (l *Layout[U]) UsageReport() string {
+	type row struct {
+		shift, size uint
+		name        string
+	}
+	var rows []row
+	for _, f := range l.fields {
+		rows = append(rows, row{f.field.Shift, f.field.Size, f.name})
+	}
+	gaps := l.Gaps()
+	for _, g := range gaps {
+		rows = append(rows, row{g.Shift, g.Size, "(unused)"})
+	}
+	slices.SortFunc(rows, func(a, b row) int {
+		return cmp.Compare(a.shift, b.shift)
+	})
+
+	width := unsignedSizeOf[U]()
+	noun := "gaps"
+	if len(gaps) == 1 {
+		noun = "gap"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d bits, %d used, %d unused in %d %s\n", width, l.UsedBits(), width-l.UsedBits(), len(gaps), noun)
+	for _, r := range rows {
+		var hi string
+		if r.size > 1 {
+			hi = fmt.Sprintf("-%d", r.shift+r.size-1)
+		}
+		fmt.Fprintf(&b, "  bits %3d%-4s  %s\n", r.shift, hi, r.name)
+	}
+	return b.String()
+}
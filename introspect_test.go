@@ -0,0 +1,85 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLayout_Fields(t *testing.T) {
+	l := newStatusLayout(t)
+
+	var names []string
+	var shifts []uint
+	for name, bf := range l.Fields() {
+		names = append(names, name)
+		shifts = append(shifts, bf.Shift)
+	}
+	if !slices.Equal(names, l.Names()) || !slices.Equal(shifts, []uint{0, 1, 4, 8}) {
+		t.Errorf("Fields() = %v at %v", names, shifts)
+	}
+	if got := l.Len(); got != 4 {
+		t.Errorf("Len() = %d, want 4", got)
+	}
+}
+
+func TestLayout_Gaps(t *testing.T) {
+	l := NewLayout[uint32]()
+	for _, err := range []error{
+		l.Add("a", 2, 3),
+		l.Add("b", 8, 1),
+		l.Add("c", 9, 7),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []Gap{{0, 2}, {5, 3}, {16, 16}}
+	if got := l.Gaps(); !slices.Equal(got, want) {
+		t.Errorf("Gaps() = %v, want %v", got, want)
+	}
+	if got := l.UsedBits(); got != 11 {
+		t.Errorf("UsedBits() = %d, want 11", got)
+	}
+
+	full := NewLayout[uint64]()
+	if err := full.Add("all", 0, 64); err != nil {
+		t.Fatal(err)
+	}
+	if got := full.Gaps(); got != nil {
+		t.Errorf("Gaps() of a full layout = %v, want none", got)
+	}
+	if got := NewLayout[uint32]().Gaps(); !slices.Equal(got, []Gap{{0, 32}}) {
+		t.Errorf("Gaps() of an empty layout = %v", got)
+	}
+}
+
+func TestLayout_UsageReport(t *testing.T) {
+	l := newStatusLayout(t)
+	want := `32 bits, 16 used, 16 unused in 1 gap
+  bits   0      active
+  bits   1-3    priority
+  bits   4-7    category
+  bits   8-15   error
+  bits  16-31   (unused)
+`
+	if got := l.UsageReport(); got != want {
+		t.Errorf("UsageReport() = %q, want %q", got, want)
+	}
+
+	if err := l.Add("flag", 20, 1); err != nil {
+		t.Fatal(err)
+	}
+	want = `32 bits, 17 used, 15 unused in 2 gaps
+  bits   0      active
+  bits   1-3    priority
+  bits   4-7    category
+  bits   8-15   error
+  bits  16-19   (unused)
+  bits  20      flag
+  bits  21-31   (unused)
+`
+	if got := l.UsageReport(); got != want {
+		t.Errorf("UsageReport() = %q, want %q", got, want)
+	}
+}
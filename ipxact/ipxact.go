@@ -0,0 +1,401 @@
+// Package ipxact imports register descriptions from IP-XACT (IEEE 1685) files.
+//
+// Parse reads the memory maps of an IP-XACT component. Each AddressBlock can
+// then be converted into a bitfield.RegisterMap with RegisterMap, carrying over
+// field offsets, widths, access types, side effects and enumerated values.
+//
+// Documents in the 1685-2009 and 1685-2014 schemas are accepted. Register files
+// are flattened, prefixing the names of their registers with the register file
+// name and an underscore, and array elements are named with an underscore and
+// their index. Only 32-bit registers are supported by RegisterMap.
+package ipxact
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Component is the root of a parsed IP-XACT document.
+type Component struct {
+	Vendor, Library, Name, Version string
+	MemoryMaps                     []*MemoryMap
+}
+
+// MemoryMap is a memory map of a component.
+type MemoryMap struct {
+	Name          string
+	Description   string
+	AddressBlocks []*AddressBlock
+}
+
+// AddressBlock is a contiguous block of registers in a memory map.
+type AddressBlock struct {
+	Name        string
+	Description string
+	BaseAddress uint64
+	Range       uint64      // Size of the block in addressable units
+	Width       uint        // Width of the block in bits
+	Registers   []*Register // Registers with offsets relative to BaseAddress
+}
+
+// Register is a register of an address block.
+type Register struct {
+	Name        string
+	Description string
+	Offset      uint64 // Byte offset relative to the address block base address
+	Size        uint   // Width in bits
+	ResetValue  uint64
+	Fields      []*Field
+}
+
+// Field is a bit field of a register.
+type Field struct {
+	Name        string
+	Description string
+	Shift       uint
+	Size        uint
+	Access      bitfield.Access
+	SideEffect  bitfield.SideEffect
+	Enum        map[uint64]string // Enumerated value names, nil if none are declared
+}
+
+// MemoryMap returns the memory map with the given name, or nil if there is none.
+func (c *Component) MemoryMap(name string) *MemoryMap {
+	for _, m := range c.MemoryMaps {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// AddressBlock returns the address block with the given name, or nil if there is none.
+func (m *MemoryMap) AddressBlock(name string) *AddressBlock {
+	for _, b := range m.AddressBlocks {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+// RegisterMap converts the address block into a RegisterMap.
+// Returns an error if a register is not 32 bits wide or the registers or fields overlap.
+func (b *AddressBlock) RegisterMap() (*bitfield.RegisterMap[uint32], error) {
+	m := bitfield.NewRegisterMap[uint32]()
+	for _, r := range b.Registers {
+		if r.Size != 32 {
+			return nil, fmt.Errorf("%s.%s: unsupported register size %d", b.Name, r.Name, r.Size)
+		}
+		layout := bitfield.NewLayout[uint32]()
+		for _, f := range r.Fields {
+			if err := layout.Add(f.Name, f.Shift, f.Size); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", b.Name, r.Name, err)
+			}
+			if f.Enum != nil {
+				if err := layout.SetEnum(f.Name, f.Enum); err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", b.Name, r.Name, err)
+				}
+			}
+		}
+		reg := bitfield.NewRegister(r.Name, layout, uint32(r.ResetValue))
+		for _, f := range r.Fields {
+			if err := reg.SetAccess(f.Name, f.Access); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", b.Name, r.Name, err)
+			}
+			if err := reg.SetSideEffect(f.Name, f.SideEffect); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", b.Name, r.Name, err)
+			}
+		}
+		if err := m.Add(r.Offset, reg); err != nil {
+			return nil, fmt.Errorf("%s: %w", b.Name, err)
+		}
+	}
+	return m, nil
+}
+
+// Parse reads an IP-XACT component document from r.
+func Parse(r io.Reader) (*Component, error) {
+	var doc xmlComponent
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("ipxact: %w", err)
+	}
+	c := &Component{Vendor: doc.Vendor, Library: doc.Library, Name: doc.Name, Version: doc.Version}
+	for _, xm := range doc.MemoryMaps {
+		m := &MemoryMap{Name: xm.Name, Description: cleanText(xm.Description)}
+		for _, xb := range xm.AddressBlocks {
+			b, err := convertAddressBlock(xb)
+			if err != nil {
+				return nil, fmt.Errorf("ipxact: memory map %s: address block %s: %w", xm.Name, xb.Name, err)
+			}
+			m.AddressBlocks = append(m.AddressBlocks, b)
+		}
+		c.MemoryMaps = append(c.MemoryMaps, m)
+	}
+	return c, nil
+}
+
+// convertAddressBlock converts an addressBlock element.
+func convertAddressBlock(xb xmlAddressBlock) (*AddressBlock, error) {
+	base, err := parseNumber(xb.BaseAddress)
+	if err != nil {
+		return nil, fmt.Errorf("baseAddress: %w", err)
+	}
+	b := &AddressBlock{Name: xb.Name, Description: cleanText(xb.Description), BaseAddress: base, Width: 32}
+	if xb.Range != "" {
+		if b.Range, err = parseNumber(xb.Range); err != nil {
+			return nil, fmt.Errorf("range: %w", err)
+		}
+	}
+	if xb.Width != "" {
+		width, err := parseNumber(xb.Width)
+		if err != nil {
+			return nil, fmt.Errorf("width: %w", err)
+		}
+		b.Width = uint(width)
+	}
+	b.Registers, err = convertRegisters(xb.xmlRegisters, 0, "", b.Width)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// convertRegisters flattens the registers and register files of a block.
+// offset and prefix are those of the enclosing register file.
+func convertRegisters(block xmlRegisters, offset uint64, prefix string, width uint) ([]*Register, error) {
+	var regs []*Register
+	for _, xr := range block.Registers {
+		roffset, err := parseNumber(xr.AddressOffset)
+		if err != nil {
+			return nil, fmt.Errorf("register %s: addressOffset: %w", xr.Name, err)
+		}
+		size := uint64(width)
+		if xr.Size != "" {
+			if size, err = parseNumber(xr.Size); err != nil {
+				return nil, fmt.Errorf("register %s: size: %w", xr.Name, err)
+			}
+		}
+		access := bitfield.ReadWrite
+		if xr.Access != "" {
+			if access, err = parseAccess(xr.Access); err != nil {
+				return nil, fmt.Errorf("register %s: %w", xr.Name, err)
+			}
+		}
+		var reset uint64
+		if xr.Reset != "" {
+			if reset, err = parseNumber(xr.Reset); err != nil {
+				return nil, fmt.Errorf("register %s: reset: %w", xr.Name, err)
+			}
+		}
+		fields, fieldReset, err := convertFields(xr.Fields, access)
+		if err != nil {
+			return nil, fmt.Errorf("register %s: %w", xr.Name, err)
+		}
+		instances, err := expand(xr.Name, xr.Dim, (size+7)/8)
+		if err != nil {
+			return nil, fmt.Errorf("register %s: %w", xr.Name, err)
+		}
+		for _, inst := range instances {
+			regs = append(regs, &Register{
+				Name:        prefix + inst.name,
+				Description: cleanText(xr.Description),
+				Offset:      offset + roffset + inst.offset,
+				Size:        uint(size),
+				ResetValue:  reset | fieldReset,
+				Fields:      fields,
+			})
+		}
+	}
+	for _, xf := range block.RegisterFiles {
+		foffset, err := parseNumber(xf.AddressOffset)
+		if err != nil {
+			return nil, fmt.Errorf("register file %s: addressOffset: %w", xf.Name, err)
+		}
+		var stride uint64
+		if len(xf.Dim) > 0 {
+			if stride, err = parseNumber(xf.Range); err != nil {
+				return nil, fmt.Errorf("register file %s: range: %w", xf.Name, err)
+			}
+		}
+		instances, err := expand(xf.Name, xf.Dim, stride)
+		if err != nil {
+			return nil, fmt.Errorf("register file %s: %w", xf.Name, err)
+		}
+		for _, inst := range instances {
+			inner, err := convertRegisters(xf.xmlRegisters, offset+foffset+inst.offset, prefix+inst.name+"_", width)
+			if err != nil {
+				return nil, fmt.Errorf("register file %s: %w", xf.Name, err)
+			}
+			regs = append(regs, inner...)
+		}
+	}
+	return regs, nil
+}
+
+// convertFields converts the fields of a register and returns them with the
+// register reset value assembled from the field resets.
+func convertFields(xfs []xmlField, access bitfield.Access) ([]*Field, uint64, error) {
+	var fields []*Field
+	var reset uint64
+	for _, xf := range xfs {
+		shift, err := parseNumber(xf.BitOffset)
+		if err != nil {
+			return nil, 0, fmt.Errorf("field %s: bitOffset: %w", xf.Name, err)
+		}
+		size, err := parseNumber(xf.BitWidth)
+		if err != nil {
+			return nil, 0, fmt.Errorf("field %s: bitWidth: %w", xf.Name, err)
+		}
+		f := &Field{Name: xf.Name, Description: cleanText(xf.Description), Shift: uint(shift), Size: uint(size), Access: access}
+		if xf.Access != "" {
+			if f.Access, err = parseAccess(xf.Access); err != nil {
+				return nil, 0, fmt.Errorf("field %s: %w", xf.Name, err)
+			}
+		}
+		if xf.Reset != "" {
+			v, err := parseNumber(xf.Reset)
+			if err != nil {
+				return nil, 0, fmt.Errorf("field %s: reset: %w", xf.Name, err)
+			}
+			reset |= v << f.Shift
+		}
+		switch strings.TrimSpace(xf.ModifiedWriteValue) {
+		case "":
+		case "oneToClear":
+			f.SideEffect = bitfield.WriteOneToClear
+		case "oneToSet":
+			f.SideEffect = bitfield.WriteOneToSet
+		default:
+			return nil, 0, fmt.Errorf("field %s: unsupported modifiedWriteValue %q", xf.Name, xf.ModifiedWriteValue)
+		}
+		switch strings.TrimSpace(xf.ReadAction) {
+		case "", "modify":
+		case "clear":
+			f.SideEffect = bitfield.ReadToClear
+		default:
+			return nil, 0, fmt.Errorf("field %s: unsupported readAction %q", xf.Name, xf.ReadAction)
+		}
+		for _, ev := range xf.EnumeratedValues {
+			n, err := parseNumber(ev.Value)
+			if err != nil {
+				return nil, 0, fmt.Errorf("field %s: enumerated value %s: %w", xf.Name, ev.Name, err)
+			}
+			if f.Enum == nil {
+				f.Enum = make(map[uint64]string)
+			}
+			f.Enum[n] = ev.Name
+		}
+		fields = append(fields, f)
+	}
+	return fields, reset, nil
+}
+
+// instance is one element of an expanded array.
+type instance struct {
+	name   string
+	offset uint64 // Offset relative to the first element
+}
+
+// expand returns the elements of an array with the given dimensions, laid out
+// stride bytes apart with the last dimension varying fastest.
+// Elements without dimensions expand to a single instance.
+func expand(name string, dims []string, stride uint64) ([]instance, error) {
+	instances := []instance{{name: name}}
+	for i := len(dims) - 1; i >= 0; i-- {
+		n, err := parseNumber(dims[i])
+		if err != nil {
+			return nil, fmt.Errorf("dim: %w", err)
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("dim must not be 0")
+		}
+		inner := instances
+		instances = make([]instance, 0, int(n)*len(inner))
+		for j := range n {
+			for _, in := range inner {
+				instances = append(instances, instance{
+					name:   name + "_" + strconv.FormatUint(j, 10) + strings.TrimPrefix(in.name, name),
+					offset: j*stride*uint64(len(inner)) + in.offset,
+				})
+			}
+		}
+	}
+	return instances, nil
+}
+
+// parseNumber parses an IP-XACT number: decimal, 0x or # hexadecimal, or a
+// Verilog-style literal such as 'h1F or 8'b1010_0101, optionally scaled by a
+// K, M, G or T suffix. Underscores are ignored.
+func parseNumber(s string) (uint64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "_", "")
+	base := 10
+	if _, lit, ok := strings.Cut(s, "'"); ok {
+		if lit == "" {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		switch lit[0] {
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		case 'd', 'D':
+			base = 10
+		case 'h', 'H':
+			base = 16
+		default:
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		return strconv.ParseUint(lit[1:], base, 64)
+	}
+	switch {
+	case strings.HasPrefix(s, "#"):
+		s, base = s[1:], 16
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		s, base = s[2:], 16
+	}
+	var scale uint64 = 1
+	if s != "" {
+		switch s[len(s)-1] {
+		case 'k', 'K':
+			scale = 1 << 10
+		case 'm', 'M':
+			scale = 1 << 20
+		case 'g', 'G':
+			scale = 1 << 30
+		case 't', 'T':
+			scale = 1 << 40
+		}
+		if scale != 1 {
+			s = s[:len(s)-1]
+		}
+	}
+	n, err := strconv.ParseUint(s, base, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * scale, nil
+}
+
+// parseAccess maps an IP-XACT access type to a bitfield.Access.
+func parseAccess(s string) (bitfield.Access, error) {
+	switch strings.TrimSpace(s) {
+	case "read-write", "read-writeOnce":
+		return bitfield.ReadWrite, nil
+	case "read-only":
+		return bitfield.ReadOnly, nil
+	case "write-only", "writeOnce":
+		return bitfield.WriteOnly, nil
+	}
+	return 0, fmt.Errorf("unknown access %q", s)
+}
+
+// cleanText collapses the whitespace of a description.
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
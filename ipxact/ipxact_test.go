@@ -0,0 +1,220 @@
+package ipxact
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+func parseExample(t *testing.T) *Component {
+	t.Helper()
+	f, err := os.Open("testdata/example.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	c, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return c
+}
+
+func TestParse(t *testing.T) {
+	c := parseExample(t)
+	if c.Vendor != "example.com" || c.Name != "dma" || c.Version != "1.0" || len(c.MemoryMaps) != 1 {
+		t.Fatalf("component = %s:%s:%s with %d memory maps", c.Vendor, c.Name, c.Version, len(c.MemoryMaps))
+	}
+	m := c.MemoryMap("regs")
+	if m == nil {
+		t.Fatal("MemoryMap(regs) = nil")
+	}
+	if m.Description != "DMA controller registers" {
+		t.Errorf("description = %q", m.Description)
+	}
+	b := m.AddressBlock("ctrl")
+	if b == nil {
+		t.Fatal("AddressBlock(ctrl) = nil")
+	}
+	if b.BaseAddress != 0x4000 || b.Range != 4096 || b.Width != 32 {
+		t.Errorf("ctrl = base 0x%X, range %d, width %d", b.BaseAddress, b.Range, b.Width)
+	}
+
+	var names []string
+	var offsets []uint64
+	for _, r := range b.Registers {
+		names = append(names, r.Name)
+		offsets = append(offsets, r.Offset)
+	}
+	wantNames := []string{"CFG", "IRQ", "CH_0_SRC", "CH_0_LEN_0", "CH_0_LEN_1", "CH_1_SRC", "CH_1_LEN_0", "CH_1_LEN_1"}
+	if !slices.Equal(names, wantNames) {
+		t.Errorf("registers = %v, want %v", names, wantNames)
+	}
+	wantOffsets := []uint64{0x0, 0x4, 0x100, 0x108, 0x10C, 0x120, 0x128, 0x12C}
+	if !slices.Equal(offsets, wantOffsets) {
+		t.Errorf("offsets = %#x, want %#x", offsets, wantOffsets)
+	}
+
+	cfg := b.Registers[0]
+	if cfg.ResetValue != 0x03000021 || cfg.Size != 32 || cfg.Description != "Configuration" {
+		t.Errorf("CFG = reset 0x%X, size %d, description %q", cfg.ResetValue, cfg.Size, cfg.Description)
+	}
+	burst := cfg.Fields[1]
+	if burst.Shift != 4 || burst.Size != 2 || burst.Access != bitfield.ReadWrite {
+		t.Errorf("BURST = shift %d, size %d, access %v", burst.Shift, burst.Size, burst.Access)
+	}
+	wantEnum := map[uint64]string{0: "SINGLE", 1: "INCR4", 2: "INCR8"}
+	if !maps.Equal(burst.Enum, wantEnum) {
+		t.Errorf("BURST enum = %v, want %v", burst.Enum, wantEnum)
+	}
+
+	irq := b.Registers[1]
+	for i, want := range []struct {
+		access bitfield.Access
+		effect bitfield.SideEffect
+	}{
+		{bitfield.ReadWrite, bitfield.WriteOneToClear},
+		{bitfield.ReadOnly, bitfield.ReadToClear},
+		{bitfield.WriteOnly, bitfield.NoSideEffect},
+	} {
+		if f := irq.Fields[i]; f.Access != want.access || f.SideEffect != want.effect {
+			t.Errorf("IRQ.%s = %v %v, want %v %v", f.Name, f.Access, f.SideEffect, want.access, want.effect)
+		}
+	}
+}
+
+func TestParse_2009(t *testing.T) {
+	src := `<spirit:component xmlns:spirit="http://www.spiritconsortium.org/XMLSchema/SPIRIT/1.5">
+  <spirit:name>timer</spirit:name>
+  <spirit:memoryMaps><spirit:memoryMap><spirit:name>regs</spirit:name>
+    <spirit:addressBlock><spirit:name>blk</spirit:name><spirit:baseAddress>#1000</spirit:baseAddress>
+      <spirit:range>0x100</spirit:range><spirit:width>32</spirit:width>
+      <spirit:register><spirit:name>CTRL</spirit:name><spirit:addressOffset>0x0</spirit:addressOffset>
+        <spirit:size>32</spirit:size><spirit:access>read-only</spirit:access>
+        <spirit:reset><spirit:value>0x00000005</spirit:value><spirit:mask>0xffffffff</spirit:mask></spirit:reset>
+        <spirit:field><spirit:name>EN</spirit:name><spirit:bitOffset>0</spirit:bitOffset><spirit:bitWidth>4</spirit:bitWidth></spirit:field>
+      </spirit:register>
+    </spirit:addressBlock>
+  </spirit:memoryMap></spirit:memoryMaps>
+</spirit:component>`
+	c, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	b := c.MemoryMap("regs").AddressBlock("blk")
+	if b.BaseAddress != 0x1000 {
+		t.Errorf("base address = 0x%X, want 0x1000", b.BaseAddress)
+	}
+	r := b.Registers[0]
+	if r.ResetValue != 5 || r.Fields[0].Access != bitfield.ReadOnly {
+		t.Errorf("CTRL = reset %d, EN access %v, want 5, RO", r.ResetValue, r.Fields[0].Access)
+	}
+}
+
+func TestAddressBlock_RegisterMap(t *testing.T) {
+	b := parseExample(t).MemoryMap("regs").AddressBlock("ctrl")
+	m, err := b.RegisterMap()
+	if err != nil {
+		t.Fatalf("RegisterMap: %v", err)
+	}
+	cfg, ok := m.ByName("CFG")
+	if !ok {
+		t.Fatal("CFG not found")
+	}
+	bf, _ := cfg.Layout.Field("BURST")
+	if got := bf.ValueString(bf.Decode(cfg.Value())); got != "INCR8" {
+		t.Errorf("CFG.BURST at reset = %s, want INCR8", got)
+	}
+	if err := cfg.Set("VERSION", 1); err == nil {
+		t.Error("Set(CFG.VERSION) succeeded on read-only field, want error")
+	}
+	irq, _ := m.ByOffset(0x4)
+	if irq.SideEffect("DONE") != bitfield.WriteOneToClear {
+		t.Errorf("IRQ.DONE side effect = %v, want W1C", irq.SideEffect("DONE"))
+	}
+	if _, ok := m.ByName("CH_1_LEN_1"); !ok {
+		t.Error("CH_1_LEN_1 not found")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	wrap := func(registers string) string {
+		return `<component><name>C</name><memoryMaps><memoryMap><name>M</name><addressBlock><name>B</name>` +
+			`<baseAddress>0</baseAddress>` + registers + `</addressBlock></memoryMap></memoryMaps></component>`
+	}
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"malformed xml", "<component>"},
+		{"bad base address", `<component><memoryMaps><memoryMap><addressBlock><baseAddress>zz</baseAddress></addressBlock></memoryMap></memoryMaps></component>`},
+		{"bad access", wrap(`<register><name>R</name><addressOffset>0</addressOffset><access>sometimes</access></register>`)},
+		{"missing bit width", wrap(`<register><name>R</name><addressOffset>0</addressOffset><field><name>F</name><bitOffset>0</bitOffset></field></register>`)},
+		{"bad reset", wrap(`<register><name>R</name><addressOffset>0</addressOffset><reset><value>'q1</value></reset></register>`)},
+		{"unsupported write value", wrap(`<register><name>R</name><addressOffset>0</addressOffset><field><name>F</name>` +
+			`<bitOffset>0</bitOffset><bitWidth>1</bitWidth><modifiedWriteValue>zeroToToggle</modifiedWriteValue></field></register>`)},
+		{"zero dim", wrap(`<register><name>R</name><dim>0</dim><addressOffset>0</addressOffset></register>`)},
+		{"register file array without range", wrap(`<registerFile><name>F</name><dim>2</dim><addressOffset>0</addressOffset></registerFile>`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.src)); err == nil {
+				t.Errorf("Parse(%q) succeeded, want error", tt.src)
+			}
+		})
+	}
+}
+
+func TestAddressBlock_RegisterMapErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *AddressBlock
+	}{
+		{"16-bit register", &AddressBlock{Name: "B", Registers: []*Register{{Name: "R", Size: 16}}}},
+		{"overlapping fields", &AddressBlock{Name: "B", Registers: []*Register{{Name: "R", Size: 32, Fields: []*Field{
+			{Name: "A", Shift: 0, Size: 4}, {Name: "B", Shift: 2, Size: 4},
+		}}}}},
+		{"overlapping registers", &AddressBlock{Name: "B", Registers: []*Register{
+			{Name: "A", Size: 32, Offset: 0}, {Name: "B", Size: 32, Offset: 2},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.b.RegisterMap(); err == nil {
+				t.Error("RegisterMap() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"42", 42, false},
+		{"0x2A", 42, false},
+		{"#2a", 42, false},
+		{"'h2A", 42, false},
+		{"8'b0010_1010", 42, false},
+		{"4K", 4096, false},
+		{"0x1M", 1 << 20, false},
+		{" 7 ", 7, false},
+		{"x", 0, true},
+		{"'z1", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNumber(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseNumber(%q) = %v, %v, want %v, err = %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package ipxact
+
+// The xml types mirror the subset of the IP-XACT schema that is imported.
+// Element names are matched without their namespace, so documents using the
+// IEEE 1685-2009 (spirit) and 1685-2014 (ipxact) namespaces are both accepted.
+
+type xmlComponent struct {
+	Vendor     string         `xml:"vendor"`
+	Library    string         `xml:"library"`
+	Name       string         `xml:"name"`
+	Version    string         `xml:"version"`
+	MemoryMaps []xmlMemoryMap `xml:"memoryMaps>memoryMap"`
+}
+
+type xmlMemoryMap struct {
+	Name          string            `xml:"name"`
+	Description   string            `xml:"description"`
+	AddressBlocks []xmlAddressBlock `xml:"addressBlock"`
+}
+
+type xmlAddressBlock struct {
+	Name        string `xml:"name"`
+	Description string `xml:"description"`
+	BaseAddress string `xml:"baseAddress"`
+	Range       string `xml:"range"`
+	Width       string `xml:"width"`
+	xmlRegisters
+}
+
+type xmlRegisters struct {
+	Registers     []xmlRegister     `xml:"register"`
+	RegisterFiles []xmlRegisterFile `xml:"registerFile"`
+}
+
+type xmlRegisterFile struct {
+	Name          string   `xml:"name"`
+	Dim           []string `xml:"dim"`
+	AddressOffset string   `xml:"addressOffset"`
+	Range         string   `xml:"range"`
+	xmlRegisters
+}
+
+type xmlRegister struct {
+	Name          string     `xml:"name"`
+	Description   string     `xml:"description"`
+	Dim           []string   `xml:"dim"`
+	AddressOffset string     `xml:"addressOffset"`
+	Size          string     `xml:"size"`
+	Access        string     `xml:"access"`
+	Reset         string     `xml:"reset>value"`
+	Fields        []xmlField `xml:"field"`
+}
+
+type xmlField struct {
+	Name               string               `xml:"name"`
+	Description        string               `xml:"description"`
+	BitOffset          string               `xml:"bitOffset"`
+	BitWidth           string               `xml:"bitWidth"`
+	Reset              string               `xml:"resets>reset>value"`
+	Access             string               `xml:"access"`
+	ModifiedWriteValue string               `xml:"modifiedWriteValue"`
+	ReadAction         string               `xml:"readAction"`
+	EnumeratedValues   []xmlEnumeratedValue `xml:"enumeratedValues>enumeratedValue"`
+}
+
+type xmlEnumeratedValue struct {
+	Name  string `xml:"name"`
+	Value string `xml:"value"`
+}
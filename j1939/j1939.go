@@ -0,0 +1,271 @@
+// Package j1939 decodes SAE J1939 traffic, the CAN protocol of heavy vehicles.
+//
+// ParseID splits a 29-bit identifier into priority, parameter group number
+// (PGN) and addresses. An SPN locates a suspect parameter in the payload of a
+// parameter group, with its scale, offset and the J1939 ranges reserved for
+// error and not-available indicators. Messages of more than 8 bytes are sent
+// with the transport protocol (TP) and are reassembled by a Reassembler.
+//
+// Parameter groups described in DBC files can be decoded with the dbc package;
+// FindMessage looks up the message of a frame by its PGN.
+package j1939
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lnear-dev/bitfield"
+	"github.com/lnear-dev/bitfield/dbc"
+)
+
+// Fields of a 29-bit J1939 identifier.
+var (
+	PriorityField = bitfield.New[uint8, uint32](26, 3) // Priority, 0 is highest
+	EDPField      = bitfield.New[uint8, uint32](25, 1) // Extended data page
+	DPField       = bitfield.New[uint8, uint32](24, 1) // Data page
+	PFField       = bitfield.New[uint8, uint32](16, 8) // PDU format
+	PSField       = bitfield.New[uint8, uint32](8, 8)  // PDU specific: destination address or group extension
+	SAField       = bitfield.New[uint8, uint32](0, 8)  // Source address
+)
+
+// IDLayout returns a Layout of the fields of a 29-bit J1939 identifier,
+// named Priority, EDP, DP, PF, PS and SA.
+func IDLayout() *bitfield.Layout[uint32] {
+	l := bitfield.NewLayout[uint32]()
+	for _, f := range []struct {
+		name  string
+		field bitfield.BitField[uint8, uint32]
+	}{
+		{"SA", SAField}, {"PS", PSField}, {"PF", PFField},
+		{"DP", DPField}, {"EDP", EDPField}, {"Priority", PriorityField},
+	} {
+		if err := l.Add(f.name, f.field.Shift, f.field.Size); err != nil {
+			panic(err)
+		}
+	}
+	return l
+}
+
+// Addresses with a special meaning.
+const (
+	GlobalAddress uint8 = 0xFF // Destination of broadcast messages
+	NullAddress   uint8 = 0xFE // Source of nodes that have not claimed an address
+)
+
+// PGN is an 18-bit parameter group number.
+type PGN uint32
+
+// MaxPGN is the largest parameter group number.
+const MaxPGN PGN = 0x3FFFF
+
+// PDU1 reports whether messages of the parameter group are sent to a
+// destination address (PDU format below 240). The PDU specific byte of PDU1
+// parameter group numbers is 0.
+func (p PGN) PDU1() bool {
+	return p>>8&0xFF < 240
+}
+
+// ID is a decomposed 29-bit J1939 identifier.
+type ID struct {
+	Priority    uint8
+	PGN         PGN
+	Source      uint8 // Source address
+	Destination uint8 // Destination address; GlobalAddress for PDU2 messages
+}
+
+// ParseID decomposes a 29-bit identifier. For PDU1 messages the PDU specific
+// byte is the destination address and is cleared in the PGN.
+func ParseID(id uint32) ID {
+	pgn := PGN(id>>8) & MaxPGN
+	dst := GlobalAddress
+	if pgn.PDU1() {
+		dst = PSField.Decode(id)
+		pgn &^= 0xFF
+	}
+	return ID{Priority: PriorityField.Decode(id), PGN: pgn, Source: SAField.Decode(id), Destination: dst}
+}
+
+// Uint32 composes the 29-bit identifier.
+// Returns an error if the priority exceeds 7, the PGN exceeds MaxPGN, or a
+// PDU1 PGN has a non-zero PDU specific byte.
+func (id ID) Uint32() (uint32, error) {
+	if !PriorityField.IsValid(id.Priority) {
+		return 0, fmt.Errorf("j1939: priority %d out of range, max 7", id.Priority)
+	}
+	if id.PGN > MaxPGN {
+		return 0, fmt.Errorf("j1939: PGN %d out of range, max %d", id.PGN, MaxPGN)
+	}
+	v := PriorityField.Encode(id.Priority) | uint32(id.PGN)<<8 | SAField.Encode(id.Source)
+	if id.PGN.PDU1() {
+		if id.PGN&0xFF != 0 {
+			return 0, fmt.Errorf("j1939: PDU1 PGN 0x%05X has a non-zero PDU specific byte", uint32(id.PGN))
+		}
+		v = PSField.Update(v, id.Destination)
+	}
+	return v, nil
+}
+
+// FindMessage returns the extended-frame message of a DBC database with the
+// PGN of a 29-bit identifier, or nil if there is none. Priority and addresses
+// are ignored, as J1939 DBC files describe a parameter group once for all
+// senders.
+func FindMessage(db *dbc.Database, id uint32) *dbc.Message {
+	pgn := ParseID(id).PGN
+	for _, m := range db.Messages {
+		if m.Extended && ParseID(m.ID).PGN == pgn {
+			return m
+		}
+	}
+	return nil
+}
+
+// Status classifies a raw parameter value according to the ranges of J1939-71.
+type Status int
+
+const (
+	Valid          Status = iota // Value in the valid range
+	Reserved                     // Value in the range reserved for future indicators
+	ErrorIndicator               // The sender reports an error in the parameter
+	NotAvailable                 // The parameter is not available or not supported
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case Valid:
+		return "valid"
+	case Reserved:
+		return "reserved"
+	case ErrorIndicator:
+		return "error"
+	case NotAvailable:
+		return "not available"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// SPN is a suspect parameter of a parameter group. The raw value is located
+// in the payload loaded as a little-endian uint64, padded with 0xFF.
+type SPN struct {
+	Number uint32
+	Name   string
+	Unit   string
+	Scaled bitfield.ScaledField[uint64, uint64] // Position, scale and offset of the value
+}
+
+// NewSPN creates an SPN at a position in J1939-71 notation: the 1-based start
+// byte, optionally followed by "." and the 1-based start bit within that byte,
+// such as "1.5", or a byte range such as "4-5".
+// Returns an error if the position is malformed or the parameter does not fit
+// in 8 bytes.
+func NewSPN(number uint32, name, position string, length uint, scale, offset float64, unit string) (SPN, error) {
+	shift, err := parsePosition(position)
+	if err != nil {
+		return SPN{}, fmt.Errorf("j1939: SPN %d: %w", number, err)
+	}
+	field, err := bitfield.Safe[uint64, uint64](shift, length)
+	if err != nil {
+		return SPN{}, fmt.Errorf("j1939: SPN %d: %d bits at %s exceed 8 bytes", number, length, position)
+	}
+	if scale == 0 {
+		return SPN{}, fmt.Errorf("j1939: SPN %d: scale must not be 0", number)
+	}
+	return SPN{Number: number, Name: name, Unit: unit, Scaled: bitfield.NewScaled(field, scale, offset)}, nil
+}
+
+// parsePosition returns the shift of a position in J1939-71 notation.
+func parsePosition(pos string) (uint, error) {
+	byteStr, bitStr, hasBit := strings.Cut(pos, ".")
+	if !hasBit {
+		byteStr, _, _ = strings.Cut(pos, "-")
+		bitStr = "1"
+	}
+	b, err1 := strconv.ParseUint(strings.TrimSpace(byteStr), 10, 8)
+	bit, err2 := strconv.ParseUint(strings.TrimSpace(bitStr), 10, 8)
+	if err1 != nil || err2 != nil || b < 1 || b > 8 || bit < 1 || bit > 8 {
+		return 0, fmt.Errorf("invalid position %q", pos)
+	}
+	return uint((b-1)*8 + bit - 1), nil
+}
+
+// load returns a payload as a little-endian uint64, padding short payloads
+// with 0xFF as unused J1939 bytes are.
+func load(data []byte) uint64 {
+	buf := [8]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	copy(buf[:], data)
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// Raw returns the raw value of the parameter in a payload.
+func (s SPN) Raw(data []byte) uint64 {
+	return s.Scaled.Field.Decode(load(data))
+}
+
+// Decode returns the physical value of the parameter in a payload and the
+// status of its raw value. The physical value is only meaningful if the status
+// is Valid.
+func (s SPN) Decode(data []byte) (float64, Status) {
+	return s.Scaled.Decode(load(data)), s.Status(s.Raw(data))
+}
+
+// Status classifies a raw value. Parameters of whole bytes reserve the top
+// values of their most significant byte: 0xFB to 0xFD, 0xFE for errors and
+// 0xFF for not available. Shorter parameters use the largest value for not
+// available and the one below for errors.
+func (s SPN) Status(raw uint64) Status {
+	size := s.Scaled.Field.Size
+	if size%8 != 0 {
+		switch m := s.Scaled.Field.Max(); {
+		case size == 1:
+			return Valid
+		case raw == m:
+			return NotAvailable
+		case raw == m-1:
+			return ErrorIndicator
+		}
+		return Valid
+	}
+	switch top := raw >> (size - 8); {
+	case top == 0xFF:
+		return NotAvailable
+	case top == 0xFE:
+		return ErrorIndicator
+	case top >= 0xFB:
+		return Reserved
+	}
+	return Valid
+}
+
+// Encode sets the parameter in a payload to a physical value, preserving the
+// other bytes.
+// Returns an error if the value is out of range or the payload is too short.
+func (s SPN) Encode(data []byte, value float64) error {
+	f := s.Scaled.Field
+	if uint(len(data))*8 < f.Shift+f.Size {
+		return fmt.Errorf("j1939: SPN %d: payload of %d bytes is too short", s.Number, len(data))
+	}
+	word, err := s.Scaled.Update(load(data), value)
+	if err != nil {
+		return fmt.Errorf("j1939: SPN %d: %w", s.Number, err)
+	}
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], word)
+	copy(data, buf[:])
+	return nil
+}
+
+// Layout returns a little-endian Layout over the payload of a parameter group
+// with one field per SPN, named after the SPN.
+// Returns an error if names are missing or duplicated, or parameters overlap.
+func Layout(spns []SPN) (*bitfield.Layout[uint64], error) {
+	l := bitfield.NewLayout[uint64]()
+	l.SetByteOrder(binary.LittleEndian)
+	for _, s := range spns {
+		if err := l.Add(s.Name, s.Scaled.Field.Shift, s.Scaled.Field.Size); err != nil {
+			return nil, fmt.Errorf("j1939: SPN %d: %w", s.Number, err)
+		}
+	}
+	return l, nil
+}
@@ -0,0 +1,204 @@
+package j1939
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield/dbc"
+)
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   uint32
+		want ID
+	}{
+		{"CCVS", 0x18FEF1FE, ID{Priority: 6, PGN: 0xFEF1, Source: 0xFE, Destination: GlobalAddress}},
+		{"EEC1", 0x0CF00400, ID{Priority: 3, PGN: 0xF004, Source: 0x00, Destination: GlobalAddress}},
+		{"request", 0x18EA00F9, ID{Priority: 6, PGN: 0xEA00, Source: 0xF9, Destination: 0x00}},
+		{"data page", 0x19FECA00, ID{Priority: 6, PGN: 0x1FECA, Source: 0x00, Destination: GlobalAddress}},
+		{"TP.CM", 0x1CEC21F9, ID{Priority: 7, PGN: PGNTPConnection, Source: 0xF9, Destination: 0x21}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseID(tt.id)
+			if got != tt.want {
+				t.Errorf("ParseID(0x%08X) = %+v, want %+v", tt.id, got, tt.want)
+			}
+			if id, err := got.Uint32(); err != nil || id != tt.id {
+				t.Errorf("Uint32() = 0x%08X, %v, want 0x%08X", id, err, tt.id)
+			}
+		})
+	}
+}
+
+func TestID_Uint32Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		id   ID
+	}{
+		{"priority", ID{Priority: 8, PGN: 0xFEF1}},
+		{"PGN", ID{PGN: MaxPGN + 1}},
+		{"PDU1 with PS", ID{PGN: 0xEA01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.id.Uint32(); err == nil {
+				t.Errorf("Uint32(%+v) succeeded, want error", tt.id)
+			}
+		})
+	}
+}
+
+func TestIDLayout(t *testing.T) {
+	got := IDLayout().DecodeAll(0x18EA00F9)
+	want := map[string]uint64{"Priority": 6, "EDP": 0, "DP": 0, "PF": 0xEA, "PS": 0x00, "SA": 0xF9}
+	if !maps.Equal(got, want) {
+		t.Errorf("DecodeAll = %v, want %v", got, want)
+	}
+}
+
+func TestFindMessage(t *testing.T) {
+	db, err := dbc.Parse(strings.NewReader(`
+BO_ 2364540158 EEC1: 8 Vector__XXX
+ SG_ EngineSpeed : 24|16@1+ (0.125,0) [0|8031.875] "rpm" Vector__XXX
+`))
+	if err != nil {
+		t.Fatalf("dbc.Parse: %v", err)
+	}
+	m := FindMessage(db, 0x0CF00400)
+	if m == nil || m.Name != "EEC1" {
+		t.Fatalf("FindMessage(0x0CF00400) = %v, want EEC1", m)
+	}
+	if got := m.Signal("EngineSpeed").Decode([]byte{0xFF, 0xFF, 0xFF, 0x68, 0x13, 0xFF, 0xFF, 0xFF}); got != 621 {
+		t.Errorf("EngineSpeed = %v, want 621", got)
+	}
+	if m := FindMessage(db, 0x18FEF100); m != nil {
+		t.Errorf("FindMessage(0x18FEF100) = %s, want nil", m.Name)
+	}
+}
+
+func newSPN(t *testing.T, number uint32, position string, length uint, scale, offset float64) SPN {
+	t.Helper()
+	s, err := NewSPN(number, "SPN", position, length, scale, offset, "")
+	if err != nil {
+		t.Fatalf("NewSPN: %v", err)
+	}
+	return s
+}
+
+func TestSPN_Decode(t *testing.T) {
+	eec1 := []byte{0xF3, 0x91, 0x8C, 0x68, 0x13, 0xFF, 0xFF, 0xFF}
+	tests := []struct {
+		name       string
+		spn        SPN
+		data       []byte
+		want       float64
+		wantStatus Status
+	}{
+		{"engine speed", newSPN(t, 190, "4-5", 16, 0.125, 0), eec1, 621, Valid},
+		{"demand torque", newSPN(t, 512, "2", 8, 1, -125), eec1, 20, Valid},
+		{"torque mode", newSPN(t, 899, "1.1", 4, 1, 0), eec1, 3, Valid},
+		{"starter mode", newSPN(t, 1675, "7.1", 4, 1, 0), eec1, 15, NotAvailable},
+		{"short payload", newSPN(t, 190, "4-5", 16, 0.125, 0), eec1[:3], 8191.875, NotAvailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, status := tt.spn.Decode(tt.data)
+			if got != tt.want || status != tt.wantStatus {
+				t.Errorf("Decode = %v, %v, want %v, %v", got, status, tt.want, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSPN_Status(t *testing.T) {
+	tests := []struct {
+		size uint
+		raw  uint64
+		want Status
+	}{
+		{16, 0xFAFF, Valid},
+		{16, 0xFB00, Reserved},
+		{16, 0xFE12, ErrorIndicator},
+		{16, 0xFFFF, NotAvailable},
+		{8, 0xFA, Valid},
+		{8, 0xFE, ErrorIndicator},
+		{32, 0xFF000000, NotAvailable},
+		{2, 1, Valid},
+		{2, 2, ErrorIndicator},
+		{2, 3, NotAvailable},
+		{4, 14, ErrorIndicator},
+		{1, 1, Valid},
+	}
+
+	for _, tt := range tests {
+		s := newSPN(t, 1, "1", tt.size, 1, 0)
+		if got := s.Status(tt.raw); got != tt.want {
+			t.Errorf("%d-bit Status(0x%X) = %v, want %v", tt.size, tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestSPN_Encode(t *testing.T) {
+	speed := newSPN(t, 190, "4-5", 16, 0.125, 0)
+	data := bytes.Repeat([]byte{0xFF}, 8)
+	if err := speed.Encode(data, 1500); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if want := []byte{0xFF, 0xFF, 0xFF, 0xE0, 0x2E, 0xFF, 0xFF, 0xFF}; !bytes.Equal(data, want) {
+		t.Errorf("payload = % X, want % X", data, want)
+	}
+	if err := speed.Encode(data, -1); err == nil {
+		t.Error("Encode(-1) succeeded, want error")
+	}
+	if err := speed.Encode(data[:4], 1); err == nil {
+		t.Error("Encode into 4-byte payload succeeded, want error")
+	}
+}
+
+func TestNewSPN_Errors(t *testing.T) {
+	tests := []struct {
+		position string
+		length   uint
+		scale    float64
+	}{
+		{"0.1", 8, 1},
+		{"9", 8, 1},
+		{"1.9", 1, 1},
+		{"x", 8, 1},
+		{"8.1", 16, 1},
+		{"1", 0, 1},
+		{"1", 8, 0},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewSPN(1, "S", tt.position, tt.length, tt.scale, 0, ""); err == nil {
+			t.Errorf("NewSPN(%q, %d, %v) succeeded, want error", tt.position, tt.length, tt.scale)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	mode, _ := NewSPN(899, "TorqueMode", "1.1", 4, 1, 0, "")
+	speed, _ := NewSPN(190, "EngineSpeed", "4-5", 16, 0.125, 0, "rpm")
+	l, err := Layout([]SPN{mode, speed})
+	if err != nil {
+		t.Fatalf("Layout: %v", err)
+	}
+	var v [8]byte
+	copy(v[:], []byte{0xF3, 0x91, 0x8C, 0x68, 0x13, 0xFF, 0xFF, 0xFF})
+	got := l.DecodeAll(load(v[:]))
+	if want := map[string]uint64{"TorqueMode": 3, "EngineSpeed": 4968}; !maps.Equal(got, want) {
+		t.Errorf("DecodeAll = %v, want %v", got, want)
+	}
+
+	if _, err := Layout([]SPN{speed, speed}); err == nil {
+		t.Error("Layout with duplicate SPN succeeded, want error")
+	}
+}
@@ -0,0 +1,155 @@
+package j1939
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+)
+
+// Parameter groups of the transport protocol.
+const (
+	PGNTPConnection PGN = 0xEC00 // TP.CM: connection management
+	PGNTPData       PGN = 0xEB00 // TP.DT: data transfer
+)
+
+// Control bytes of TP.CM messages.
+const (
+	tpRTS   = 16  // Request to send
+	tpCTS   = 17  // Clear to send
+	tpEnd   = 19  // End of message acknowledgment
+	tpBAM   = 32  // Broadcast announce message
+	tpAbort = 255 // Connection abort
+)
+
+// Limits of a transported message.
+const (
+	tpMinSize = 9
+	tpMaxSize = 1785 // 255 packets of 7 bytes
+)
+
+// Message is a complete J1939 message: a single frame or a message reassembled
+// from transport protocol packets.
+type Message struct {
+	ID   ID // Identifier; for transported messages the PGN is that of the payload
+	Data []byte
+}
+
+// session identifies a transfer by its source and destination addresses.
+type session struct {
+	src, dst uint8
+}
+
+// transfer is a transport protocol transfer in progress.
+type transfer struct {
+	id      ID
+	data    []byte
+	size    int // Message size in bytes announced
+	packets int // Number of packets announced
+	next    int // Sequence number of the next packet
+}
+
+// Reassembler reassembles messages sent with the J1939 transport protocol,
+// both broadcast (BAM) and connection mode (RTS/CTS) transfers, from the
+// frames observed on a bus. It only listens and never sends CTS or
+// acknowledgments. Timeouts are not enforced; a new announcement from the
+// same sender replaces a stale transfer.
+// A Reassembler is not safe for concurrent use.
+type Reassembler struct {
+	handler   func(Message)
+	transfers map[session]*transfer
+}
+
+// NewReassembler creates a Reassembler that passes every complete message to
+// handler: frames of other parameter groups as they are received, and
+// transported messages once their last packet is received.
+func NewReassembler(handler func(Message)) *Reassembler {
+	return &Reassembler{handler: handler, transfers: make(map[session]*transfer)}
+}
+
+// Pending returns the number of transfers in progress.
+func (r *Reassembler) Pending() int {
+	return len(r.transfers)
+}
+
+// Receive processes a frame with a 29-bit identifier.
+// Returns an error if a transport protocol frame is malformed or out of
+// sequence; the transfer it belongs to is then dropped.
+func (r *Reassembler) Receive(id uint32, data []byte) error {
+	frame := ParseID(id)
+	switch frame.PGN {
+	case PGNTPConnection:
+		return r.connection(frame, data)
+	case PGNTPData:
+		return r.data(frame, data)
+	}
+	r.handler(Message{ID: frame, Data: slices.Clone(data)})
+	return nil
+}
+
+// connection processes a TP.CM frame.
+func (r *Reassembler) connection(frame ID, data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("j1939: TP.CM from 0x%02X: length %d, want 8", frame.Source, len(data))
+	}
+	key := session{frame.Source, frame.Destination}
+	switch data[0] {
+	case tpRTS, tpBAM:
+		delete(r.transfers, key)
+		if (data[0] == tpBAM) != (frame.Destination == GlobalAddress) {
+			return fmt.Errorf("j1939: TP.CM from 0x%02X: control byte %d sent to 0x%02X", frame.Source, data[0], frame.Destination)
+		}
+		size := int(binary.LittleEndian.Uint16(data[1:3]))
+		packets := int(data[3])
+		if size < tpMinSize || size > tpMaxSize || packets != (size+6)/7 {
+			return fmt.Errorf("j1939: TP.CM from 0x%02X: %d bytes in %d packets", frame.Source, size, packets)
+		}
+		pgn := PGN(uint32(data[5]) | uint32(data[6])<<8 | uint32(data[7])<<16)
+		if pgn > MaxPGN {
+			return fmt.Errorf("j1939: TP.CM from 0x%02X: invalid PGN 0x%X", frame.Source, uint32(pgn))
+		}
+		r.transfers[key] = &transfer{
+			id:      ID{Priority: frame.Priority, PGN: pgn, Source: frame.Source, Destination: frame.Destination},
+			data:    make([]byte, 0, packets*7),
+			size:    size,
+			packets: packets,
+			next:    1,
+		}
+	case tpCTS, tpEnd:
+		// Sent by the receiver; the transfer is tracked from the data packets.
+	case tpAbort:
+		delete(r.transfers, key)
+		delete(r.transfers, session{frame.Destination, frame.Source})
+	default:
+		return fmt.Errorf("j1939: TP.CM from 0x%02X: unknown control byte %d", frame.Source, data[0])
+	}
+	return nil
+}
+
+// data processes a TP.DT frame.
+func (r *Reassembler) data(frame ID, data []byte) error {
+	key := session{frame.Source, frame.Destination}
+	t, ok := r.transfers[key]
+	if !ok {
+		return fmt.Errorf("j1939: TP.DT from 0x%02X: no transfer in progress", frame.Source)
+	}
+	if len(data) != 8 {
+		delete(r.transfers, key)
+		return fmt.Errorf("j1939: TP.DT from 0x%02X: length %d, want 8", frame.Source, len(data))
+	}
+	seq := int(data[0])
+	// In connection mode the receiver may ask for packets again with a CTS,
+	// so a packet that was already received restarts the transfer from there.
+	resend := seq >= 1 && seq < t.next && frame.Destination != GlobalAddress
+	if seq != t.next && !resend {
+		delete(r.transfers, key)
+		return fmt.Errorf("j1939: TP.DT from 0x%02X: packet %d, want %d", frame.Source, seq, t.next)
+	}
+	t.data = append(t.data[:(seq-1)*7], data[1:]...)
+	t.next = seq + 1
+	if t.next <= t.packets {
+		return nil
+	}
+	delete(r.transfers, key)
+	r.handler(Message{ID: t.id, Data: t.data[:t.size]})
+	return nil
+}
@@ -0,0 +1,128 @@
+package j1939
+
+import (
+	"bytes"
+	"testing"
+)
+
+type frame struct {
+	id   uint32
+	data []byte
+}
+
+func receiveAll(t *testing.T, frames []frame) []Message {
+	t.Helper()
+	var got []Message
+	r := NewReassembler(func(m Message) { got = append(got, m) })
+	for i, f := range frames {
+		if err := r.Receive(f.id, f.data); err != nil {
+			t.Fatalf("Receive frame %d: %v", i, err)
+		}
+	}
+	if r.Pending() != 0 {
+		t.Errorf("Pending = %d, want 0", r.Pending())
+	}
+	return got
+}
+
+// payload returns n bytes counting up from 1.
+func payload(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i + 1)
+	}
+	return b
+}
+
+func TestReassembler_BAM(t *testing.T) {
+	want := payload(20)
+	got := receiveAll(t, []frame{
+		{0x1CECFF00, []byte{32, 20, 0, 3, 0xFF, 0xE3, 0xFE, 0x00}},
+		{0x1CEBFF00, append([]byte{1}, want[0:7]...)},
+		{0x0CF00400, []byte{0xF3, 0x91, 0x8C, 0x68, 0x13, 0xFF, 0xFF, 0xFF}},
+		{0x1CEBFF00, append([]byte{2}, want[7:14]...)},
+		{0x1CEBFF00, append([]byte{3}, append(want[14:20], 0xFF)...)},
+	})
+	if len(got) != 2 {
+		t.Fatalf("got %d messages, want 2", len(got))
+	}
+	if got[0].ID.PGN != 0xF004 || len(got[0].Data) != 8 {
+		t.Errorf("message 0 = PGN 0x%X with %d bytes, want EEC1 frame", uint32(got[0].ID.PGN), len(got[0].Data))
+	}
+	wantID := ID{Priority: 7, PGN: 0xFEE3, Source: 0x00, Destination: GlobalAddress}
+	if got[1].ID != wantID || !bytes.Equal(got[1].Data, want) {
+		t.Errorf("message 1 = %+v % X, want %+v % X", got[1].ID, got[1].Data, wantID, want)
+	}
+}
+
+func TestReassembler_ConnectionMode(t *testing.T) {
+	want := payload(10)
+	got := receiveAll(t, []frame{
+		{0x1CEC00F9, []byte{16, 10, 0, 2, 2, 0xCA, 0xFE, 0x00}},
+		{0x1CECF900, []byte{17, 2, 1, 0xFF, 0xFF, 0xCA, 0xFE, 0x00}},
+		{0x1CEB00F9, append([]byte{1}, want[0:7]...)},
+		{0x1CEB00F9, []byte{1, 0, 0, 0, 0, 0, 0, 0}},
+		{0x1CECF900, []byte{17, 2, 1, 0xFF, 0xFF, 0xCA, 0xFE, 0x00}},
+		{0x1CEB00F9, append([]byte{1}, want[0:7]...)},
+		{0x1CEB00F9, append([]byte{2}, append(want[7:10], 0xFF, 0xFF, 0xFF, 0xFF)...)},
+		{0x1CECF900, []byte{19, 10, 0, 2, 0xFF, 0xCA, 0xFE, 0x00}},
+	})
+	if len(got) != 1 {
+		t.Fatalf("got %d messages, want 1", len(got))
+	}
+	wantID := ID{Priority: 7, PGN: 0xFECA, Source: 0xF9, Destination: 0x00}
+	if got[0].ID != wantID || !bytes.Equal(got[0].Data, want) {
+		t.Errorf("message = %+v % X, want %+v % X", got[0].ID, got[0].Data, wantID, want)
+	}
+}
+
+func TestReassembler_Abort(t *testing.T) {
+	r := NewReassembler(func(m Message) { t.Errorf("unexpected message %+v", m) })
+	if err := r.Receive(0x1CEC00F9, []byte{16, 10, 0, 2, 2, 0xCA, 0xFE, 0x00}); err != nil {
+		t.Fatalf("Receive RTS: %v", err)
+	}
+	if err := r.Receive(0x1CECF900, []byte{255, 1, 0xFF, 0xFF, 0xFF, 0xCA, 0xFE, 0x00}); err != nil {
+		t.Fatalf("Receive abort: %v", err)
+	}
+	if r.Pending() != 0 {
+		t.Errorf("Pending after abort = %d, want 0", r.Pending())
+	}
+}
+
+func TestReassembler_Errors(t *testing.T) {
+	bam := frame{0x1CECFF00, []byte{32, 20, 0, 3, 0xFF, 0xE3, 0xFE, 0x00}}
+	tests := []struct {
+		name   string
+		frames []frame
+	}{
+		{"short TP.CM", []frame{{0x1CECFF00, []byte{32, 20, 0}}}},
+		{"unknown control byte", []frame{{0x1CECFF00, []byte{99, 20, 0, 3, 0xFF, 0xE3, 0xFE, 0x00}}}},
+		{"packet count", []frame{{0x1CECFF00, []byte{32, 20, 0, 4, 0xFF, 0xE3, 0xFE, 0x00}}}},
+		{"too short", []frame{{0x1CECFF00, []byte{32, 8, 0, 2, 0xFF, 0xE3, 0xFE, 0x00}}}},
+		{"BAM to address", []frame{{0x1CEC00F9, []byte{32, 20, 0, 3, 0xFF, 0xE3, 0xFE, 0x00}}}},
+		{"RTS to global", []frame{{0x1CECFFF9, []byte{16, 20, 0, 3, 0xFF, 0xE3, 0xFE, 0x00}}}},
+		{"invalid PGN", []frame{{0x1CECFF00, []byte{32, 20, 0, 3, 0xFF, 0xE3, 0xFE, 0x04}}}},
+		{"no transfer", []frame{{0x1CEBFF00, []byte{1, 1, 2, 3, 4, 5, 6, 7}}}},
+		{"short TP.DT", []frame{bam, {0x1CEBFF00, []byte{1, 1, 2}}}},
+		{"out of sequence", []frame{bam, {0x1CEBFF00, []byte{2, 1, 2, 3, 4, 5, 6, 7}}}},
+		{"repeated broadcast", []frame{bam, {0x1CEBFF00, []byte{1, 1, 2, 3, 4, 5, 6, 7}}, {0x1CEBFF00, []byte{1, 1, 2, 3, 4, 5, 6, 7}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReassembler(func(Message) {})
+			var err error
+			for _, f := range tt.frames {
+				if err = r.Receive(f.id, f.data); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				t.Error("Receive succeeded, want error")
+			}
+			if r.Pending() != 0 {
+				t.Errorf("Pending = %d, want 0", r.Pending())
+			}
+		})
+	}
+}
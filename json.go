@@ -0,0 +1,93 @@
+package bitfield
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// MarshalJSON renders the container as a JSON object with one member per field,
+// in the order the fields were added to the layout.
+// Values with a registered enum name are rendered as strings, other 1-bit fields
+// as booleans and all other fields as numbers, for example {"priority":"High","active":true}.
+// Fields that are not present, as declared with Layout.SetCondition, are left out.
+func (p Packed[U]) MarshalJSON() ([]byte, error) {
+	l, err := p.layout()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range l.fields {
+		if !l.present(i, p.Value) {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, _ := json.Marshal(fieldValue(f.field, f.field.Decode(p.Value)))
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON sets the fields named in a JSON object, as produced by MarshalJSON.
+// Values may be numbers, booleans, enum names or numeric strings.
+// Fields missing from the object are left unchanged.
+// Returns an error, leaving p unchanged, if p has no layout, the object names an
+// unknown field or a field that is not present in the result, or a value is
+// invalid or does not fit in its field.
+func (p *Packed[U]) UnmarshalJSON(data []byte) error {
+	l, err := p.layout()
+	if err != nil {
+		return err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	container := p.Value
+	for name, raw := range members {
+		bf, ok := l.Field(name)
+		if !ok {
+			return fmt.Errorf("unknown field %q", name)
+		}
+		value, err := unmarshalFieldJSON(bf, raw)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", name, err)
+		}
+		container = bf.Update(container, value)
+	}
+	if err := l.checkPresent(container, maps.Keys(members)); err != nil {
+		return err
+	}
+	p.Value = container
+	return nil
+}
+
+// unmarshalFieldJSON converts a JSON value into a value of bf.
+func unmarshalFieldJSON[U storageType](bf BitField[uint64, U], raw json.RawMessage) (uint64, error) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return 0, err
+	}
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return bf.Parse("1")
+		}
+		return bf.Parse("0")
+	case json.Number:
+		return bf.Parse(v.String())
+	case string:
+		return bf.Parse(v)
+	}
+	return 0, fmt.Errorf("invalid value %s", raw)
+}
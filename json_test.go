@@ -0,0 +1,86 @@
+package bitfield
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestPacked_MarshalJSON(t *testing.T) {
+	l := newStatusLayout(t)
+	tests := []struct {
+		name  string
+		value uint32
+		want  string
+	}{
+		{"enum name", 0x00002A57, `{"active":true,"priority":"High","category":5,"error":42}`},
+		{"unnamed enum value", 0x00000004, `{"active":false,"priority":2,"category":0,"error":0}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(l.Bind(tt.value))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal(0x%08X) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := json.Marshal(Packed[uint32]{}); err == nil {
+		t.Error("Marshal without layout succeeded, want error")
+	}
+}
+
+func TestPacked_UnmarshalJSON(t *testing.T) {
+	l := newStatusLayout(t)
+	tests := []struct {
+		name    string
+		initial uint32
+		input   string
+		want    uint32
+		wantErr bool
+	}{
+		{"round trip", 0, `{"active":true,"priority":"High","category":5,"error":42}`, 0x00002A57, false},
+		{"numbers", 0, `{"active":1,"priority":3,"category":5,"error":42}`, 0x00002A57, false},
+		{"numeric string", 0x0000FFFF, `{"error":"0x2A"}`, 0x00002AFF, false},
+		{"partial update", 0x0000FFFF, `{"active":false}`, 0x0000FFFE, false},
+		{"unknown field", 0x0000FFFF, `{"bogus":1}`, 0x0000FFFF, true},
+		{"value too large", 0x0000FFFF, `{"category":16}`, 0x0000FFFF, true},
+		{"unknown name", 0x0000FFFF, `{"priority":"Urgent"}`, 0x0000FFFF, true},
+		{"negative", 0x0000FFFF, `{"error":-1}`, 0x0000FFFF, true},
+		{"fraction", 0x0000FFFF, `{"error":1.5}`, 0x0000FFFF, true},
+		{"null", 0x0000FFFF, `{"error":null}`, 0x0000FFFF, true},
+		{"not an object", 0x0000FFFF, `[1]`, 0x0000FFFF, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := l.Bind(tt.initial)
+			err := json.Unmarshal([]byte(tt.input), &p)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s): err = %v, want err = %v", tt.input, err, tt.wantErr)
+			}
+			if p.Value != tt.want {
+				t.Errorf("Unmarshal(%s): Value = 0x%08X, want 0x%08X", tt.input, p.Value, tt.want)
+			}
+		})
+	}
+
+	if err := l.SetAllowed("active", 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range []string{`{"active":false}`, `{"active":0}`} {
+		p := l.Bind(0x00000001)
+		if err := json.Unmarshal([]byte(input), &p); !errors.Is(err, ErrValueNotAllowed) {
+			t.Errorf("Unmarshal(%s) with 0 not allowed: err = %v, want %v", input, err, ErrValueNotAllowed)
+		}
+	}
+
+	var p Packed[uint32]
+	if err := json.Unmarshal([]byte(`{}`), &p); err == nil {
+		t.Error("Unmarshal without layout succeeded, want error")
+	}
+}
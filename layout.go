@@ -0,0 +1,177 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+)
+
+// Layout describes a set of named, non-overlapping bit fields within a container of type U.
+// It is useful when a single register or word holds many fields that would otherwise
+// have to be managed as separate BitField variables.
+// Field values are exchanged as uint64 so that fields of different widths can be
+// handled uniformly.
+type Layout[U storageType] struct {
+	fields []layoutField[U]
+	index  map[string]int
+	used   U                // Union of the masks of all fields
+	order  binary.ByteOrder // Byte order of the binary encoding, nil for big-endian
+}
+
+// layoutField is a single named entry of a Layout.
+type layoutField[U storageType] struct {
+	name  string
+	field BitField[uint64, U]
+	cond  *fieldCondition // Condition for the field to be present, nil if always present
+}
+
+// NewLayout creates an empty Layout for containers of type U.
+func NewLayout[U storageType]() *Layout[U] {
+	return &Layout[U]{index: make(map[string]int)}
+}
+
+// Add adds a named field with the given shift and size to the layout.
+// Options such as WithDescription and WithUnit attach metadata to the field,
+// which is named after name.
+// Returns an error if:
+// - name is empty or already used by another field
+// - size is 0
+// - shift + size exceeds the bit size of the container type U
+// - the field overlaps a field that was added before
+// - an option is invalid, such as an enum value that does not fit
+func (l *Layout[U]) Add(name string, shift, size uint, opts ...Option) error {
+	if name == "" {
+		return fmt.Errorf("field name must not be empty")
+	}
+	if _, ok := l.index[name]; ok {
+		return fmt.Errorf("duplicate field %q", name)
+	}
+	if size == 0 {
+		return fmt.Errorf("field %q: %w", name, ErrSizeOutOfRange)
+	}
+	if width := unsignedSizeOf[U](); shift >= width || shift+size > width {
+		return fmt.Errorf("field %q: %w", name, &FieldOverflowError{Shift: shift, Size: size, Width: width})
+	}
+	bf, err := New[uint64, U](shift, size).withOptions(append(opts[:len(opts):len(opts)], WithName(name)))
+	if err != nil {
+		return fmt.Errorf("field %q: %w", name, err)
+	}
+	if overlap := l.used & bf.Mask; overlap != 0 {
+		for _, f := range l.fields {
+			if f.field.Mask&overlap != 0 {
+				return fmt.Errorf("field %q overlaps field %q", name, f.name)
+			}
+		}
+	}
+	l.index[name] = len(l.fields)
+	l.fields = append(l.fields, layoutField[U]{name: name, field: bf})
+	l.used |= bf.Mask
+	return nil
+}
+
+// Field returns the BitField registered under name.
+// The second return value reports whether the field exists.
+func (l *Layout[U]) Field(name string) (BitField[uint64, U], bool) {
+	i, ok := l.index[name]
+	if !ok {
+		return BitField[uint64, U]{}, false
+	}
+	return l.fields[i].field, true
+}
+
+// Names returns the names of the fields of the layout, in the order they were added.
+func (l *Layout[U]) Names() []string {
+	names := make([]string, len(l.fields))
+	for i, f := range l.fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// Reserved returns the mask of the reserved bits of the container, those outside of all fields.
+func (l *Layout[U]) Reserved() U {
+	return ^l.used
+}
+
+// Validate checks that the reserved bits of container are zero and that fields
+// restricted with SetAllowed hold allowed values, as they should in registers and
+// wire data that follow the layout.
+// The bits of fields that are not present, as declared with SetCondition, count
+// as reserved.
+// Returns an error wrapping ErrReservedBits, with the bits that are set, or
+// ErrValueNotAllowed, with the field, if they do not.
+func (l *Layout[U]) Validate(container U) error {
+	if reserved := container &^ l.presentMask(container); reserved != 0 {
+		return fmt.Errorf("%w: 0x%X", ErrReservedBits, reserved)
+	}
+	for i, f := range l.fields {
+		if !l.present(i, container) {
+			continue
+		}
+		if value := f.field.Decode(container); !f.field.IsValid(value) {
+			return fmt.Errorf("field %q: %w", f.name, f.field.invalidValue(value))
+		}
+	}
+	return nil
+}
+
+// Normalize returns container with its reserved bits cleared, including the
+// bits of fields that are not present, as declared with SetCondition.
+// Fields holding values that are not allowed are left as they are.
+// Containers decoded from untrusted sources can be normalized so that bits
+// the layout does not describe do not leak into comparisons or re-encoding.
+func (l *Layout[U]) Normalize(container U) U {
+	return container & l.presentMask(container)
+}
+
+// SetEnum registers names for the values of a field, so that they are used
+// when the layout's containers are rendered or parsed, for example as JSON.
+// Returns an error if the layout has no such field, a value does not fit in the field,
+// or a name is used for more than one value.
+func (l *Layout[U]) SetEnum(name string, names map[uint64]string) error {
+	i, ok := l.index[name]
+	if !ok {
+		return fmt.Errorf("unknown field %q", name)
+	}
+	bf := l.fields[i].field
+	if err := checkEnum(bf, names); err != nil {
+		return fmt.Errorf("field %q: %w", name, err)
+	}
+	l.fields[i].field = bf.WithEnum(names)
+	return nil
+}
+
+// DecodeAll extracts every field of the layout that is present in container.
+// The returned map is keyed by field name.
+func (l *Layout[U]) DecodeAll(container U) map[string]uint64 {
+	values := make(map[string]uint64, len(l.fields))
+	for i, f := range l.fields {
+		if l.present(i, container) {
+			values[f.name] = f.field.Decode(container)
+		}
+	}
+	return values
+}
+
+// EncodeAll builds a container from a map of field values.
+// Fields that are missing from values are left as 0.
+// Returns an error if values contains an unknown field name, a value
+// that does not fit in its field, or a field that is not present in the result.
+func (l *Layout[U]) EncodeAll(values map[string]uint64) (U, error) {
+	var container U
+	for name, value := range values {
+		i, ok := l.index[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown field %q", name)
+		}
+		bf := l.fields[i].field
+		if !bf.IsValid(value) {
+			return 0, fmt.Errorf("field %q: %w", name, bf.invalidValue(value))
+		}
+		container = bf.Update(container, value)
+	}
+	if err := l.checkPresent(container, maps.Keys(values)); err != nil {
+		return 0, err
+	}
+	return container, nil
+}
@@ -0,0 +1,215 @@
+package bitfield
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// testField is a field added to a layout by mustLayout.
+type testField struct {
+	name        string
+	shift, size uint
+}
+
+// mustLayout returns a layout of the given fields, failing the test if one cannot be added.
+func mustLayout(t *testing.T, fields ...testField) *Layout[uint32] {
+	t.Helper()
+	l := NewLayout[uint32]()
+	for _, f := range fields {
+		if err := l.Add(f.name, f.shift, f.size); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func TestLayout_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		shift   uint
+		size    uint
+		wantErr bool
+	}{
+		{"valid field", "mode", 4, 2, false},
+		{"adjacent field", "enable", 6, 1, false},
+		{"full remaining width", "data", 8, 24, false},
+		{"empty name", "", 0, 1, true},
+		{"duplicate name", "flag", 0, 1, true},
+		{"zero size", "zero", 0, 0, true},
+		{"overlap", "overlap", 5, 2, true},
+		{"exceeds container", "wide", 30, 4, true},
+		{"shift out of range", "far", 32, 1, true},
+	}
+
+	l := NewLayout[uint32]()
+	if err := l.Add("flag", 0, 1); err != nil {
+		t.Fatalf("Add(flag): %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := l.Add(tt.field, tt.shift, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Add(%q, %v, %v): err = %v, want err = %v",
+					tt.field, tt.shift, tt.size, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLayout_Field(t *testing.T) {
+	l := NewLayout[uint32]()
+	if err := l.Add("priority", 1, 3); err != nil {
+		t.Fatal(err)
+	}
+	bf, ok := l.Field("priority")
+	if !ok {
+		t.Fatal("Field(priority) not found")
+	}
+	if bf.Shift != 1 || bf.Size != 3 || bf.Mask != 0xE {
+		t.Errorf("Field(priority) = %+v, want shift=1 size=3 mask=0xE", bf)
+	}
+	if _, ok := l.Field("missing"); ok {
+		t.Error("Field(missing) found, want not found")
+	}
+}
+
+func TestLayout_DecodeAll(t *testing.T) {
+	got := newStatusLayout(t).DecodeAll(0x00002A57)
+	want := map[string]uint64{"active": 1, "priority": 3, "category": 5, "error": 42}
+	if !maps.Equal(got, want) {
+		t.Errorf("DecodeAll(0x00002A57) = %v, want %v", got, want)
+	}
+}
+
+func TestLayout_EncodeAll(t *testing.T) {
+	l := NewLayout[uint64]()
+	if err := l.Add("low", 0, 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Add("high", 32, 32); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]uint64
+		want    uint64
+		wantErr bool
+	}{
+		{"all fields", map[string]uint64{"low": 0xAB, "high": 0xFFFFFFFF}, 0xFFFFFFFF000000AB, false},
+		{"missing field", map[string]uint64{"low": 1}, 1, false},
+		{"empty", nil, 0, false},
+		{"unknown field", map[string]uint64{"mid": 1}, 0, true},
+		{"value too large", map[string]uint64{"low": 0x100}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.EncodeAll(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EncodeAll(%v): err = %v, want err = %v", tt.values, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("EncodeAll(%v) = 0x%X, want 0x%X", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayout_EncodeAllRejectsTruncatedValues(t *testing.T) {
+	// Values wider than the container must not be silently truncated.
+	l := NewLayout[uint32]()
+	if err := l.Add("all", 0, 32); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.EncodeAll(map[string]uint64{"all": 1 << 32}); err == nil {
+		t.Error("EncodeAll(1<<32) succeeded, want error")
+	}
+	got, err := l.EncodeAll(map[string]uint64{"all": 0xFFFFFFFF})
+	if err != nil || got != 0xFFFFFFFF {
+		t.Errorf("EncodeAll(0xFFFFFFFF) = 0x%X, %v, want 0xFFFFFFFF, nil", got, err)
+	}
+}
+
+func TestLayout_SetEnum(t *testing.T) {
+	l := NewLayout[uint32]()
+	if err := l.Add("mode", 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		field   string
+		names   map[uint64]string
+		wantErr bool
+	}{
+		{"valid", "mode", map[uint64]string{0: "Off", 1: "Normal", 3: "LowPower"}, false},
+		{"unknown field", "missing", map[uint64]string{0: "Off"}, true},
+		{"value out of range", "mode", map[uint64]string{4: "Turbo"}, true},
+		{"duplicate name", "mode", map[uint64]string{0: "Off", 1: "Off"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := l.SetEnum(tt.field, tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetEnum(%q, %v): err = %v, want err = %v", tt.field, tt.names, err, tt.wantErr)
+			}
+		})
+	}
+
+	bf, _ := l.Field("mode")
+	if got := bf.DecodeString(3); got != "LowPower" {
+		t.Errorf("DecodeString(3) = %q, want LowPower", got)
+	}
+}
+
+func TestLayout_Names(t *testing.T) {
+	l := newStatusLayout(t)
+	want := []string{"active", "priority", "category", "error"}
+	if got := l.Names(); !slices.Equal(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if got := NewLayout[uint32]().Names(); len(got) != 0 {
+		t.Errorf("Names() of empty layout = %v, want none", got)
+	}
+}
+
+func TestLayout_Validate(t *testing.T) {
+	l := newStatusLayout(t)
+	if got := l.Reserved(); got != 0xFFFF0000 {
+		t.Errorf("Reserved() = 0x%X, want 0xFFFF0000", got)
+	}
+
+	tests := []struct {
+		container uint32
+		wantErr   bool
+		normal    uint32
+	}{
+		{0x0000ABCD, false, 0x0000ABCD},
+		{0x00000000, false, 0x00000000},
+		{0x0001ABCD, true, 0x0000ABCD},
+		{0xFFFFFFFF, true, 0x0000FFFF},
+	}
+	for _, tt := range tests {
+		err := l.Validate(tt.container)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(0x%X) = %v, want error %v", tt.container, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrReservedBits) {
+			t.Errorf("Validate(0x%X) = %v, want ErrReservedBits", tt.container, err)
+		}
+		if got := l.Normalize(tt.container); got != tt.normal {
+			t.Errorf("Normalize(0x%X) = 0x%X, want 0x%X", tt.container, got, tt.normal)
+		}
+		if err := l.Validate(l.Normalize(tt.container)); err != nil {
+			t.Errorf("Validate(Normalize(0x%X)) = %v", tt.container, err)
+		}
+	}
+
+	if got := NewLayout[uint64]().Normalize(0xFF); got != 0 {
+		t.Errorf("Normalize(0xFF) of empty layout = 0x%X, want 0", got)
+	}
+}
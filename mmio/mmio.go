@@ -0,0 +1,112 @@
+// Package mmio binds bitfield layouts to memory-mapped I/O registers.
+//
+// A Register wraps the address of a hardware register and a Layout describing
+// its fields. Every access is a single full-width load or store performed with
+// sync/atomic, so the compiler can neither elide nor split it. This gives the
+// volatile semantics needed for device registers mapped through /dev/mem or on
+// bare-metal targets.
+//
+// The address must stay valid and correctly aligned for as long as the
+// Register is used; Go pointers obtained from mapped memory (for example with
+// syscall.Mmap) satisfy this. Addresses of ordinary Go variables may be used in
+// tests.
+package mmio
+
+import (
+	"fmt"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Register is a memory-mapped register of type U with a field layout.
+type Register[U uint32 | uint64] struct {
+	addr   unsafe.Pointer
+	layout *bitfield.Layout[U]
+}
+
+// New binds layout to the register at addr.
+// Returns an error if addr is nil or not aligned to the register size.
+func New[U uint32 | uint64](addr unsafe.Pointer, layout *bitfield.Layout[U]) (*Register[U], error) {
+	if addr == nil {
+		return nil, fmt.Errorf("mmio: nil register address")
+	}
+	if size := unsafe.Sizeof(U(0)); uintptr(addr)%size != 0 {
+		return nil, fmt.Errorf("mmio: address %p not aligned to %d bytes", addr, size)
+	}
+	return &Register[U]{addr: addr, layout: layout}, nil
+}
+
+// Layout returns the field layout of the register.
+func (r *Register[U]) Layout() *bitfield.Layout[U] {
+	return r.layout
+}
+
+// Load performs a single load of the whole register.
+func (r *Register[U]) Load() U {
+	switch p := any((*U)(r.addr)).(type) {
+	case *uint32:
+		return U(atomic.LoadUint32(p))
+	case *uint64:
+		return U(atomic.LoadUint64(p))
+	}
+	panic("unreachable")
+}
+
+// Store performs a single store of the whole register.
+func (r *Register[U]) Store(value U) {
+	switch p := any((*U)(r.addr)).(type) {
+	case *uint32:
+		atomic.StoreUint32(p, uint32(value))
+	case *uint64:
+		atomic.StoreUint64(p, uint64(value))
+	}
+}
+
+// Read loads the register and returns the value of one field.
+// Returns an error if the layout has no such field.
+func (r *Register[U]) Read(field string) (uint64, error) {
+	bf, err := r.field(field)
+	if err != nil {
+		return 0, err
+	}
+	return bf.Decode(r.Load()), nil
+}
+
+// Write stores value into one field with a read-modify-write cycle,
+// preserving all other fields.
+// The cycle is not atomic with respect to other writers of the register;
+// callers sharing a register must serialize access.
+// Returns an error if the layout has no such field or the value does not fit.
+func (r *Register[U]) Write(field string, value uint64) error {
+	bf, err := r.field(field)
+	if err != nil {
+		return err
+	}
+	if !bf.IsValid(value) {
+		return fmt.Errorf("mmio: value %v out of range for field %q, max %v", value, field, bf.Max())
+	}
+	r.Store(bf.Update(r.Load(), value))
+	return nil
+}
+
+// Modify applies fn to the current register value and stores the result,
+// allowing several fields to be changed with a single store.
+func (r *Register[U]) Modify(fn func(U) U) {
+	r.Store(fn(r.Load()))
+}
+
+// ReadAll loads the register once and decodes every field.
+func (r *Register[U]) ReadAll() map[string]uint64 {
+	return r.layout.DecodeAll(r.Load())
+}
+
+// field looks up a field of the layout.
+func (r *Register[U]) field(name string) (bitfield.BitField[uint64, U], error) {
+	bf, ok := r.layout.Field(name)
+	if !ok {
+		return bf, fmt.Errorf("mmio: unknown field %q", name)
+	}
+	return bf, nil
+}
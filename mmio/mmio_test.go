@@ -0,0 +1,92 @@
+package mmio
+
+import (
+	"maps"
+	"testing"
+	"unsafe"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+func newLayout[U uint32 | uint64](t *testing.T) *bitfield.Layout[U] {
+	t.Helper()
+	l := bitfield.NewLayout[U]()
+	if err := l.Add("enable", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Add("mode", 4, 3); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestRegister_ReadWrite32(t *testing.T) {
+	hw := uint32(0xFFFF0000)
+	reg, err := New(unsafe.Pointer(&hw), newLayout[uint32](t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reg.Write("mode", 5); err != nil {
+		t.Fatalf("Write(mode): %v", err)
+	}
+	if err := reg.Write("enable", 1); err != nil {
+		t.Fatalf("Write(enable): %v", err)
+	}
+	if hw != 0xFFFF0051 {
+		t.Errorf("register memory = 0x%08X, want 0xFFFF0051", hw)
+	}
+
+	hw = 0x00000030 // Simulate a hardware-side change
+	if got, err := reg.Read("mode"); err != nil || got != 3 {
+		t.Errorf("Read(mode) = %v, %v, want 3, nil", got, err)
+	}
+	want := map[string]uint64{"enable": 0, "mode": 3}
+	if got := reg.ReadAll(); !maps.Equal(got, want) {
+		t.Errorf("ReadAll() = %v, want %v", got, want)
+	}
+}
+
+func TestRegister_Modify64(t *testing.T) {
+	hw := uint64(0x1)
+	reg, err := New(unsafe.Pointer(&hw), newLayout[uint64](t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mode, _ := reg.Layout().Field("mode")
+	reg.Modify(func(v uint64) uint64 { return mode.Update(v, 7) | 1<<63 })
+	if hw != 0x8000000000000071 {
+		t.Errorf("register memory = 0x%X, want 0x8000000000000071", hw)
+	}
+	if reg.Load() != hw {
+		t.Errorf("Load() = 0x%X, want 0x%X", reg.Load(), hw)
+	}
+}
+
+func TestRegister_Errors(t *testing.T) {
+	hw := [2]uint64{}
+	if _, err := New[uint32](nil, newLayout[uint32](t)); err == nil {
+		t.Error("New(nil) succeeded, want error")
+	}
+	misaligned := unsafe.Add(unsafe.Pointer(&hw[0]), 2)
+	if _, err := New(misaligned, newLayout[uint32](t)); err == nil {
+		t.Error("New with misaligned address succeeded, want error")
+	}
+
+	reg, err := New(unsafe.Pointer(&hw[0]), newLayout[uint64](t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Write("mode", 8); err == nil {
+		t.Error("Write(mode, 8) succeeded, want error")
+	}
+	if err := reg.Write("missing", 0); err == nil {
+		t.Error("Write(missing) succeeded, want error")
+	}
+	if _, err := reg.Read("missing"); err == nil {
+		t.Error("Read(missing) succeeded, want error")
+	}
+	if hw[0] != 0 {
+		t.Errorf("failed writes modified the register: 0x%X", hw[0])
+	}
+}
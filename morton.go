@@ -0,0 +1,78 @@
+package bitfield
+
+import "fmt"
+
+// spread2 spreads the low 32 bits of x to the even bit positions of the result.
+func spread2(x uint64) uint64 {
+	x &= 0x00000000FFFFFFFF
+	x = (x | x<<16) & 0x0000FFFF0000FFFF
+	x = (x | x<<8) & 0x00FF00FF00FF00FF
+	x = (x | x<<4) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
+
+// compact2 gathers the even bits of x into the low 32 bits, the inverse of spread2.
+func compact2(x uint64) uint64 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x>>4) & 0x00FF00FF00FF00FF
+	x = (x | x>>8) & 0x0000FFFF0000FFFF
+	x = (x | x>>16) & 0x00000000FFFFFFFF
+	return x
+}
+
+// spread3 spreads the low 21 bits of x to every third bit position of the result.
+func spread3(x uint64) uint64 {
+	x &= 0x1FFFFF
+	x = (x | x<<32) & 0x001F00000000FFFF
+	x = (x | x<<16) & 0x001F0000FF0000FF
+	x = (x | x<<8) & 0x100F00F00F00F00F
+	x = (x | x<<4) & 0x10C30C30C30C30C3
+	x = (x | x<<2) & 0x1249249249249249
+	return x
+}
+
+// compact3 gathers every third bit of x into the low 21 bits, the inverse of spread3.
+func compact3(x uint64) uint64 {
+	x &= 0x1249249249249249
+	x = (x | x>>2) & 0x10C30C30C30C30C3
+	x = (x | x>>4) & 0x100F00F00F00F00F
+	x = (x | x>>8) & 0x001F0000FF0000FF
+	x = (x | x>>16) & 0x001F00000000FFFF
+	x = (x | x>>32) & 0x1FFFFF
+	return x
+}
+
+// MaxMorton3 is the largest coordinate Interleave3 accepts: 21 bits per axis.
+const MaxMorton3 = 1<<21 - 1
+
+// Interleave2 returns the Z-order (Morton) key of a 2D point: bit i of x goes to
+// bit 2i of the key and bit i of y to bit 2i+1. Points close in space tend to
+// have close keys, which makes the key useful for spatial indexes and tiling.
+func Interleave2(x, y uint32) uint64 {
+	return spread2(uint64(x)) | spread2(uint64(y))<<1
+}
+
+// Deinterleave2 returns the coordinates of a 2D Z-order key, the inverse of Interleave2.
+func Deinterleave2(key uint64) (x, y uint32) {
+	return uint32(compact2(key)), uint32(compact2(key >> 1))
+}
+
+// Interleave3 returns the Z-order (Morton) key of a 3D point: bit i of x, y and z
+// goes to bits 3i, 3i+1 and 3i+2 of the key.
+// Panics if a coordinate exceeds MaxMorton3.
+func Interleave3(x, y, z uint32) uint64 {
+	if x > MaxMorton3 || y > MaxMorton3 || z > MaxMorton3 {
+		panic(fmt.Sprintf("coordinates (%d, %d, %d) out of range, max %d", x, y, z, MaxMorton3))
+	}
+	return spread3(uint64(x)) | spread3(uint64(y))<<1 | spread3(uint64(z))<<2
+}
+
+// Deinterleave3 returns the coordinates of a 3D Z-order key, the inverse of Interleave3.
+// Bit 63 of the key is ignored.
+func Deinterleave3(key uint64) (x, y, z uint32) {
+	return uint32(compact3(key)), uint32(compact3(key >> 1)), uint32(compact3(key >> 2))
+}
@@ -0,0 +1,74 @@
+package bitfield
+
+import "testing"
+
+func TestInterleave2(t *testing.T) {
+	tests := []struct {
+		x, y uint32
+		want uint64
+	}{
+		{0, 0, 0},
+		{1, 0, 0x1},
+		{0, 1, 0x2},
+		{3, 3, 0xF},
+		{0x5, 0x2, 0x19},
+		{0xFFFFFFFF, 0, 0x5555555555555555},
+		{0, 0xFFFFFFFF, 0xAAAAAAAAAAAAAAAA},
+		{0x12345678, 0x9ABCDEF0, 0x838C8FB0B3BCBF40},
+	}
+
+	for _, tt := range tests {
+		got := Interleave2(tt.x, tt.y)
+		if got != tt.want {
+			t.Errorf("Interleave2(0x%X, 0x%X) = 0x%X, want 0x%X", tt.x, tt.y, got, tt.want)
+		}
+		if x, y := Deinterleave2(got); x != tt.x || y != tt.y {
+			t.Errorf("Deinterleave2(0x%X) = 0x%X, 0x%X, want 0x%X, 0x%X", got, x, y, tt.x, tt.y)
+		}
+	}
+}
+
+func TestInterleave3(t *testing.T) {
+	tests := []struct {
+		x, y, z uint32
+		want    uint64
+	}{
+		{0, 0, 0, 0},
+		{1, 0, 0, 0x1},
+		{0, 1, 0, 0x2},
+		{0, 0, 1, 0x4},
+		{3, 5, 6, 0x1AB},
+		{MaxMorton3, 0, 0, 0x1249249249249249},
+		{MaxMorton3, MaxMorton3, MaxMorton3, 0x7FFFFFFFFFFFFFFF},
+	}
+
+	for _, tt := range tests {
+		got := Interleave3(tt.x, tt.y, tt.z)
+		if got != tt.want {
+			t.Errorf("Interleave3(%d, %d, %d) = 0x%X, want 0x%X", tt.x, tt.y, tt.z, got, tt.want)
+		}
+		if x, y, z := Deinterleave3(got); x != tt.x || y != tt.y || z != tt.z {
+			t.Errorf("Deinterleave3(0x%X) = %d, %d, %d, want %d, %d, %d", got, x, y, z, tt.x, tt.y, tt.z)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Interleave3 with 22-bit coordinate did not panic")
+		}
+	}()
+	Interleave3(1<<21, 0, 0)
+}
+
+func TestMortonRoundTrip(t *testing.T) {
+	for i := uint64(0); i < 1000; i++ {
+		x, y := uint32(i*2654435761), uint32(i*40503)
+		if gx, gy := Deinterleave2(Interleave2(x, y)); gx != x || gy != y {
+			t.Fatalf("2D round trip of (0x%X, 0x%X) = (0x%X, 0x%X)", x, y, gx, gy)
+		}
+		x, y, z := x&MaxMorton3, y&MaxMorton3, uint32(i*7919)&MaxMorton3
+		if gx, gy, gz := Deinterleave3(Interleave3(x, y, z)); gx != x || gy != y || gz != z {
+			t.Fatalf("3D round trip of (%d, %d, %d) = (%d, %d, %d)", x, y, z, gx, gy, gz)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package bitfield
+
+// Decode2 extracts two fields from a container in one call:
+//
+//	mode, count := bitfield.Decode2(status, modeField, countField)
+func Decode2[T1, T2 Unsigned, U storageType](container U, f1 BitField[T1, U], f2 BitField[T2, U]) (T1, T2) {
+	return f1.Decode(container), f2.Decode(container)
+}
+
+// Decode3 extracts three fields from a container in one call.
+func Decode3[T1, T2, T3 Unsigned, U storageType](container U, f1 BitField[T1, U], f2 BitField[T2, U], f3 BitField[T3, U]) (T1, T2, T3) {
+	return f1.Decode(container), f2.Decode(container), f3.Decode(container)
+}
+
+// Decode4 extracts four fields from a container in one call.
+func Decode4[T1, T2, T3, T4 Unsigned, U storageType](container U, f1 BitField[T1, U], f2 BitField[T2, U], f3 BitField[T3, U], f4 BitField[T4, U]) (T1, T2, T3, T4) {
+	return f1.Decode(container), f2.Decode(container), f3.Decode(container), f4.Decode(container)
+}
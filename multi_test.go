@@ -0,0 +1,32 @@
+package bitfield
+
+import "testing"
+
+func TestDecodeN(t *testing.T) {
+	active := New[uint8, uint32](0, 1)
+	priority := New[Priority, uint32](1, 3)
+	category := New[uint16, uint32](4, 4)
+	errField := New[uint64, uint32](8, 8)
+	container := uint32(0xAB95)
+
+	if a, p := Decode2(container, active, priority); a != 1 || p != High {
+		t.Errorf("Decode2() = %d, %d, want 1, %d", a, p, High)
+	}
+	if a, p, c := Decode3(container, active, priority, category); a != 1 || p != High || c != 9 {
+		t.Errorf("Decode3() = %d, %d, %d, want 1, %d, 9", a, p, c, High)
+	}
+	if a, p, c, e := Decode4(container, active, priority, category, errField); a != 1 || p != High || c != 9 || e != 0xAB {
+		t.Errorf("Decode4() = %d, %d, %d, %d, want 1, %d, 9, 0xAB", a, p, c, e, High)
+	}
+}
+
+func BenchmarkDecode4(b *testing.B) {
+	f1, f2 := New[uint8, uint64](0, 8), New[uint16, uint64](8, 16)
+	f3, f4 := New[uint8, uint64](24, 4), New[uint32, uint64](32, 32)
+	var sink uint64
+	for i := range b.N {
+		v1, v2, v3, v4 := Decode4(uint64(i)*0x9E3779B97F4A7C15, f1, f2, f3, f4)
+		sink += uint64(v1) + uint64(v2) + uint64(v3) + uint64(v4)
+	}
+	_ = sink
+}
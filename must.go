@@ -0,0 +1,23 @@
+package bitfield
+
+// Must returns v, panicking with err if it is not nil. It wraps the Safe
+// constructors for package-level declarations, where a bad field definition
+// is a programming error that should stop the program at init time:
+//
+//	var Mode = bitfield.Must(bitfield.SafeRange[uint8, uint32](5, 3))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustSafe is like Safe but panics if the parameters or options are invalid.
+func MustSafe[T Unsigned, U storageType](shift, size uint, opts ...Option) BitField[T, U] {
+	return Must(Safe[T, U](shift, size, opts...))
+}
+
+// MustNext is like SafeNext but panics if the new field would exceed the bounds of type T.
+func MustNext[T Unsigned, U storageType, Old Unsigned](bf BitField[Old, U], size uint) BitField[T, U] {
+	return Must(SafeNext[T, U](bf, size))
+}
@@ -0,0 +1,41 @@
+package bitfield
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMust(t *testing.T) {
+	bf := MustSafe[uint8, uint32](4, 3)
+	if bf.Shift != 4 || bf.Size != 3 {
+		t.Errorf("MustSafe(4, 3) = %+v", bf)
+	}
+	next := MustNext[uint8](bf, 1)
+	if next.Shift != 7 || next.Size != 1 {
+		t.Errorf("MustNext(bf, 1) = %+v", next)
+	}
+	if r := Must(SafeRange[uint8, uint32](5, 3)); r.Shift != 3 || r.Size != 3 {
+		t.Errorf("Must(SafeRange(5, 3)) = %+v", r)
+	}
+
+	tests := []struct {
+		name string
+		fn   func()
+		want error
+	}{
+		{"MustSafe", func() { MustSafe[uint8, uint32](8, 1) }, ErrShiftOutOfRange},
+		{"MustNext", func() { MustNext[uint8](bf, 2) }, ErrFieldOverflow},
+		{"Must", func() { Must(SafeFlag[uint32](40)) }, ErrShiftOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("panic value = %v, want %v", err, tt.want)
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package bitfield
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FieldSpec describes a field to be placed by Optimize.
+type FieldSpec struct {
+	Name  string
+	Size  uint
+	Align uint // The shift of the field must be a multiple of Align; 0 and 1 allow any shift
+}
+
+// Placement is the position chosen by Optimize for a field.
+type Placement struct {
+	Name        string
+	Shift, Size uint
+}
+
+// Packing is the result of Optimize.
+type Packing struct {
+	Fields []Placement // Placed fields in ascending order of shift
+	Width  uint        // Number of bits up to the end of the highest field
+	Gaps   uint        // Number of unused bits below Width
+}
+
+// Optimize assigns shifts to fields so that they fit in as few bits as possible,
+// within a container of width bits, for designing new packed formats.
+// Fields are placed from the most to the least aligned and, for equal
+// alignments, from the largest to the smallest, each at the lowest shift that
+// respects its alignment and does not overlap a field placed before, so that
+// small fields fill the gaps left by aligned ones.
+// Without alignment, or when every alignment is a power of two and every
+// field's size is a multiple of its alignment, the result has no gaps.
+// Otherwise it is a good placement but not necessarily the narrowest.
+// Returns an error if width is not between 1 and 64, a name is empty or used
+// twice, a size is 0, or the fields do not fit in width bits.
+func Optimize(specs []FieldSpec, width uint) (Packing, error) {
+	if width == 0 || width > 64 {
+		return Packing{}, fmt.Errorf("invalid container width %d, want 1 to 64", width)
+	}
+	seen := make(map[string]bool, len(specs))
+	for _, s := range specs {
+		if s.Name == "" {
+			return Packing{}, fmt.Errorf("field name must not be empty")
+		}
+		if seen[s.Name] {
+			return Packing{}, fmt.Errorf("duplicate field %q", s.Name)
+		}
+		seen[s.Name] = true
+		if s.Size == 0 || s.Size > width {
+			return Packing{}, fmt.Errorf("field %q: %w", s.Name, ErrSizeOutOfRange)
+		}
+	}
+
+	order := slices.Clone(specs)
+	slices.SortStableFunc(order, func(a, b FieldSpec) int {
+		if c := cmp.Compare(max(b.Align, 1), max(a.Align, 1)); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Size, a.Size)
+	})
+	var p Packing
+	var used uint64
+	for _, s := range order {
+		step := max(s.Align, 1)
+		placed := false
+		for shift := uint(0); shift+s.Size <= width; shift += step {
+			if mask := lowBits(s.Size) << shift; used&mask == 0 {
+				used |= mask
+				p.Fields = append(p.Fields, Placement{Name: s.Name, Shift: shift, Size: s.Size})
+				p.Width = max(p.Width, shift+s.Size)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			return Packing{}, fmt.Errorf("field %q of %d bits does not fit in %d bits", s.Name, s.Size, width)
+		}
+	}
+	slices.SortFunc(p.Fields, func(a, b Placement) int {
+		return cmp.Compare(a.Shift, b.Shift)
+	})
+	for _, f := range p.Fields {
+		p.Gaps += f.Size
+	}
+	p.Gaps = p.Width - p.Gaps
+	return p, nil
+}
+
+// OptimizeLayout places fields with Optimize within a container of type U and
+// returns a Layout with the fields added in the order of specs, along with the
+// chosen placement.
+func OptimizeLayout[U storageType](specs ...FieldSpec) (*Layout[U], Packing, error) {
+	p, err := Optimize(specs, unsignedSizeOf[U]())
+	if err != nil {
+		return nil, Packing{}, err
+	}
+	shifts := make(map[string]uint, len(p.Fields))
+	for _, f := range p.Fields {
+		shifts[f.Name] = f.Shift
+	}
+	l := NewLayout[U]()
+	for _, s := range specs {
+		if err := l.Add(s.Name, shifts[s.Name], s.Size); err != nil {
+			return nil, Packing{}, err
+		}
+	}
+	return l, p, nil
+}
+
+// String reports the placement with one line per field, from the lowest shift:
+//
+//	width 13 bits, 0 unused
+//	  shift  size  field
+//	      0     8  id
+//	      8     4  kind
+//	     12     1  valid
+func (p Packing) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "width %d bits, %d unused\n", p.Width, p.Gaps)
+	b.WriteString("  shift  size  field\n")
+	for _, f := range p.Fields {
+		fmt.Fprintf(&b, "  %5d  %4d  %s\n", f.Shift, f.Size, f.Name)
+	}
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOptimize(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []FieldSpec
+		width uint
+		want  []Placement
+		total uint
+		gaps  uint
+	}{
+		{
+			name:  "largest first",
+			specs: []FieldSpec{{Name: "valid", Size: 1}, {Name: "id", Size: 8}, {Name: "kind", Size: 4}},
+			width: 32,
+			want:  []Placement{{"id", 0, 8}, {"kind", 8, 4}, {"valid", 12, 1}},
+			total: 13,
+		},
+		{
+			name: "small fields fill gaps",
+			specs: []FieldSpec{
+				{Name: "flag", Size: 1},
+				{Name: "a", Size: 3, Align: 4},
+				{Name: "b", Size: 3, Align: 4},
+				{Name: "c", Size: 1},
+			},
+			width: 16,
+			want:  []Placement{{"a", 0, 3}, {"flag", 3, 1}, {"b", 4, 3}, {"c", 7, 1}},
+			total: 8,
+		},
+		{
+			name:  "aligned with gap",
+			specs: []FieldSpec{{Name: "lo", Size: 3}, {Name: "word", Size: 16, Align: 16}},
+			width: 32,
+			want:  []Placement{{"word", 0, 16}, {"lo", 16, 3}},
+			total: 19,
+		},
+		{
+			name:  "unavoidable gap",
+			specs: []FieldSpec{{Name: "x", Size: 2, Align: 8}, {Name: "y", Size: 2, Align: 8}},
+			width: 16,
+			want:  []Placement{{"x", 0, 2}, {"y", 8, 2}},
+			total: 10,
+			gaps:  6,
+		},
+		{
+			name:  "full width",
+			specs: []FieldSpec{{Name: "hi", Size: 32, Align: 32}, {Name: "lo", Size: 32}},
+			width: 64,
+			want:  []Placement{{"hi", 0, 32}, {"lo", 32, 32}},
+			total: 64,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Optimize(tt.specs, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got.Fields, tt.want) || got.Width != tt.total || got.Gaps != tt.gaps {
+				t.Errorf("Optimize() = %v, width %d, gaps %d, want %v, width %d, gaps %d",
+					got.Fields, got.Width, got.Gaps, tt.want, tt.total, tt.gaps)
+			}
+		})
+	}
+}
+
+func TestOptimize_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		specs []FieldSpec
+		width uint
+	}{
+		{"zero width", []FieldSpec{{Name: "a", Size: 1}}, 0},
+		{"too wide", []FieldSpec{{Name: "a", Size: 1}}, 65},
+		{"empty name", []FieldSpec{{Size: 1}}, 8},
+		{"duplicate name", []FieldSpec{{Name: "a", Size: 1}, {Name: "a", Size: 2}}, 8},
+		{"zero size", []FieldSpec{{Name: "a"}}, 8},
+		{"does not fit", []FieldSpec{{Name: "a", Size: 5}, {Name: "b", Size: 4}}, 8},
+		{"alignment does not fit", []FieldSpec{{Name: "a", Size: 8}, {Name: "b", Size: 2, Align: 4}}, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, err := Optimize(tt.specs, tt.width); err == nil {
+				t.Errorf("Optimize() = %v, want error", p)
+			}
+		})
+	}
+}
+
+func TestOptimizeLayout(t *testing.T) {
+	l, p, err := OptimizeLayout[uint32](
+		FieldSpec{Name: "valid", Size: 1},
+		FieldSpec{Name: "id", Size: 8},
+		FieldSpec{Name: "kind", Size: 4},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Names(); !slices.Equal(got, []string{"valid", "id", "kind"}) {
+		t.Errorf("Names() = %v", got)
+	}
+	if bf, _ := l.Field("valid"); bf.Shift != 12 {
+		t.Errorf("valid shift = %d, want 12", bf.Shift)
+	}
+	want := `width 13 bits, 0 unused
+  shift  size  field
+      0     8  id
+      8     4  kind
+     12     1  valid
+`
+	if got := p.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if _, _, err := OptimizeLayout[uint32](FieldSpec{Name: "a", Size: 33}); err == nil {
+		t.Error("OptimizeLayout() with a field wider than the container succeeded")
+	}
+}
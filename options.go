@@ -0,0 +1,106 @@
+package bitfield
+
+import "fmt"
+
+// Option sets metadata on a field created with New, Safe, MustSafe or Layout.Add.
+// The name is used by BitField.String. Descriptions and units are shown by
+// Layout.Diagram, Layout.Diff and the documentation generated by the codegen
+// package; other renderings, such as JSON and LogValue, show values only.
+type Option func(*fieldOptions)
+
+// fieldOptions collects the metadata set by options.
+type fieldOptions struct {
+	name        string
+	description string
+	unit        string
+	enum        map[uint64]string // Nil unless WithEnum is used
+}
+
+// WithName sets the name of the field.
+// Layout.Add names the field after its name in the layout instead.
+func WithName(name string) Option {
+	return func(o *fieldOptions) { o.name = name }
+}
+
+// WithDescription sets a human-readable description of the field.
+func WithDescription(description string) Option {
+	return func(o *fieldOptions) { o.description = description }
+}
+
+// WithUnit sets the unit of the field's values, such as "ms" or "mV".
+func WithUnit(unit string) Option {
+	return func(o *fieldOptions) { o.unit = unit }
+}
+
+// WithEnum registers names for the values of the field, as the WithEnum method does.
+func WithEnum[T Unsigned](names map[T]string) Option {
+	enum := make(map[uint64]string, len(names))
+	for value, name := range names {
+		enum[uint64(value)] = name
+	}
+	return func(o *fieldOptions) { o.enum = enum }
+}
+
+// withOptions returns a copy of the field with the metadata of opts.
+// Returns an error if an enum value does not fit in the field or an enum name
+// is used for more than one value.
+func (bf BitField[T, U]) withOptions(opts []Option) (BitField[T, U], error) {
+	if len(opts) == 0 {
+		return bf, nil
+	}
+	var o fieldOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.enum != nil {
+		if err := checkEnum(bf, o.enum); err != nil {
+			return bf, err
+		}
+		names := make(map[T]string, len(o.enum))
+		for value, name := range o.enum {
+			names[T(value)] = name
+		}
+		bf = bf.WithEnum(names)
+	}
+	meta := bf.meta.clone()
+	meta.name = o.name
+	meta.description = o.description
+	meta.unit = o.unit
+	bf.meta = meta
+	return bf, nil
+}
+
+// FieldName returns the name of the field, set with WithName or by Layout.Add,
+// or "" if it has none.
+func (bf BitField[T, U]) FieldName() string {
+	if bf.meta == nil {
+		return ""
+	}
+	return bf.meta.name
+}
+
+// Description returns the description of the field set with WithDescription.
+func (bf BitField[T, U]) Description() string {
+	if bf.meta == nil {
+		return ""
+	}
+	return bf.meta.description
+}
+
+// Unit returns the unit of the field set with WithUnit.
+func (bf BitField[T, U]) Unit() string {
+	if bf.meta == nil {
+		return ""
+	}
+	return bf.meta.unit
+}
+
+// String returns the name of the field followed by its bits, as in "mode[5:4]",
+// or the bits alone for fields without a name.
+func (bf BitField[T, U]) String() string {
+	bits := fmt.Sprintf("[%d:%d]", bf.Shift+bf.Size-1, bf.Shift)
+	if bf.Size == 1 {
+		bits = fmt.Sprintf("[%d]", bf.Shift)
+	}
+	return bf.FieldName() + bits
+}
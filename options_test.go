@@ -0,0 +1,147 @@
+package bitfield
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestNew_Options(t *testing.T) {
+	bf := New[uint8, uint32](4, 3,
+		WithName("mode"),
+		WithDescription("Operating mode"),
+		WithUnit("steps"),
+		WithEnum(map[uint8]string{0: "off", 5: "turbo"}),
+	)
+	if got := bf.FieldName(); got != "mode" {
+		t.Errorf("FieldName() = %q, want mode", got)
+	}
+	if got := bf.Description(); got != "Operating mode" {
+		t.Errorf("Description() = %q", got)
+	}
+	if got := bf.Unit(); got != "steps" {
+		t.Errorf("Unit() = %q", got)
+	}
+	if got := bf.Enum(); !maps.Equal(got, map[uint8]string{0: "off", 5: "turbo"}) {
+		t.Errorf("Enum() = %v", got)
+	}
+	if got := bf.DecodeString(0x50); got != "turbo" {
+		t.Errorf("DecodeString(0x50) = %q, want turbo", got)
+	}
+	if got := bf.WithAllowed(0, 5).FieldName(); got != "mode" {
+		t.Errorf("FieldName() after WithAllowed = %q, want mode", got)
+	}
+
+	plain := New[uint8, uint32](4, 3)
+	if plain.FieldName() != "" || plain.Description() != "" || plain.Unit() != "" {
+		t.Errorf("field without options has metadata")
+	}
+	if plain != New[uint8, uint32](4, 3) {
+		t.Errorf("fields without options are not comparable")
+	}
+}
+
+func TestSafe_Options(t *testing.T) {
+	bf, err := Safe[uint16, uint32](0, 4, WithName("count"), WithEnum(map[uint16]string{15: "max"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bf.FieldName() != "count" || bf.ValueString(15) != "max" {
+		t.Errorf("Safe() = %v with enum %v", bf, bf.Enum())
+	}
+
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"enum value out of range", WithEnum(map[uint16]string{16: "big"})},
+		{"duplicate enum name", WithEnum(map[uint16]string{1: "x", 2: "x"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if bf, err := Safe[uint16, uint32](0, 4, tt.opt); err == nil {
+				t.Errorf("Safe() = %v, want error", bf)
+			}
+			defer func() {
+				if recover() == nil {
+					t.Error("New() did not panic")
+				}
+			}()
+			New[uint16, uint32](0, 4, tt.opt)
+		})
+	}
+}
+
+func TestLayout_AddOptions(t *testing.T) {
+	l := NewLayout[uint32]()
+	if err := l.Add("timeout", 0, 8, WithName("ignored"), WithUnit("ms"), WithEnum(map[uint64]string{0: "never"})); err != nil {
+		t.Fatal(err)
+	}
+	bf, _ := l.Field("timeout")
+	if bf.FieldName() != "timeout" || bf.Unit() != "ms" || bf.ValueString(0) != "never" {
+		t.Errorf("Field() = %v, unit %q, enum %v", bf, bf.Unit(), bf.Enum())
+	}
+	if err := l.Add("mode", 8, 2, WithEnum(map[uint64]string{4: "bad"})); err == nil {
+		t.Error("Add() with an invalid enum succeeded")
+	}
+	if _, ok := l.Field("mode"); ok {
+		t.Error("Add() with an invalid option added the field")
+	}
+}
+
+func TestBitField_String(t *testing.T) {
+	tests := []struct {
+		bf   BitField[uint8, uint32]
+		want string
+	}{
+		{New[uint8, uint32](4, 2, WithName("mode")), "mode[5:4]"},
+		{New[uint8, uint32](4, 2), "[5:4]"},
+		{New[uint8, uint32](7, 1, WithName("ready")), "ready[7]"},
+	}
+	for _, tt := range tests {
+		if got := tt.bf.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestMustSafe_Options(t *testing.T) {
+	bf := MustSafe[uint8, uint32](0, 4, WithName("level"), WithEnum(map[uint8]string{1: "low"}))
+	if bf.FieldName() != "level" || bf.ValueString(1) != "low" {
+		t.Errorf("MustSafe() = %v with enum %v", bf, bf.Enum())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("MustSafe() with an invalid enum did not panic")
+		}
+	}()
+	MustSafe[uint8, uint32](0, 4, WithEnum(map[uint64]string{16: "big"}))
+}
+
+func TestLayout_OptionsRendering(t *testing.T) {
+	l := NewLayout[uint32]()
+	for _, err := range []error{
+		l.Add("timeout", 0, 8, WithDescription("Receive timeout"), WithUnit("ms")),
+		l.Add("retries", 8, 4, WithDescription("Retry count")),
+		l.Add("gain", 12, 4, WithUnit("dB")),
+		l.Add("spare", 16, 4),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	diagram := l.Diagram()
+	for _, line := range []string{"timeout: Receive timeout, in ms\n", "retries: Retry count\n", "gain: in dB\n"} {
+		if !strings.Contains(diagram, line) {
+			t.Errorf("Diagram() is missing %q:\n%s", line, diagram)
+		}
+	}
+	if strings.Contains(diagram, "spare:") {
+		t.Errorf("Diagram() lists a field without metadata:\n%s", diagram)
+	}
+
+	changes := l.Diff(0x0A, 0x14)
+	if len(changes) != 1 || changes[0].String() != "timeout: 0xa -> 0x14 (ms)" {
+		t.Errorf("Diff() = %v, want the unit of timeout", changes)
+	}
+}
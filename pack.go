@@ -0,0 +1,150 @@
+package bitfield
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// structField maps a tagged Go struct field to a bit field of the container.
+type structField struct {
+	index int    // Index of the Go field within the struct
+	name  string // Name of the Go field, used as the layout field name
+}
+
+// Pack packs the tagged fields of a struct into a container of type U.
+// v must be a struct or a pointer to a struct. Fields are selected with struct tags
+// of the form `bitfield:"shift=4,size=3"`; untagged fields and fields tagged
+// `bitfield:"-"` are ignored. Tagged fields must be unsigned integers or bools,
+// and bools must have size 1.
+// Returns an error if the tags are invalid, fields overlap, or a value does not
+// fit in its field.
+func Pack[U storageType](v any) (U, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return 0, fmt.Errorf("cannot pack nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("cannot pack %v, want struct", rv.Type())
+	}
+	layout, fields, err := structLayout[U](rv.Type())
+	if err != nil {
+		return 0, err
+	}
+	return packStruct(layout, fields, rv)
+}
+
+// packStruct encodes the fields of struct value rv into a container of layout.
+func packStruct[U storageType](layout *Layout[U], fields []structField, rv reflect.Value) (U, error) {
+	values := make(map[string]uint64, len(fields))
+	for _, sf := range fields {
+		fv := rv.Field(sf.index)
+		if fv.Kind() == reflect.Bool {
+			if fv.Bool() {
+				values[sf.name] = 1
+			}
+			continue
+		}
+		values[sf.name] = fv.Uint()
+	}
+	return layout.EncodeAll(values)
+}
+
+// Unpack decodes a container into the tagged fields of the struct pointed to by v.
+// The tags follow the same rules as for Pack. Untagged fields are left untouched.
+// Returns an error if v is not a non-nil pointer to a struct or if the tags are invalid.
+func Unpack[U storageType](container U, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unpack into %T, want non-nil pointer to struct", v)
+	}
+	rv = rv.Elem()
+	layout, fields, err := structLayout[U](rv.Type())
+	if err != nil {
+		return err
+	}
+	unpackStruct(layout, fields, container, rv)
+	return nil
+}
+
+// unpackStruct decodes a container of layout into the fields of struct value rv.
+func unpackStruct[U storageType](layout *Layout[U], fields []structField, container U, rv reflect.Value) {
+	values := layout.DecodeAll(container)
+	for _, sf := range fields {
+		fv := rv.Field(sf.index)
+		if fv.Kind() == reflect.Bool {
+			fv.SetBool(values[sf.name] != 0)
+			continue
+		}
+		fv.SetUint(values[sf.name])
+	}
+}
+
+// structLayout builds a Layout from the bitfield tags of struct type t.
+func structLayout[U storageType](t reflect.Type) (*Layout[U], []structField, error) {
+	layout := NewLayout[U]()
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("bitfield")
+		if !ok || tag == "-" {
+			continue
+		}
+		if !f.IsExported() {
+			return nil, nil, fmt.Errorf("field %s: tagged field must be exported", f.Name)
+		}
+		shift, size, err := parseFieldTag(tag)
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		switch f.Type.Kind() {
+		case reflect.Bool:
+			if size != 1 {
+				return nil, nil, fmt.Errorf("field %s: bool field must have size 1", f.Name)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if size > uint(f.Type.Bits()) {
+				return nil, nil, fmt.Errorf("field %s: size %d exceeds %v", f.Name, size, f.Type)
+			}
+		default:
+			return nil, nil, fmt.Errorf("field %s: unsupported type %v", f.Name, f.Type)
+		}
+		if err := layout.Add(f.Name, shift, size); err != nil {
+			return nil, nil, err
+		}
+		fields = append(fields, structField{index: i, name: f.Name})
+	}
+	return layout, fields, nil
+}
+
+// parseFieldTag parses a tag of the form "shift=4,size=3".
+// Both keys are required.
+func parseFieldTag(tag string) (shift, size uint, err error) {
+	var hasShift, hasSize bool
+	for _, part := range strings.Split(tag, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return 0, 0, fmt.Errorf("invalid tag option %q", part)
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 0)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid value for %s: %q", key, value)
+		}
+		switch strings.TrimSpace(key) {
+		case "shift":
+			shift, hasShift = uint(n), true
+		case "size":
+			size, hasSize = uint(n), true
+		default:
+			return 0, 0, fmt.Errorf("unknown tag option %q", key)
+		}
+	}
+	if !hasShift || !hasSize {
+		return 0, 0, fmt.Errorf("tag %q must specify shift and size", tag)
+	}
+	return shift, size, nil
+}
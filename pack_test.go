@@ -0,0 +1,103 @@
+package bitfield
+
+import "testing"
+
+type packedHeader struct {
+	Active   bool   `bitfield:"shift=0,size=1"`
+	Priority uint8  `bitfield:"shift=1,size=3"`
+	Category uint8  `bitfield:"shift=4,size=4"`
+	Code     uint16 `bitfield:"shift=8,size=8"`
+	Comment  string // Untagged fields are ignored
+	Skipped  uint8  `bitfield:"-"`
+}
+
+func TestPack(t *testing.T) {
+	h := packedHeader{Active: true, Priority: 3, Category: 5, Code: 42, Comment: "x", Skipped: 9}
+	got, err := Pack[uint32](h)
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+	if got != 0x00002A57 {
+		t.Errorf("Pack(%+v) = 0x%08X, want 0x00002A57", h, got)
+	}
+
+	// Pointers to structs are accepted as well.
+	if got, err := Pack[uint32](&h); err != nil || got != 0x00002A57 {
+		t.Errorf("Pack(&h) = 0x%08X, %v, want 0x00002A57, nil", got, err)
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	h := packedHeader{Comment: "kept", Skipped: 9}
+	if err := Unpack[uint32](0x00002A57, &h); err != nil {
+		t.Fatalf("Unpack: %v", err)
+	}
+	want := packedHeader{Active: true, Priority: 3, Category: 5, Code: 42, Comment: "kept", Skipped: 9}
+	if h != want {
+		t.Errorf("Unpack(0x00002A57) = %+v, want %+v", h, want)
+	}
+}
+
+func TestPack_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"not a struct", 42},
+		{"nil pointer", (*packedHeader)(nil)},
+		{"value too large", packedHeader{Priority: 8}},
+		{"missing size", struct {
+			A uint8 `bitfield:"shift=0"`
+		}{}},
+		{"unknown option", struct {
+			A uint8 `bitfield:"shift=0,size=1,width=2"`
+		}{}},
+		{"bad number", struct {
+			A uint8 `bitfield:"shift=x,size=1"`
+		}{}},
+		{"overlap", struct {
+			A uint8 `bitfield:"shift=0,size=4"`
+			B uint8 `bitfield:"shift=3,size=4"`
+		}{}},
+		{"size exceeds type", struct {
+			A uint8 `bitfield:"shift=0,size=9"`
+		}{}},
+		{"bool too wide", struct {
+			A bool `bitfield:"shift=0,size=2"`
+		}{}},
+		{"unsupported type", struct {
+			A int `bitfield:"shift=0,size=2"`
+		}{}},
+		{"exceeds container", struct {
+			A uint64 `bitfield:"shift=30,size=4"`
+		}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Pack[uint32](tt.v); err == nil {
+				t.Errorf("Pack(%+v) succeeded, want error", tt.v)
+			}
+		})
+	}
+}
+
+func TestUnpack_Errors(t *testing.T) {
+	var h packedHeader
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"non-pointer", h},
+		{"nil pointer", (*packedHeader)(nil)},
+		{"pointer to non-struct", new(int)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Unpack[uint32](0, tt.v); err == nil {
+				t.Errorf("Unpack(%T) succeeded, want error", tt.v)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package bitfield
+
+import "fmt"
+
+// Packed is a container value bound to the Layout that describes it.
+// It gives packed storage a readable form: Packed implements JSON, text and binary marshaling
+// in terms of the named fields of the layout.
+type Packed[U storageType] struct {
+	Layout *Layout[U]
+	Value  U
+}
+
+// Bind returns value bound to the layout.
+func (l *Layout[U]) Bind(value U) Packed[U] {
+	return Packed[U]{Layout: l, Value: value}
+}
+
+// Get returns the value of a field.
+// The second return value reports whether the field exists; it is false for
+// every field if p has no layout.
+func (p Packed[U]) Get(name string) (uint64, bool) {
+	if p.Layout == nil {
+		return 0, false
+	}
+	bf, ok := p.Layout.Field(name)
+	if !ok {
+		return 0, false
+	}
+	return bf.Decode(p.Value), true
+}
+
+// layout returns the layout of p, or an error if p is not bound to one.
+func (p Packed[U]) layout() (*Layout[U], error) {
+	if p.Layout == nil {
+		return nil, fmt.Errorf("packed value has no layout")
+	}
+	return p.Layout, nil
+}
+
+// fieldValue returns the natural representation of a field value: its enum name
+// if one is registered, a bool for 1-bit fields, or the number otherwise.
+func fieldValue[U storageType](bf BitField[uint64, U], value uint64) any {
+	if name, ok := bf.Name(value); ok {
+		return name
+	}
+	if bf.Size == 1 {
+		return value == 1
+	}
+	return value
+}
@@ -0,0 +1,51 @@
+package bitfield
+
+import "testing"
+
+// newStatusLayout returns a status register layout with a 1-bit active flag,
+// a 3-bit priority with enum names, a 4-bit category and an 8-bit error code.
+func newStatusLayout(t *testing.T) *Layout[uint32] {
+	t.Helper()
+	l := mustLayout(t,
+		testField{"active", 0, 1},
+		testField{"priority", 1, 3},
+		testField{"category", 4, 4},
+		testField{"error", 8, 8},
+	)
+	if err := l.SetEnum("priority", map[uint64]string{0: "Low", 1: "Medium", 3: "High"}); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestPacked_NoLayout(t *testing.T) {
+	p := Packed[uint32]{Value: 0x2A}
+	if got, ok := p.Get("active"); got != 0 || ok {
+		t.Errorf("Get(active) = %v, %v, want 0, false", got, ok)
+	}
+	if got, want := p.Diagram(), "Packed(0x2a)\n"; got != want {
+		t.Errorf("Diagram() = %q, want %q", got, want)
+	}
+}
+
+func TestPacked_Get(t *testing.T) {
+	p := newStatusLayout(t).Bind(0x00002A57)
+	tests := []struct {
+		field  string
+		want   uint64
+		wantOK bool
+	}{
+		{"active", 1, true},
+		{"priority", 3, true},
+		{"category", 5, true},
+		{"error", 42, true},
+		{"missing", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := p.Get(tt.field)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Get(%q) = %v, %v, want %v, %v", tt.field, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
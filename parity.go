@@ -0,0 +1,89 @@
+package bitfield
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Parity selects the parity convention of a ParityField.
+type Parity int
+
+const (
+	EvenParity Parity = iota // The data and parity bits hold an even number of ones
+	OddParity                // The data and parity bits hold an odd number of ones
+)
+
+// String returns "even" or "odd".
+func (p Parity) String() string {
+	if p == OddParity {
+		return "odd"
+	}
+	return "even"
+}
+
+// ParityField is a data field protected by a parity bit, as in UART frames and
+// other formats that embed parity. The parity bit is computed over the data
+// field whenever it is encoded or updated, and verified when it is decoded.
+type ParityField[T Unsigned, U storageType] struct {
+	Data   BitField[T, U] // Bits covered by the parity
+	Bit    Flag[U]        // Parity bit
+	Parity Parity         // Parity convention
+}
+
+// NewParity creates a ParityField protecting data with a parity bit at the given position.
+// Note: This function doesn't perform validation, use SafeParity for validated creation.
+func NewParity[T Unsigned, U storageType](data BitField[T, U], bit uint, parity Parity) ParityField[T, U] {
+	return ParityField[T, U]{Data: data, Bit: NewFlag[U](bit), Parity: parity}
+}
+
+// SafeParity creates a ParityField after validating the parameters.
+// Returns an error if bit is outside the container type U or inside the data field.
+func SafeParity[T Unsigned, U storageType](data BitField[T, U], bit uint, parity Parity) (ParityField[T, U], error) {
+	flag, err := SafeFlag[U](bit)
+	if err != nil {
+		return ParityField[T, U]{}, err
+	}
+	if data.Mask&flag.Mask != 0 {
+		return ParityField[T, U]{}, fmt.Errorf("parity bit %d overlaps the data field", bit)
+	}
+	return ParityField[T, U]{Data: data, Bit: flag, Parity: parity}, nil
+}
+
+// parityBit reports whether the parity bit must be set for the data bits of container.
+func (pf ParityField[T, U]) parityBit(container U) bool {
+	odd := bits.OnesCount64(uint64(container&pf.Data.Mask))%2 == 1
+	return odd != (pf.Parity == OddParity)
+}
+
+// Encode encodes a value into the data field together with its parity bit.
+// Panics if the value is too large for the data field.
+func (pf ParityField[T, U]) Encode(value T) U {
+	return pf.Seal(pf.Data.Encode(value))
+}
+
+// Update sets the data field within an existing container and recomputes the parity bit.
+// Panics if the new value is too large for the data field.
+func (pf ParityField[T, U]) Update(previous U, value T) U {
+	return pf.Seal(pf.Data.Update(previous, value))
+}
+
+// Seal returns container with the parity bit recomputed from its data field,
+// for containers whose data bits were modified directly.
+func (pf ParityField[T, U]) Seal(container U) U {
+	return pf.Bit.SetTo(container, pf.parityBit(container))
+}
+
+// Check reports whether the parity bit of container matches its data field.
+func (pf ParityField[T, U]) Check(container U) bool {
+	return pf.Bit.IsSet(container) == pf.parityBit(container)
+}
+
+// Decode verifies the parity bit of container and extracts the data field.
+// Returns the data and an error wrapping ErrParity if the parity bit does not match.
+func (pf ParityField[T, U]) Decode(container U) (T, error) {
+	value := pf.Data.Decode(container)
+	if !pf.Check(container) {
+		return value, fmt.Errorf("%w: %s parity bit %d over 0x%X", ErrParity, pf.Parity, pf.Bit.Bit, pf.Data.Mask)
+	}
+	return value, nil
+}
@@ -0,0 +1,81 @@
+package bitfield
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParityField_Encode(t *testing.T) {
+	data := New[uint8, uint32](0, 8)
+	tests := []struct {
+		parity Parity
+		value  uint8
+		want   uint32
+	}{
+		{EvenParity, 0x00, 0x000},
+		{EvenParity, 0x41, 0x041},
+		{EvenParity, 0x07, 0x107},
+		{OddParity, 0x00, 0x100},
+		{OddParity, 0x41, 0x141},
+		{OddParity, 0x07, 0x007},
+	}
+
+	for _, tt := range tests {
+		pf := NewParity(data, 8, tt.parity)
+		got := pf.Encode(tt.value)
+		if got != tt.want {
+			t.Errorf("%s Encode(0x%02X) = 0x%03X, want 0x%03X", tt.parity, tt.value, got, tt.want)
+		}
+		if value, err := pf.Decode(got); err != nil || value != tt.value {
+			t.Errorf("%s Decode(0x%03X) = 0x%02X, %v, want 0x%02X", tt.parity, got, value, err, tt.value)
+		}
+	}
+}
+
+func TestParityField_Update(t *testing.T) {
+	pf := NewParity(New[uint8, uint32](4, 4), 15, EvenParity)
+
+	// Bits outside the data field are not covered by the parity.
+	got := pf.Update(0x000F, 0x3)
+	if got != 0x003F {
+		t.Errorf("Update(0x000F, 3) = 0x%04X, want 0x003F", got)
+	}
+	got = pf.Update(got, 0x7)
+	if got != 0x807F {
+		t.Errorf("Update(0x003F, 7) = 0x%04X, want 0x807F", got)
+	}
+	if !pf.Check(got) {
+		t.Errorf("Check(0x%04X) = false after Update", got)
+	}
+	if sealed := pf.Seal(got ^ 0x0010); sealed != 0x006F {
+		t.Errorf("Seal(0x%04X) = 0x%04X, want 0x006F", got^0x0010, sealed)
+	}
+}
+
+func TestParityField_DecodeError(t *testing.T) {
+	pf := NewParity(New[uint8, uint32](0, 8), 8, EvenParity)
+	container := pf.Encode(0x55)
+
+	for bit := range uint(9) {
+		corrupted := container ^ 1<<bit
+		if pf.Check(corrupted) {
+			t.Errorf("Check(0x%03X) = true with bit %d flipped", corrupted, bit)
+		}
+		if _, err := pf.Decode(corrupted); !errors.Is(err, ErrParity) {
+			t.Errorf("Decode(0x%03X) = %v, want ErrParity", corrupted, err)
+		}
+	}
+}
+
+func TestSafeParity(t *testing.T) {
+	data := New[uint8, uint32](0, 8)
+	if _, err := SafeParity(data, 8, OddParity); err != nil {
+		t.Errorf("SafeParity(bit 8) = %v", err)
+	}
+	if _, err := SafeParity(data, 32, OddParity); !errors.Is(err, ErrShiftOutOfRange) {
+		t.Errorf("SafeParity(bit 32) = %v, want ErrShiftOutOfRange", err)
+	}
+	if _, err := SafeParity(data, 3, OddParity); err == nil {
+		t.Error("SafeParity(bit 3) succeeded inside the data field, want error")
+	}
+}
@@ -0,0 +1,95 @@
+// Package dna packs DNA sequences into 2 bits per base, 32 bases per uint64.
+//
+// Bases are coded A=0, C=1, G=2, T=3, so the complement of a base is its code
+// with both bits inverted. Base i of a sequence is the 2-bit field at bit 2i of
+// the backing words, accessed with bitfield.WordField.
+package dna
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Bases lists the bases in code order.
+const Bases = "ACGT"
+
+// Code returns the 2-bit code of a base, accepting upper and lower case.
+// The second return value is false if b is not A, C, G or T.
+func Code(b byte) (uint8, bool) {
+	i := strings.IndexByte(Bases, b&^0x20) // ASCII upper case
+	return uint8(i), i >= 0
+}
+
+// Sequence is a DNA sequence packed 2 bits per base.
+// The zero value is an empty sequence.
+type Sequence struct {
+	words []uint64
+	n     int // Number of bases
+}
+
+// base returns the field of base i.
+func base(i int) bitfield.WordField[uint8, uint64] {
+	return bitfield.NewWordField[uint8, uint64](2*uint(i), 2)
+}
+
+// Pack packs a sequence of bases given as letters.
+// Returns an error if s contains a character other than A, C, G or T in either case.
+func Pack(s string) (Sequence, error) {
+	seq := Sequence{words: make([]uint64, (len(s)+31)/32), n: len(s)}
+	for i := range len(s) {
+		code, ok := Code(s[i])
+		if !ok {
+			return Sequence{}, fmt.Errorf("dna: invalid base %q at position %d", s[i], i)
+		}
+		base(i).Update(seq.words, code)
+	}
+	return seq, nil
+}
+
+// Len returns the number of bases in the sequence.
+func (s Sequence) Len() int {
+	return s.n
+}
+
+// Words returns a copy of the packed words of the sequence. Bits beyond the last base are 0.
+func (s Sequence) Words() []uint64 {
+	return slices.Clone(s.words)
+}
+
+// Code returns the 2-bit code of base i.
+// Panics if i is out of range.
+func (s Sequence) Code(i int) uint8 {
+	if i < 0 || i >= s.n {
+		panic(fmt.Sprintf("dna: index %d out of range [0, %d)", i, s.n))
+	}
+	return base(i).Decode(s.words)
+}
+
+// At returns the letter of base i.
+// Panics if i is out of range.
+func (s Sequence) At(i int) byte {
+	return Bases[s.Code(i)]
+}
+
+// String returns the sequence as upper-case letters.
+func (s Sequence) String() string {
+	var b strings.Builder
+	b.Grow(s.n)
+	for i := range s.n {
+		b.WriteByte(s.At(i))
+	}
+	return b.String()
+}
+
+// ReverseComplement returns the reverse complement of the sequence: the bases in
+// reverse order with A and T, and C and G, swapped, as read from the other strand.
+func (s Sequence) ReverseComplement() Sequence {
+	rc := Sequence{words: make([]uint64, len(s.words)), n: s.n}
+	for i := range s.n {
+		base(s.n-1-i).Update(rc.words, s.Code(i)^3)
+	}
+	return rc
+}
@@ -0,0 +1,78 @@
+package dna
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPack(t *testing.T) {
+	seq, err := Pack("ACGTacgt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq.Len() != 8 || seq.String() != "ACGTACGT" {
+		t.Errorf("Pack = %d bases %q, want 8 bases ACGTACGT", seq.Len(), seq.String())
+	}
+	if got, want := seq.Words(), []uint64{0xE4E4}; !slices.Equal(got, want) {
+		t.Errorf("Words() = %X, want %X", got, want)
+	}
+	if seq.At(2) != 'G' || seq.Code(3) != 3 {
+		t.Errorf("At(2) = %c, Code(3) = %d, want G, 3", seq.At(2), seq.Code(3))
+	}
+
+	long := strings.Repeat("GATTACA", 10)
+	seq, err = Pack(long)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seq.Words()) != 3 || seq.String() != long {
+		t.Errorf("Pack of %d bases = %d words, %q", len(long), len(seq.Words()), seq.String())
+	}
+
+	for _, s := range []string{"ACGN", "AC-G", "ACGU"} {
+		if _, err := Pack(s); err == nil {
+			t.Errorf("Pack(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestSequence_ReverseComplement(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"A", "T"},
+		{"ACGT", "ACGT"},
+		{"GATTACA", "TGTAATC"},
+		{strings.Repeat("AAC", 20) + "G", "C" + strings.Repeat("GTT", 20)},
+	}
+
+	for _, tt := range tests {
+		seq, err := Pack(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rc := seq.ReverseComplement()
+		if got := rc.String(); got != tt.want {
+			t.Errorf("ReverseComplement(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if back := rc.ReverseComplement().String(); back != strings.ToUpper(tt.in) {
+			t.Errorf("double ReverseComplement(%q) = %q", tt.in, back)
+		}
+	}
+}
+
+func TestSequence_CodePanics(t *testing.T) {
+	seq, _ := Pack("ACG")
+	for _, i := range []int{-1, 3} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Code(%d) did not panic", i)
+				}
+			}()
+			seq.Code(i)
+		}()
+	}
+}
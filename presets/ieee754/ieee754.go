@@ -0,0 +1,256 @@
+// Package ieee754 dissects IEEE 754 binary floating-point bit patterns into
+// their sign, exponent and mantissa fields.
+//
+// A Format describes the field sizes of a binary interchange format. Float32
+// and Float64 match the Go types; Float16 and BFloat16 are common formats
+// without a Go type, and NewFormat creates custom formats such as 8-bit
+// floats. Bit patterns of any format are held in a uint64 and can be
+// converted to and from float64 with correct rounding.
+package ieee754
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Format is a binary floating-point format with IEEE 754 semantics: a sign
+// bit above a biased exponent above the mantissa, an implicit leading 1 for
+// normal numbers, and the largest exponent reserved for infinities and NaNs.
+type Format struct {
+	Name     string
+	Sign     bitfield.BitField[uint64, uint64]
+	Exponent bitfield.BitField[uint64, uint64] // Biased exponent
+	Mantissa bitfield.BitField[uint64, uint64] // Fraction, without the implicit leading bit
+	Bias     int
+}
+
+// Predefined formats.
+var (
+	Float16  = mustFormat("float16", 5, 10)
+	BFloat16 = mustFormat("bfloat16", 8, 7)
+	Float32  = mustFormat("float32", 8, 23)
+	Float64  = mustFormat("float64", 11, 52)
+)
+
+// NewFormat creates a format with the given exponent and mantissa sizes and
+// the IEEE 754 bias 2^(exponentBits-1) - 1.
+// Returns an error if the exponent has fewer than 2 or more than 11 bits or
+// the mantissa has fewer than 1 or more than 52 bits, so that every value of
+// the format is exactly representable as a float64.
+func NewFormat(name string, exponentBits, mantissaBits uint) (Format, error) {
+	if exponentBits < 2 || exponentBits > 11 {
+		return Format{}, fmt.Errorf("ieee754: invalid exponent size %d", exponentBits)
+	}
+	if mantissaBits < 1 || mantissaBits > 52 {
+		return Format{}, fmt.Errorf("ieee754: invalid mantissa size %d", mantissaBits)
+	}
+	return Format{
+		Name:     name,
+		Sign:     bitfield.New[uint64, uint64](exponentBits+mantissaBits, 1),
+		Exponent: bitfield.New[uint64, uint64](mantissaBits, exponentBits),
+		Mantissa: bitfield.New[uint64, uint64](0, mantissaBits),
+		Bias:     1<<(exponentBits-1) - 1,
+	}, nil
+}
+
+func mustFormat(name string, exponentBits, mantissaBits uint) Format {
+	f, err := NewFormat(name, exponentBits, mantissaBits)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Bits returns the total size of the format in bits.
+func (f Format) Bits() uint {
+	return f.Sign.Shift + 1
+}
+
+// Layout returns a Layout with the fields of the format, named Sign,
+// Exponent and Mantissa.
+func (f Format) Layout() *bitfield.Layout[uint64] {
+	l := bitfield.NewLayout[uint64]()
+	for _, fl := range []struct {
+		name  string
+		field bitfield.BitField[uint64, uint64]
+	}{{"Mantissa", f.Mantissa}, {"Exponent", f.Exponent}, {"Sign", f.Sign}} {
+		if err := l.Add(fl.name, fl.field.Shift, fl.field.Size); err != nil {
+			panic(err)
+		}
+	}
+	return l
+}
+
+// Parts are the fields of a floating-point bit pattern.
+type Parts struct {
+	Negative bool
+	Exponent uint64 // Biased exponent
+	Mantissa uint64 // Fraction, without the implicit leading bit
+}
+
+// Decompose splits a bit pattern of the format into its fields.
+// Bits above the format are ignored.
+func (f Format) Decompose(bits uint64) Parts {
+	return Parts{
+		Negative: f.Sign.Decode(bits) != 0,
+		Exponent: f.Exponent.Decode(bits),
+		Mantissa: f.Mantissa.Decode(bits),
+	}
+}
+
+// Compose builds a bit pattern of the format from its fields.
+// Returns an error if the exponent or mantissa does not fit in its field.
+func (f Format) Compose(p Parts) (uint64, error) {
+	if !f.Exponent.IsValid(p.Exponent) {
+		return 0, fmt.Errorf("ieee754: %s exponent %d out of range, max %d", f.Name, p.Exponent, f.Exponent.Max())
+	}
+	if !f.Mantissa.IsValid(p.Mantissa) {
+		return 0, fmt.Errorf("ieee754: %s mantissa 0x%X out of range, max 0x%X", f.Name, p.Mantissa, f.Mantissa.Max())
+	}
+	bits := f.Exponent.Encode(p.Exponent) | f.Mantissa.Encode(p.Mantissa)
+	if p.Negative {
+		bits |= f.Sign.Mask
+	}
+	return bits, nil
+}
+
+// Class is the kind of number a bit pattern encodes.
+type Class int
+
+const (
+	Zero Class = iota
+	Subnormal
+	Normal
+	Infinity
+	NaN
+)
+
+// String returns the name of the class.
+func (c Class) String() string {
+	switch c {
+	case Zero:
+		return "zero"
+	case Subnormal:
+		return "subnormal"
+	case Normal:
+		return "normal"
+	case Infinity:
+		return "infinity"
+	case NaN:
+		return "NaN"
+	}
+	return fmt.Sprintf("Class(%d)", int(c))
+}
+
+// Classify returns the class of a bit pattern of the format.
+func (f Format) Classify(bits uint64) Class {
+	p := f.Decompose(bits)
+	switch p.Exponent {
+	case 0:
+		if p.Mantissa == 0 {
+			return Zero
+		}
+		return Subnormal
+	case f.Exponent.Max():
+		if p.Mantissa == 0 {
+			return Infinity
+		}
+		return NaN
+	}
+	return Normal
+}
+
+// Float64 returns the value of a bit pattern of the format.
+func (f Format) Float64(bits uint64) float64 {
+	p := f.Decompose(bits)
+	var v float64
+	switch f.Classify(bits) {
+	case Zero:
+	case Subnormal:
+		v = math.Ldexp(float64(p.Mantissa), 1-f.Bias-int(f.Mantissa.Size))
+	case Normal:
+		v = math.Ldexp(float64(p.Mantissa|1<<f.Mantissa.Size), int(p.Exponent)-f.Bias-int(f.Mantissa.Size))
+	case Infinity:
+		v = math.Inf(1)
+	case NaN:
+		return math.NaN()
+	}
+	if p.Negative {
+		v = -v
+	}
+	return v
+}
+
+// FromFloat64 returns the bit pattern of the format nearest to x, rounding
+// ties to even. Values too large for the format become infinities, and NaNs
+// become a quiet NaN.
+func (f Format) FromFloat64(x float64) uint64 {
+	var sign uint64
+	if math.Signbit(x) {
+		sign, x = f.Sign.Mask, -x
+	}
+	size := int(f.Mantissa.Size)
+	inf := f.Exponent.Mask
+	switch {
+	case math.IsNaN(x):
+		return sign | inf | f.Mantissa.Encode(1<<(size-1))
+	case math.IsInf(x, 0):
+		return sign | inf
+	case x == 0:
+		return sign
+	}
+	frac, exp := math.Frexp(x) // x = frac * 2^exp with frac in [0.5, 1)
+	biased := exp - 1 + f.Bias
+	if biased <= 0 {
+		// Subnormal; rounding up to the smallest normal number carries into the exponent.
+		return sign | uint64(math.RoundToEven(math.Ldexp(x, f.Bias+size-1)))
+	}
+	significand := uint64(math.RoundToEven(math.Ldexp(frac, size+1)))
+	// A significand rounded up to 2^(size+1) carries into the exponent.
+	bits := uint64(biased)<<size + significand - 1<<size
+	if bits >= inf {
+		return sign | inf
+	}
+	return sign | bits
+}
+
+// Dissect describes a bit pattern of the format, for debugging.
+func (f Format) Dissect(bits uint64) string {
+	p := f.Decompose(bits)
+	sign := 0
+	if p.Negative {
+		sign = 1
+	}
+	digits := (f.Mantissa.Size + 3) / 4
+	s := fmt.Sprintf("sign=%d exponent=%d", sign, p.Exponent)
+	if c := f.Classify(bits); c == Normal {
+		s += fmt.Sprintf(" (2^%d)", int(p.Exponent)-f.Bias)
+	}
+	return s + fmt.Sprintf(" mantissa=0x%0*X %v %v", digits, p.Mantissa, f.Classify(bits), f.Float64(bits))
+}
+
+// Decompose32 splits a float32 into its fields.
+func Decompose32(x float32) Parts {
+	return Float32.Decompose(uint64(math.Float32bits(x)))
+}
+
+// Compose32 builds a float32 from its fields.
+// Returns an error if the exponent or mantissa does not fit in its field.
+func Compose32(p Parts) (float32, error) {
+	bits, err := Float32.Compose(p)
+	return math.Float32frombits(uint32(bits)), err
+}
+
+// Decompose64 splits a float64 into its fields.
+func Decompose64(x float64) Parts {
+	return Float64.Decompose(math.Float64bits(x))
+}
+
+// Compose64 builds a float64 from its fields.
+// Returns an error if the exponent or mantissa does not fit in its field.
+func Compose64(p Parts) (float64, error) {
+	bits, err := Float64.Compose(p)
+	return math.Float64frombits(bits), err
+}
@@ -0,0 +1,182 @@
+package ieee754
+
+import (
+	"maps"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestDecompose(t *testing.T) {
+	tests := []struct {
+		x    float64
+		want Parts
+	}{
+		{1, Parts{Exponent: 1023}},
+		{-2, Parts{Negative: true, Exponent: 1024}},
+		{1.5, Parts{Exponent: 1023, Mantissa: 1 << 51}},
+		{math.SmallestNonzeroFloat64, Parts{Mantissa: 1}},
+		{math.Inf(-1), Parts{Negative: true, Exponent: 2047}},
+	}
+
+	for _, tt := range tests {
+		got := Decompose64(tt.x)
+		if got != tt.want {
+			t.Errorf("Decompose64(%v) = %+v, want %+v", tt.x, got, tt.want)
+		}
+		if x, err := Compose64(got); err != nil || x != tt.x {
+			t.Errorf("Compose64(%+v) = %v, %v, want %v", got, x, err, tt.x)
+		}
+	}
+
+	if got := Decompose32(-0.75); got != (Parts{Negative: true, Exponent: 126, Mantissa: 1 << 22}) {
+		t.Errorf("Decompose32(-0.75) = %+v", got)
+	}
+	if x, err := Compose32(Parts{Exponent: 128, Mantissa: 1 << 21}); err != nil || x != 2.5 {
+		t.Errorf("Compose32 = %v, %v, want 2.5", x, err)
+	}
+}
+
+func TestFormat_ComposeErrors(t *testing.T) {
+	tests := []Parts{
+		{Exponent: 256},
+		{Mantissa: 1 << 23},
+	}
+
+	for _, p := range tests {
+		if _, err := Float32.Compose(p); err == nil {
+			t.Errorf("Compose(%+v) succeeded, want error", p)
+		}
+	}
+}
+
+func TestFormat_Classify(t *testing.T) {
+	tests := []struct {
+		f    Format
+		bits uint64
+		want Class
+	}{
+		{Float32, 0x80000000, Zero},
+		{Float32, 0x00000001, Subnormal},
+		{Float32, 0x3F800000, Normal},
+		{Float32, 0xFF800000, Infinity},
+		{Float32, 0x7FC00000, NaN},
+		{Float16, 0x7C00, Infinity},
+		{Float16, 0x03FF, Subnormal},
+	}
+
+	for _, tt := range tests {
+		if got := tt.f.Classify(tt.bits); got != tt.want {
+			t.Errorf("%s Classify(0x%X) = %v, want %v", tt.f.Name, tt.bits, got, tt.want)
+		}
+	}
+}
+
+func TestFormat_FromFloat64(t *testing.T) {
+	tests := []struct {
+		f    Format
+		x    float64
+		want uint64
+	}{
+		{Float16, 1, 0x3C00},
+		{Float16, -2, 0xC000},
+		{Float16, 0.1, 0x2E66},
+		{Float16, 65504, 0x7BFF},
+		{Float16, 65519, 0x7BFF},
+		{Float16, 65520, 0x7C00},
+		{Float16, math.Ldexp(1, -24), 0x0001},
+		{Float16, math.Ldexp(1, -25), 0x0000},
+		{Float16, math.Ldexp(3, -26), 0x0001},
+		{Float16, math.Ldexp(1023.5, -24), 0x0400},
+		{Float16, math.Copysign(0, -1), 0x8000},
+		{Float16, math.Inf(-1), 0xFC00},
+		{Float16, math.NaN(), 0x7E00},
+		{BFloat16, 1, 0x3F80},
+		{BFloat16, math.Pi, 0x4049},
+		{Float32, 1, 0x3F800000},
+	}
+
+	for _, tt := range tests {
+		if got := tt.f.FromFloat64(tt.x); got != tt.want {
+			t.Errorf("%s FromFloat64(%v) = 0x%X, want 0x%X", tt.f.Name, tt.x, got, tt.want)
+		}
+	}
+}
+
+// TestFloat32 checks conversions against the float32 conversions of the Go runtime.
+func TestFloat32(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 100000 {
+		x := math.Float64frombits(r.Uint64())
+		if math.IsNaN(x) {
+			continue
+		}
+		want := uint64(math.Float32bits(float32(x)))
+		if got := Float32.FromFloat64(x); got != want {
+			t.Fatalf("FromFloat64(%v) = 0x%08X, want 0x%08X", x, got, want)
+		}
+		bits := uint64(r.Uint32())
+		if Float32.Classify(bits) == NaN {
+			continue
+		}
+		if got, want := Float32.Float64(bits), float64(math.Float32frombits(uint32(bits))); got != want {
+			t.Fatalf("Float64(0x%08X) = %v, want %v", bits, got, want)
+		}
+	}
+}
+
+func TestFloat16_RoundTrip(t *testing.T) {
+	for bits := range uint64(1 << 16) {
+		if Float16.Classify(bits) == NaN {
+			continue
+		}
+		if got := Float16.FromFloat64(Float16.Float64(bits)); got != bits {
+			t.Fatalf("FromFloat64(Float64(0x%04X)) = 0x%04X", bits, got)
+		}
+	}
+}
+
+func TestNewFormat(t *testing.T) {
+	e5m2, err := NewFormat("E5M2", 5, 2)
+	if err != nil {
+		t.Fatalf("NewFormat: %v", err)
+	}
+	if e5m2.Bits() != 8 || e5m2.Bias != 15 {
+		t.Errorf("E5M2 = %d bits, bias %d, want 8 bits, bias 15", e5m2.Bits(), e5m2.Bias)
+	}
+	if got := e5m2.Float64(0x3C); got != 1 {
+		t.Errorf("E5M2 Float64(0x3C) = %v, want 1", got)
+	}
+
+	for _, size := range [][2]uint{{1, 10}, {12, 10}, {5, 0}, {5, 53}} {
+		if _, err := NewFormat("bad", size[0], size[1]); err == nil {
+			t.Errorf("NewFormat(%d, %d) succeeded, want error", size[0], size[1])
+		}
+	}
+}
+
+func TestFormat_Layout(t *testing.T) {
+	got := Float32.Layout().DecodeAll(0xC0490FDB)
+	want := map[string]uint64{"Sign": 1, "Exponent": 128, "Mantissa": 0x490FDB}
+	if !maps.Equal(got, want) {
+		t.Errorf("DecodeAll = %v, want %v", got, want)
+	}
+}
+
+func TestFormat_Dissect(t *testing.T) {
+	tests := []struct {
+		f    Format
+		bits uint64
+		want string
+	}{
+		{Float32, 0x3F800000, "sign=0 exponent=127 (2^0) mantissa=0x000000 normal 1"},
+		{Float16, 0x8001, "sign=1 exponent=0 mantissa=0x001 subnormal -5.960464477539063e-08"},
+		{Float16, 0x7C00, "sign=0 exponent=31 mantissa=0x000 infinity +Inf"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.f.Dissect(tt.bits); got != tt.want {
+			t.Errorf("Dissect(0x%X) = %q, want %q", tt.bits, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package net provides ready-made layouts of the fixed parts of common
+// network protocol headers: Ethernet with and without an IEEE 802.1Q VLAN
+// tag, IPv4, TCP and UDP.
+//
+// Fields are located with bitfield.ByteField in MSBFirst bit order, so the bit
+// offsets are those of the header diagrams in the RFCs, counted from the most
+// significant bit of the first byte, and packets can be read and written in
+// place. Options and payloads following the fixed part are not covered.
+package net
+
+import (
+	"fmt"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Field is a named field of a Header.
+type Field struct {
+	Name string
+	bitfield.ByteField[uint64]
+}
+
+// Header is the fixed part of a protocol header: named fields covering its
+// bytes without gaps, in the order of the header diagram.
+type Header struct {
+	Name   string
+	Size   int // Size in bytes
+	fields []Field
+	index  map[string]int
+}
+
+// field is the name and size in bits of a header field, for newHeader.
+type field struct {
+	name string
+	size uint
+}
+
+// newHeader lays out consecutive fields from bit 0.
+// Panics if the fields do not fill a whole number of bytes.
+func newHeader(name string, fields ...field) *Header {
+	h := &Header{Name: name, index: make(map[string]int)}
+	var offset uint
+	for _, f := range fields {
+		h.index[f.name] = len(h.fields)
+		h.fields = append(h.fields, Field{Name: f.name, ByteField: bitfield.NewByteField[uint64](offset, f.size, bitfield.MSBFirst)})
+		offset += f.size
+	}
+	if offset%8 != 0 || len(h.index) != len(fields) {
+		panic(fmt.Sprintf("net: invalid %s header", name))
+	}
+	h.Size = int(offset / 8)
+	return h
+}
+
+// Ethernet II header (IEEE 802.3).
+var Ethernet = newHeader("Ethernet",
+	field{"Destination", 48},
+	field{"Source", 48},
+	field{"EtherType", 16},
+)
+
+// EthernetVLAN is an Ethernet header with an IEEE 802.1Q tag.
+var EthernetVLAN = newHeader("EthernetVLAN",
+	field{"Destination", 48},
+	field{"Source", 48},
+	field{"TPID", 16}, // Tag protocol identifier, EtherTypeVLAN
+	field{"PCP", 3},   // Priority code point
+	field{"DEI", 1},   // Drop eligible indicator
+	field{"VID", 12},  // VLAN identifier
+	field{"EtherType", 16},
+)
+
+// IPv4 header (RFC 791), with the DSCP and ECN fields of RFC 2474 and RFC 3168.
+var IPv4 = newHeader("IPv4",
+	field{"Version", 4},
+	field{"IHL", 4}, // Header length in 32-bit words
+	field{"DSCP", 6},
+	field{"ECN", 2},
+	field{"TotalLength", 16},
+	field{"Identification", 16},
+	field{"Reserved", 1},
+	field{"DF", 1}, // Don't fragment
+	field{"MF", 1}, // More fragments
+	field{"FragmentOffset", 13},
+	field{"TTL", 8},
+	field{"Protocol", 8},
+	field{"HeaderChecksum", 16},
+	field{"Source", 32},
+	field{"Destination", 32},
+)
+
+// TCP header (RFC 9293).
+var TCP = newHeader("TCP",
+	field{"SourcePort", 16},
+	field{"DestinationPort", 16},
+	field{"SequenceNumber", 32},
+	field{"AcknowledgmentNumber", 32},
+	field{"DataOffset", 4}, // Header length in 32-bit words
+	field{"Reserved", 4},
+	field{"CWR", 1},
+	field{"ECE", 1},
+	field{"URG", 1},
+	field{"ACK", 1},
+	field{"PSH", 1},
+	field{"RST", 1},
+	field{"SYN", 1},
+	field{"FIN", 1},
+	field{"Window", 16},
+	field{"Checksum", 16},
+	field{"UrgentPointer", 16},
+)
+
+// UDP header (RFC 768).
+var UDP = newHeader("UDP",
+	field{"SourcePort", 16},
+	field{"DestinationPort", 16},
+	field{"Length", 16},
+	field{"Checksum", 16},
+)
+
+// EtherType values.
+const (
+	EtherTypeIPv4 = 0x0800
+	EtherTypeARP  = 0x0806
+	EtherTypeVLAN = 0x8100
+	EtherTypeIPv6 = 0x86DD
+)
+
+// IP protocol numbers.
+const (
+	ProtocolICMP = 1
+	ProtocolTCP  = 6
+	ProtocolUDP  = 17
+)
+
+// Fields returns the fields of the header in diagram order.
+func (h *Header) Fields() []Field {
+	return append([]Field(nil), h.fields...)
+}
+
+// Field returns the field with the given name.
+// The second return value reports whether the field exists.
+func (h *Header) Field(name string) (bitfield.ByteField[uint64], bool) {
+	i, ok := h.index[name]
+	if !ok {
+		return bitfield.ByteField[uint64]{}, false
+	}
+	return h.fields[i].ByteField, true
+}
+
+// Decode extracts every field of the header from the start of buf.
+// Returns an error if buf is shorter than the header.
+func (h *Header) Decode(buf []byte) (map[string]uint64, error) {
+	if len(buf) < h.Size {
+		return nil, fmt.Errorf("net: %s header of %d bytes exceeds buffer of %d bytes", h.Name, h.Size, len(buf))
+	}
+	values := make(map[string]uint64, len(h.fields))
+	for _, f := range h.fields {
+		values[f.Name] = f.Decode(buf)
+	}
+	return values, nil
+}
+
+// Encode writes the given fields of the header at the start of buf,
+// preserving all other bits.
+// Returns an error, leaving buf unchanged, if buf is shorter than the header,
+// a field does not exist, or a value does not fit in its field.
+func (h *Header) Encode(buf []byte, values map[string]uint64) error {
+	if len(buf) < h.Size {
+		return fmt.Errorf("net: %s header of %d bytes exceeds buffer of %d bytes", h.Name, h.Size, len(buf))
+	}
+	for name, v := range values {
+		f, ok := h.Field(name)
+		if !ok {
+			return fmt.Errorf("net: %s header has no field %q", h.Name, name)
+		}
+		if !f.IsValid(v) {
+			return fmt.Errorf("net: %s value %v out of range for %d-bit field %q", h.Name, v, f.Size, name)
+		}
+	}
+	for name, v := range values {
+		f, _ := h.Field(name)
+		f.Update(buf, v)
+	}
+	return nil
+}
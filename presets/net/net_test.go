@@ -0,0 +1,140 @@
+package net
+
+import (
+	"bytes"
+	"maps"
+	"testing"
+)
+
+// TestOffsets checks field offsets against the bit numbering of the RFC header diagrams.
+func TestOffsets(t *testing.T) {
+	tests := []struct {
+		h      *Header
+		field  string
+		offset uint
+		size   uint
+	}{
+		{Ethernet, "EtherType", 96, 16},
+		{EthernetVLAN, "PCP", 112, 3},
+		{EthernetVLAN, "VID", 116, 12},
+		{EthernetVLAN, "EtherType", 128, 16},
+		{IPv4, "IHL", 4, 4},
+		{IPv4, "ECN", 14, 2},
+		{IPv4, "DF", 49, 1},
+		{IPv4, "FragmentOffset", 51, 13},
+		{IPv4, "Protocol", 72, 8},
+		{IPv4, "Destination", 128, 32},
+		{TCP, "SequenceNumber", 32, 32},
+		{TCP, "DataOffset", 96, 4},
+		{TCP, "CWR", 104, 1},
+		{TCP, "SYN", 110, 1},
+		{TCP, "FIN", 111, 1},
+		{TCP, "UrgentPointer", 144, 16},
+		{UDP, "Checksum", 48, 16},
+	}
+
+	for _, tt := range tests {
+		f, ok := tt.h.Field(tt.field)
+		if !ok || f.Offset != tt.offset || f.Size != tt.size {
+			t.Errorf("%s.%s = offset %d, size %d, found %v, want offset %d, size %d",
+				tt.h.Name, tt.field, f.Offset, f.Size, ok, tt.offset, tt.size)
+		}
+	}
+
+	sizes := map[*Header]int{Ethernet: 14, EthernetVLAN: 18, IPv4: 20, TCP: 20, UDP: 8}
+	for h, want := range sizes {
+		if h.Size != want {
+			t.Errorf("%s size = %d, want %d", h.Name, h.Size, want)
+		}
+	}
+}
+
+func TestHeader_Decode(t *testing.T) {
+	tests := []struct {
+		name string
+		h    *Header
+		buf  []byte
+		want map[string]uint64
+	}{
+		{
+			"ethernet vlan", EthernetVLAN,
+			[]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x81, 0x00, 0xA0, 0x64, 0x08, 0x00},
+			map[string]uint64{
+				"Destination": 0xFFFFFFFFFFFF, "Source": 0x001122334455, "TPID": EtherTypeVLAN,
+				"PCP": 5, "DEI": 0, "VID": 100, "EtherType": EtherTypeIPv4,
+			},
+		},
+		{
+			"ipv4", IPv4,
+			[]byte{0x45, 0x00, 0x00, 0x73, 0x00, 0x00, 0x40, 0x00, 0x40, 0x11, 0xB8, 0x61, 0xC0, 0xA8, 0x00, 0x01, 0xC0, 0xA8, 0x00, 0xC7},
+			map[string]uint64{
+				"Version": 4, "IHL": 5, "DSCP": 0, "ECN": 0, "TotalLength": 115, "Identification": 0,
+				"Reserved": 0, "DF": 1, "MF": 0, "FragmentOffset": 0, "TTL": 64, "Protocol": ProtocolUDP,
+				"HeaderChecksum": 0xB861, "Source": 0xC0A80001, "Destination": 0xC0A800C7,
+			},
+		},
+		{
+			"tcp syn ack", TCP,
+			[]byte{0x00, 0x50, 0xD4, 0x31, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00, 0x01, 0x80, 0x12, 0xFA, 0xF0, 0x12, 0x34, 0x00, 0x00},
+			map[string]uint64{
+				"SourcePort": 80, "DestinationPort": 54321, "SequenceNumber": 0x1000, "AcknowledgmentNumber": 1,
+				"DataOffset": 8, "Reserved": 0, "CWR": 0, "ECE": 0, "URG": 0, "ACK": 1, "PSH": 0, "RST": 0,
+				"SYN": 1, "FIN": 0, "Window": 0xFAF0, "Checksum": 0x1234, "UrgentPointer": 0,
+			},
+		},
+		{
+			"udp", UDP,
+			[]byte{0x04, 0xD2, 0x00, 0x35, 0x00, 0x1C, 0xAB, 0xCD, 0xEE},
+			map[string]uint64{"SourcePort": 1234, "DestinationPort": 53, "Length": 28, "Checksum": 0xABCD},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.h.Decode(tt.buf)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("Decode = %v, want %v", got, tt.want)
+			}
+
+			buf := make([]byte, len(tt.buf))
+			copy(buf[tt.h.Size:], tt.buf[tt.h.Size:])
+			if err := tt.h.Encode(buf, tt.want); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if !bytes.Equal(buf, tt.buf) {
+				t.Errorf("Encode = % X, want % X", buf, tt.buf)
+			}
+		})
+	}
+}
+
+func TestHeader_Errors(t *testing.T) {
+	if _, err := IPv4.Decode(make([]byte, 19)); err == nil {
+		t.Error("Decode of 19 bytes succeeded, want error")
+	}
+
+	buf := make([]byte, 20)
+	tests := []struct {
+		name   string
+		buf    []byte
+		values map[string]uint64
+	}{
+		{"short buffer", buf[:8], map[string]uint64{"Version": 4}},
+		{"unknown field", buf, map[string]uint64{"Version": 4, "Flags": 1}},
+		{"out of range", buf, map[string]uint64{"Version": 4, "IHL": 16}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := IPv4.Encode(tt.buf, tt.values); err == nil {
+				t.Errorf("Encode(%v) succeeded, want error", tt.values)
+			}
+			if !bytes.Equal(buf, make([]byte, 20)) {
+				t.Errorf("failed Encode modified buffer to % X", buf)
+			}
+		})
+	}
+}
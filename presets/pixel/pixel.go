@@ -0,0 +1,148 @@
+// Package pixel provides layouts of 16-bit packed pixel formats and color
+// types implementing image/color.Color and image/color.Model for them.
+//
+// Pixels are held in the low 16 bits of a uint32 container, so they can be
+// packed and unpacked with the Layout of their Format like any other bit field.
+// Channels are stored without premultiplied alpha and are scaled to and from
+// the 16-bit channels of the image/color package with rounding.
+package pixel
+
+import (
+	"image/color"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Format describes a 16-bit packed pixel format.
+type Format struct {
+	Name       string
+	R, G, B, A bitfield.BitField[uint16, uint32] // A has size 0 in formats without alpha
+}
+
+// Pixel formats, named from the most significant channel.
+var (
+	FormatRGB565 = Format{
+		Name: "RGB565",
+		R:    bitfield.New[uint16, uint32](11, 5),
+		G:    bitfield.New[uint16, uint32](5, 6),
+		B:    bitfield.New[uint16, uint32](0, 5),
+	}
+	FormatARGB1555 = Format{
+		Name: "ARGB1555",
+		A:    bitfield.New[uint16, uint32](15, 1),
+		R:    bitfield.New[uint16, uint32](10, 5),
+		G:    bitfield.New[uint16, uint32](5, 5),
+		B:    bitfield.New[uint16, uint32](0, 5),
+	}
+	FormatRGBA4444 = Format{
+		Name: "RGBA4444",
+		R:    bitfield.New[uint16, uint32](12, 4),
+		G:    bitfield.New[uint16, uint32](8, 4),
+		B:    bitfield.New[uint16, uint32](4, 4),
+		A:    bitfield.New[uint16, uint32](0, 4),
+	}
+)
+
+// Layout returns a Layout with the channels of the format, named R, G, B and,
+// if the format has alpha, A.
+func (f Format) Layout() *bitfield.Layout[uint32] {
+	l := bitfield.NewLayout[uint32]()
+	for _, ch := range []struct {
+		name  string
+		field bitfield.BitField[uint16, uint32]
+	}{{"R", f.R}, {"G", f.G}, {"B", f.B}, {"A", f.A}} {
+		if ch.field.Size == 0 {
+			continue
+		}
+		if err := l.Add(ch.name, ch.field.Shift, ch.field.Size); err != nil {
+			panic(err)
+		}
+	}
+	return l
+}
+
+// Pack converts a color to a pixel of the format. Translucent colors are
+// composited over black in formats without alpha.
+func (f Format) Pack(c color.Color) uint16 {
+	if f.A.Size == 0 {
+		r, g, b, _ := c.RGBA()
+		return uint16(f.R.Encode(quantize(uint16(r), f.R)) | f.G.Encode(quantize(uint16(g), f.G)) | f.B.Encode(quantize(uint16(b), f.B)))
+	}
+	n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	return uint16(f.R.Encode(quantize(n.R, f.R)) | f.G.Encode(quantize(n.G, f.G)) |
+		f.B.Encode(quantize(n.B, f.B)) | f.A.Encode(quantize(n.A, f.A)))
+}
+
+// Unpack converts a pixel of the format to a color. Formats without alpha
+// are opaque.
+func (f Format) Unpack(pixel uint16) color.NRGBA64 {
+	v := uint32(pixel)
+	c := color.NRGBA64{R: expand(v, f.R), G: expand(v, f.G), B: expand(v, f.B), A: 0xFFFF}
+	if f.A.Size != 0 {
+		c.A = expand(v, f.A)
+	}
+	return c
+}
+
+// quantize scales a 16-bit channel to the size of a field, rounding to nearest.
+func quantize(v uint16, bf bitfield.BitField[uint16, uint32]) uint16 {
+	return uint16((uint32(v)*uint32(bf.Max()) + 0x7FFF) / 0xFFFF)
+}
+
+// expand scales a channel field to 16 bits, rounding to nearest.
+func expand(v uint32, bf bitfield.BitField[uint16, uint32]) uint16 {
+	m := uint32(bf.Max())
+	return uint16((uint32(bf.Decode(v))*0xFFFF + m/2) / m)
+}
+
+// RGB565 is a pixel with 5 bits of red, 6 of green and 5 of blue.
+type RGB565 uint16
+
+// RGBA implements color.Color.
+func (c RGB565) RGBA() (r, g, b, a uint32) {
+	return FormatRGB565.Unpack(uint16(c)).RGBA()
+}
+
+// ARGB1555 is a pixel with 1 bit of alpha and 5 bits each of red, green and blue.
+type ARGB1555 uint16
+
+// RGBA implements color.Color.
+func (c ARGB1555) RGBA() (r, g, b, a uint32) {
+	return FormatARGB1555.Unpack(uint16(c)).RGBA()
+}
+
+// RGBA4444 is a pixel with 4 bits each of red, green, blue and alpha.
+type RGBA4444 uint16
+
+// RGBA implements color.Color.
+func (c RGBA4444) RGBA() (r, g, b, a uint32) {
+	return FormatRGBA4444.Unpack(uint16(c)).RGBA()
+}
+
+// Models for the pixel types.
+var (
+	RGB565Model   color.Model = color.ModelFunc(rgb565Model)
+	ARGB1555Model color.Model = color.ModelFunc(argb1555Model)
+	RGBA4444Model color.Model = color.ModelFunc(rgba4444Model)
+)
+
+func rgb565Model(c color.Color) color.Color {
+	if c, ok := c.(RGB565); ok {
+		return c
+	}
+	return RGB565(FormatRGB565.Pack(c))
+}
+
+func argb1555Model(c color.Color) color.Color {
+	if c, ok := c.(ARGB1555); ok {
+		return c
+	}
+	return ARGB1555(FormatARGB1555.Pack(c))
+}
+
+func rgba4444Model(c color.Color) color.Color {
+	if c, ok := c.(RGBA4444); ok {
+		return c
+	}
+	return RGBA4444(FormatRGBA4444.Pack(c))
+}
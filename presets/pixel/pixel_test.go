@@ -0,0 +1,107 @@
+package pixel
+
+import (
+	"image/color"
+	"maps"
+	"testing"
+)
+
+func TestFormat_Pack(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Format
+		c    color.Color
+		want uint16
+	}{
+		{"565 white", FormatRGB565, color.White, 0xFFFF},
+		{"565 red", FormatRGB565, color.RGBA{R: 0xFF, A: 0xFF}, 0xF800},
+		{"565 green", FormatRGB565, color.RGBA{G: 0xFF, A: 0xFF}, 0x07E0},
+		{"565 gray", FormatRGB565, color.Gray{Y: 0x80}, 0x8410},
+		{"565 over black", FormatRGB565, color.NRGBA{R: 0xFF, A: 0x80}, 0x8000},
+		{"1555 opaque blue", FormatARGB1555, color.RGBA{B: 0xFF, A: 0xFF}, 0x801F},
+		{"1555 transparent", FormatARGB1555, color.Transparent, 0x0000},
+		{"4444 translucent red", FormatRGBA4444, color.NRGBA{R: 0xFF, A: 0x88}, 0xF008},
+		{"4444 white", FormatRGBA4444, color.White, 0xFFFF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.Pack(tt.c); got != tt.want {
+				t.Errorf("Pack(%v) = 0x%04X, want 0x%04X", tt.c, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat_Unpack(t *testing.T) {
+	tests := []struct {
+		name  string
+		f     Format
+		pixel uint16
+		want  color.NRGBA64
+	}{
+		{"565 red", FormatRGB565, 0xF800, color.NRGBA64{R: 0xFFFF, A: 0xFFFF}},
+		{"565 mid green", FormatRGB565, 0x0400, color.NRGBA64{G: 0x8208, A: 0xFFFF}},
+		{"1555 transparent blue", FormatARGB1555, 0x001F, color.NRGBA64{B: 0xFFFF}},
+		{"4444", FormatRGBA4444, 0x1234, color.NRGBA64{R: 0x1111, G: 0x2222, B: 0x3333, A: 0x4444}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.Unpack(tt.pixel); got != tt.want {
+				t.Errorf("Unpack(0x%04X) = %v, want %v", tt.pixel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, f := range []Format{FormatRGB565, FormatARGB1555, FormatRGBA4444} {
+		for v := range 1 << 16 {
+			p := uint16(v)
+			if f.A.Size != 0 && f.A.Decode(uint32(p)) == 0 {
+				continue // Channels of transparent pixels are lost in premultiplied colors
+			}
+			if got := f.Pack(f.Unpack(p)); got != p {
+				t.Errorf("%s: Pack(Unpack(0x%04X)) = 0x%04X", f.Name, p, got)
+				break
+			}
+		}
+	}
+}
+
+func TestModels(t *testing.T) {
+	tests := []struct {
+		model color.Model
+		c     color.Color
+		want  color.Color
+	}{
+		{RGB565Model, color.RGBA{R: 0xFF, A: 0xFF}, RGB565(0xF800)},
+		{RGB565Model, RGB565(0x1234), RGB565(0x1234)},
+		{ARGB1555Model, color.White, ARGB1555(0xFFFF)},
+		{ARGB1555Model, ARGB1555(0x1234), ARGB1555(0x1234)},
+		{RGBA4444Model, color.Black, RGBA4444(0x000F)},
+		{RGBA4444Model, RGBA4444(0x1234), RGBA4444(0x1234)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.model.Convert(tt.c); got != tt.want {
+			t.Errorf("Convert(%v) = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+
+	r, g, b, a := RGBA4444(0xF008).RGBA()
+	if r != 0x8888 || g != 0 || b != 0 || a != 0x8888 {
+		t.Errorf("RGBA4444(0xF008).RGBA() = %X, %X, %X, %X, want premultiplied 8888, 0, 0, 8888", r, g, b, a)
+	}
+}
+
+func TestFormat_Layout(t *testing.T) {
+	got := FormatRGB565.Layout().DecodeAll(0x8410)
+	if want := map[string]uint64{"R": 16, "G": 32, "B": 16}; !maps.Equal(got, want) {
+		t.Errorf("RGB565 DecodeAll = %v, want %v", got, want)
+	}
+	if names := FormatARGB1555.Layout().Names(); len(names) != 4 {
+		t.Errorf("ARGB1555 fields = %v, want 4", names)
+	}
+}
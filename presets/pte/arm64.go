@@ -0,0 +1,158 @@
+package pte
+
+import (
+	"fmt"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// AccessPermission is the AP[2:1] field of an AArch64 descriptor.
+type AccessPermission uint8
+
+const (
+	APReadWriteEL1 AccessPermission = iota // Read/write at EL1, no access at EL0
+	APReadWrite                            // Read/write at EL1 and EL0
+	APReadOnlyEL1                          // Read-only at EL1, no access at EL0
+	APReadOnly                             // Read-only at EL1 and EL0
+)
+
+// Shareability is the SH field of an AArch64 descriptor.
+type Shareability uint8
+
+const (
+	NonShareable   Shareability = 0
+	OuterShareable Shareability = 2
+	InnerShareable Shareability = 3
+)
+
+// Fields of an AArch64 stage 1 page descriptor.
+var (
+	armValid         = flag("Valid", 0)
+	armPage          = flag("Page", 1)
+	armAttrIndex     = bits("AttrIndex", 2, 3)
+	armNonSecure     = flag("NonSecure", 5)
+	armAP            = bits("AP", 6, 2)
+	armSH            = bits("SH", 8, 2)
+	armAccessFlag    = flag("AccessFlag", 10)
+	armNotGlobal     = flag("NotGlobal", 11)
+	armFrame         = bits("Frame", 12, 36)
+	armGuarded       = flag("Guarded", 50)
+	armDirtyBitMod   = flag("DirtyBitModifier", 51)
+	armContiguous    = flag("Contiguous", 52)
+	armPrivNoExecute = flag("PrivilegedNoExecute", 53)
+	armUserNoExecute = flag("UserNoExecute", 54)
+	armSoftware      = bits("Software", 55, 4)
+)
+
+var armFields = []field{
+	armValid, armPage, armAttrIndex, armNonSecure, armAP, armSH, armAccessFlag, armNotGlobal, armFrame,
+	armGuarded, armDirtyBitMod, armContiguous, armPrivNoExecute, armUserNoExecute, armSoftware,
+}
+
+// ARM64Layout returns a Layout of an AArch64 stage 1 level 3 page descriptor
+// with the 4 KiB granule. Fields are named after the ARM64Entry fields, and
+// the AP and SH fields have enum names.
+func ARM64Layout() *bitfield.Layout[uint64] {
+	l := newLayout(armFields)
+	if err := l.SetEnum("AP", map[uint64]string{
+		uint64(APReadWriteEL1): "RW_EL1", uint64(APReadWrite): "RW",
+		uint64(APReadOnlyEL1): "RO_EL1", uint64(APReadOnly): "RO",
+	}); err != nil {
+		panic(err)
+	}
+	if err := l.SetEnum("SH", map[uint64]string{
+		uint64(NonShareable): "NonShareable", uint64(OuterShareable): "OuterShareable",
+		uint64(InnerShareable): "InnerShareable",
+	}); err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// ARM64Entry is an AArch64 stage 1 level 3 page descriptor with the 4 KiB granule.
+type ARM64Entry struct {
+	Valid               bool
+	Page                bool  // Descriptor type bit, set for page descriptors
+	AttrIndex           uint8 // Index into MAIR_ELx
+	NonSecure           bool
+	AP                  AccessPermission
+	SH                  Shareability
+	AccessFlag          bool
+	NotGlobal           bool   // nG
+	Frame               uint64 // Output address bits 47:12
+	Guarded             bool   // GP, for BTI
+	DirtyBitModifier    bool   // DBM
+	Contiguous          bool
+	PrivilegedNoExecute bool  // PXN
+	UserNoExecute       bool  // UXN, or XN at EL2 and EL3
+	Software            uint8 // Bits 55 to 58, reserved for software use
+}
+
+// NewARM64Page returns a valid page descriptor for a frame with the access
+// flag set, the common starting point for constructing entries.
+func NewARM64Page(frame uint64, attrIndex uint8, ap AccessPermission, sh Shareability) ARM64Entry {
+	return ARM64Entry{Valid: true, Page: true, AttrIndex: attrIndex, AP: ap, SH: sh, AccessFlag: true, Frame: frame}
+}
+
+// DecodeARM64 splits an AArch64 page descriptor into its fields.
+func DecodeARM64(desc uint64) ARM64Entry {
+	return ARM64Entry{
+		Valid:               armValid.bf.Decode(desc) != 0,
+		Page:                armPage.bf.Decode(desc) != 0,
+		AttrIndex:           uint8(armAttrIndex.bf.Decode(desc)),
+		NonSecure:           armNonSecure.bf.Decode(desc) != 0,
+		AP:                  AccessPermission(armAP.bf.Decode(desc)),
+		SH:                  Shareability(armSH.bf.Decode(desc)),
+		AccessFlag:          armAccessFlag.bf.Decode(desc) != 0,
+		NotGlobal:           armNotGlobal.bf.Decode(desc) != 0,
+		Frame:               armFrame.bf.Decode(desc),
+		Guarded:             armGuarded.bf.Decode(desc) != 0,
+		DirtyBitModifier:    armDirtyBitMod.bf.Decode(desc) != 0,
+		Contiguous:          armContiguous.bf.Decode(desc) != 0,
+		PrivilegedNoExecute: armPrivNoExecute.bf.Decode(desc) != 0,
+		UserNoExecute:       armUserNoExecute.bf.Decode(desc) != 0,
+		Software:            uint8(armSoftware.bf.Decode(desc)),
+	}
+}
+
+// Address returns the output address of the page.
+func (e ARM64Entry) Address() uint64 {
+	return e.Frame << PageShift
+}
+
+// Encode builds the page descriptor.
+// Returns an error if a field value does not fit, or SH is the reserved value 1.
+func (e ARM64Entry) Encode() (uint64, error) {
+	for _, f := range []struct {
+		field field
+		value uint64
+	}{
+		{armAttrIndex, uint64(e.AttrIndex)},
+		{armAP, uint64(e.AP)},
+		{armSH, uint64(e.SH)},
+		{armFrame, e.Frame},
+		{armSoftware, uint64(e.Software)},
+	} {
+		if !f.field.bf.IsValid(f.value) {
+			return 0, fmt.Errorf("pte: arm64 %s 0x%X out of range, max 0x%X", f.field.name, f.value, f.field.bf.Max())
+		}
+	}
+	if e.SH == 1 {
+		return 0, fmt.Errorf("pte: arm64 SH value 1 is reserved")
+	}
+	return armValid.bf.Encode(boolBit(e.Valid)) |
+		armPage.bf.Encode(boolBit(e.Page)) |
+		armAttrIndex.bf.Encode(uint64(e.AttrIndex)) |
+		armNonSecure.bf.Encode(boolBit(e.NonSecure)) |
+		armAP.bf.Encode(uint64(e.AP)) |
+		armSH.bf.Encode(uint64(e.SH)) |
+		armAccessFlag.bf.Encode(boolBit(e.AccessFlag)) |
+		armNotGlobal.bf.Encode(boolBit(e.NotGlobal)) |
+		armFrame.bf.Encode(e.Frame) |
+		armGuarded.bf.Encode(boolBit(e.Guarded)) |
+		armDirtyBitMod.bf.Encode(boolBit(e.DirtyBitModifier)) |
+		armContiguous.bf.Encode(boolBit(e.Contiguous)) |
+		armPrivNoExecute.bf.Encode(boolBit(e.PrivilegedNoExecute)) |
+		armUserNoExecute.bf.Encode(boolBit(e.UserNoExecute)) |
+		armSoftware.bf.Encode(uint64(e.Software)), nil
+}
@@ -0,0 +1,57 @@
+package pte
+
+import "testing"
+
+func TestARM64Entry_Encode(t *testing.T) {
+	e := NewARM64Page(0x40000, 4, APReadOnly, InnerShareable)
+	e.PrivilegedNoExecute, e.UserNoExecute = true, true
+	const want = 0x00600000400007D3
+	got, err := e.Encode()
+	if err != nil || got != want {
+		t.Fatalf("Encode = 0x%X, %v, want 0x%X", got, err, uint64(want))
+	}
+	if d := DecodeARM64(got); d != e {
+		t.Errorf("DecodeARM64(0x%X) = %+v, want %+v", got, d, e)
+	}
+	if e.Address() != 0x40000000 {
+		t.Errorf("Address = 0x%X, want 0x40000000", e.Address())
+	}
+}
+
+func TestARM64Entry_RoundTrip(t *testing.T) {
+	tests := []uint64{0, 0x07FC0FFFFFFFFEFF, 0x0000000000000003, 0x00400000FFFFF443}
+	for _, desc := range tests {
+		if got, err := DecodeARM64(desc).Encode(); err != nil || got != desc {
+			t.Errorf("Encode(DecodeARM64(0x%X)) = 0x%X, %v", desc, got, err)
+		}
+	}
+}
+
+func TestARM64Entry_EncodeErrors(t *testing.T) {
+	tests := []ARM64Entry{
+		{Frame: 1 << 36},
+		{AttrIndex: 8},
+		{AP: 4},
+		{SH: 1},
+		{SH: 4},
+		{Software: 16},
+	}
+
+	for _, e := range tests {
+		if _, err := e.Encode(); err == nil {
+			t.Errorf("Encode(%+v) succeeded, want error", e)
+		}
+	}
+}
+
+func TestARM64Layout(t *testing.T) {
+	l := ARM64Layout()
+	ap, _ := l.Field("AP")
+	if got := ap.ValueString(uint64(APReadOnly)); got != "RO" {
+		t.Errorf("AP.ValueString(3) = %q, want RO", got)
+	}
+	values := l.DecodeAll(0x00600000400007D3)
+	if values["SH"] != uint64(InnerShareable) || values["Frame"] != 0x40000 || values["AttrIndex"] != 4 {
+		t.Errorf("DecodeAll = %v", values)
+	}
+}
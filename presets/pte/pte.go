@@ -0,0 +1,49 @@
+// Package pte provides layouts of the page table entries of x86-64 and
+// ARMv8-A (AArch64), and entry types to decode and construct them.
+//
+// The layouts describe 4 KiB page mappings: x86-64 page table entries (the
+// last level of 4- and 5-level paging) and AArch64 stage 1 level 3 page
+// descriptors with the 4 KiB translation granule. Physical addresses are
+// given as frame numbers, the address shifted right by 12 bits.
+package pte
+
+import "github.com/lnear-dev/bitfield"
+
+// PageShift is the log2 of the page size of the layouts.
+const PageShift = 12
+
+// field is a named field of an entry layout.
+type field struct {
+	name string
+	bf   bitfield.BitField[uint64, uint64]
+}
+
+// flag creates a 1-bit field.
+func flag(name string, bit uint) field {
+	return field{name, bitfield.New[uint64, uint64](bit, 1)}
+}
+
+// bits creates a multi-bit field.
+func bits(name string, shift, size uint) field {
+	return field{name, bitfield.New[uint64, uint64](shift, size)}
+}
+
+// newLayout creates a Layout of the given fields.
+// Panics if the fields are invalid, which is a bug in the field tables.
+func newLayout(fields []field) *bitfield.Layout[uint64] {
+	l := bitfield.NewLayout[uint64]()
+	for _, f := range fields {
+		if err := l.Add(f.name, f.bf.Shift, f.bf.Size); err != nil {
+			panic(err)
+		}
+	}
+	return l
+}
+
+// boolBit returns 1 for true and 0 for false.
+func boolBit(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
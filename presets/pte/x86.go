@@ -0,0 +1,112 @@
+package pte
+
+import (
+	"fmt"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Fields of an x86-64 page table entry.
+var (
+	x86Present       = flag("Present", 0)
+	x86Writable      = flag("Writable", 1)
+	x86User          = flag("User", 2)
+	x86WriteThrough  = flag("WriteThrough", 3)
+	x86CacheDisable  = flag("CacheDisable", 4)
+	x86Accessed      = flag("Accessed", 5)
+	x86Dirty         = flag("Dirty", 6)
+	x86PAT           = flag("PAT", 7)
+	x86Global        = flag("Global", 8)
+	x86Available     = bits("Available", 9, 3)
+	x86Frame         = bits("Frame", 12, 40)
+	x86AvailableHigh = bits("AvailableHigh", 52, 7)
+	x86ProtectionKey = bits("ProtectionKey", 59, 4)
+	x86NoExecute     = flag("NoExecute", 63)
+)
+
+var x86Fields = []field{
+	x86Present, x86Writable, x86User, x86WriteThrough, x86CacheDisable, x86Accessed, x86Dirty,
+	x86PAT, x86Global, x86Available, x86Frame, x86AvailableHigh, x86ProtectionKey, x86NoExecute,
+}
+
+// X86Layout returns a Layout of an x86-64 page table entry mapping a 4 KiB page.
+// Fields are named after the X86Entry fields.
+func X86Layout() *bitfield.Layout[uint64] {
+	return newLayout(x86Fields)
+}
+
+// X86Entry is an x86-64 page table entry mapping a 4 KiB page.
+type X86Entry struct {
+	Present       bool
+	Writable      bool
+	User          bool // Accessible from user mode
+	WriteThrough  bool // PWT
+	CacheDisable  bool // PCD
+	Accessed      bool
+	Dirty         bool
+	PAT           bool
+	Global        bool
+	Available     uint8  // Bits 9 to 11, ignored by the processor
+	Frame         uint64 // Physical frame number, up to 40 bits
+	AvailableHigh uint8  // Bits 52 to 58, ignored by the processor
+	ProtectionKey uint8  // Protection key, used when PKE or PKS is enabled
+	NoExecute     bool   // XD, used when EFER.NXE is set
+}
+
+// DecodeX86 splits an x86-64 page table entry into its fields.
+func DecodeX86(pte uint64) X86Entry {
+	return X86Entry{
+		Present:       x86Present.bf.Decode(pte) != 0,
+		Writable:      x86Writable.bf.Decode(pte) != 0,
+		User:          x86User.bf.Decode(pte) != 0,
+		WriteThrough:  x86WriteThrough.bf.Decode(pte) != 0,
+		CacheDisable:  x86CacheDisable.bf.Decode(pte) != 0,
+		Accessed:      x86Accessed.bf.Decode(pte) != 0,
+		Dirty:         x86Dirty.bf.Decode(pte) != 0,
+		PAT:           x86PAT.bf.Decode(pte) != 0,
+		Global:        x86Global.bf.Decode(pte) != 0,
+		Available:     uint8(x86Available.bf.Decode(pte)),
+		Frame:         x86Frame.bf.Decode(pte),
+		AvailableHigh: uint8(x86AvailableHigh.bf.Decode(pte)),
+		ProtectionKey: uint8(x86ProtectionKey.bf.Decode(pte)),
+		NoExecute:     x86NoExecute.bf.Decode(pte) != 0,
+	}
+}
+
+// Address returns the physical address of the page.
+func (e X86Entry) Address() uint64 {
+	return e.Frame << PageShift
+}
+
+// Encode builds the page table entry.
+// Returns an error if the frame number, protection key or available bits do
+// not fit in their fields.
+func (e X86Entry) Encode() (uint64, error) {
+	for _, f := range []struct {
+		field field
+		value uint64
+	}{
+		{x86Available, uint64(e.Available)},
+		{x86Frame, e.Frame},
+		{x86AvailableHigh, uint64(e.AvailableHigh)},
+		{x86ProtectionKey, uint64(e.ProtectionKey)},
+	} {
+		if !f.field.bf.IsValid(f.value) {
+			return 0, fmt.Errorf("pte: x86 %s 0x%X out of range, max 0x%X", f.field.name, f.value, f.field.bf.Max())
+		}
+	}
+	return x86Present.bf.Encode(boolBit(e.Present)) |
+		x86Writable.bf.Encode(boolBit(e.Writable)) |
+		x86User.bf.Encode(boolBit(e.User)) |
+		x86WriteThrough.bf.Encode(boolBit(e.WriteThrough)) |
+		x86CacheDisable.bf.Encode(boolBit(e.CacheDisable)) |
+		x86Accessed.bf.Encode(boolBit(e.Accessed)) |
+		x86Dirty.bf.Encode(boolBit(e.Dirty)) |
+		x86PAT.bf.Encode(boolBit(e.PAT)) |
+		x86Global.bf.Encode(boolBit(e.Global)) |
+		x86Available.bf.Encode(uint64(e.Available)) |
+		x86Frame.bf.Encode(e.Frame) |
+		x86AvailableHigh.bf.Encode(uint64(e.AvailableHigh)) |
+		x86ProtectionKey.bf.Encode(uint64(e.ProtectionKey)) |
+		x86NoExecute.bf.Encode(boolBit(e.NoExecute)), nil
+}
@@ -0,0 +1,56 @@
+package pte
+
+import "testing"
+
+func TestDecodeX86(t *testing.T) {
+	const pte = 0x8000000012345067
+	want := X86Entry{
+		Present: true, Writable: true, User: true, Accessed: true, Dirty: true,
+		Frame: 0x12345, NoExecute: true,
+	}
+	got := DecodeX86(pte)
+	if got != want {
+		t.Errorf("DecodeX86(0x%X) = %+v, want %+v", uint64(pte), got, want)
+	}
+	if got.Address() != 0x12345000 {
+		t.Errorf("Address = 0x%X, want 0x12345000", got.Address())
+	}
+	if v, err := got.Encode(); err != nil || v != pte {
+		t.Errorf("Encode = 0x%X, %v, want 0x%X", v, err, uint64(pte))
+	}
+}
+
+func TestX86Entry_RoundTrip(t *testing.T) {
+	tests := []uint64{0, 0xFFFFFFFFFFFFFFFF, 0x7FF0000000000E00, 0x000FFFFFFFFFF000, 0x0000000000000198}
+	for _, pte := range tests {
+		if got, err := DecodeX86(pte).Encode(); err != nil || got != pte {
+			t.Errorf("Encode(DecodeX86(0x%X)) = 0x%X, %v", pte, got, err)
+		}
+	}
+}
+
+func TestX86Entry_EncodeErrors(t *testing.T) {
+	tests := []X86Entry{
+		{Frame: 1 << 40},
+		{ProtectionKey: 16},
+		{Available: 8},
+		{AvailableHigh: 128},
+	}
+
+	for _, e := range tests {
+		if _, err := e.Encode(); err == nil {
+			t.Errorf("Encode(%+v) succeeded, want error", e)
+		}
+	}
+}
+
+func TestX86Layout(t *testing.T) {
+	l := X86Layout()
+	values := l.DecodeAll(0x8000000012345067)
+	if values["Frame"] != 0x12345 || values["NoExecute"] != 1 || values["Global"] != 0 {
+		t.Errorf("DecodeAll = %v", values)
+	}
+	if len(l.Names()) != len(x86Fields) {
+		t.Errorf("Names = %v, want %d fields", l.Names(), len(x86Fields))
+	}
+}
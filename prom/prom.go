@@ -0,0 +1,131 @@
+// Package prom exports the field values of a bitfield.RegisterMap as Prometheus gauges.
+//
+// A Collector reports one gauge sample per readable field, labeled with the
+// register and field name:
+//
+//	device_register_field{register="STATUS",field="ready"} 1
+//
+// Values are taken from the registers of the map. If the Collector has an
+// accessor, every register is read from the bus instead, so that scrapes reflect
+// the live device state. Scrapes never change the registers of the map, but
+// reading registers with ReadToClear fields clears them on the device; leave such
+// registers out of exported maps.
+//
+// Prometheus may scrape concurrently with the driver that owns the map. Set
+// Collector.Lock to the lock the driver holds while it accesses the registers.
+//
+// The package is a separate module, so that users of bitfield do not depend on
+// the Prometheus client unless they import it.
+package prom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lnear-dev/bitfield"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a prometheus.Collector for the fields of a RegisterMap.
+type Collector[U uint32 | uint64] struct {
+	// Timeout bounds the bus reads of one scrape. Zero means no timeout.
+	Timeout time.Duration
+
+	// Lock, if set, is held while a scrape reads the registers, or the bus if
+	// the collector has an accessor. It should be the lock that the driver holds
+	// while it accesses the same registers.
+	Lock sync.Locker
+
+	mu   sync.Mutex // Serializes scrapes
+	regs *bitfield.RegisterMap[U]
+	acc  bitfield.RegisterAccessor
+	desc *prometheus.Desc
+}
+
+// sample is the value of a readable register field taken during a scrape.
+type sample struct {
+	register, field string
+	value           uint64
+}
+
+// NewCollector creates a collector exporting the fields of regs as the gauge name,
+// which should follow the Prometheus naming conventions, such as "device_register_field".
+// constLabels are added to every sample. If acc is nil, the collector reports
+// the values currently held by the registers and never accesses the bus.
+func NewCollector[U uint32 | uint64](name string, regs *bitfield.RegisterMap[U], acc bitfield.RegisterAccessor, constLabels prometheus.Labels) *Collector[U] {
+	return &Collector[U]{
+		regs: regs,
+		acc:  acc,
+		desc: prometheus.NewDesc(name, "Decoded value of a register field.", []string{"register", "field"}, constLabels),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector[U]) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+// Write-only fields are skipped. A register whose bus read fails is reported
+// as an invalid metric and its fields are left out of the scrape.
+func (c *Collector[U]) Collect(ch chan<- prometheus.Metric) {
+	samples, errs := c.snapshot()
+	for _, err := range errs {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+	}
+	for _, s := range samples {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(s.value), s.register, s.field)
+	}
+}
+
+// snapshot takes the values of the readable fields of every register, holding
+// Lock if it is set, and returns them with the errors of failed bus reads.
+func (c *Collector[U]) snapshot() ([]sample, []error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Lock != nil {
+		c.Lock.Lock()
+		defer c.Lock.Unlock()
+	}
+	ctx := context.Background()
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	var samples []sample
+	var errs []error
+	for _, reg := range c.regs.Registers() {
+		value, err := c.read(ctx, reg)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, field := range reg.Layout.Names() {
+			if reg.Access(field) == bitfield.WriteOnly {
+				continue
+			}
+			bf, _ := reg.Layout.Field(field)
+			samples = append(samples, sample{register: reg.Name, field: field, value: bf.Decode(value)})
+		}
+	}
+	return samples, errs
+}
+
+// read returns the value of reg, read from the bus if the collector has an
+// accessor. Unlike bitfield.ReadRegister, it leaves the register unchanged.
+func (c *Collector[U]) read(ctx context.Context, reg *bitfield.Register[U]) (U, error) {
+	if c.acc == nil {
+		return reg.Value(), nil
+	}
+	raw, err := c.acc.Read(ctx, reg.Offset)
+	if err != nil {
+		return 0, fmt.Errorf("register %s: read: %w", reg.Name, err)
+	}
+	if value := U(raw); uint64(value) == raw {
+		return value, nil
+	}
+	return 0, fmt.Errorf("register %s: read value 0x%X exceeds register width", reg.Name, raw)
+}
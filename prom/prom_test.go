@@ -0,0 +1,138 @@
+package prom
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newTestMap returns a map with a STATUS register (ready, errors) at 0 and
+// a CTRL register (mode, write-only command) at 4.
+func newTestMap(t *testing.T) *bitfield.RegisterMap[uint32] {
+	t.Helper()
+	status := bitfield.NewLayout[uint32]()
+	ctrl := bitfield.NewLayout[uint32]()
+	for _, err := range []error{
+		status.Add("ready", 0, 1),
+		status.Add("errors", 4, 4),
+		ctrl.Add("mode", 0, 2),
+		ctrl.Add("command", 8, 8),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := bitfield.NewRegisterMap[uint32]()
+	ctrlReg := bitfield.NewRegister("CTRL", ctrl, 0x2)
+	if err := ctrlReg.SetAccess("command", bitfield.WriteOnly); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(0, bitfield.NewRegister("STATUS", status, 0x31)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(4, ctrlReg); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("device_register_field", newTestMap(t), nil, prometheus.Labels{"device": "dev0"})
+	want := `
+# HELP device_register_field Decoded value of a register field.
+# TYPE device_register_field gauge
+device_register_field{device="dev0",field="errors",register="STATUS"} 3
+device_register_field{device="dev0",field="mode",register="CTRL"} 2
+device_register_field{device="dev0",field="ready",register="STATUS"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollector_Accessor(t *testing.T) {
+	ctx := context.Background()
+	bus := bitfield.NewMemoryAccessor()
+	_ = bus.Write(ctx, 0, 0x50)
+	_ = bus.Write(ctx, 4, 0x1)
+	c := NewCollector("device_register_field", newTestMap(t), bus, nil)
+	want := `
+# HELP device_register_field Decoded value of a register field.
+# TYPE device_register_field gauge
+device_register_field{field="errors",register="STATUS"} 5
+device_register_field{field="mode",register="CTRL"} 1
+device_register_field{field="ready",register="STATUS"} 0
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+	if reads, _ := bus.Counts(); reads != 2 {
+		t.Errorf("bus reads = %d, want 2", reads)
+	}
+}
+
+// failingAccessor returns err from every bus operation.
+type failingAccessor struct{ err error }
+
+func (f failingAccessor) Read(context.Context, uint64) (uint64, error) { return 0, f.err }
+func (f failingAccessor) Write(context.Context, uint64, uint64) error  { return f.err }
+
+func TestCollector_ReadError(t *testing.T) {
+	c := NewCollector("device_register_field", newTestMap(t), failingAccessor{errors.New("bus fault")}, nil)
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.Gather(); err == nil || !strings.Contains(err.Error(), "bus fault") {
+		t.Errorf("Gather() err = %v, want bus fault", err)
+	}
+}
+
+// countingLocker is a sync.Locker that counts how many times it was locked.
+type countingLocker struct {
+	sync.Mutex
+	locks int
+}
+
+func (l *countingLocker) Lock() {
+	l.Mutex.Lock()
+	l.locks++
+}
+
+func TestCollector_LeavesRegisters(t *testing.T) {
+	ctx := context.Background()
+	bus := bitfield.NewMemoryAccessor()
+	_ = bus.Write(ctx, 0, 0x50)
+	m := newTestMap(t)
+	status, _ := m.ByName("STATUS")
+	if err := status.SetSideEffect("errors", bitfield.ReadToClear); err != nil {
+		t.Fatal(err)
+	}
+	lock := &countingLocker{}
+	c := NewCollector("device_register_field", m, bus, nil)
+	c.Lock = lock
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if n := testutil.CollectAndCount(c); n != 3 {
+				t.Errorf("CollectAndCount() = %d, want 3", n)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := status.Value(); got != 0x31 {
+		t.Errorf("STATUS = 0x%X after scrapes, want 0x31", got)
+	}
+	if lock.locks != 4 {
+		t.Errorf("Lock taken %d times, want 4", lock.locks)
+	}
+}
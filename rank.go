@@ -0,0 +1,134 @@
+package bitfield
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// Rank returns the number of set bits at positions below i.
+// It scans the BitSet; use a RankIndex for repeated queries on large sets.
+func (b *BitSet) Rank(i uint) int {
+	i = min(i, b.n)
+	rank := 0
+	for _, w := range b.words[:i/64] {
+		rank += bits.OnesCount64(w)
+	}
+	if i%64 != 0 {
+		rank += bits.OnesCount64(b.words[i/64] & lowBits(i%64))
+	}
+	return rank
+}
+
+// Select returns the position of the set bit with rank k, that is the (k+1)-th set bit.
+// The second return value is false if fewer than k+1 bits are set.
+// It scans the BitSet; use a RankIndex for repeated queries on large sets.
+func (b *BitSet) Select(k int) (uint, bool) {
+	if k < 0 {
+		return 0, false
+	}
+	for i, w := range b.words {
+		if n := bits.OnesCount64(w); k >= n {
+			k -= n
+			continue
+		}
+		return uint(i)*64 + selectInWord(w, k), true
+	}
+	return 0, false
+}
+
+// selectInWord returns the position of the set bit with rank k in w,
+// which must have more than k bits set.
+func selectInWord(w uint64, k int) uint {
+	for ; k > 0; k-- {
+		w &= w - 1
+	}
+	return uint(bits.TrailingZeros64(w))
+}
+
+// rankSubCounts holds the rank9 second-level counts of a block: for words 1 to 7
+// of the block, the number of set bits in the block before that word.
+var rankSubCounts = NewFieldArray[uint64, uint64](0, 9, 7)
+
+// RankIndex answers Rank and Select queries on a BitSet in constant and
+// logarithmic time, using the rank9 layout: for each block of 8 words, the
+// number of set bits before the block and the packed counts within it,
+// about 25% extra space.
+// The index describes the BitSet at the time it was built; rebuild it after
+// modifying the BitSet.
+type RankIndex struct {
+	set    *BitSet
+	blocks []uint64 // Pairs of cumulative count before the block and packed sub-counts
+	count  int
+}
+
+// NewRankIndex builds a RankIndex for b.
+func NewRankIndex(b *BitSet) *RankIndex {
+	nblocks := (len(b.words) + 7) / 8
+	idx := &RankIndex{set: b, blocks: make([]uint64, 0, 2*nblocks)}
+	total := uint64(0)
+	for blk := range nblocks {
+		var sub, inBlock uint64
+		for j := range 8 {
+			i := blk*8 + j
+			if i >= len(b.words) {
+				break
+			}
+			if j > 0 {
+				sub = rankSubCounts.UpdateAt(sub, j-1, inBlock)
+			}
+			inBlock += uint64(bits.OnesCount64(b.words[i]))
+		}
+		idx.blocks = append(idx.blocks, total, sub)
+		total += inBlock
+	}
+	idx.count = int(total)
+	return idx
+}
+
+// Count returns the number of set bits.
+func (idx *RankIndex) Count() int {
+	return idx.count
+}
+
+// Rank returns the number of set bits at positions below i.
+func (idx *RankIndex) Rank(i uint) int {
+	if i >= idx.set.n {
+		return idx.count
+	}
+	word := i / 64
+	rank := idx.blocks[2*(word/8)] + idx.subCount(word)
+	if i%64 != 0 {
+		rank += uint64(bits.OnesCount64(idx.set.words[word] & lowBits(i%64)))
+	}
+	return int(rank)
+}
+
+// subCount returns the number of set bits before a word within its block.
+func (idx *RankIndex) subCount(word uint) uint64 {
+	if word%8 == 0 {
+		return 0
+	}
+	return rankSubCounts.DecodeAt(idx.blocks[2*(word/8)+1], int(word%8)-1)
+}
+
+// Select returns the position of the set bit with rank k, that is the (k+1)-th set bit.
+// The second return value is false if fewer than k+1 bits are set.
+func (idx *RankIndex) Select(k int) (uint, bool) {
+	if k < 0 || k >= idx.count {
+		return 0, false
+	}
+	// Find the last block starting at or below rank k.
+	blk := sort.Search(len(idx.blocks)/2, func(b int) bool {
+		return idx.blocks[2*b] > uint64(k)
+	}) - 1
+	rem := uint64(k) - idx.blocks[2*blk]
+	word := uint(blk) * 8
+	for j := uint(7); j > 0; j-- {
+		if w := uint(blk)*8 + j; w < uint(len(idx.set.words)) && idx.subCount(w) <= rem {
+			word = w
+			break
+		}
+	}
+	rem -= idx.subCount(word)
+	return word*64 + selectInWord(idx.set.words[word], int(rem)), true
+}
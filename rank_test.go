@@ -0,0 +1,63 @@
+package bitfield
+
+import "testing"
+
+func rankTestSet() *BitSet {
+	b := NewBitSet(2000)
+	for i := uint(0); i < 2000; i++ {
+		if i%7 == 0 || i%13 == 5 || (i > 700 && i < 900) {
+			b.Set(i)
+		}
+	}
+	return b
+}
+
+func TestBitSet_RankSelect(t *testing.T) {
+	b := rankTestSet()
+	idx := NewRankIndex(b)
+	if idx.Count() != b.Count() {
+		t.Fatalf("RankIndex.Count() = %d, want %d", idx.Count(), b.Count())
+	}
+
+	rank := 0
+	for i := uint(0); i <= b.Len(); i++ {
+		if got := b.Rank(i); got != rank {
+			t.Fatalf("BitSet.Rank(%d) = %d, want %d", i, got, rank)
+		}
+		if got := idx.Rank(i); got != rank {
+			t.Fatalf("RankIndex.Rank(%d) = %d, want %d", i, got, rank)
+		}
+		if b.Test(i) {
+			if got, ok := b.Select(rank); !ok || got != i {
+				t.Fatalf("BitSet.Select(%d) = %d, %v, want %d", rank, got, ok, i)
+			}
+			if got, ok := idx.Select(rank); !ok || got != i {
+				t.Fatalf("RankIndex.Select(%d) = %d, %v, want %d", rank, got, ok, i)
+			}
+			rank++
+		}
+	}
+	if got := idx.Rank(5000); got != rank {
+		t.Errorf("RankIndex.Rank beyond Len = %d, want %d", got, rank)
+	}
+
+	for _, k := range []int{-1, rank} {
+		if _, ok := b.Select(k); ok {
+			t.Errorf("BitSet.Select(%d) succeeded, want false", k)
+		}
+		if _, ok := idx.Select(k); ok {
+			t.Errorf("RankIndex.Select(%d) succeeded, want false", k)
+		}
+	}
+}
+
+func TestRankIndex_Empty(t *testing.T) {
+	var b BitSet
+	idx := NewRankIndex(&b)
+	if idx.Rank(10) != 0 || idx.Count() != 0 {
+		t.Errorf("empty RankIndex = rank %d, count %d", idx.Rank(10), idx.Count())
+	}
+	if _, ok := idx.Select(0); ok {
+		t.Error("Select(0) on empty set succeeded")
+	}
+}
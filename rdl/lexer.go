@@ -0,0 +1,159 @@
+package rdl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tokenKind classifies the tokens of a SystemRDL source.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokPunct // One of { } ; = [ ] : @ , += -> and other single characters
+)
+
+// token is a lexical token with the line it starts on.
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of file"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lex splits src into tokens, dropping comments and whitespace.
+func lex(src string) ([]token, error) {
+	var toks []token
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '"':
+			startLine := line
+			i++
+			var b strings.Builder
+			for ; i < len(src) && src[i] != '"'; i++ {
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+				}
+				if src[i] == '\n' {
+					line++
+				}
+				b.WriteByte(src[i])
+			}
+			if i >= len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", startLine)
+			}
+			toks = append(toks, token{tokString, b.String(), startLine})
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			toks = append(toks, token{tokIdent, src[start:i], line})
+		case isDigit(c) || c == '\'':
+			start := i
+			for i < len(src) && (isIdentPart(src[i]) || src[i] == '\'') {
+				i++
+			}
+			toks = append(toks, token{tokNumber, src[start:i], line})
+		case strings.HasPrefix(src[i:], "+=") || strings.HasPrefix(src[i:], "%=") || strings.HasPrefix(src[i:], "->"):
+			toks = append(toks, token{tokPunct, src[i : i+2], line})
+			i += 2
+		default:
+			toks = append(toks, token{tokPunct, string(c), line})
+			i++
+		}
+	}
+	return append(toks, token{tokEOF, "", line}), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// parseNumber parses a SystemRDL number: decimal, 0x hexadecimal, or a
+// Verilog-style literal such as 4'b1010, 32'h0000_00FF or 'd10.
+// Underscores are ignored.
+func parseNumber(s string) (uint64, error) {
+	s = strings.ReplaceAll(s, "_", "")
+	if width, rest, ok := strings.Cut(s, "'"); ok {
+		if len(rest) < 2 {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		var base int
+		switch rest[0] {
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		case 'd', 'D':
+			base = 10
+		case 'h', 'H':
+			base = 16
+		default:
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		v, err := strconv.ParseUint(rest[1:], base, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		if width != "" {
+			w, err := strconv.ParseUint(width, 10, 8)
+			if err != nil || w == 0 || w > 64 {
+				return 0, fmt.Errorf("invalid width in number %q", s)
+			}
+			if w < 64 && v>>w != 0 {
+				return 0, fmt.Errorf("number %q does not fit in %d bits", s, w)
+			}
+		}
+		return v, nil
+	}
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s, base = s[2:], 16
+	}
+	v, err := strconv.ParseUint(s, base, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
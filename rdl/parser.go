@@ -0,0 +1,380 @@
+package rdl
+
+import "fmt"
+
+// component is a component definition: an addrmap, regfile, reg or field.
+// The root scope of a file is a component of kind "root".
+type component struct {
+	kind     string
+	name     string // Type name, empty for anonymous definitions
+	line     int
+	props    map[string]value
+	defaults map[string]value // Default property assignments for nested definitions
+	types    map[string]*component
+	enums    map[string]*enumDef
+	insts    []*instance
+	order    []*component // Named definitions in source order
+	parent   *component   // Lexically enclosing scope
+}
+
+// value is the right-hand side of a property assignment.
+// Properties assigned without a value, such as "rclr;", hold the identifier true.
+type value struct {
+	tok token
+	num uint64 // Numeric value, valid if tok.kind is tokNumber
+}
+
+// instance is an instantiation of a component.
+type instance struct {
+	comp  *component
+	name  string
+	line  int
+	dims  []uint64 // Array dimensions of registers and register files
+	rng   []uint64 // Bit range [msb:lsb] or width [n] of a field
+	reset *uint64  // Field reset value given with =
+	at    *uint64  // Address given with @, or field lsb
+	inc   *uint64  // Array stride given with +=
+	align *uint64  // Address alignment given with %=
+}
+
+// enumDef is an enumeration declared with the enum keyword.
+type enumDef struct {
+	name    string
+	entries []enumEntry
+}
+
+// enumEntry is a single value of an enumeration.
+type enumEntry struct {
+	name  string
+	value uint64
+}
+
+func newComponent(kind, name string, line int, parent *component) *component {
+	return &component{
+		kind:     kind,
+		name:     name,
+		line:     line,
+		props:    make(map[string]value),
+		defaults: make(map[string]value),
+		types:    make(map[string]*component),
+		enums:    make(map[string]*enumDef),
+		parent:   parent,
+	}
+}
+
+// lookupType finds a component type by name in scope or its enclosing scopes.
+func (c *component) lookupType(name string) *component {
+	for s := c; s != nil; s = s.parent {
+		if t, ok := s.types[name]; ok {
+			return t
+		}
+	}
+	return nil
+}
+
+// lookupEnum finds an enumeration by name in scope or its enclosing scopes.
+func (c *component) lookupEnum(name string) *enumDef {
+	for s := c; s != nil; s = s.parent {
+		if e, ok := s.enums[name]; ok {
+			return e
+		}
+	}
+	return nil
+}
+
+// prop returns a property of the component, falling back to the default
+// assignments of the enclosing scopes.
+func (c *component) prop(name string) (value, bool) {
+	if v, ok := c.props[name]; ok {
+		return v, true
+	}
+	for s := c.parent; s != nil; s = s.parent {
+		if v, ok := s.defaults[name]; ok {
+			return v, true
+		}
+	}
+	return value{}, false
+}
+
+// parser is a recursive descent parser over the tokens of a file.
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.toks) {
+		return p.toks[len(p.toks)-1]
+	}
+	return p.toks[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is reports whether the next token is the punctuation or keyword s.
+func (p *parser) is(s string) bool {
+	t := p.peek()
+	return (t.kind == tokPunct || t.kind == tokIdent) && t.text == s
+}
+
+// expect consumes the punctuation or keyword s.
+func (p *parser) expect(s string) error {
+	if t := p.next(); (t.kind != tokPunct && t.kind != tokIdent) || t.text != s {
+		return fmt.Errorf("line %d: expected %q, found %v", t.line, s, t)
+	}
+	return nil
+}
+
+func (p *parser) ident() (token, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return t, fmt.Errorf("line %d: expected identifier, found %v", t.line, t)
+	}
+	return t, nil
+}
+
+func (p *parser) number() (uint64, error) {
+	t := p.next()
+	if t.kind != tokNumber {
+		return 0, fmt.Errorf("line %d: expected number, found %v", t.line, t)
+	}
+	n, err := parseNumber(t.text)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: %w", t.line, err)
+	}
+	return n, nil
+}
+
+// componentKinds are the component types that can be defined.
+var componentKinds = map[string]bool{"addrmap": true, "regfile": true, "reg": true, "field": true}
+
+// parseBody parses the statements of a scope up to its closing brace or the end of file.
+func (p *parser) parseBody(scope *component) error {
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokEOF:
+			if scope.kind != "root" {
+				return fmt.Errorf("line %d: missing '}' for %s defined on line %d", t.line, scope.kind, scope.line)
+			}
+			return nil
+		case t.kind == tokPunct && t.text == "}":
+			if scope.kind == "root" {
+				return fmt.Errorf("line %d: unexpected '}'", t.line)
+			}
+			return nil
+		case t.kind != tokIdent:
+			return fmt.Errorf("line %d: unexpected %v", t.line, t)
+		case t.text == "enum":
+			if err := p.parseEnum(scope); err != nil {
+				return err
+			}
+		case componentKinds[t.text]:
+			if err := p.parseDefinition(scope); err != nil {
+				return err
+			}
+		case t.text == "default":
+			p.next()
+			if err := p.parseAssignment(scope.defaults); err != nil {
+				return err
+			}
+		case t.text == "external" || t.text == "internal":
+			p.next() // Instantiation modifiers do not affect the register layout
+		case p.peekAt(1).kind == tokPunct && p.peekAt(1).text == "->":
+			return fmt.Errorf("line %d: dynamic property assignments are not supported", t.line)
+		case p.peekAt(1).kind == tokIdent:
+			p.next()
+			typ := scope.lookupType(t.text)
+			if typ == nil {
+				return fmt.Errorf("line %d: unknown component type %q", t.line, t.text)
+			}
+			if err := p.parseInstances(scope, typ); err != nil {
+				return err
+			}
+		default:
+			if err := p.parseAssignment(scope.props); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseDefinition parses a component definition with optional instances.
+func (p *parser) parseDefinition(scope *component) error {
+	kw := p.next()
+	name := ""
+	if p.peek().kind == tokIdent {
+		name = p.next().text
+	}
+	c := newComponent(kw.text, name, kw.line, scope)
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	if err := p.parseBody(c); err != nil {
+		return err
+	}
+	if err := p.expect("}"); err != nil {
+		return err
+	}
+	if name != "" {
+		if _, ok := scope.types[name]; ok {
+			return fmt.Errorf("line %d: duplicate definition of %q", kw.line, name)
+		}
+		scope.types[name] = c
+		scope.order = append(scope.order, c)
+	}
+	if p.is(";") {
+		p.next()
+		if name == "" {
+			return fmt.Errorf("line %d: anonymous %s is never instantiated", kw.line, kw.text)
+		}
+		return nil
+	}
+	for p.is("external") || p.is("internal") {
+		p.next()
+	}
+	return p.parseInstances(scope, c)
+}
+
+// parseInstances parses a comma-separated list of instances of typ ending in ';'.
+func (p *parser) parseInstances(scope, typ *component) error {
+	for {
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		inst := &instance{comp: typ, name: name.text, line: name.line}
+		for p.is("[") {
+			p.next()
+			a, err := p.number()
+			if err != nil {
+				return err
+			}
+			dim := []uint64{a}
+			if p.is(":") {
+				p.next()
+				b, err := p.number()
+				if err != nil {
+					return err
+				}
+				dim = append(dim, b)
+			}
+			if err := p.expect("]"); err != nil {
+				return err
+			}
+			if typ.kind == "field" {
+				if inst.rng != nil {
+					return fmt.Errorf("line %d: field %s has more than one bit range", name.line, name.text)
+				}
+				inst.rng = dim
+			} else {
+				if len(dim) != 1 {
+					return fmt.Errorf("line %d: array %s must use [count] dimensions", name.line, name.text)
+				}
+				inst.dims = append(inst.dims, a)
+			}
+		}
+		for _, op := range []struct {
+			tok string
+			dst **uint64
+		}{{"=", &inst.reset}, {"@", &inst.at}, {"+=", &inst.inc}, {"%=", &inst.align}} {
+			if p.is(op.tok) {
+				p.next()
+				n, err := p.number()
+				if err != nil {
+					return err
+				}
+				*op.dst = &n
+			}
+		}
+		scope.insts = append(scope.insts, inst)
+		if p.is(",") {
+			p.next()
+			continue
+		}
+		return p.expect(";")
+	}
+}
+
+// parseAssignment parses "name;" or "name = value;" into props.
+func (p *parser) parseAssignment(props map[string]value) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	v := value{tok: token{tokIdent, "true", name.line}}
+	if p.is("=") {
+		p.next()
+		t := p.next()
+		switch t.kind {
+		case tokNumber:
+			n, err := parseNumber(t.text)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", t.line, err)
+			}
+			v = value{tok: t, num: n}
+		case tokIdent, tokString:
+			v = value{tok: t}
+		default:
+			return fmt.Errorf("line %d: invalid value %v for property %s", t.line, t, name.text)
+		}
+	}
+	props[name.text] = v
+	return p.expect(";")
+}
+
+// parseEnum parses an enum definition.
+func (p *parser) parseEnum(scope *component) error {
+	p.next()
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	e := &enumDef{name: name.text}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	var next uint64
+	for !p.is("}") {
+		entry, err := p.ident()
+		if err != nil {
+			return err
+		}
+		v := next
+		if p.is("=") {
+			p.next()
+			if v, err = p.number(); err != nil {
+				return err
+			}
+		}
+		if p.is("{") {
+			// Entry properties such as desc and name are not needed for the layout.
+			p.next()
+			for !p.is("}") {
+				if err := p.parseAssignment(make(map[string]value)); err != nil {
+					return err
+				}
+			}
+			p.next()
+		}
+		if err := p.expect(";"); err != nil {
+			return err
+		}
+		e.entries = append(e.entries, enumEntry{name: entry.text, value: v})
+		next = v + 1
+	}
+	p.next()
+	if err := p.expect(";"); err != nil {
+		return err
+	}
+	scope.enums[e.name] = e
+	return nil
+}
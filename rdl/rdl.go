@@ -0,0 +1,321 @@
+// Package rdl imports register descriptions written in SystemRDL 2.0.
+//
+// Parse reads the top-level address map of a SystemRDL file. The address map
+// can then be converted into a bitfield.RegisterMap with RegisterMap, carrying
+// over field access types, side effects, reset values and enumerations.
+//
+// The importer covers the subset of SystemRDL used to describe register
+// layouts: addrmap, regfile, reg and field definitions, named and anonymous,
+// with default property assignments, enums, arrays and the @, += and %=
+// address operators. Register files are flattened, prefixing the names of
+// their registers with the register file name and an underscore, and array
+// elements are named with an underscore and their index. Dynamic property
+// assignments, parameters and Perl preprocessing are not supported.
+package rdl
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// AddrMap is an elaborated address map.
+type AddrMap struct {
+	Name        string
+	Description string
+	Registers   []*Register // Registers in address order
+}
+
+// Register is a register of an address map.
+type Register struct {
+	Name        string
+	Description string
+	Offset      uint64 // Byte address relative to the address map
+	Size        uint   // Width in bits
+	ResetValue  uint64 // Combined reset values of the fields
+	Fields      []*Field
+}
+
+// Field is a bit field of a register.
+type Field struct {
+	Name        string
+	Description string
+	Shift       uint
+	Size        uint
+	Access      bitfield.Access
+	SideEffect  bitfield.SideEffect
+	ResetValue  uint64
+	Enum        map[uint64]string // Names of the encode enumeration, nil if none
+}
+
+// Parse reads a SystemRDL file and elaborates its top-level address map,
+// which is the last addrmap defined at the root of the file.
+func Parse(r io.Reader) (*AddrMap, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("rdl: %w", err)
+	}
+	toks, err := lex(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("rdl: %w", err)
+	}
+	p := &parser{toks: toks}
+	root := newComponent("root", "", 1, nil)
+	if err := p.parseBody(root); err != nil {
+		return nil, fmt.Errorf("rdl: %w", err)
+	}
+	var top *component
+	for _, c := range root.order {
+		if c.kind == "addrmap" {
+			top = c
+		}
+	}
+	if top == nil {
+		return nil, fmt.Errorf("rdl: no addrmap defined")
+	}
+	m := &AddrMap{Name: top.name, Description: stringProp(top, "desc")}
+	if _, err := elaborateBlock(top, 0, "", &m.Registers); err != nil {
+		return nil, fmt.Errorf("rdl: addrmap %s: %w", top.name, err)
+	}
+	slices.SortStableFunc(m.Registers, func(a, b *Register) int {
+		switch {
+		case a.Offset < b.Offset:
+			return -1
+		case a.Offset > b.Offset:
+			return 1
+		}
+		return 0
+	})
+	return m, nil
+}
+
+// RegisterMap converts the address map into a RegisterMap, with field access
+// types, side effects and enumerations applied.
+// Returns an error if a register is not 32 bits wide or the registers or fields overlap.
+func (m *AddrMap) RegisterMap() (*bitfield.RegisterMap[uint32], error) {
+	rm := bitfield.NewRegisterMap[uint32]()
+	for _, r := range m.Registers {
+		if r.Size != 32 {
+			return nil, fmt.Errorf("%s.%s: unsupported register size %d", m.Name, r.Name, r.Size)
+		}
+		layout := bitfield.NewLayout[uint32]()
+		for _, f := range r.Fields {
+			if err := layout.Add(f.Name, f.Shift, f.Size); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", m.Name, r.Name, err)
+			}
+			if f.Enum != nil {
+				if err := layout.SetEnum(f.Name, f.Enum); err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", m.Name, r.Name, err)
+				}
+			}
+		}
+		reg := bitfield.NewRegister(r.Name, layout, uint32(r.ResetValue))
+		for _, f := range r.Fields {
+			if err := reg.SetAccess(f.Name, f.Access); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", m.Name, r.Name, err)
+			}
+			if err := reg.SetSideEffect(f.Name, f.SideEffect); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", m.Name, r.Name, err)
+			}
+		}
+		if err := rm.Add(r.Offset, reg); err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Name, err)
+		}
+	}
+	return rm, nil
+}
+
+// elaborateBlock appends the registers of an addrmap or regfile placed at base
+// to regs, and returns the size of the block in bytes.
+func elaborateBlock(c *component, base uint64, prefix string, regs *[]*Register) (uint64, error) {
+	var next, end uint64
+	for _, inst := range c.insts {
+		var size uint64
+		switch inst.comp.kind {
+		case "reg":
+			width, err := regWidth(inst.comp)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: %w", inst.line, err)
+			}
+			size = uint64(width / 8)
+		case "regfile", "addrmap":
+			var err error
+			if size, err = elaborateBlock(inst.comp, 0, "", new([]*Register)); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("line %d: %s %s cannot be placed in %s", inst.line, inst.comp.kind, inst.name, c.kind)
+		}
+
+		offset := next
+		align := uint64(1)
+		for align < size {
+			align <<= 1 // Blocks are aligned to their size rounded up to a power of two
+		}
+		if inst.align != nil {
+			align = *inst.align
+		}
+		if inst.at != nil {
+			offset = *inst.at
+		} else if align > 0 && offset%align != 0 {
+			offset += align - offset%align
+		}
+		stride := size
+		if inst.inc != nil {
+			stride = *inst.inc
+		}
+
+		names := []string{inst.name}
+		for _, dim := range inst.dims {
+			var expanded []string
+			for _, n := range names {
+				for i := range dim {
+					expanded = append(expanded, fmt.Sprintf("%s_%d", n, i))
+				}
+			}
+			names = expanded
+		}
+		for i, name := range names {
+			at := base + offset + uint64(i)*stride
+			if inst.comp.kind == "reg" {
+				r, err := elaborateRegister(inst.comp, prefix+name, at)
+				if err != nil {
+					return 0, fmt.Errorf("register %s: %w", prefix+name, err)
+				}
+				*regs = append(*regs, r)
+			} else if _, err := elaborateBlock(inst.comp, at, prefix+name+"_", regs); err != nil {
+				return 0, fmt.Errorf("%s %s: %w", inst.comp.kind, prefix+name, err)
+			}
+		}
+		next = offset + uint64(len(names)-1)*stride + size
+		end = max(end, next)
+	}
+	return end, nil
+}
+
+// regWidth returns the regwidth property of a register, 32 by default.
+func regWidth(c *component) (uint, error) {
+	v, ok := c.prop("regwidth")
+	if !ok {
+		return 32, nil
+	}
+	switch v.num {
+	case 8, 16, 32, 64:
+		return uint(v.num), nil
+	}
+	return 0, fmt.Errorf("unsupported regwidth %s", v.tok.text)
+}
+
+// elaborateRegister builds the register defined by c at the given address.
+func elaborateRegister(c *component, name string, offset uint64) (*Register, error) {
+	width, err := regWidth(c)
+	if err != nil {
+		return nil, err
+	}
+	r := &Register{Name: name, Description: stringProp(c, "desc"), Offset: offset, Size: width}
+	var next uint
+	for _, inst := range c.insts {
+		if inst.comp.kind != "field" {
+			return nil, fmt.Errorf("line %d: %s %s cannot be placed in a register", inst.line, inst.comp.kind, inst.name)
+		}
+		if len(inst.dims) > 0 {
+			return nil, fmt.Errorf("line %d: field arrays are not supported", inst.line)
+		}
+		f, err := elaborateField(inst, next)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", inst.name, err)
+		}
+		if f.Shift+f.Size > width {
+			return nil, fmt.Errorf("field %s: bits %d:%d exceed regwidth %d", f.Name, f.Shift+f.Size-1, f.Shift, width)
+		}
+		r.ResetValue |= f.ResetValue << f.Shift
+		r.Fields = append(r.Fields, f)
+		next = f.Shift + f.Size
+	}
+	return r, nil
+}
+
+// elaborateField builds a field instance. Fields without an explicit position are placed at next.
+func elaborateField(inst *instance, next uint) (*Field, error) {
+	c := inst.comp
+	f := &Field{Name: inst.name, Description: stringProp(c, "desc"), Shift: next, Size: 1}
+	if v, ok := c.prop("fieldwidth"); ok {
+		f.Size = uint(v.num)
+	}
+	switch len(inst.rng) {
+	case 1:
+		f.Size = uint(inst.rng[0])
+	case 2:
+		hi, lo := max(inst.rng[0], inst.rng[1]), min(inst.rng[0], inst.rng[1])
+		f.Shift, f.Size = uint(lo), uint(hi-lo+1)
+	}
+	if len(inst.rng) != 2 && inst.at != nil {
+		f.Shift = uint(*inst.at)
+	}
+	if f.Size == 0 || f.Size > 64 {
+		return nil, fmt.Errorf("line %d: invalid width %d", inst.line, f.Size)
+	}
+
+	if inst.reset != nil {
+		f.ResetValue = *inst.reset
+	} else if v, ok := c.prop("reset"); ok {
+		f.ResetValue = v.num
+	}
+	if f.Size < 64 && f.ResetValue>>f.Size != 0 {
+		return nil, fmt.Errorf("line %d: reset value %#x does not fit in %d bits", inst.line, f.ResetValue, f.Size)
+	}
+
+	switch sw := identProp(c, "sw", "rw"); sw {
+	case "rw", "rw1", "wr":
+		f.Access = bitfield.ReadWrite
+	case "r", "na":
+		f.Access = bitfield.ReadOnly
+	case "w", "w1":
+		f.Access = bitfield.WriteOnly
+	default:
+		return nil, fmt.Errorf("line %d: unknown sw access %q", inst.line, sw)
+	}
+
+	switch {
+	case identProp(c, "onread", "") == "rclr" || identProp(c, "rclr", "") == "true":
+		f.SideEffect = bitfield.ReadToClear
+	case identProp(c, "onwrite", "") == "woclr" || identProp(c, "woclr", "") == "true":
+		f.SideEffect = bitfield.WriteOneToClear
+	case identProp(c, "onwrite", "") == "woset" || identProp(c, "woset", "") == "true":
+		f.SideEffect = bitfield.WriteOneToSet
+	case identProp(c, "singlepulse", "") == "true":
+		f.SideEffect = bitfield.WriteLatch
+	}
+
+	if name := identProp(c, "encode", ""); name != "" {
+		e := c.lookupEnum(name)
+		if e == nil {
+			return nil, fmt.Errorf("line %d: unknown enum %q", inst.line, name)
+		}
+		f.Enum = make(map[uint64]string, len(e.entries))
+		for _, entry := range e.entries {
+			f.Enum[entry.value] = entry.name
+		}
+	}
+	return f, nil
+}
+
+// stringProp returns a string property of c, or "" if it is not set.
+func stringProp(c *component, name string) string {
+	v, ok := c.prop(name)
+	if !ok || v.tok.kind != tokString {
+		return ""
+	}
+	return v.tok.text
+}
+
+// identProp returns an identifier property of c, or def if it is not set.
+func identProp(c *component, name, def string) string {
+	v, ok := c.prop(name)
+	if !ok || v.tok.kind != tokIdent {
+		return def
+	}
+	return v.tok.text
+}
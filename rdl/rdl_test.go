@@ -0,0 +1,201 @@
+package rdl
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+func parseExample(t *testing.T) *AddrMap {
+	t.Helper()
+	f, err := os.Open("testdata/example.rdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return m
+}
+
+func TestParse(t *testing.T) {
+	m := parseExample(t)
+	if m.Name != "uart" || m.Description != "Universal asynchronous receiver/transmitter" {
+		t.Errorf("addrmap = %s, description %q", m.Name, m.Description)
+	}
+
+	var names []string
+	var offsets []uint64
+	for _, r := range m.Registers {
+		names = append(names, r.Name)
+		offsets = append(offsets, r.Offset)
+	}
+	wantNames := []string{"ctrl", "status", "fifo_0", "fifo_1", "fifo_2", "fifo_3", "ch_0_cfg", "ch_0_cnt", "ch_1_cfg", "ch_1_cnt"}
+	if !slices.Equal(names, wantNames) {
+		t.Errorf("registers = %v, want %v", names, wantNames)
+	}
+	wantOffsets := []uint64{0x0, 0x4, 0x10, 0x14, 0x18, 0x1C, 0x100, 0x104, 0x110, 0x114}
+	if !slices.Equal(offsets, wantOffsets) {
+		t.Errorf("offsets = %#x, want %#x", offsets, wantOffsets)
+	}
+
+	ctrl := m.Registers[0]
+	if ctrl.Size != 32 || ctrl.ResetValue != 0x5 || ctrl.Description != "Control register" {
+		t.Errorf("ctrl = size %d, reset %#x, description %q", ctrl.Size, ctrl.ResetValue, ctrl.Description)
+	}
+	fields := []struct {
+		name        string
+		shift, size uint
+		access      bitfield.Access
+		effect      bitfield.SideEffect
+	}{
+		{"en", 0, 1, bitfield.ReadWrite, bitfield.NoSideEffect},
+		{"parity", 1, 2, bitfield.ReadWrite, bitfield.NoSideEffect},
+		{"stop", 4, 4, bitfield.ReadWrite, bitfield.NoSideEffect},
+		{"flush", 31, 1, bitfield.WriteOnly, bitfield.WriteLatch},
+	}
+	for i, want := range fields {
+		f := ctrl.Fields[i]
+		if f.Name != want.name || f.Shift != want.shift || f.Size != want.size || f.Access != want.access || f.SideEffect != want.effect {
+			t.Errorf("ctrl field %d = %s %d/%d %v %v, want %s %d/%d %v %v", i,
+				f.Name, f.Shift, f.Size, f.Access, f.SideEffect,
+				want.name, want.shift, want.size, want.access, want.effect)
+		}
+	}
+	wantEnum := map[uint64]string{0: "NONE", 1: "EVEN", 2: "ODD"}
+	if !maps.Equal(ctrl.Fields[1].Enum, wantEnum) {
+		t.Errorf("parity enum = %v, want %v", ctrl.Fields[1].Enum, wantEnum)
+	}
+
+	status := m.Registers[1]
+	fields = []struct {
+		name        string
+		shift, size uint
+		access      bitfield.Access
+		effect      bitfield.SideEffect
+	}{
+		{"overrun", 0, 1, bitfield.ReadOnly, bitfield.ReadToClear},
+		{"busy", 1, 1, bitfield.ReadOnly, bitfield.NoSideEffect},
+		{"irq", 2, 8, bitfield.ReadWrite, bitfield.WriteOneToClear},
+	}
+	for i, want := range fields {
+		f := status.Fields[i]
+		if f.Name != want.name || f.Shift != want.shift || f.Size != want.size || f.Access != want.access || f.SideEffect != want.effect {
+			t.Errorf("status field %d = %s %d/%d %v %v, want %s %d/%d %v %v", i,
+				f.Name, f.Shift, f.Size, f.Access, f.SideEffect,
+				want.name, want.shift, want.size, want.access, want.effect)
+		}
+	}
+	if got := m.Registers[6].Fields[0].SideEffect; got != bitfield.WriteOneToSet {
+		t.Errorf("ch_0_cfg.start side effect = %v, want W1S", got)
+	}
+}
+
+func TestAddrMap_RegisterMap(t *testing.T) {
+	m, err := parseExample(t).RegisterMap()
+	if err != nil {
+		t.Fatalf("RegisterMap: %v", err)
+	}
+	ctrl, ok := m.ByName("ctrl")
+	if !ok {
+		t.Fatal("ctrl not found")
+	}
+	bf, _ := ctrl.Layout.Field("parity")
+	if got := bf.ValueString(bf.Decode(ctrl.Value())); got != "ODD" {
+		t.Errorf("ctrl.parity at reset = %s, want ODD", got)
+	}
+	if ctrl.Access("flush") != bitfield.WriteOnly || ctrl.SideEffect("flush") != bitfield.WriteLatch {
+		t.Errorf("ctrl.flush = %v %v, want WO latch", ctrl.Access("flush"), ctrl.SideEffect("flush"))
+	}
+	status, _ := m.ByOffset(0x4)
+	if err := status.Set("busy", 1); err == nil {
+		t.Error("Set(status.busy) succeeded on read-only field, want error")
+	}
+	if _, ok := m.ByName("ch_1_cnt"); !ok {
+		t.Error("ch_1_cnt not found")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"no addrmap", `reg r { field {} f; };`},
+		{"unterminated definition", `addrmap a { reg { field {} f; } r;`},
+		{"unknown type", `addrmap a { foo_r r; };`},
+		{"unknown enum", `addrmap a { reg { field { encode = e; } f[2]; } r; };`},
+		{"unknown access", `addrmap a { reg { field { sw = rx; } f; } r; };`},
+		{"field too wide", `addrmap a { reg { field {} f[40]; } r; };`},
+		{"field beyond regwidth", `addrmap a { reg { regwidth = 8; field {} f[9:8]; } r; };`},
+		{"reset too large", `addrmap a { reg { field {} f[1:0] = 4; } r; };`},
+		{"bad regwidth", `addrmap a { reg { regwidth = 24; field {} f; } r; };`},
+		{"field in addrmap", `addrmap a { field {} f; };`},
+		{"dynamic assignment", `addrmap a { reg { field {} f; } r; r.f -> reset = 1; };`},
+		{"unterminated comment", `addrmap a { /* };`},
+		{"bad number", `addrmap a { reg { field {} f = 4'hz; } r; };`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.src)); err == nil {
+				t.Errorf("Parse(%q) succeeded, want error", tt.src)
+			}
+		})
+	}
+}
+
+func TestAddrMap_RegisterMapErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *AddrMap
+	}{
+		{"64-bit register", &AddrMap{Name: "A", Registers: []*Register{{Name: "R", Size: 64}}}},
+		{"overlapping fields", &AddrMap{Name: "A", Registers: []*Register{{Name: "R", Size: 32, Fields: []*Field{
+			{Name: "F", Shift: 0, Size: 4}, {Name: "G", Shift: 2, Size: 4},
+		}}}}},
+		{"overlapping registers", &AddrMap{Name: "A", Registers: []*Register{
+			{Name: "R", Size: 32, Offset: 0}, {Name: "S", Size: 32, Offset: 2},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.m.RegisterMap(); err == nil {
+				t.Error("RegisterMap() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"42", 42, false},
+		{"0x2A", 42, false},
+		{"32'h2a", 42, false},
+		{"6'b10_1010", 42, false},
+		{"'d42", 42, false},
+		{"8'o52", 42, false},
+		{"2'd7", 0, true},
+		{"4'hz", 0, true},
+		{"010", 10, false},
+		{"0x", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNumber(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseNumber(%q) = %v, %v, want %v, err = %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,430 @@
+// Package regcsv imports register maps kept in spreadsheets and exported as CSV.
+//
+// Each row of the file describes one field, or a register when the field
+// column is empty. Which columns hold which properties is configured with a
+// Format, so existing spreadsheets can be imported without reshaping them:
+//
+//	register,offset,field,bits,access,reset,enum,description
+//	CTRL,0x00,,,,,,Control register
+//	,,EN,0,RW,1,,Enable
+//	,,MODE,2:1,RW,0,0=Idle;1=Run;2=Sleep,Operating mode
+//	STATUS,0x04,BUSY,0,RO,,,
+//
+// A register name or offset left empty continues the register of the previous
+// row, as when cells are merged in the spreadsheet. Validation errors name the
+// row they were found on, numbered as in the spreadsheet with the header as
+// row 1.
+package regcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Format describes the layout of a CSV register map. Each column field holds
+// the header of the column with that property; headers are matched without
+// regard to case or surrounding spaces, and an empty header marks a property
+// that is not present in the file.
+type Format struct {
+	Register    string // Register name, required
+	Offset      string // Register byte offset, required
+	Field       string // Field name, required
+	Bits        string // Bit range "msb:lsb", "[msb:lsb]" or a single bit
+	Shift       string // Least significant bit, used with Width instead of Bits
+	Width       string // Field width in bits
+	Size        string // Register width in bits, 32 if not present
+	Access      string // Field access: RW, RO, WO or a side effect such as W1C
+	Reset       string // Reset value of the field, or of the register on register rows
+	Enum        string // Enumerated values such as "0=Off;1=On"
+	Description string
+
+	Comma rune // Field delimiter, ',' if zero
+}
+
+// DefaultFormat is the format whose headers are the lowercase property names,
+// as in the example of the package documentation.
+var DefaultFormat = Format{
+	Register:    "register",
+	Offset:      "offset",
+	Field:       "field",
+	Bits:        "bits",
+	Size:        "size",
+	Access:      "access",
+	Reset:       "reset",
+	Enum:        "enum",
+	Description: "description",
+}
+
+// Map is an imported register map.
+type Map struct {
+	Registers []*Register // Registers in file order
+}
+
+// Register is a register of the map.
+type Register struct {
+	Name        string
+	Description string
+	Offset      uint64
+	Size        uint   // Width in bits
+	ResetValue  uint64 // Register reset value combined with the field reset values
+	Fields      []*Field
+	Row         int // Row of the first line describing the register
+}
+
+// Field is a bit field of a register.
+type Field struct {
+	Name        string
+	Description string
+	Shift       uint
+	Size        uint
+	Access      bitfield.Access
+	SideEffect  bitfield.SideEffect
+	ResetValue  uint64
+	Enum        map[uint64]string // Enumerated value names, nil if none are given
+	Row         int
+}
+
+// Parse reads a CSV register map from r using format f.
+// Rows whose cells are all empty are skipped.
+func Parse(r io.Reader, f Format) (*Map, error) {
+	cr := csv.NewReader(r)
+	if f.Comma != 0 {
+		cr.Comma = f.Comma
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("regcsv: missing header row")
+		}
+		return nil, fmt.Errorf("regcsv: %w", err)
+	}
+	cols, err := f.columns(header)
+	if err != nil {
+		return nil, fmt.Errorf("regcsv: row 1: %w", err)
+	}
+
+	m := &Map{}
+	byName := make(map[string]*Register)
+	var reg *Register
+	for row := 2; ; row++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("regcsv: %w", err)
+		}
+		rec := cols.record(record)
+		if rec.empty() {
+			continue
+		}
+		if name := rec.get(colRegister); name != "" || rec.get(colOffset) != "" {
+			if name == "" {
+				return nil, fmt.Errorf("regcsv: row %d: offset without register name", row)
+			}
+			if _, ok := byName[name]; ok {
+				return nil, fmt.Errorf("regcsv: row %d: duplicate register %q", row, name)
+			}
+			if reg, err = rec.register(row); err != nil {
+				return nil, fmt.Errorf("regcsv: row %d: register %s: %w", row, name, err)
+			}
+			byName[name] = reg
+			m.Registers = append(m.Registers, reg)
+		} else if reg == nil {
+			return nil, fmt.Errorf("regcsv: row %d: field before the first register", row)
+		}
+		if rec.get(colField) == "" {
+			if reg.Row != row {
+				return nil, fmt.Errorf("regcsv: row %d: missing field name", row)
+			}
+			continue
+		}
+		fld, err := rec.field(reg)
+		if err != nil {
+			return nil, fmt.Errorf("regcsv: row %d: field %s.%s: %w", row, reg.Name, rec.get(colField), err)
+		}
+		fld.Row = row
+		reg.ResetValue |= fld.ResetValue << fld.Shift
+		reg.Fields = append(reg.Fields, fld)
+	}
+	return m, nil
+}
+
+// RegisterMap converts the map into a RegisterMap, with field access types,
+// side effects and enumerations applied.
+// Returns an error naming the row of the offending register or field if a
+// register is not 32 bits wide or the registers or fields overlap.
+func (m *Map) RegisterMap() (*bitfield.RegisterMap[uint32], error) {
+	rm := bitfield.NewRegisterMap[uint32]()
+	for _, r := range m.Registers {
+		if r.Size != 32 {
+			return nil, fmt.Errorf("row %d: register %s: unsupported register size %d", r.Row, r.Name, r.Size)
+		}
+		layout := bitfield.NewLayout[uint32]()
+		for _, f := range r.Fields {
+			if err := layout.Add(f.Name, f.Shift, f.Size); err != nil {
+				return nil, fmt.Errorf("row %d: register %s: %w", f.Row, r.Name, err)
+			}
+			if f.Enum != nil {
+				if err := layout.SetEnum(f.Name, f.Enum); err != nil {
+					return nil, fmt.Errorf("row %d: register %s: %w", f.Row, r.Name, err)
+				}
+			}
+		}
+		reg := bitfield.NewRegister(r.Name, layout, uint32(r.ResetValue))
+		for _, f := range r.Fields {
+			if err := reg.SetAccess(f.Name, f.Access); err != nil {
+				return nil, fmt.Errorf("row %d: %w", f.Row, err)
+			}
+			if err := reg.SetSideEffect(f.Name, f.SideEffect); err != nil {
+				return nil, fmt.Errorf("row %d: %w", f.Row, err)
+			}
+		}
+		if err := rm.Add(r.Offset, reg); err != nil {
+			return nil, fmt.Errorf("row %d: %w", r.Row, err)
+		}
+	}
+	return rm, nil
+}
+
+// Column indices of the properties of a Format.
+const (
+	colRegister = iota
+	colOffset
+	colField
+	colBits
+	colShift
+	colWidth
+	colSize
+	colAccess
+	colReset
+	colEnum
+	colDescription
+	numColumns
+)
+
+// columns maps property indices to record indices, -1 for absent columns.
+type columns [numColumns]int
+
+// columns locates the columns of f in the header row.
+func (f Format) columns(header []string) (columns, error) {
+	var cols columns
+	names := [numColumns]string{f.Register, f.Offset, f.Field, f.Bits, f.Shift, f.Width, f.Size, f.Access, f.Reset, f.Enum, f.Description}
+	for i, name := range names {
+		cols[i] = -1
+		if name == "" {
+			continue
+		}
+		for j, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name)) {
+				cols[i] = j
+				break
+			}
+		}
+	}
+	for _, req := range []struct {
+		col  int
+		name string
+	}{{colRegister, f.Register}, {colOffset, f.Offset}, {colField, f.Field}} {
+		if cols[req.col] < 0 {
+			return cols, fmt.Errorf("missing column %q", req.name)
+		}
+	}
+	if cols[colBits] < 0 && (cols[colShift] < 0 || cols[colWidth] < 0) {
+		return cols, fmt.Errorf("missing bits column %q or shift and width columns", f.Bits)
+	}
+	return cols, nil
+}
+
+// record is a data row with its cells rearranged by property.
+type record [numColumns]string
+
+func (c columns) record(cells []string) record {
+	var r record
+	for i, j := range c {
+		if j >= 0 && j < len(cells) {
+			r[i] = strings.TrimSpace(cells[j])
+		}
+	}
+	return r
+}
+
+func (r *record) get(col int) string { return r[col] }
+
+func (r *record) empty() bool {
+	for _, s := range r {
+		if s != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// register builds the register declared by a row.
+func (r *record) register(row int) (*Register, error) {
+	reg := &Register{Name: r.get(colRegister), Size: 32, Row: row}
+	if r.get(colField) == "" {
+		reg.Description = r.get(colDescription)
+	}
+	offset, err := parseNumber(r.get(colOffset))
+	if err != nil {
+		return nil, fmt.Errorf("offset: %w", err)
+	}
+	reg.Offset = offset
+	if s := r.get(colSize); s != "" {
+		size, err := parseNumber(s)
+		if err != nil {
+			return nil, fmt.Errorf("size: %w", err)
+		}
+		reg.Size = uint(size)
+	}
+	if s := r.get(colReset); s != "" && r.get(colField) == "" {
+		if reg.ResetValue, err = parseNumber(s); err != nil {
+			return nil, fmt.Errorf("reset: %w", err)
+		}
+	}
+	return reg, nil
+}
+
+// field builds the field described by a row of register reg.
+func (r *record) field(reg *Register) (*Field, error) {
+	f := &Field{Name: r.get(colField), Description: r.get(colDescription)}
+	var err error
+	if s := r.get(colBits); s != "" {
+		if f.Shift, f.Size, err = parseBits(s); err != nil {
+			return nil, err
+		}
+	} else {
+		shift, err := parseNumber(r.get(colShift))
+		if err != nil {
+			return nil, fmt.Errorf("shift: %w", err)
+		}
+		width, err := parseNumber(r.get(colWidth))
+		if err != nil {
+			return nil, fmt.Errorf("width: %w", err)
+		}
+		if width == 0 {
+			return nil, fmt.Errorf("width must not be 0")
+		}
+		f.Shift, f.Size = uint(shift), uint(width)
+	}
+	if f.Shift+f.Size > reg.Size {
+		return nil, fmt.Errorf("bits %d:%d exceed the %d-bit register", f.Shift+f.Size-1, f.Shift, reg.Size)
+	}
+	if f.Access, f.SideEffect, err = parseAccess(r.get(colAccess)); err != nil {
+		return nil, err
+	}
+	if s := r.get(colReset); s != "" {
+		if f.ResetValue, err = parseNumber(s); err != nil {
+			return nil, fmt.Errorf("reset: %w", err)
+		}
+		if f.Size < 64 && f.ResetValue>>f.Size != 0 {
+			return nil, fmt.Errorf("reset value %#x does not fit in %d bits", f.ResetValue, f.Size)
+		}
+	}
+	if s := r.get(colEnum); s != "" {
+		if f.Enum, err = parseEnum(s); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// parseBits parses a bit range: "msb:lsb", "[msb:lsb]", "msb..lsb" or a single bit.
+func parseBits(s string) (shift, size uint, err error) {
+	s = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "["), "]")
+	hi, lo, ok := strings.Cut(s, ":")
+	if !ok {
+		hi, lo, ok = strings.Cut(s, "..")
+	}
+	if !ok {
+		lo = hi
+	}
+	msb, err := strconv.ParseUint(strings.TrimSpace(hi), 10, 8)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid bits %q", s)
+	}
+	lsb, err := strconv.ParseUint(strings.TrimSpace(lo), 10, 8)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid bits %q", s)
+	}
+	if msb < lsb {
+		msb, lsb = lsb, msb
+	}
+	return uint(lsb), uint(msb - lsb + 1), nil
+}
+
+// parseAccess maps an access cell to an access type and side effect.
+// An empty cell means read-write.
+func parseAccess(s string) (bitfield.Access, bitfield.SideEffect, error) {
+	switch strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), " ", "")) {
+	case "", "RW", "R/W", "READ-WRITE":
+		return bitfield.ReadWrite, bitfield.NoSideEffect, nil
+	case "RO", "R", "READ-ONLY":
+		return bitfield.ReadOnly, bitfield.NoSideEffect, nil
+	case "WO", "W", "WRITE-ONLY":
+		return bitfield.WriteOnly, bitfield.NoSideEffect, nil
+	case "W1C", "RW1C":
+		return bitfield.ReadWrite, bitfield.WriteOneToClear, nil
+	case "W1S", "RW1S":
+		return bitfield.ReadWrite, bitfield.WriteOneToSet, nil
+	case "RC", "ROC", "RCLR":
+		return bitfield.ReadOnly, bitfield.ReadToClear, nil
+	case "WP", "PULSE", "LATCH":
+		return bitfield.WriteOnly, bitfield.WriteLatch, nil
+	}
+	return 0, 0, fmt.Errorf("unknown access %q", s)
+}
+
+// parseEnum parses enumerated values such as "0=Off;1=On" or "0: Off, 1: On".
+// Entries may also be separated by newlines, as in multi-line spreadsheet cells.
+func parseEnum(s string) (map[uint64]string, error) {
+	enum := make(map[uint64]string)
+	entries := strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == ',' || r == '\n' })
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		value, name, ok := strings.Cut(entry, "=")
+		if !ok {
+			value, name, ok = strings.Cut(entry, ":")
+		}
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid enum entry %q", entry)
+		}
+		v, err := parseNumber(value)
+		if err != nil {
+			return nil, fmt.Errorf("enum entry %q: %w", entry, err)
+		}
+		if prev, dup := enum[v]; dup {
+			return nil, fmt.Errorf("enum value %d named both %q and %q", v, prev, name)
+		}
+		enum[v] = name
+	}
+	return enum, nil
+}
+
+// parseNumber parses a decimal, 0x hexadecimal or 0b binary number.
+// Underscores are ignored.
+func parseNumber(s string) (uint64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "_", "")
+	switch {
+	case s == "":
+		return 0, fmt.Errorf("missing value")
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		return strconv.ParseUint(s[2:], 16, 64)
+	case strings.HasPrefix(s, "0b"), strings.HasPrefix(s, "0B"):
+		return strconv.ParseUint(s[2:], 2, 64)
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
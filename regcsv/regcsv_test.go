@@ -0,0 +1,193 @@
+package regcsv
+
+import (
+	"maps"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+func parseExample(t *testing.T) *Map {
+	t.Helper()
+	f, err := os.Open("testdata/uart.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m, err := Parse(f, DefaultFormat)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return m
+}
+
+func TestParse(t *testing.T) {
+	m := parseExample(t)
+	if len(m.Registers) != 3 {
+		t.Fatalf("got %d registers, want 3", len(m.Registers))
+	}
+
+	ctrl := m.Registers[0]
+	if ctrl.Name != "CTRL" || ctrl.Offset != 0 || ctrl.Description != "Control register" || ctrl.Row != 2 {
+		t.Errorf("CTRL = %s at 0x%X, description %q, row %d", ctrl.Name, ctrl.Offset, ctrl.Description, ctrl.Row)
+	}
+	if ctrl.ResetValue != 0x1005 {
+		t.Errorf("CTRL reset = 0x%X, want 0x1005", ctrl.ResetValue)
+	}
+	mode := ctrl.Fields[1]
+	if mode.Shift != 1 || mode.Size != 2 || mode.Row != 4 || mode.Description != "Operating mode" {
+		t.Errorf("MODE = shift %d, size %d, row %d, description %q", mode.Shift, mode.Size, mode.Row, mode.Description)
+	}
+	wantEnum := map[uint64]string{0: "Idle", 1: "Run", 2: "Sleep"}
+	if !maps.Equal(mode.Enum, wantEnum) {
+		t.Errorf("MODE enum = %v, want %v", mode.Enum, wantEnum)
+	}
+	if div := ctrl.Fields[2]; div.Shift != 8 || div.Size != 8 {
+		t.Errorf("DIV = shift %d, size %d, want 8, 8", div.Shift, div.Size)
+	}
+
+	status := m.Registers[1]
+	if status.Offset != 4 || status.Row != 7 || len(status.Fields) != 3 {
+		t.Fatalf("STATUS = offset 0x%X, row %d with %d fields", status.Offset, status.Row, len(status.Fields))
+	}
+	for i, want := range []struct {
+		access bitfield.Access
+		effect bitfield.SideEffect
+		row    int
+	}{
+		{bitfield.ReadOnly, bitfield.NoSideEffect, 7},
+		{bitfield.ReadOnly, bitfield.ReadToClear, 8},
+		{bitfield.ReadWrite, bitfield.WriteOneToClear, 9},
+	} {
+		if f := status.Fields[i]; f.Access != want.access || f.SideEffect != want.effect || f.Row != want.row {
+			t.Errorf("STATUS.%s = %v %v row %d, want %v %v row %d", f.Name, f.Access, f.SideEffect, f.Row, want.access, want.effect, want.row)
+		}
+	}
+	if d := status.Fields[1].Description; d != "Overrun,\ncleared on read" {
+		t.Errorf("OVR description = %q", d)
+	}
+	if v := m.Registers[2].Fields[0]; v.Shift != 0 || v.Size != 8 {
+		t.Errorf("DATA.VALUE = shift %d, size %d, want 0, 8", v.Shift, v.Size)
+	}
+}
+
+func TestParse_Format(t *testing.T) {
+	src := "Addr;Reg Name;Bit Field;LSB;Width;Type\n" +
+		"0x10;CFG;EN;0;1;R/W\n" +
+		";;LEVEL;4;4;RO\n"
+	f := Format{Register: "reg name", Offset: "ADDR", Field: "bit field", Shift: "lsb", Width: "width", Access: "type", Comma: ';'}
+	m, err := Parse(strings.NewReader(src), f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	cfg := m.Registers[0]
+	if cfg.Name != "CFG" || cfg.Offset != 0x10 || len(cfg.Fields) != 2 {
+		t.Fatalf("CFG = %s at 0x%X with %d fields", cfg.Name, cfg.Offset, len(cfg.Fields))
+	}
+	if l := cfg.Fields[1]; l.Shift != 4 || l.Size != 4 || l.Access != bitfield.ReadOnly {
+		t.Errorf("LEVEL = shift %d, size %d, access %v", l.Shift, l.Size, l.Access)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	const header = "register,offset,field,bits,access,reset,enum,size\n"
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"empty", "", "missing header row"},
+		{"missing column", "register,field,bits\n", `row 1: missing column "offset"`},
+		{"missing bits", "register,offset,field\n", "row 1: missing bits column"},
+		{"field first", header + ",,EN,0\n", "row 2: field before the first register"},
+		{"missing register name", header + "R,0,A,0\n,4,B,1\n", "row 3: offset without register name"},
+		{"duplicate register", header + "R,0,A,0\nR,4,B,0\n", `row 3: duplicate register "R"`},
+		{"bad offset", header + "R,zz,A,0\n", "row 2: register R: offset"},
+		{"bad bits", header + "R,0,A,x:0\n", "row 2: field R.A: invalid bits"},
+		{"bits beyond register", header + "R,0,A,32\n", "row 2: field R.A: bits 32:32 exceed the 32-bit register"},
+		{"bad access", header + "R,0,A,0,RX\n", `row 2: field R.A: unknown access "RX"`},
+		{"reset too large", header + "R,0,\n,,A,1:0,,4\n", "row 3: field R.A: reset value 0x4 does not fit in 2 bits"},
+		{"bad enum", header + "R,0,A,1:0,,,0=On;Off\n", `row 2: field R.A: invalid enum entry "Off"`},
+		{"duplicate enum value", header + "R,0,A,1:0,,,0=On;0=Off\n", "row 2: field R.A: enum value 0 named both"},
+		{"missing field name", header + "R,0,A,0\n,,,1,RW\n", "row 3: missing field name"},
+		{"bad size", header + "R,0,A,0,,,,big\n", "row 2: register R: size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.src), DefaultFormat)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMap_RegisterMap(t *testing.T) {
+	m, err := parseExample(t).RegisterMap()
+	if err != nil {
+		t.Fatalf("RegisterMap: %v", err)
+	}
+	ctrl, ok := m.ByName("CTRL")
+	if !ok {
+		t.Fatal("CTRL not found")
+	}
+	bf, _ := ctrl.Layout.Field("MODE")
+	if got := bf.ValueString(bf.Decode(ctrl.Value())); got != "Sleep" {
+		t.Errorf("CTRL.MODE at reset = %s, want Sleep", got)
+	}
+	status, _ := m.ByOffset(0x4)
+	if status.SideEffect("IRQ") != bitfield.WriteOneToClear || status.Access("BUSY") != bitfield.ReadOnly {
+		t.Errorf("STATUS.IRQ = %v, BUSY = %v, want W1C, RO", status.SideEffect("IRQ"), status.Access("BUSY"))
+	}
+}
+
+func TestMap_RegisterMapErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"16-bit register", "R,0,A,0,16\n", "row 2: register R: unsupported register size 16"},
+		{"overlapping fields", "R,0,A,3:0\n,,B,5:2\n", "row 3: register R:"},
+		{"overlapping registers", "R,0,A,0\nS,2,B,0\n", "row 3: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(strings.NewReader("register,offset,field,bits,size\n"+tt.src), DefaultFormat)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			_, err = m.RegisterMap()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("RegisterMap() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseBits(t *testing.T) {
+	tests := []struct {
+		in          string
+		shift, size uint
+		wantErr     bool
+	}{
+		{"7", 7, 1, false},
+		{"7:4", 4, 4, false},
+		{"[31:16]", 16, 16, false},
+		{"3..0", 0, 4, false},
+		{"0:3", 0, 4, false},
+		{"", 0, 0, true},
+		{"a:b", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		shift, size, err := parseBits(tt.in)
+		if (err != nil) != tt.wantErr || shift != tt.shift || size != tt.size {
+			t.Errorf("parseBits(%q) = %d, %d, %v, want %d, %d, err = %v", tt.in, shift, size, err, tt.shift, tt.size, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package bitfield
+
+import "fmt"
+
+// Access describes how software may access a register field.
+type Access int
+
+const (
+	ReadWrite Access = iota // Field can be read and written
+	ReadOnly                // Writes to the field are rejected
+	WriteOnly               // Reads of the field are rejected
+)
+
+// String returns the conventional abbreviation of the access type.
+func (a Access) String() string {
+	switch a {
+	case ReadWrite:
+		return "RW"
+	case ReadOnly:
+		return "RO"
+	case WriteOnly:
+		return "WO"
+	}
+	return fmt.Sprintf("Access(%d)", int(a))
+}
+
+// Register models a device register: a Layout plus a reset value and
+// per-field access permissions. It holds the current register value.
+// A Register is not safe for concurrent use.
+type Register[U storageType] struct {
+	Name       string
+	Layout     *Layout[U]
+	ResetValue U      // Value loaded by Reset
+	Offset     uint64 // Byte offset of the register, set by RegisterMap.Add
+
+	value   U
+	access  map[string]Access
+	effects map[string]SideEffect
+}
+
+// NewRegister creates a register with the given layout and reset value.
+// The register starts out holding the reset value and all fields are ReadWrite.
+func NewRegister[U storageType](name string, layout *Layout[U], reset U) *Register[U] {
+	return &Register[U]{
+		Name:       name,
+		Layout:     layout,
+		ResetValue: reset,
+		value:      reset,
+		access:     make(map[string]Access),
+		effects:    make(map[string]SideEffect),
+	}
+}
+
+// SetAccess sets the access permissions of a field.
+// Returns an error if the layout has no such field or the access type is unknown.
+func (r *Register[U]) SetAccess(field string, access Access) error {
+	if _, ok := r.Layout.Field(field); !ok {
+		return fmt.Errorf("register %s: unknown field %q", r.Name, field)
+	}
+	if access < ReadWrite || access > WriteOnly {
+		return fmt.Errorf("register %s: invalid access %v for field %q", r.Name, access, field)
+	}
+	r.access[field] = access
+	return nil
+}
+
+// Access returns the access permissions of a field.
+// Fields without explicit permissions are ReadWrite.
+func (r *Register[U]) Access(field string) Access {
+	return r.access[field]
+}
+
+// Reset loads the reset value into the register.
+func (r *Register[U]) Reset() {
+	r.value = r.ResetValue
+}
+
+// Value returns the current raw register value.
+func (r *Register[U]) Value() U {
+	return r.value
+}
+
+// Load replaces the raw register value, for example with a value read from hardware.
+// Access permissions are not checked.
+func (r *Register[U]) Load(value U) {
+	r.value = value
+}
+
+// Get returns the current value of a field.
+// Returns an error if the field does not exist or is write-only.
+func (r *Register[U]) Get(field string) (uint64, error) {
+	bf, err := r.readable(field)
+	if err != nil {
+		return 0, err
+	}
+	return bf.Decode(r.value), nil
+}
+
+// Set writes a field of the register, preserving all other fields.
+// The write follows the side-effect semantics of the register's fields,
+// so for example setting a WriteOneToClear field to 1 clears it.
+// Returns an error if the field does not exist, is read-only, or the value does not fit.
+func (r *Register[U]) Set(field string, value uint64) error {
+	word, err := r.writeWord(field, value, r.value)
+	if err != nil {
+		return err
+	}
+	r.applyWrite(word)
+	return nil
+}
+
+// field looks up a field of the register's layout.
+func (r *Register[U]) field(name string) (BitField[uint64, U], error) {
+	bf, ok := r.Layout.Field(name)
+	if !ok {
+		return bf, fmt.Errorf("register %s: unknown field %q", r.Name, name)
+	}
+	return bf, nil
+}
+
+// readable looks up a field and checks that it may be read.
+func (r *Register[U]) readable(name string) (BitField[uint64, U], error) {
+	bf, err := r.field(name)
+	if err == nil && r.access[name] == WriteOnly {
+		err = fmt.Errorf("register %s: field %q is write-only", r.Name, name)
+	}
+	return bf, err
+}
+
+// writable looks up a field and checks that value may be written to it.
+func (r *Register[U]) writable(name string, value uint64) (BitField[uint64, U], error) {
+	bf, err := r.field(name)
+	if err != nil {
+		return bf, err
+	}
+	if r.access[name] == ReadOnly {
+		return bf, fmt.Errorf("register %s: field %q is read-only", r.Name, name)
+	}
+	if !bf.IsValid(value) {
+		return bf, fmt.Errorf("register %s: field %q: %w", r.Name, name, bf.invalidValue(value))
+	}
+	return bf, nil
+}
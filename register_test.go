@@ -0,0 +1,121 @@
+package bitfield
+
+import (
+	"errors"
+	"testing"
+)
+
+// newTestRegister returns a control register with an enable bit, a 2-bit mode,
+// a read-only status field and a write-only command field.
+func newTestRegister(t *testing.T) *Register[uint32] {
+	t.Helper()
+	l := mustLayout(t,
+		testField{"enable", 0, 1},
+		testField{"mode", 1, 2},
+		testField{"status", 8, 4},
+		testField{"command", 16, 8},
+	)
+	r := NewRegister("CTRL", l, 0x00000A04)
+	if err := r.SetAccess("status", ReadOnly); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetAccess("command", WriteOnly); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRegister_Reset(t *testing.T) {
+	r := newTestRegister(t)
+	if got := r.Value(); got != 0x00000A04 {
+		t.Errorf("initial Value() = 0x%08X, want reset value 0x00000A04", got)
+	}
+	r.Load(0xFFFFFFFF)
+	r.Reset()
+	if got := r.Value(); got != 0x00000A04 {
+		t.Errorf("Value() after Reset = 0x%08X, want 0x00000A04", got)
+	}
+}
+
+func TestRegister_Get(t *testing.T) {
+	r := newTestRegister(t)
+	tests := []struct {
+		field   string
+		want    uint64
+		wantErr bool
+	}{
+		{"mode", 2, false},
+		{"status", 0xA, false},
+		{"command", 0, true},
+		{"missing", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := r.Get(tt.field)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Get(%q): err = %v, want err = %v", tt.field, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Get(%q) = %v, want %v", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestRegister_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		value   uint64
+		want    uint32
+		wantErr bool
+	}{
+		{"read-write field", "enable", 1, 0x00000A05, false},
+		{"write-only field", "command", 0x5A, 0x005A0A04, false},
+		{"read-only field", "status", 1, 0x00000A04, true},
+		{"value too large", "mode", 4, 0x00000A04, true},
+		{"unknown field", "missing", 0, 0x00000A04, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRegister(t)
+			err := r.Set(tt.field, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Set(%q, %v): err = %v, want err = %v", tt.field, tt.value, err, tt.wantErr)
+			}
+			if got := r.Value(); got != tt.want {
+				t.Errorf("Value() = 0x%08X, want 0x%08X", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegister_SetNotAllowed(t *testing.T) {
+	r := newTestRegister(t)
+	if err := r.Layout.SetAllowed("mode", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Set("mode", 2); !errors.Is(err, ErrValueNotAllowed) {
+		t.Errorf("Set(mode, 2) = %v, want %v", err, ErrValueNotAllowed)
+	}
+	if got := r.Value(); got != 0x00000A04 {
+		t.Errorf("Value() = 0x%08X, want 0x00000A04", got)
+	}
+}
+
+func TestRegister_SetAccess(t *testing.T) {
+	r := newTestRegister(t)
+	if got := r.Access("enable"); got != ReadWrite {
+		t.Errorf("Access(enable) = %v, want RW", got)
+	}
+	if got := r.Access("status"); got != ReadOnly {
+		t.Errorf("Access(status) = %v, want RO", got)
+	}
+	if err := r.SetAccess("missing", ReadOnly); err == nil {
+		t.Error("SetAccess(missing) succeeded, want error")
+	}
+	if err := r.SetAccess("enable", Access(9)); err == nil {
+		t.Error("SetAccess(enable, 9) succeeded, want error")
+	}
+}
@@ -0,0 +1,100 @@
+package bitfield
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"slices"
+)
+
+// RegisterMap associates registers with byte offsets in an address space,
+// such as the register block of a peripheral.
+// Registers can be looked up by offset or by name and iterated in offset order.
+type RegisterMap[U storageType] struct {
+	regs   []*Register[U] // Sorted by offset
+	byName map[string]*Register[U]
+}
+
+// NewRegisterMap creates an empty RegisterMap.
+func NewRegisterMap[U storageType]() *RegisterMap[U] {
+	return &RegisterMap[U]{byName: make(map[string]*Register[U])}
+}
+
+// registerBytes returns the size in bytes of a register of type U.
+func registerBytes[U storageType]() uint64 {
+	return uint64(unsignedSizeOf[U]() / 8)
+}
+
+// Add places a register at the given byte offset and records the offset in reg.Offset.
+// Returns an error if the register has no name, its name is already used,
+// or it would overlap a register that was added before.
+func (m *RegisterMap[U]) Add(offset uint64, reg *Register[U]) error {
+	if reg.Name == "" {
+		return fmt.Errorf("register name must not be empty")
+	}
+	if _, ok := m.byName[reg.Name]; ok {
+		return fmt.Errorf("duplicate register %q", reg.Name)
+	}
+	size := registerBytes[U]()
+	i, _ := slices.BinarySearchFunc(m.regs, offset, func(r *Register[U], off uint64) int {
+		return cmp.Compare(r.Offset, off)
+	})
+	if i > 0 && m.regs[i-1].Offset+size > offset {
+		return fmt.Errorf("register %q at 0x%X overlaps register %q", reg.Name, offset, m.regs[i-1].Name)
+	}
+	if i < len(m.regs) && offset+size > m.regs[i].Offset {
+		return fmt.Errorf("register %q at 0x%X overlaps register %q", reg.Name, offset, m.regs[i].Name)
+	}
+	reg.Offset = offset
+	m.regs = slices.Insert(m.regs, i, reg)
+	m.byName[reg.Name] = reg
+	return nil
+}
+
+// ByName returns the register with the given name.
+// The second return value reports whether the register exists.
+func (m *RegisterMap[U]) ByName(name string) (*Register[U], bool) {
+	reg, ok := m.byName[name]
+	return reg, ok
+}
+
+// ByOffset returns the register at the given byte offset.
+// The second return value reports whether a register starts at that offset.
+func (m *RegisterMap[U]) ByOffset(offset uint64) (*Register[U], bool) {
+	i, ok := slices.BinarySearchFunc(m.regs, offset, func(r *Register[U], off uint64) int {
+		return cmp.Compare(r.Offset, off)
+	})
+	if !ok {
+		return nil, false
+	}
+	return m.regs[i], true
+}
+
+// Registers returns the registers of the map in ascending offset order.
+func (m *RegisterMap[U]) Registers() []*Register[U] {
+	return slices.Clone(m.regs)
+}
+
+// DecodeDump decodes a memory dump of the register block into field values.
+// Register offsets are byte offsets into dump, and each register is read using order.
+// The result maps register names to the decoded fields of that register;
+// write-only fields are omitted.
+// Returns an error if dump is too short to hold every register.
+func (m *RegisterMap[U]) DecodeDump(dump []byte, order binary.ByteOrder) (map[string]map[string]uint64, error) {
+	size := registerBytes[U]()
+	values := make(map[string]map[string]uint64, len(m.regs))
+	for _, reg := range m.regs {
+		if reg.Offset+size > uint64(len(dump)) {
+			return nil, fmt.Errorf("register %q at 0x%X exceeds dump of %d bytes", reg.Name, reg.Offset, len(dump))
+		}
+		container := getBinary[U](dump[reg.Offset:reg.Offset+size], order)
+		fields := reg.Layout.DecodeAll(container)
+		for name := range fields {
+			if reg.Access(name) == WriteOnly {
+				delete(fields, name)
+			}
+		}
+		values[reg.Name] = fields
+	}
+	return values, nil
+}
@@ -0,0 +1,124 @@
+package bitfield
+
+import (
+	"encoding/binary"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// newTestRegisterMap returns a map with CTRL at 0x0, DATA at 0x8 and STATUS at 0x4.
+func newTestRegisterMap(t *testing.T) *RegisterMap[uint32] {
+	t.Helper()
+	data := NewLayout[uint32]()
+	if err := data.Add("value", 0, 16); err != nil {
+		t.Fatal(err)
+	}
+	status := NewLayout[uint32]()
+	if err := status.Add("ready", 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := status.Add("errors", 4, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewRegisterMap[uint32]()
+	for _, r := range []struct {
+		offset uint64
+		reg    *Register[uint32]
+	}{
+		{0x0, newTestRegister(t)},
+		{0x8, NewRegister("DATA", data, 0)},
+		{0x4, NewRegister("STATUS", status, 0)},
+	} {
+		if err := m.Add(r.offset, r.reg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+func TestRegisterMap_Add(t *testing.T) {
+	m := newTestRegisterMap(t)
+	l := NewLayout[uint32]()
+	tests := []struct {
+		name    string
+		offset  uint64
+		reg     *Register[uint32]
+		wantErr bool
+	}{
+		{"after last", 0xC, NewRegister("EXTRA", l, 0), false},
+		{"gap", 0x20, NewRegister("FAR", l, 0), false},
+		{"empty name", 0x30, NewRegister("", l, 0), true},
+		{"duplicate name", 0x40, NewRegister("DATA", l, 0), true},
+		{"same offset", 0x4, NewRegister("ALIAS", l, 0), true},
+		{"overlaps previous", 0x6, NewRegister("MISALIGNED", l, 0), true},
+		{"overlaps next", 0x1E, NewRegister("STRADDLE", l, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.Add(tt.offset, tt.reg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Add(0x%X, %q): err = %v, want err = %v", tt.offset, tt.reg.Name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterMap_Lookup(t *testing.T) {
+	m := newTestRegisterMap(t)
+
+	reg, ok := m.ByName("STATUS")
+	if !ok || reg.Offset != 0x4 {
+		t.Errorf("ByName(STATUS) = %v, %v, want register at 0x4", reg, ok)
+	}
+	reg, ok = m.ByOffset(0x8)
+	if !ok || reg.Name != "DATA" {
+		t.Errorf("ByOffset(0x8) = %v, %v, want DATA", reg, ok)
+	}
+	if _, ok := m.ByOffset(0x2); ok {
+		t.Error("ByOffset(0x2) found a register, want none")
+	}
+	if _, ok := m.ByName("MISSING"); ok {
+		t.Error("ByName(MISSING) found a register, want none")
+	}
+
+	var names []string
+	for _, r := range m.Registers() {
+		names = append(names, r.Name)
+	}
+	if want := []string{"CTRL", "STATUS", "DATA"}; !slices.Equal(names, want) {
+		t.Errorf("Registers() = %v, want %v", names, want)
+	}
+}
+
+func TestRegisterMap_DecodeDump(t *testing.T) {
+	m := newTestRegisterMap(t)
+	dump := make([]byte, 12)
+	binary.LittleEndian.PutUint32(dump[0:], 0x00FF0A05)
+	binary.LittleEndian.PutUint32(dump[4:], 0x00000031)
+	binary.LittleEndian.PutUint32(dump[8:], 0xDEADBEEF)
+
+	got, err := m.DecodeDump(dump, binary.LittleEndian)
+	if err != nil {
+		t.Fatalf("DecodeDump: %v", err)
+	}
+	want := map[string]map[string]uint64{
+		"CTRL":   {"enable": 1, "mode": 2, "status": 0xA},
+		"STATUS": {"ready": 1, "errors": 3},
+		"DATA":   {"value": 0xBEEF},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DecodeDump() = %v, want %v", got, want)
+	}
+	for name, fields := range want {
+		if !maps.Equal(got[name], fields) {
+			t.Errorf("DecodeDump()[%s] = %v, want %v", name, got[name], fields)
+		}
+	}
+
+	if _, err := m.DecodeDump(dump[:10], binary.LittleEndian); err == nil {
+		t.Error("DecodeDump of short dump succeeded, want error")
+	}
+}
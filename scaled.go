@@ -0,0 +1,101 @@
+package bitfield
+
+import (
+	"fmt"
+	"math"
+)
+
+// RoundingMode selects how physical values are quantized to raw field values.
+type RoundingMode int
+
+const (
+	RoundNearest    RoundingMode = iota // Round half away from zero
+	RoundDown                           // Round toward negative infinity
+	RoundUp                             // Round toward positive infinity
+	RoundTowardZero                     // Discard the fractional part
+)
+
+// round applies the rounding mode to x.
+func (m RoundingMode) round(x float64) float64 {
+	switch m {
+	case RoundDown:
+		return math.Floor(x)
+	case RoundUp:
+		return math.Ceil(x)
+	case RoundTowardZero:
+		return math.Trunc(x)
+	}
+	return math.Round(x)
+}
+
+// ScaledField maps the raw value of a BitField to a physical quantity using
+// physical = raw*Scale + Offset, the usual convention for sensor registers and CAN signals.
+type ScaledField[T Unsigned, U storageType] struct {
+	Field    BitField[T, U] // Field holding the raw value
+	Scale    float64        // Physical value of one raw step; must not be 0
+	Offset   float64        // Physical value of raw 0
+	Rounding RoundingMode   // How physical values are quantized on encode
+}
+
+// NewScaled creates a ScaledField over field with the given scale and offset,
+// rounding to the nearest raw value on encode.
+func NewScaled[T Unsigned, U storageType](field BitField[T, U], scale, offset float64) ScaledField[T, U] {
+	return ScaledField[T, U]{Field: field, Scale: scale, Offset: offset}
+}
+
+// Decode extracts the raw field value from the container and converts it to a physical value.
+func (sf ScaledField[T, U]) Decode(container U) float64 {
+	return float64(sf.Field.Decode(container))*sf.Scale + sf.Offset
+}
+
+// Raw quantizes a physical value to a raw field value.
+// Returns an error if the value is not finite, Scale is 0, or the quantized value
+// does not fit in the field.
+func (sf ScaledField[T, U]) Raw(value float64) (T, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("value %v is not finite", value)
+	}
+	if sf.Scale == 0 {
+		return 0, fmt.Errorf("scale must not be 0")
+	}
+	raw := sf.Rounding.round((value - sf.Offset) / sf.Scale)
+	if raw < 0 || raw > sf.rawMax() {
+		return 0, fmt.Errorf("value %v out of range [%v, %v]", value, sf.Min(), sf.Max())
+	}
+	return T(raw), nil
+}
+
+// Encode quantizes a physical value and encodes it into the field.
+// Returns an error under the same conditions as Raw.
+func (sf ScaledField[T, U]) Encode(value float64) (U, error) {
+	raw, err := sf.Raw(value)
+	if err != nil {
+		return 0, err
+	}
+	return sf.Field.Encode(raw), nil
+}
+
+// Update quantizes a physical value and sets the field within an existing container.
+// Returns an error under the same conditions as Raw, leaving the container unchanged.
+func (sf ScaledField[T, U]) Update(previous U, value float64) (U, error) {
+	raw, err := sf.Raw(value)
+	if err != nil {
+		return previous, err
+	}
+	return sf.Field.Update(previous, raw), nil
+}
+
+// Min returns the smallest physical value the field can represent.
+func (sf ScaledField[T, U]) Min() float64 {
+	return math.Min(sf.Offset, sf.rawMax()*sf.Scale+sf.Offset)
+}
+
+// Max returns the largest physical value the field can represent.
+func (sf ScaledField[T, U]) Max() float64 {
+	return math.Max(sf.Offset, sf.rawMax()*sf.Scale+sf.Offset)
+}
+
+// rawMax returns the largest raw value of the field as a float64.
+func (sf ScaledField[T, U]) rawMax() float64 {
+	return float64(sf.Field.Max())
+}
@@ -0,0 +1,95 @@
+package bitfield
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScaledField_Decode(t *testing.T) {
+	// Temperature in 0.5 °C steps from -40 °C, stored in bits 8..15.
+	temp := NewScaled(New[uint8, uint32](8, 8), 0.5, -40)
+	tests := []struct {
+		container uint32
+		want      float64
+	}{
+		{0x0000, -40},
+		{0x5000, 0},
+		{0xFF00, 87.5},
+	}
+
+	for _, tt := range tests {
+		if got := temp.Decode(tt.container); got != tt.want {
+			t.Errorf("Decode(0x%X) = %v, want %v", tt.container, got, tt.want)
+		}
+	}
+	if temp.Min() != -40 || temp.Max() != 87.5 {
+		t.Errorf("range = [%v, %v], want [-40, 87.5]", temp.Min(), temp.Max())
+	}
+}
+
+func TestScaledField_Encode(t *testing.T) {
+	field := New[uint8, uint32](0, 8)
+	tests := []struct {
+		name     string
+		rounding RoundingMode
+		value    float64
+		want     uint32
+		wantErr  bool
+	}{
+		{"exact", RoundNearest, 25, 130, false},
+		{"nearest up", RoundNearest, 25.3, 131, false},
+		{"nearest down", RoundNearest, 25.2, 130, false},
+		{"down", RoundDown, 25.4, 130, false},
+		{"up", RoundUp, 25.1, 131, false},
+		{"toward zero", RoundTowardZero, 25.4, 130, false},
+		{"too low", RoundNearest, -40.5, 0, true},
+		{"too high", RoundNearest, 88, 0, true},
+		{"nan", RoundNearest, math.NaN(), 0, true},
+		{"infinite", RoundNearest, math.Inf(1), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sf := ScaledField[uint8, uint32]{Field: field, Scale: 0.5, Offset: -40, Rounding: tt.rounding}
+			got, err := sf.Encode(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Encode(%v): err = %v, want err = %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Encode(%v) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaledField_Update(t *testing.T) {
+	sf := NewScaled(New[uint16, uint32](16, 16), 0.01, 0)
+	got, err := sf.Update(0x0000BEEF, 12.34)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1234<<16|0xBEEF {
+		t.Errorf("Update(12.34) = 0x%08X, want 0x%08X", got, 1234<<16|0xBEEF)
+	}
+	if got, err := sf.Update(0x0000BEEF, -1); err == nil || got != 0x0000BEEF {
+		t.Errorf("Update(-1) = 0x%08X, %v, want unchanged container and error", got, err)
+	}
+}
+
+func TestScaledField_NegativeScale(t *testing.T) {
+	sf := NewScaled(New[uint8, uint64](0, 4), -2, 10)
+	if sf.Min() != -20 || sf.Max() != 10 {
+		t.Errorf("range = [%v, %v], want [-20, 10]", sf.Min(), sf.Max())
+	}
+	raw, err := sf.Raw(-4)
+	if err != nil || raw != 7 {
+		t.Errorf("Raw(-4) = %v, %v, want 7, nil", raw, err)
+	}
+}
+
+func TestScaledField_ZeroScale(t *testing.T) {
+	sf := NewScaled(New[uint8, uint64](0, 4), 0, 0)
+	if _, err := sf.Raw(1); err == nil {
+		t.Error("Raw with zero scale succeeded, want error")
+	}
+}
@@ -0,0 +1,109 @@
+package bitfield
+
+import (
+	"context"
+	"fmt"
+)
+
+// Shadow caches a register that lives behind a slow bus, such as an I2C device.
+// Field writes are recorded locally and coalesced, and Flush sends them to the
+// device with a single bus write. The register is read from the bus at most once,
+// on first use or when Refresh is called.
+// A Shadow is not safe for concurrent use.
+type Shadow[U storageType] struct {
+	Register *Register[U]
+
+	acc     RegisterAccessor
+	loaded  bool
+	pending U // Values of the dirty bits
+	dirty   U // Mask of the bits written since the last Flush
+}
+
+// NewShadow creates a shadow of reg that accesses the device through acc.
+func NewShadow[U storageType](acc RegisterAccessor, reg *Register[U]) *Shadow[U] {
+	return &Shadow[U]{Register: reg, acc: acc}
+}
+
+// Refresh reads the register from the bus into the cache.
+// Pending writes are kept and still take precedence over the value read.
+func (s *Shadow[U]) Refresh(ctx context.Context) error {
+	if _, err := ReadRegister(ctx, s.acc, s.Register); err != nil {
+		return err
+	}
+	s.loaded = true
+	return nil
+}
+
+// load reads the register from the bus if it has not been read yet.
+func (s *Shadow[U]) load(ctx context.Context) error {
+	if s.loaded {
+		return nil
+	}
+	return s.Refresh(ctx)
+}
+
+// Value returns the cached register value with the pending writes applied.
+func (s *Shadow[U]) Value() U {
+	return s.Register.Value()&^s.dirty | s.pending
+}
+
+// Get returns the value of a field, including pending writes.
+// The register is read from the bus if it has not been read yet.
+// Returns an error if the field does not exist, is write-only, or the bus access fails.
+func (s *Shadow[U]) Get(ctx context.Context, field string) (uint64, error) {
+	bf, err := s.Register.readable(field)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.load(ctx); err != nil {
+		return 0, err
+	}
+	return bf.Decode(s.Value()), nil
+}
+
+// Set records a write of a field without accessing the bus.
+// The register is read from the bus if it has not been read yet, so that
+// Flush can write back the fields that were not set.
+// Returns an error if the field does not exist, is read-only, the value does not fit,
+// or the bus access fails.
+func (s *Shadow[U]) Set(ctx context.Context, field string, value uint64) error {
+	bf, err := s.Register.writable(field, value)
+	if err != nil {
+		return err
+	}
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+	s.pending = bf.Update(s.pending, value)
+	s.dirty |= bf.Mask
+	return nil
+}
+
+// Dirty reports whether there are writes that have not been flushed.
+func (s *Shadow[U]) Dirty() bool {
+	return s.dirty != 0
+}
+
+// Discard drops all pending writes.
+func (s *Shadow[U]) Discard() {
+	s.pending, s.dirty = 0, 0
+}
+
+// Flush writes all pending field writes to the device with a single bus write
+// and applies the write to the register. Fields that were not set keep their
+// cached value, except fields with write side effects, which are written as 0.
+// Flush does nothing if there are no pending writes.
+// Pending writes are kept if the bus access fails.
+func (s *Shadow[U]) Flush(ctx context.Context) error {
+	if s.dirty == 0 {
+		return nil
+	}
+	reg := s.Register
+	word := reg.writeBase(reg.Value())&^s.dirty | s.pending
+	if err := s.acc.Write(ctx, reg.Offset, uint64(word)); err != nil {
+		return fmt.Errorf("register %s: write: %w", reg.Name, err)
+	}
+	reg.applyWrite(word)
+	s.Discard()
+	return nil
+}
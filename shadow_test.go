@@ -0,0 +1,118 @@
+package bitfield
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestShadow_Flush(t *testing.T) {
+	ctx := context.Background()
+	m := newTestRegisterMap(t)
+	ctrl, _ := m.ByName("CTRL")
+	bus := NewMemoryAccessor()
+	_ = bus.Write(ctx, ctrl.Offset, 0x00000A00)
+	s := NewShadow(bus, ctrl)
+	reads, writes := bus.Counts()
+
+	if err := s.Set(ctx, "enable", 1); err != nil {
+		t.Fatalf("Set(enable): %v", err)
+	}
+	if err := s.Set(ctx, "mode", 1); err != nil {
+		t.Fatalf("Set(mode): %v", err)
+	}
+	if err := s.Set(ctx, "command", 0x42); err != nil {
+		t.Fatalf("Set(command): %v", err)
+	}
+	if got, err := s.Get(ctx, "mode"); err != nil || got != 1 {
+		t.Errorf("Get(mode) = %v, %v, want 1, nil", got, err)
+	}
+	if !s.Dirty() {
+		t.Error("Dirty() = false after Set, want true")
+	}
+	if got, _ := bus.Read(ctx, ctrl.Offset); got != 0x00000A00 {
+		t.Errorf("bus value before Flush = 0x%08X, want 0x00000A00", got)
+	}
+
+	if err := s.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got, _ := bus.Read(ctx, ctrl.Offset); got != 0x00420A03 {
+		t.Errorf("bus value after Flush = 0x%08X, want 0x00420A03", got)
+	}
+	// Ignore the two verification reads above.
+	r, w := bus.Counts()
+	if r-reads-2 != 1 || w-writes != 1 {
+		t.Errorf("bus traffic = %d reads, %d writes, want 1 read, 1 write", r-reads-2, w-writes)
+	}
+	if s.Dirty() {
+		t.Error("Dirty() = true after Flush, want false")
+	}
+	if got := ctrl.Value(); got != 0x00420A03 {
+		t.Errorf("register value = 0x%08X, want 0x00420A03", got)
+	}
+
+	if err := s.Flush(ctx); err != nil {
+		t.Fatalf("second Flush: %v", err)
+	}
+	if _, after := bus.Counts(); after != w {
+		t.Errorf("Flush with no pending writes reached the bus")
+	}
+}
+
+func TestShadow_SideEffects(t *testing.T) {
+	ctx := context.Background()
+	r := newInterruptRegister(t)
+	bus := NewMemoryAccessor()
+	_ = bus.Write(ctx, r.Offset, 0x0300001F)
+	s := NewShadow(bus, r)
+
+	if err := s.Set(ctx, "pending", 0x2); err != nil {
+		t.Fatalf("Set(pending): %v", err)
+	}
+	if err := s.Set(ctx, "prio", 4); err != nil {
+		t.Fatalf("Set(prio): %v", err)
+	}
+	if err := s.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got, _ := bus.Read(ctx, r.Offset); got != 0x04000002 {
+		t.Errorf("bus word = 0x%08X, want 0x04000002", got)
+	}
+	if got := r.Value(); got != 0x0400001D {
+		t.Errorf("register value = 0x%08X, want 0x0400001D", got)
+	}
+}
+
+func TestShadow_Errors(t *testing.T) {
+	ctx := context.Background()
+	reg := newTestRegister(t)
+	s := NewShadow(NewMemoryAccessor(), reg)
+
+	if err := s.Set(ctx, "status", 1); err == nil {
+		t.Error("Set(status) on read-only field succeeded, want error")
+	}
+	if err := s.Set(ctx, "mode", 4); err == nil {
+		t.Error("Set(mode, 4) succeeded, want error")
+	}
+	if _, err := s.Get(ctx, "command"); err == nil {
+		t.Error("Get(command) on write-only field succeeded, want error")
+	}
+	if s.Dirty() {
+		t.Error("rejected writes marked the shadow dirty")
+	}
+
+	if err := s.Set(ctx, "mode", 3); err != nil {
+		t.Fatalf("Set(mode): %v", err)
+	}
+	s.Discard()
+	if s.Dirty() || s.Value() != reg.Value() {
+		t.Errorf("Discard left pending writes: Value() = 0x%08X", s.Value())
+	}
+
+	errBus := errors.New("bus fault")
+	s = NewShadow(failingAccessor{errBus}, reg)
+	if err := s.Set(ctx, "mode", 1); !errors.Is(err, errBus) {
+		t.Errorf("Set with failing bus: err = %v, want %v", err, errBus)
+	}
+}
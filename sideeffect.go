@@ -0,0 +1,105 @@
+package bitfield
+
+import "fmt"
+
+// SideEffect describes how hardware reacts to accesses of a register field,
+// beyond simply storing the written value.
+type SideEffect int
+
+const (
+	NoSideEffect    SideEffect = iota // Field stores the written value
+	WriteOneToClear                   // W1C: writing 1 clears a bit, writing 0 has no effect
+	WriteOneToSet                     // W1S: writing 1 sets a bit, writing 0 has no effect
+	ReadToClear                       // RC: reading returns the value and then clears the field
+	WriteLatch                        // Writes trigger an action and are not retained; reads return 0
+)
+
+// String returns the conventional abbreviation of the side effect.
+func (e SideEffect) String() string {
+	switch e {
+	case NoSideEffect:
+		return "none"
+	case WriteOneToClear:
+		return "W1C"
+	case WriteOneToSet:
+		return "W1S"
+	case ReadToClear:
+		return "RC"
+	case WriteLatch:
+		return "latch"
+	}
+	return fmt.Sprintf("SideEffect(%d)", int(e))
+}
+
+// SetSideEffect sets the side-effect semantics of a field.
+// Returns an error if the layout has no such field or the side effect is unknown.
+func (r *Register[U]) SetSideEffect(field string, effect SideEffect) error {
+	if _, err := r.field(field); err != nil {
+		return err
+	}
+	if effect < NoSideEffect || effect > WriteLatch {
+		return fmt.Errorf("register %s: invalid side effect %v for field %q", r.Name, effect, field)
+	}
+	r.effects[field] = effect
+	return nil
+}
+
+// SideEffect returns the side-effect semantics of a field.
+// Fields without explicit semantics have NoSideEffect.
+func (r *Register[U]) SideEffect(field string) SideEffect {
+	return r.effects[field]
+}
+
+// writeWord returns the bus word that sets field to value, given the current register value.
+// Other fields keep their current value, except fields with write side effects,
+// whose bits are written as 0 so that they are not triggered.
+func (r *Register[U]) writeWord(field string, value uint64, current U) (U, error) {
+	bf, err := r.writable(field, value)
+	if err != nil {
+		return 0, err
+	}
+	return bf.Update(r.writeBase(current), value), nil
+}
+
+// writeBase returns current with the bits of all fields that have write side effects cleared.
+func (r *Register[U]) writeBase(current U) U {
+	for name, effect := range r.effects {
+		switch effect {
+		case WriteOneToClear, WriteOneToSet, WriteLatch:
+			f, _ := r.Layout.Field(name)
+			current &^= f.Mask
+		}
+	}
+	return current
+}
+
+// applyWrite updates the register value to reflect a bus write of word.
+func (r *Register[U]) applyWrite(word U) {
+	next := word
+	for name, effect := range r.effects {
+		f, _ := r.Layout.Field(name)
+		old, written := r.value&f.Mask, word&f.Mask
+		switch effect {
+		case WriteOneToClear:
+			next = next&^f.Mask | old&^written
+		case WriteOneToSet:
+			next = next&^f.Mask | old | written
+		case WriteLatch:
+			next &^= f.Mask
+		}
+	}
+	r.value = next
+}
+
+// applyRead updates the register value to reflect a bus read that returned raw.
+// ReadToClear fields are cleared by the read and WriteLatch fields always read as 0.
+func (r *Register[U]) applyRead(raw U) {
+	next := raw
+	for name, effect := range r.effects {
+		if effect == ReadToClear || effect == WriteLatch {
+			f, _ := r.Layout.Field(name)
+			next &^= f.Mask
+		}
+	}
+	r.value = next
+}
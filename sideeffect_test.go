@@ -0,0 +1,143 @@
+package bitfield
+
+import (
+	"context"
+	"testing"
+)
+
+// newInterruptRegister returns an interrupt register with a W1C pending field,
+// a W1S enable field, a read-to-clear overflow counter and a latch trigger.
+func newInterruptRegister(t *testing.T) *Register[uint32] {
+	t.Helper()
+	r := NewRegister("IRQ", mustLayout(t,
+		testField{"pending", 0, 4},
+		testField{"enable", 4, 4},
+		testField{"overflow", 8, 8},
+		testField{"trigger", 16, 1},
+		testField{"prio", 24, 3},
+	), 0)
+	for _, err := range []error{
+		r.SetSideEffect("pending", WriteOneToClear),
+		r.SetSideEffect("enable", WriteOneToSet),
+		r.SetSideEffect("overflow", ReadToClear),
+		r.SetSideEffect("trigger", WriteLatch),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func TestSideEffect_String(t *testing.T) {
+	tests := []struct {
+		effect SideEffect
+		want   string
+	}{
+		{NoSideEffect, "none"},
+		{WriteOneToClear, "W1C"},
+		{WriteOneToSet, "W1S"},
+		{ReadToClear, "RC"},
+		{WriteLatch, "latch"},
+		{SideEffect(9), "SideEffect(9)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.effect.String(); got != tt.want {
+			t.Errorf("SideEffect(%d).String() = %q, want %q", int(tt.effect), got, tt.want)
+		}
+	}
+}
+
+func TestRegister_SetSideEffect(t *testing.T) {
+	r := newInterruptRegister(t)
+	if got := r.SideEffect("pending"); got != WriteOneToClear {
+		t.Errorf("SideEffect(pending) = %v, want W1C", got)
+	}
+	if got := r.SideEffect("prio"); got != NoSideEffect {
+		t.Errorf("SideEffect(prio) = %v, want none", got)
+	}
+	if err := r.SetSideEffect("missing", ReadToClear); err == nil {
+		t.Error("SetSideEffect(missing) succeeded, want error")
+	}
+	if err := r.SetSideEffect("prio", SideEffect(9)); err == nil {
+		t.Error("SetSideEffect(prio, 9) succeeded, want error")
+	}
+}
+
+func TestRegister_SetSideEffects(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		value uint64
+		want  uint32
+	}{
+		{"W1C clears written bits", "pending", 0x5, 0x030000AA},
+		{"W1C zero is a no-op", "pending", 0x0, 0x030000AF},
+		{"W1S sets written bits", "enable", 0x4, 0x030000EF},
+		{"latch is not retained", "trigger", 1, 0x030000AF},
+		{"plain field keeps W1C bits", "prio", 5, 0x050000AF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newInterruptRegister(t)
+			r.Load(0x030000AF)
+			if err := r.Set(tt.field, tt.value); err != nil {
+				t.Fatalf("Set(%q, %v): %v", tt.field, tt.value, err)
+			}
+			if got := r.Value(); got != tt.want {
+				t.Errorf("Value() = 0x%08X, want 0x%08X", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadField_ReadToClear(t *testing.T) {
+	ctx := context.Background()
+	r := newInterruptRegister(t)
+	bus := NewMemoryAccessor()
+	_ = bus.Write(ctx, r.Offset, 0x03002A0F)
+
+	got, err := ReadField(ctx, bus, r, "overflow")
+	if err != nil || got != 0x2A {
+		t.Errorf("ReadField(overflow) = %v, %v, want 42, nil", got, err)
+	}
+	if got := r.Value(); got != 0x0300000F {
+		t.Errorf("register value after read = 0x%08X, want 0x0300000F", got)
+	}
+}
+
+func TestWriteField_SideEffects(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		value   uint64
+		wantBus uint32 // Word written to the bus
+		wantReg uint32 // Model value after the write
+	}{
+		{"W1C writes only the bits to clear", "pending", 0x1, 0x03000001, 0x0300000E},
+		{"W1S writes only the bits to set", "enable", 0x2, 0x03000020, 0x0300002F},
+		{"latch is written then reads as 0", "trigger", 1, 0x03010000, 0x0300000F},
+		{"plain write masks side-effect fields", "prio", 1, 0x01000000, 0x0100000F},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newInterruptRegister(t)
+			bus := NewMemoryAccessor()
+			_ = bus.Write(ctx, r.Offset, 0x03002A0F)
+
+			if err := WriteField(ctx, bus, r, tt.field, tt.value); err != nil {
+				t.Fatalf("WriteField(%q, %v): %v", tt.field, tt.value, err)
+			}
+			if got, _ := bus.Read(ctx, r.Offset); got != uint64(tt.wantBus) {
+				t.Errorf("bus word = 0x%08X, want 0x%08X", got, tt.wantBus)
+			}
+			if got := r.Value(); got != tt.wantReg {
+				t.Errorf("Value() = 0x%08X, want 0x%08X", got, tt.wantReg)
+			}
+		})
+	}
+}
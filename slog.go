@@ -0,0 +1,23 @@
+package bitfield
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer, so a logged container expands into a group
+// with one attribute per field, in the order the fields were added to the layout.
+// As in MarshalJSON, values with a registered enum name are logged as strings,
+// other 1-bit fields as booleans and all other fields as numbers.
+// Fields that are not present, as declared with Layout.SetCondition, are left out.
+// A container without a layout is logged as its raw value.
+func (p Packed[U]) LogValue() slog.Value {
+	if p.Layout == nil {
+		return slog.Uint64Value(uint64(p.Value))
+	}
+	attrs := make([]slog.Attr, 0, len(p.Layout.fields))
+	for i, f := range p.Layout.fields {
+		if !p.Layout.present(i, p.Value) {
+			continue
+		}
+		attrs = append(attrs, slog.Any(f.name, fieldValue(f.field, f.field.Decode(p.Value))))
+	}
+	return slog.GroupValue(attrs...)
+}
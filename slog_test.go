@@ -0,0 +1,37 @@
+package bitfield
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestPacked_LogValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Packed[uint32]
+		want  string
+	}{
+		{"fields", newStatusLayout(t).Bind(0x00002A57), "status.active=true status.priority=High status.category=5 status.error=42"},
+		{"no layout", Packed[uint32]{Value: 42}, "status=42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+			slog.New(h).Info("", "status", tt.value)
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("logged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package bitfield
+
+import "fmt"
+
+// Snapshot holds the raw values of the registers of a RegisterMap, keyed by register name.
+type Snapshot[U storageType] map[string]U
+
+// Snapshot captures the current value of every register of the map.
+func (m *RegisterMap[U]) Snapshot() Snapshot[U] {
+	snap := make(Snapshot[U], len(m.regs))
+	for _, reg := range m.regs {
+		snap[reg.Name] = reg.value
+	}
+	return snap
+}
+
+// Restore loads the values of a snapshot into the registers of the map.
+// Registers that are not in the snapshot keep their current value.
+// Returns an error, without loading anything, if the snapshot names a register
+// that is not in the map.
+func (m *RegisterMap[U]) Restore(snap Snapshot[U]) error {
+	for name := range snap {
+		if _, ok := m.byName[name]; !ok {
+			return fmt.Errorf("unknown register %q in snapshot", name)
+		}
+	}
+	for name, value := range snap {
+		m.byName[name].value = value
+	}
+	return nil
+}
+
+// Diff returns the fields whose values differ between two snapshots of the map,
+// in register offset order and then in the order the fields were added to the layout.
+// Only registers present in both snapshots are compared, and write-only fields are ignored.
+func (m *RegisterMap[U]) Diff(from, to Snapshot[U]) []FieldChange {
+	var changes []FieldChange
+	for _, reg := range m.regs {
+		a, okA := from[reg.Name]
+		b, okB := to[reg.Name]
+		if !okA || !okB || a == b {
+			continue
+		}
+		for _, c := range reg.Layout.Diff(a, b) {
+			if reg.Access(c.Field) == WriteOnly {
+				continue
+			}
+			c.Register = reg.Name
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,61 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRegisterMap_SnapshotRestore(t *testing.T) {
+	m := newTestRegisterMap(t)
+	ctrl, _ := m.ByName("CTRL")
+	data, _ := m.ByName("DATA")
+	_ = ctrl.Set("mode", 3)
+	_ = data.Set("value", 0x1234)
+
+	snap := m.Snapshot()
+	_ = ctrl.Set("mode", 0)
+	_ = data.Set("value", 0)
+	if err := m.Restore(snap); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got, _ := ctrl.Get("mode"); got != 3 {
+		t.Errorf("CTRL.mode after Restore = %v, want 3", got)
+	}
+	if got, _ := data.Get("value"); got != 0x1234 {
+		t.Errorf("DATA.value after Restore = %#x, want 0x1234", got)
+	}
+
+	if err := m.Restore(Snapshot[uint32]{"CTRL": 0, "BOGUS": 1}); err == nil {
+		t.Error("Restore with unknown register succeeded, want error")
+	}
+	if got, _ := ctrl.Get("mode"); got != 3 {
+		t.Errorf("failed Restore modified CTRL.mode: %v", got)
+	}
+}
+
+func TestRegisterMap_Diff(t *testing.T) {
+	m := newTestRegisterMap(t)
+	ctrl, _ := m.ByName("CTRL")
+	status, _ := m.ByName("STATUS")
+	before := m.Snapshot()
+
+	_ = ctrl.Set("enable", 1)
+	_ = ctrl.Set("command", 0x10) // write-only, not reported
+	ctrl.Load(ctrl.Value() &^ 0xF00)
+	status.Load(0x31)
+	after := m.Snapshot()
+
+	got := m.Diff(before, after)
+	want := []FieldChange{
+		{Register: "CTRL", Field: "enable", Old: 0, New: 1},
+		{Register: "CTRL", Field: "status", Old: 0xA, New: 0},
+		{Register: "STATUS", Field: "ready", Old: 0, New: 1},
+		{Register: "STATUS", Field: "errors", Old: 0, New: 3},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+	if got := m.Diff(after, after); len(got) != 0 {
+		t.Errorf("Diff of identical snapshots = %v, want none", got)
+	}
+}
@@ -0,0 +1,214 @@
+// Package snowflake generates unique, roughly time-ordered 64-bit IDs in the
+// style of Twitter's Snowflake: a timestamp above a node number above a
+// per-tick sequence number.
+//
+// A Format declares the bit layout of the IDs once; a Generator hands out IDs
+// for one node and a Format decodes IDs back into their components. The most
+// significant bit is never used, so IDs are positive as int64 values too.
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// ErrClockSkew is returned by Generator.Next when the clock moved backward by
+// more than the skew the generator tolerates.
+var ErrClockSkew = errors.New("snowflake: clock moved backward")
+
+// Format is the bit layout of IDs.
+type Format struct {
+	Timestamp bitfield.BitField[uint64, uint64] // Ticks since Epoch
+	Node      bitfield.BitField[uint64, uint64]
+	Sequence  bitfield.BitField[uint64, uint64] // Counter of IDs within a tick
+	Epoch     time.Time                         // Time of timestamp 0
+	Tick      time.Duration                     // Resolution of the timestamp
+}
+
+// Twitter is the format of Twitter's Snowflake IDs: 41 bits of milliseconds
+// since 2010-11-04 01:42:54.657 UTC, 10 bits of node and 12 bits of sequence.
+var Twitter = Format{
+	Timestamp: bitfield.New[uint64, uint64](22, 41),
+	Node:      bitfield.New[uint64, uint64](12, 10),
+	Sequence:  bitfield.New[uint64, uint64](0, 12),
+	Epoch:     time.UnixMilli(1288834974657).UTC(),
+	Tick:      time.Millisecond,
+}
+
+// NewFormat lays out the timestamp, node and sequence fields from the most
+// significant used bit down.
+// Returns an error if a field has no bits, the fields need more than 63 bits,
+// or tick is not positive.
+func NewFormat(timestampBits, nodeBits, sequenceBits uint, epoch time.Time, tick time.Duration) (Format, error) {
+	if timestampBits == 0 || nodeBits == 0 || sequenceBits == 0 {
+		return Format{}, fmt.Errorf("snowflake: fields must have at least 1 bit")
+	}
+	if timestampBits+nodeBits+sequenceBits > 63 {
+		return Format{}, fmt.Errorf("snowflake: %d bits exceed 63", timestampBits+nodeBits+sequenceBits)
+	}
+	if tick <= 0 {
+		return Format{}, fmt.Errorf("snowflake: tick must be positive")
+	}
+	return Format{
+		Timestamp: bitfield.New[uint64, uint64](nodeBits+sequenceBits, timestampBits),
+		Node:      bitfield.New[uint64, uint64](sequenceBits, nodeBits),
+		Sequence:  bitfield.New[uint64, uint64](0, sequenceBits),
+		Epoch:     epoch,
+		Tick:      tick,
+	}, nil
+}
+
+// Layout returns a Layout with the fields of the format, named Timestamp,
+// Node and Sequence.
+func (f Format) Layout() *bitfield.Layout[uint64] {
+	l := bitfield.NewLayout[uint64]()
+	for _, fl := range []struct {
+		name  string
+		field bitfield.BitField[uint64, uint64]
+	}{{"Sequence", f.Sequence}, {"Node", f.Node}, {"Timestamp", f.Timestamp}} {
+		if err := l.Add(fl.name, fl.field.Shift, fl.field.Size); err != nil {
+			panic(err)
+		}
+	}
+	return l
+}
+
+// Parts are the components of an ID.
+type Parts struct {
+	Time     time.Time // Start of the tick of the timestamp
+	Node     uint64
+	Sequence uint64
+}
+
+// Decode splits an ID into its components.
+func (f Format) Decode(id uint64) Parts {
+	return Parts{
+		Time:     f.Epoch.Add(time.Duration(f.Timestamp.Decode(id)) * f.Tick),
+		Node:     f.Node.Decode(id),
+		Sequence: f.Sequence.Decode(id),
+	}
+}
+
+// Compose builds an ID from its components, truncating the time to the tick.
+// Returns an error if a component does not fit in its field.
+func (f Format) Compose(p Parts) (uint64, error) {
+	ticks, err := f.ticks(p.Time)
+	if err != nil {
+		return 0, err
+	}
+	if !f.Node.IsValid(p.Node) {
+		return 0, fmt.Errorf("snowflake: node %d out of range, max %d", p.Node, f.Node.Max())
+	}
+	if !f.Sequence.IsValid(p.Sequence) {
+		return 0, fmt.Errorf("snowflake: sequence %d out of range, max %d", p.Sequence, f.Sequence.Max())
+	}
+	return f.Timestamp.Encode(ticks) | f.Node.Encode(p.Node) | f.Sequence.Encode(p.Sequence), nil
+}
+
+// ticks returns the timestamp of a time.
+// Returns an error if the time is before the epoch or beyond the timestamp field.
+func (f Format) ticks(t time.Time) (uint64, error) {
+	d := t.Sub(f.Epoch)
+	if d < 0 {
+		return 0, fmt.Errorf("snowflake: time %v before epoch %v", t, f.Epoch)
+	}
+	ticks := uint64(d / f.Tick)
+	if !f.Timestamp.IsValid(ticks) {
+		return 0, fmt.Errorf("snowflake: time %v beyond the %d-bit timestamp", t, f.Timestamp.Size)
+	}
+	return ticks, nil
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// WithClock makes the generator read the time from now instead of time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(g *Generator) {
+		g.now = now
+	}
+}
+
+// WithMaxSkew makes the generator tolerate the clock moving backward by up
+// to d, and running ahead of the clock by up to d when a tick runs out of
+// sequence numbers. The default is 0: IDs are never generated ahead of the
+// clock, and any backward step of the clock fails.
+func WithMaxSkew(d time.Duration) Option {
+	return func(g *Generator) {
+		g.maxSkew = d
+	}
+}
+
+// Generator generates IDs of a format for one node.
+// IDs of a generator are strictly increasing. When the clock moves backward
+// within the tolerated skew, the generator keeps counting from the last
+// timestamp it used. When a tick runs out of sequence numbers, the generator
+// continues in the next tick, waiting for the clock if that would put it
+// further ahead than the tolerated skew.
+// A Generator is safe for concurrent use.
+type Generator struct {
+	format  Format
+	node    uint64
+	now     func() time.Time
+	maxSkew time.Duration
+
+	mu       sync.Mutex
+	last     uint64 // Timestamp of the last ID
+	sequence uint64 // Sequence number of the last ID
+	started  bool   // Whether an ID was generated
+}
+
+// NewGenerator creates a generator for a node.
+// Returns an error if the node does not fit in the node field.
+func NewGenerator(f Format, node uint64, opts ...Option) (*Generator, error) {
+	if !f.Node.IsValid(node) {
+		return nil, fmt.Errorf("snowflake: node %d out of range, max %d", node, f.Node.Max())
+	}
+	g := &Generator{format: f, node: node, now: time.Now}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, nil
+}
+
+// Next returns a new ID.
+// Returns an error wrapping ErrClockSkew if the clock moved backward by more
+// than the tolerated skew, or an error if the time is outside the timestamp
+// field.
+func (g *Generator) Next() (uint64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	skew := uint64(g.maxSkew / g.format.Tick)
+	now, err := g.format.ticks(g.now())
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case !g.started || now > g.last:
+		g.last, g.sequence, g.started = now, 0, true
+	case g.last-now > skew:
+		return 0, fmt.Errorf("%w by %v", ErrClockSkew, time.Duration(g.last-now)*g.format.Tick)
+	case g.sequence < g.format.Sequence.Max():
+		g.sequence++
+	default:
+		// The tick ran out of sequence numbers: continue in the next one,
+		// waiting until it is within the tolerated skew of the clock.
+		next := g.last + 1
+		for now < next && next-now > skew {
+			time.Sleep(time.Duration(next-now-skew) * g.format.Tick)
+			if now, err = g.format.ticks(g.now()); err != nil {
+				return 0, err
+			}
+		}
+		next = max(next, now)
+		if !g.format.Timestamp.IsValid(next) {
+			return 0, fmt.Errorf("snowflake: timestamps of the %d-bit field exhausted", g.format.Timestamp.Size)
+		}
+		g.last, g.sequence = next, 0
+	}
+	return g.format.Timestamp.Encode(g.last) | g.format.Node.Encode(g.node) | g.format.Sequence.Encode(g.sequence), nil
+}
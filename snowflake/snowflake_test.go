@@ -0,0 +1,191 @@
+package snowflake
+
+import (
+	"errors"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock controlled by tests.
+type fakeClock struct {
+	mu   sync.Mutex
+	t    time.Time
+	step time.Duration // Added after every reading
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := c.t
+	c.t = c.t.Add(c.step)
+	return t
+}
+
+func (c *fakeClock) set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+func TestFormat_Decode(t *testing.T) {
+	const id = 1212092628029698048
+	got := Twitter.Decode(id)
+	want := Parts{Time: time.UnixMilli(1577820376771).UTC(), Node: 327, Sequence: 0}
+	if !got.Time.Equal(want.Time) || got.Node != want.Node || got.Sequence != want.Sequence {
+		t.Errorf("Decode(%d) = %+v, want %+v", uint64(id), got, want)
+	}
+	if back, err := Twitter.Compose(got); err != nil || back != id {
+		t.Errorf("Compose = %d, %v, want %d", back, err, uint64(id))
+	}
+	values := Twitter.Layout().DecodeAll(id)
+	if want := map[string]uint64{"Timestamp": 288985402114, "Node": 327, "Sequence": 0}; !maps.Equal(values, want) {
+		t.Errorf("DecodeAll = %v, want %v", values, want)
+	}
+}
+
+func TestFormat_ComposeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Parts
+	}{
+		{"before epoch", Parts{Time: Twitter.Epoch.Add(-time.Millisecond)}},
+		{"beyond timestamp", Parts{Time: Twitter.Epoch.Add(1 << 41 * time.Millisecond)}},
+		{"node", Parts{Time: Twitter.Epoch, Node: 1024}},
+		{"sequence", Parts{Time: Twitter.Epoch, Sequence: 4096}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Twitter.Compose(tt.p); err == nil {
+				t.Errorf("Compose(%+v) succeeded, want error", tt.p)
+			}
+		})
+	}
+}
+
+func TestNewFormat(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f, err := NewFormat(39, 8, 16, epoch, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewFormat: %v", err)
+	}
+	if f.Timestamp.Shift != 24 || f.Node.Shift != 16 || f.Sequence.Size != 16 {
+		t.Errorf("format = timestamp at %d, node at %d, sequence of %d bits", f.Timestamp.Shift, f.Node.Shift, f.Sequence.Size)
+	}
+
+	for _, bits := range [][3]uint{{0, 10, 12}, {41, 0, 12}, {41, 10, 0}, {42, 10, 12}} {
+		if _, err := NewFormat(bits[0], bits[1], bits[2], epoch, time.Millisecond); err == nil {
+			t.Errorf("NewFormat(%v) succeeded, want error", bits)
+		}
+	}
+	if _, err := NewFormat(41, 10, 12, epoch, 0); err == nil {
+		t.Error("NewFormat with zero tick succeeded, want error")
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	f, _ := NewFormat(41, 10, 2, Twitter.Epoch, time.Millisecond)
+	clock := &fakeClock{t: f.Epoch.Add(time.Second)}
+	g, err := NewGenerator(f, 5, WithClock(clock.now), WithMaxSkew(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+	next := func() Parts {
+		t.Helper()
+		id, err := g.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		return f.Decode(id)
+	}
+
+	start := clock.t
+	for i := range 4 {
+		if p := next(); !p.Time.Equal(start) || p.Sequence != uint64(i) || p.Node != 5 {
+			t.Fatalf("ID %d = %+v, want sequence %d at %v", i, p, i, start)
+		}
+	}
+	if p := next(); !p.Time.Equal(start.Add(time.Millisecond)) || p.Sequence != 0 {
+		t.Errorf("ID after exhausted tick = %+v, want sequence 0 in the next tick", p)
+	}
+
+	clock.set(start.Add(-3 * time.Millisecond))
+	if p := next(); !p.Time.Equal(start.Add(time.Millisecond)) || p.Sequence != 1 {
+		t.Errorf("ID after clock stepped back = %+v, want sequence 1 at the last timestamp", p)
+	}
+
+	clock.set(start.Add(-10 * time.Millisecond))
+	if _, err := g.Next(); !errors.Is(err, ErrClockSkew) {
+		t.Errorf("Next after clock stepped back 11ms = %v, want ErrClockSkew", err)
+	}
+
+	clock.set(start.Add(time.Second))
+	if p := next(); !p.Time.Equal(start.Add(time.Second)) || p.Sequence != 0 {
+		t.Errorf("ID after clock advanced = %+v", p)
+	}
+}
+
+func TestGenerator_WaitsForClock(t *testing.T) {
+	f, _ := NewFormat(41, 10, 1, Twitter.Epoch, time.Millisecond)
+	clock := &fakeClock{t: f.Epoch, step: 100 * time.Microsecond}
+	g, _ := NewGenerator(f, 0, WithClock(clock.now))
+	var last uint64
+	for i := range 20 {
+		id, err := g.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if i > 0 && id <= last {
+			t.Fatalf("ID %d = %d, not above %d", i, id, last)
+		}
+		if p := f.Decode(id); p.Time.After(clock.now()) {
+			t.Fatalf("ID %d at %v is ahead of the clock", i, p.Time)
+		}
+		last = id
+	}
+}
+
+func TestGenerator_Concurrent(t *testing.T) {
+	g, err := NewGenerator(Twitter, 1)
+	if err != nil {
+		t.Fatalf("NewGenerator: %v", err)
+	}
+	const workers, perWorker = 8, 1000
+	ids := make(chan uint64, workers*perWorker)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWorker {
+				id, err := g.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ids <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	seen := make(map[uint64]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate ID %d", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestGenerator_Errors(t *testing.T) {
+	if _, err := NewGenerator(Twitter, 1024); err == nil {
+		t.Error("NewGenerator with node 1024 succeeded, want error")
+	}
+	g, _ := NewGenerator(Twitter, 0, WithClock(func() time.Time { return Twitter.Epoch.Add(-time.Hour) }))
+	if _, err := g.Next(); err == nil {
+		t.Error("Next before epoch succeeded, want error")
+	}
+}
@@ -0,0 +1,107 @@
+package bitfield
+
+import "fmt"
+
+// Segment describes one contiguous run of bits of a SplitField.
+type Segment struct {
+	Shift      uint // Position of the least significant bit of the segment in the container
+	Size       uint // Number of bits in the segment
+	ValueShift uint // Position of the segment's least significant bit within the value
+}
+
+// SplitField represents a value whose bits are scattered across several
+// non-adjacent ranges of a container, as found in instruction encodings such as
+// RISC-V B-type and J-type immediates.
+// T represents the type of values that can be stored in the field.
+// U represents the container type where the field will be stored.
+type SplitField[T Unsigned, U storageType] struct {
+	Segments  []Segment // Ranges making up the field
+	Mask      U         // Mask with 1s in all container positions of the field
+	ValueMask uint64    // Mask with 1s in all value bits covered by a segment
+}
+
+// NewSplit creates a new SplitField from the given segments.
+// Value bits not covered by any segment are always 0.
+// Note: This function doesn't perform validation, use SafeSplit for validated creation.
+func NewSplit[T Unsigned, U storageType](segments ...Segment) SplitField[T, U] {
+	sf := SplitField[T, U]{Segments: segments}
+	for _, s := range segments {
+		sf.Mask |= New[T, U](s.Shift, s.Size).Mask
+		sf.ValueMask |= lowBits(s.Size) << s.ValueShift
+	}
+	return sf
+}
+
+// SafeSplit creates a new SplitField from the given segments, after validating them.
+// Returns an error if:
+// - no segments are given or a segment has size 0
+// - a segment exceeds the container type U or the value type T
+// - two segments overlap in the container or in the value
+func SafeSplit[T Unsigned, U storageType](segments ...Segment) (SplitField[T, U], error) {
+	if len(segments) == 0 {
+		return SplitField[T, U]{}, fmt.Errorf("split field needs at least one segment")
+	}
+	var mask U
+	var valueMask uint64
+	for i, s := range segments {
+		switch {
+		case s.Size == 0:
+			return SplitField[T, U]{}, fmt.Errorf("segment %d: %w", i, ErrSizeOutOfRange)
+		case s.Shift+s.Size > unsignedSizeOf[U]():
+			return SplitField[T, U]{}, fmt.Errorf("segment %d: %w", i, &FieldOverflowError{Shift: s.Shift, Size: s.Size, Width: unsignedSizeOf[U]()})
+		case s.ValueShift+s.Size > unsignedSizeOf[T]():
+			return SplitField[T, U]{}, fmt.Errorf("segment %d: %w", i, &FieldOverflowError{Shift: s.ValueShift, Size: s.Size, Width: unsignedSizeOf[T]()})
+		}
+		m := New[T, U](s.Shift, s.Size).Mask
+		vm := lowBits(s.Size) << s.ValueShift
+		if mask&m != 0 {
+			return SplitField[T, U]{}, fmt.Errorf("segment %d: overlaps another segment in the container", i)
+		}
+		if valueMask&vm != 0 {
+			return SplitField[T, U]{}, fmt.Errorf("segment %d: overlaps another segment in the value", i)
+		}
+		mask |= m
+		valueMask |= vm
+	}
+	return NewSplit[T, U](segments...), nil
+}
+
+// IsValid checks if the value can be represented by the field.
+// Returns true if every set bit of the value is covered by a segment.
+func (sf SplitField[T, U]) IsValid(value T) bool {
+	return uint64(value)&^sf.ValueMask == 0
+}
+
+// Encode scatters the bits of value across the segments of the field.
+// Panics if the value has bits set that are not covered by any segment.
+func (sf SplitField[T, U]) Encode(value T) U {
+	if !sf.IsValid(value) {
+		panic(fmt.Sprintf("value %v not representable, covered bits 0x%X", value, sf.ValueMask))
+	}
+	var container U
+	v := uint64(value)
+	for _, s := range sf.Segments {
+		container |= U(v>>s.ValueShift&lowBits(s.Size)) << s.Shift
+	}
+	return container
+}
+
+// Decode gathers the bits of the field from the container into a value.
+func (sf SplitField[T, U]) Decode(container U) T {
+	var v uint64
+	for _, s := range sf.Segments {
+		v |= uint64(container>>s.Shift) & lowBits(s.Size) << s.ValueShift
+	}
+	return T(v)
+}
+
+// Update sets the field within an existing container, preserving all other bits.
+// Panics if the value has bits set that are not covered by any segment.
+func (sf SplitField[T, U]) Update(previous U, value T) U {
+	return previous&^sf.Mask | sf.Encode(value)
+}
+
+// Clear zeroes out all bits of the field while preserving all other bits.
+func (sf SplitField[T, U]) Clear(container U) U {
+	return container &^ sf.Mask
+}
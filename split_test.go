@@ -0,0 +1,90 @@
+package bitfield
+
+import "testing"
+
+// riscvBImm is the immediate of a RISC-V B-type instruction: imm[12|10:5] in
+// bits 31:25 and imm[4:1|11] in bits 11:7.
+var riscvBImm = NewSplit[uint32, uint32](
+	Segment{Shift: 31, Size: 1, ValueShift: 12},
+	Segment{Shift: 25, Size: 6, ValueShift: 5},
+	Segment{Shift: 8, Size: 4, ValueShift: 1},
+	Segment{Shift: 7, Size: 1, ValueShift: 11},
+)
+
+func TestSafeSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []Segment
+		wantErr  bool
+	}{
+		{"valid", []Segment{{0, 4, 4}, {8, 4, 0}}, false},
+		{"no segments", nil, true},
+		{"zero size", []Segment{{0, 0, 0}}, true},
+		{"exceeds container", []Segment{{30, 4, 0}}, true},
+		{"exceeds value type", []Segment{{0, 4, 6}}, true},
+		{"container overlap", []Segment{{0, 4, 0}, {2, 4, 4}}, true},
+		{"value overlap", []Segment{{0, 4, 0}, {8, 4, 2}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SafeSplit[uint8, uint32](tt.segments...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SafeSplit(%v): err = %v, want err = %v", tt.segments, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSplitField_RISCVBranch(t *testing.T) {
+	tests := []struct {
+		name string
+		insn uint32 // Encoded instruction
+		imm  uint32 // Immediate as an unsigned 13-bit value
+	}{
+		{"beq x0, x0, +8", 0x00000463, 8},
+		{"bne a0, a1, +2048", 0x00B51063 | 1<<7, 2048},
+		{"blt t0, t1, -4", 0xFE62CEE3, 0x1FFC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := riscvBImm.Decode(tt.insn); got != tt.imm {
+				t.Errorf("Decode(0x%08X) = 0x%X, want 0x%X", tt.insn, got, tt.imm)
+			}
+			if got := riscvBImm.Update(tt.insn, tt.imm); got != tt.insn {
+				t.Errorf("Update(0x%08X, 0x%X) = 0x%08X, want unchanged", tt.insn, tt.imm, got)
+			}
+			if got := riscvBImm.Encode(tt.imm) | riscvBImm.Clear(tt.insn); got != tt.insn {
+				t.Errorf("Encode(0x%X) | Clear() = 0x%08X, want 0x%08X", tt.imm, got, tt.insn)
+			}
+		})
+	}
+}
+
+func TestSplitField_IsValid(t *testing.T) {
+	tests := []struct {
+		value uint32
+		want  bool
+	}{
+		{0, true},
+		{0x1FFE, true},
+		{1, false},      // imm[0] is not encoded
+		{0x2000, false}, // beyond imm[12]
+	}
+
+	for _, tt := range tests {
+		if got := riscvBImm.IsValid(tt.value); got != tt.want {
+			t.Errorf("IsValid(0x%X) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSplitField_EncodePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Encode(1) did not panic")
+		}
+	}()
+	riscvBImm.Encode(1)
+}
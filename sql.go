@@ -0,0 +1,72 @@
+package bitfield
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+)
+
+// Column stores a container in a database column through database/sql.
+// It implements driver.Valuer and sql.Scanner, storing the container as an
+// integer so that it fits a BIGINT column. 64-bit containers with the top bit set
+// are stored as the negative int64 with the same bits and restored unchanged.
+type Column[U storageType] struct {
+	Bits U
+
+	// Layout is optional. If set, Scan rejects values that do not pass
+	// Layout.Validate: reserved bits set, bits of fields that are not present,
+	// or values that are not allowed.
+	Layout *Layout[U]
+}
+
+// Value implements driver.Valuer.
+func (c Column[U]) Value() (driver.Value, error) {
+	return int64(uint64(c.Bits)), nil
+}
+
+// Scan implements sql.Scanner. It accepts integers, and decimal numbers
+// stored as text, as returned by drivers that do not convert column types.
+// Returns an error, leaving c unchanged, if src is NULL or not a number,
+// does not fit in the container, or fails validation against the layout.
+func (c *Column[U]) Scan(src any) error {
+	var bits uint64
+	switch v := src.(type) {
+	case int64:
+		bits = uint64(v)
+	case []byte:
+		return c.scanText(string(v))
+	case string:
+		return c.scanText(v)
+	case nil:
+		return fmt.Errorf("cannot scan NULL into column")
+	default:
+		return fmt.Errorf("cannot scan %T into column", src)
+	}
+	return c.set(bits)
+}
+
+// scanText parses a column value stored as a decimal number.
+func (c *Column[U]) scanText(s string) error {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return c.set(uint64(n))
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid column value %q", s)
+	}
+	return c.set(n)
+}
+
+// set validates bits and stores them in c.
+func (c *Column[U]) set(bits uint64) error {
+	if width := unsignedSizeOf[U](); width < 64 && bits>>width != 0 {
+		return fmt.Errorf("column value %#x exceeds %d bits", bits, width)
+	}
+	if c.Layout != nil {
+		if err := c.Layout.Validate(U(bits)); err != nil {
+			return fmt.Errorf("column value %#x: %w", bits, err)
+		}
+	}
+	c.Bits = U(bits)
+	return nil
+}
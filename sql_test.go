@@ -0,0 +1,103 @@
+package bitfield
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = Column[uint64]{}
+	_ sql.Scanner   = (*Column[uint64])(nil)
+)
+
+func TestColumn_Value(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Column[uint64]
+		want int64
+	}{
+		{"small", Column[uint64]{Bits: 0x2A57}, 0x2A57},
+		{"top bit set", Column[uint64]{Bits: 0x8000_0000_0000_0001}, -0x7FFF_FFFF_FFFF_FFFF},
+	}
+
+	for _, tt := range tests {
+		v, err := tt.got.Value()
+		if err != nil || v != tt.want {
+			t.Errorf("%s: Value() = %v, %v, want %v, nil", tt.name, v, err, tt.want)
+		}
+	}
+}
+
+func TestColumn_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    uint64
+		wantErr bool
+	}{
+		{"int64", int64(0x2A57), 0x2A57, false},
+		{"negative int64", int64(-1), 0xFFFF_FFFF_FFFF_FFFF, false},
+		{"bytes", []byte("10839"), 0x2A57, false},
+		{"negative string", "-1", 0xFFFF_FFFF_FFFF_FFFF, false},
+		{"unsigned string", "18446744073709551615", 0xFFFF_FFFF_FFFF_FFFF, false},
+		{"null", nil, 7, true},
+		{"float", 1.5, 7, true},
+		{"not a number", "0x10", 7, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Column[uint64]{Bits: 7}
+			err := c.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan(%v): err = %v, want err = %v", tt.src, err, tt.wantErr)
+			}
+			if c.Bits != tt.want {
+				t.Errorf("Scan(%v): Bits = %#x, want %#x", tt.src, c.Bits, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumn_ScanValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		wantErr bool
+	}{
+		{"within layout", int64(0xFFFF), false},
+		{"outside layout", int64(0x10000), true},
+		{"exceeds container", int64(1 << 32), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Column[uint32]{Layout: newStatusLayout(t)}
+			if err := c.Scan(tt.src); (err != nil) != tt.wantErr {
+				t.Errorf("Scan(%v): err = %v, want err = %v", tt.src, err, tt.wantErr)
+			}
+		})
+	}
+
+	l := newStatusLayout(t)
+	if err := l.SetAllowed("category", 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	c := Column[uint32]{Layout: l}
+	if err := c.Scan(int64(0x30)); !errors.Is(err, ErrValueNotAllowed) {
+		t.Errorf("Scan() of a value that is not allowed: err = %v, want %v", err, ErrValueNotAllowed)
+	}
+	if err := c.Scan(int64(0x10000)); !errors.Is(err, ErrReservedBits) {
+		t.Errorf("Scan() outside layout: err = %v, want %v", err, ErrReservedBits)
+	}
+	if err := c.Scan(int64(0x20)); err != nil || c.Bits != 0x20 {
+		t.Errorf("Scan() = %#x, %v, want 0x20, nil", c.Bits, err)
+	}
+
+	c = Column[uint32]{}
+	if err := c.Scan(int64(0xFFFF_FFFF)); err != nil || c.Bits != 0xFFFF_FFFF {
+		t.Errorf("Scan without layout = %#x, %v, want 0xffffffff, nil", c.Bits, err)
+	}
+}
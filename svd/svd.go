@@ -0,0 +1,386 @@
+// Package svd imports register descriptions from CMSIS-SVD files.
+//
+// Parse reads an SVD document into a Device. Each Peripheral can then be
+// converted into a bitfield.RegisterMap with RegisterMap, giving access to
+// every register and field of a microcontroller without hand transcription.
+//
+// Register arrays (dim), clusters and derived peripherals are expanded.
+// Enumerated values become enum names of the layout, taken from the read or
+// read-write set if a field has one, and the oneToClear and oneToSet
+// modifiedWriteValues and the clear readAction become side effects of the
+// register. Only 32-bit registers are supported by RegisterMap.
+package svd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// Device is the root of a parsed SVD document.
+type Device struct {
+	Name        string
+	Peripherals []*Peripheral
+}
+
+// Peripheral is a peripheral with its registers.
+type Peripheral struct {
+	Name        string
+	Description string
+	BaseAddress uint64
+	Registers   []*Register // Registers with offsets relative to BaseAddress
+}
+
+// Register is a register of a peripheral.
+type Register struct {
+	Name        string
+	Description string
+	Offset      uint64 // Byte offset relative to the peripheral base address
+	Size        uint   // Width in bits
+	Access      bitfield.Access
+	ResetValue  uint64
+	Fields      []*Field
+}
+
+// Field is a bit field of a register.
+type Field struct {
+	Name        string
+	Description string
+	Shift       uint
+	Size        uint
+	Access      bitfield.Access
+	SideEffect  bitfield.SideEffect
+	Enum        map[uint64]string // Enumerated value names, nil if none are declared
+}
+
+// Peripheral returns the peripheral with the given name, or nil if there is none.
+func (d *Device) Peripheral(name string) *Peripheral {
+	for _, p := range d.Peripherals {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// RegisterMap converts the peripheral into a RegisterMap.
+// Field access permissions are taken from the field or, if not declared there,
+// from the register.
+// Returns an error if a register is not 32 bits wide, the registers or fields
+// overlap, or an enumerated value does not fit in its field.
+func (p *Peripheral) RegisterMap() (*bitfield.RegisterMap[uint32], error) {
+	m := bitfield.NewRegisterMap[uint32]()
+	for _, r := range p.Registers {
+		if r.Size != 32 {
+			return nil, fmt.Errorf("%s.%s: unsupported register size %d", p.Name, r.Name, r.Size)
+		}
+		layout := bitfield.NewLayout[uint32]()
+		for _, f := range r.Fields {
+			if err := layout.Add(f.Name, f.Shift, f.Size); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", p.Name, r.Name, err)
+			}
+			if f.Enum != nil {
+				if err := layout.SetEnum(f.Name, f.Enum); err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", p.Name, r.Name, err)
+				}
+			}
+		}
+		reg := bitfield.NewRegister(r.Name, layout, uint32(r.ResetValue))
+		for _, f := range r.Fields {
+			if err := reg.SetAccess(f.Name, f.Access); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", p.Name, r.Name, err)
+			}
+			if err := reg.SetSideEffect(f.Name, f.SideEffect); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", p.Name, r.Name, err)
+			}
+		}
+		if err := m.Add(r.Offset, reg); err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Name, err)
+		}
+	}
+	return m, nil
+}
+
+// Parse reads a CMSIS-SVD document from r.
+func Parse(r io.Reader) (*Device, error) {
+	var doc xmlDevice
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("svd: %w", err)
+	}
+	defaults, err := doc.props.resolve(registerProps{size: 32, access: bitfield.ReadWrite})
+	if err != nil {
+		return nil, fmt.Errorf("svd: device %s: %w", doc.Name, err)
+	}
+
+	d := &Device{Name: doc.Name}
+	byName := make(map[string]*xmlPeripheral, len(doc.Peripherals))
+	for i := range doc.Peripherals {
+		byName[doc.Peripherals[i].Name] = &doc.Peripherals[i]
+	}
+	for i := range doc.Peripherals {
+		xp := &doc.Peripherals[i]
+		p, err := convertPeripheral(xp, byName, defaults)
+		if err != nil {
+			return nil, fmt.Errorf("svd: peripheral %s: %w", xp.Name, err)
+		}
+		d.Peripherals = append(d.Peripherals, p)
+	}
+	return d, nil
+}
+
+// convertPeripheral converts a peripheral element, resolving derivedFrom.
+func convertPeripheral(xp *xmlPeripheral, byName map[string]*xmlPeripheral, defaults registerProps) (*Peripheral, error) {
+	base, err := parseNumber(xp.BaseAddress)
+	if err != nil {
+		return nil, fmt.Errorf("baseAddress: %w", err)
+	}
+	p := &Peripheral{Name: xp.Name, Description: cleanText(xp.Description), BaseAddress: base}
+
+	src := xp
+	if xp.DerivedFrom != "" {
+		parent, ok := byName[xp.DerivedFrom]
+		if !ok {
+			return nil, fmt.Errorf("derived from unknown peripheral %q", xp.DerivedFrom)
+		}
+		if len(xp.Registers.Registers) == 0 && len(xp.Registers.Clusters) == 0 {
+			src = parent
+		}
+		if p.Description == "" {
+			p.Description = cleanText(parent.Description)
+		}
+	}
+	props, err := src.props.resolve(defaults)
+	if err != nil {
+		return nil, err
+	}
+	p.Registers, err = convertRegisters(src.Registers, 0, "", props)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// convertRegisters flattens the registers and clusters of a registers block.
+// offset and prefix are those of the enclosing cluster.
+func convertRegisters(block xmlRegisters, offset uint64, prefix string, props registerProps) ([]*Register, error) {
+	var regs []*Register
+	for _, xr := range block.Registers {
+		if xr.AlternateRegister != "" || xr.AlternateGroup != "" {
+			continue // Alternate views overlap their primary register
+		}
+		instances, err := xr.dim.expand(xr.Name)
+		if err != nil {
+			return nil, fmt.Errorf("register %s: %w", xr.Name, err)
+		}
+		rprops, err := xr.props.resolve(props)
+		if err != nil {
+			return nil, fmt.Errorf("register %s: %w", xr.Name, err)
+		}
+		roffset, err := parseNumber(xr.AddressOffset)
+		if err != nil {
+			return nil, fmt.Errorf("register %s: addressOffset: %w", xr.Name, err)
+		}
+		fields, err := convertFields(xr.Fields, rprops.access)
+		if err != nil {
+			return nil, fmt.Errorf("register %s: %w", xr.Name, err)
+		}
+		for _, inst := range instances {
+			regs = append(regs, &Register{
+				Name:        prefix + inst.name,
+				Description: cleanText(xr.Description),
+				Offset:      offset + roffset + inst.offset,
+				Size:        rprops.size,
+				Access:      rprops.access,
+				ResetValue:  rprops.reset,
+				Fields:      fields,
+			})
+		}
+	}
+	for _, xc := range block.Clusters {
+		instances, err := xc.dim.expand(xc.Name)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %w", xc.Name, err)
+		}
+		cprops, err := xc.props.resolve(props)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %w", xc.Name, err)
+		}
+		coffset, err := parseNumber(xc.AddressOffset)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: addressOffset: %w", xc.Name, err)
+		}
+		for _, inst := range instances {
+			inner, err := convertRegisters(xc.xmlRegisters, offset+coffset+inst.offset, prefix+inst.name+"_", cprops)
+			if err != nil {
+				return nil, fmt.Errorf("cluster %s: %w", xc.Name, err)
+			}
+			regs = append(regs, inner...)
+		}
+	}
+	return regs, nil
+}
+
+// bitRangePattern matches the bitRange form "[msb:lsb]".
+var bitRangePattern = regexp.MustCompile(`^\[\s*(\d+)\s*:\s*(\d+)\s*\]$`)
+
+// convertFields converts the fields of a register.
+func convertFields(xfs []xmlField, access bitfield.Access) ([]*Field, error) {
+	var fields []*Field
+	for _, xf := range xfs {
+		f := &Field{Name: xf.Name, Description: cleanText(xf.Description), Access: access}
+		switch {
+		case xf.BitOffset != "":
+			shift, err := parseNumber(xf.BitOffset)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: bitOffset: %w", xf.Name, err)
+			}
+			size := uint64(1)
+			if xf.BitWidth != "" {
+				if size, err = parseNumber(xf.BitWidth); err != nil {
+					return nil, fmt.Errorf("field %s: bitWidth: %w", xf.Name, err)
+				}
+			}
+			f.Shift, f.Size = uint(shift), uint(size)
+		case xf.LSB != "" && xf.MSB != "":
+			lsb, err := parseNumber(xf.LSB)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: lsb: %w", xf.Name, err)
+			}
+			msb, err := parseNumber(xf.MSB)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: msb: %w", xf.Name, err)
+			}
+			if msb < lsb {
+				return nil, fmt.Errorf("field %s: msb %d below lsb %d", xf.Name, msb, lsb)
+			}
+			f.Shift, f.Size = uint(lsb), uint(msb-lsb+1)
+		case xf.BitRange != "":
+			m := bitRangePattern.FindStringSubmatch(strings.TrimSpace(xf.BitRange))
+			if m == nil {
+				return nil, fmt.Errorf("field %s: invalid bitRange %q", xf.Name, xf.BitRange)
+			}
+			msb, _ := strconv.ParseUint(m[1], 10, 0)
+			lsb, _ := strconv.ParseUint(m[2], 10, 0)
+			if msb < lsb {
+				return nil, fmt.Errorf("field %s: invalid bitRange %q", xf.Name, xf.BitRange)
+			}
+			f.Shift, f.Size = uint(lsb), uint(msb-lsb+1)
+		default:
+			return nil, fmt.Errorf("field %s: missing bit position", xf.Name)
+		}
+		if xf.Access != "" {
+			a, err := parseAccess(xf.Access)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", xf.Name, err)
+			}
+			f.Access = a
+		}
+		switch strings.TrimSpace(xf.ModifiedWriteValues) {
+		case "", "modify":
+		case "oneToClear":
+			f.SideEffect = bitfield.WriteOneToClear
+		case "oneToSet":
+			f.SideEffect = bitfield.WriteOneToSet
+		default:
+			return nil, fmt.Errorf("field %s: unsupported modifiedWriteValues %q", xf.Name, xf.ModifiedWriteValues)
+		}
+		switch strings.TrimSpace(xf.ReadAction) {
+		case "", "modify", "modifyExternal":
+		case "clear":
+			if f.SideEffect != bitfield.NoSideEffect {
+				return nil, fmt.Errorf("field %s: readAction clear conflicts with modifiedWriteValues %q", xf.Name, xf.ModifiedWriteValues)
+			}
+			f.SideEffect = bitfield.ReadToClear
+		default:
+			return nil, fmt.Errorf("field %s: unsupported readAction %q", xf.Name, xf.ReadAction)
+		}
+		ev, err := readEnumeratedValues(xf.EnumeratedValues)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", xf.Name, err)
+		}
+		for _, v := range ev.Values {
+			if v.Value == "" || isDontCare(v.Value) {
+				continue // isDefault entries have no value; don't-care values match several
+			}
+			n, err := parseNumber(v.Value)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: enumerated value %s: %w", xf.Name, v.Name, err)
+			}
+			if f.Enum == nil {
+				f.Enum = make(map[uint64]string)
+			}
+			f.Enum[n] = v.Name
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// readEnumeratedValues returns the enumerated values set used to name the values
+// read from a field: the read or read-write set, or else the write set.
+func readEnumeratedValues(sets []xmlEnumeratedValues) (xmlEnumeratedValues, error) {
+	var read, write *xmlEnumeratedValues
+	for i := range sets {
+		switch usage := strings.TrimSpace(sets[i].Usage); usage {
+		case "", "read-write", "read":
+			if read == nil {
+				read = &sets[i]
+			}
+		case "write":
+			if write == nil {
+				write = &sets[i]
+			}
+		default:
+			return xmlEnumeratedValues{}, fmt.Errorf("unknown enumeratedValues usage %q", usage)
+		}
+	}
+	switch {
+	case read != nil:
+		return *read, nil
+	case write != nil:
+		return *write, nil
+	}
+	return xmlEnumeratedValues{}, nil
+}
+
+// isDontCare reports whether s is a binary enumerated value with don't-care
+// bits, such as #1x0.
+func isDontCare(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "#") && strings.ContainsAny(s, "xX")
+}
+
+// parseNumber parses an SVD scaledNonNegativeInteger: decimal, 0x hexadecimal or #binary.
+func parseNumber(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "#"):
+		return strconv.ParseUint(s[1:], 2, 64)
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		return strconv.ParseUint(s[2:], 16, 64)
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// parseAccess maps an SVD access type to a bitfield.Access.
+func parseAccess(s string) (bitfield.Access, error) {
+	switch strings.TrimSpace(s) {
+	case "read-write", "read-writeOnce":
+		return bitfield.ReadWrite, nil
+	case "read-only":
+		return bitfield.ReadOnly, nil
+	case "write-only", "writeOnce":
+		return bitfield.WriteOnly, nil
+	}
+	return 0, fmt.Errorf("unknown access %q", s)
+}
+
+// cleanText collapses the whitespace of a description.
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,234 @@
+package svd
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+func parseExample(t *testing.T) *Device {
+	t.Helper()
+	f, err := os.Open("testdata/example.svd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	d, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return d
+}
+
+func TestParse(t *testing.T) {
+	d := parseExample(t)
+	if d.Name != "EXAMPLE" || len(d.Peripherals) != 2 {
+		t.Fatalf("device = %s with %d peripherals, want EXAMPLE with 2", d.Name, len(d.Peripherals))
+	}
+
+	timer := d.Peripheral("TIMER0")
+	if timer == nil {
+		t.Fatal("Peripheral(TIMER0) = nil")
+	}
+	if timer.BaseAddress != 0x40010000 || timer.Description != "32-bit timer" {
+		t.Errorf("TIMER0 = base 0x%X, description %q", timer.BaseAddress, timer.Description)
+	}
+
+	var names []string
+	for _, r := range timer.Registers {
+		names = append(names, r.Name)
+	}
+	want := []string{"CR", "SR", "CC0", "CC1", "CC2", "CHA_CFG", "CHB_CFG"}
+	if !slices.Equal(names, want) {
+		t.Errorf("registers = %v, want %v", names, want)
+	}
+
+	cr := timer.Registers[0]
+	if cr.ResetValue != 4 || cr.Size != 32 || cr.Access != bitfield.ReadWrite {
+		t.Errorf("CR = reset 0x%X, size %d, access %v", cr.ResetValue, cr.Size, cr.Access)
+	}
+	mode := cr.Fields[1]
+	if mode.Shift != 1 || mode.Size != 2 {
+		t.Errorf("MODE = shift %d, size %d, want shift 1, size 2", mode.Shift, mode.Size)
+	}
+	wantEnum := map[uint64]string{0: "OneShot", 1: "Periodic", 2: "Capture"}
+	if !maps.Equal(mode.Enum, wantEnum) {
+		t.Errorf("MODE enum = %v, want %v", mode.Enum, wantEnum)
+	}
+	if p := cr.Fields[2]; p.Shift != 8 || p.Size != 8 {
+		t.Errorf("PRESCALE = shift %d, size %d, want shift 8, size 8", p.Shift, p.Size)
+	}
+
+	sr := timer.Registers[1]
+	if sr.Fields[0].Access != bitfield.ReadOnly || sr.Fields[1].Access != bitfield.WriteOnly {
+		t.Errorf("SR field access = %v, %v, want RO, WO", sr.Fields[0].Access, sr.Fields[1].Access)
+	}
+
+	offsets := []uint64{0x0, 0x4, 0x10, 0x14, 0x18, 0x20, 0x30}
+	for i, r := range timer.Registers {
+		if r.Offset != offsets[i] {
+			t.Errorf("%s offset = 0x%X, want 0x%X", r.Name, r.Offset, offsets[i])
+		}
+	}
+
+	derived := d.Peripheral("TIMER1")
+	if derived.BaseAddress != 0x40011000 || len(derived.Registers) != len(timer.Registers) {
+		t.Errorf("TIMER1 = base 0x%X with %d registers, want 0x40011000 with %d",
+			derived.BaseAddress, len(derived.Registers), len(timer.Registers))
+	}
+	if derived.Description != timer.Description {
+		t.Errorf("TIMER1 description = %q, want inherited %q", derived.Description, timer.Description)
+	}
+}
+
+func TestPeripheral_RegisterMap(t *testing.T) {
+	d := parseExample(t)
+	m, err := d.Peripheral("TIMER0").RegisterMap()
+	if err != nil {
+		t.Fatalf("RegisterMap: %v", err)
+	}
+	cr, ok := m.ByName("CR")
+	if !ok {
+		t.Fatal("CR not found")
+	}
+	if got, _ := cr.Get("MODE"); got != 2 {
+		t.Errorf("CR.MODE at reset = %d, want 2", got)
+	}
+	sr, _ := m.ByOffset(0x4)
+	if err := sr.Set("BUSY", 1); err == nil {
+		t.Error("Set(SR.BUSY) succeeded on read-only field, want error")
+	}
+	if sr.Access("CLR") != bitfield.WriteOnly {
+		t.Errorf("SR.CLR access = %v, want WO", sr.Access("CLR"))
+	}
+	if sr.SideEffect("OVF") != bitfield.WriteOneToClear || sr.SideEffect("CAPT") != bitfield.ReadToClear {
+		t.Errorf("SR side effects = %v, %v, want W1C, RC", sr.SideEffect("OVF"), sr.SideEffect("CAPT"))
+	}
+	sr.Load(0x4)
+	if err := sr.Set("OVF", 1); err != nil || sr.Value() != 0 {
+		t.Errorf("Set(SR.OVF, 1) = %v, value 0x%X, want W1C to clear it", err, sr.Value())
+	}
+	if mode, _ := cr.Layout.Field("MODE"); !maps.Equal(mode.Enum(), map[uint64]string{0: "OneShot", 1: "Periodic", 2: "Capture"}) {
+		t.Errorf("MODE enum = %v, want the enumerated values", mode.Enum())
+	}
+	if _, ok := m.ByName("CHB_CFG"); !ok {
+		t.Error("CHB_CFG not found")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	wrap := func(registers string) string {
+		return `<device><name>D</name><peripherals><peripheral><name>P</name>` +
+			`<baseAddress>0</baseAddress><registers>` + registers + `</registers></peripheral></peripherals></device>`
+	}
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"malformed xml", "<device>"},
+		{"bad base address", `<device><peripherals><peripheral><name>P</name><baseAddress>zz</baseAddress></peripheral></peripherals></device>`},
+		{"unknown parent", `<device><peripherals><peripheral derivedFrom="X"><name>P</name><baseAddress>0</baseAddress></peripheral></peripherals></device>`},
+		{"bad access", wrap(`<register><name>R</name><addressOffset>0</addressOffset><access>sometimes</access></register>`)},
+		{"missing bit position", wrap(`<register><name>R</name><addressOffset>0</addressOffset><fields><field><name>F</name></field></fields></register>`)},
+		{"bad bit range", wrap(`<register><name>R</name><addressOffset>0</addressOffset><fields><field><name>F</name><bitRange>[1:4]</bitRange></field></fields></register>`)},
+		{"bad dim index", wrap(`<register><dim>2</dim><dimIncrement>4</dimIncrement><dimIndex>A,B,C</dimIndex><name>R%s</name><addressOffset>0</addressOffset></register>`)},
+		{"zero dim", wrap(`<register><dim>0</dim><dimIncrement>4</dimIncrement><name>R%s</name><addressOffset>0</addressOffset></register>`)},
+		{"huge dim", wrap(`<register><dim>0x1000000000</dim><dimIncrement>4</dimIncrement><name>R%s</name><addressOffset>0</addressOffset></register>`)},
+		{"bad modifiedWriteValues", wrap(`<register><name>R</name><addressOffset>0</addressOffset><fields><field><name>F</name><bitOffset>0</bitOffset><modifiedWriteValues>zeroToToggle</modifiedWriteValues></field></fields></register>`)},
+		{"conflicting side effects", wrap(`<register><name>R</name><addressOffset>0</addressOffset><fields><field><name>F</name><bitOffset>0</bitOffset><modifiedWriteValues>oneToClear</modifiedWriteValues><readAction>clear</readAction></field></fields></register>`)},
+		{"bad enumeratedValues usage", wrap(`<register><name>R</name><addressOffset>0</addressOffset><fields><field><name>F</name><bitOffset>0</bitOffset><enumeratedValues><usage>sometimes</usage></enumeratedValues></field></fields></register>`)},
+		{"bad readAction", wrap(`<register><name>R</name><addressOffset>0</addressOffset><fields><field><name>F</name><bitOffset>0</bitOffset><readAction>explode</readAction></field></fields></register>`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.src)); err == nil {
+				t.Errorf("Parse(%q) succeeded, want error", tt.src)
+			}
+		})
+	}
+}
+
+func TestParse_EnumeratedValues(t *testing.T) {
+	src := `<device><name>D</name><peripherals><peripheral><name>P</name><baseAddress>0</baseAddress><registers>
+	<register><name>R</name><addressOffset>0</addressOffset><fields>
+	  <field><name>CMD</name><bitOffset>0</bitOffset><bitWidth>3</bitWidth>
+	    <enumeratedValues><usage>write</usage>
+	      <enumeratedValue><name>Start</name><value>1</value></enumeratedValue>
+	    </enumeratedValues>
+	    <enumeratedValues><usage>read</usage>
+	      <enumeratedValue><name>Idle</name><value>0</value></enumeratedValue>
+	      <enumeratedValue><name>Busy</name><value>#1x0</value></enumeratedValue>
+	      <enumeratedValue><name>Done</name><value>#001</value></enumeratedValue>
+	    </enumeratedValues>
+	  </field>
+	  <field><name>GO</name><bitOffset>4</bitOffset>
+	    <enumeratedValues><usage>write</usage>
+	      <enumeratedValue><name>Go</name><value>1</value></enumeratedValue>
+	    </enumeratedValues>
+	  </field>
+	</fields></register></registers></peripheral></peripherals></device>`
+	d, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	fields := d.Peripherals[0].Registers[0].Fields
+	if want := map[uint64]string{0: "Idle", 1: "Done"}; !maps.Equal(fields[0].Enum, want) {
+		t.Errorf("CMD enum = %v, want the read values %v", fields[0].Enum, want)
+	}
+	if want := map[uint64]string{1: "Go"}; !maps.Equal(fields[1].Enum, want) {
+		t.Errorf("GO enum = %v, want the write values %v", fields[1].Enum, want)
+	}
+	if _, err := d.Peripherals[0].RegisterMap(); err != nil {
+		t.Errorf("RegisterMap: %v", err)
+	}
+}
+
+func TestPeripheral_RegisterMapErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		p    *Peripheral
+	}{
+		{"16-bit register", &Peripheral{Name: "P", Registers: []*Register{{Name: "R", Size: 16}}}},
+		{"overlapping fields", &Peripheral{Name: "P", Registers: []*Register{{Name: "R", Size: 32, Fields: []*Field{
+			{Name: "A", Shift: 0, Size: 4}, {Name: "B", Shift: 2, Size: 4},
+		}}}}},
+		{"overlapping registers", &Peripheral{Name: "P", Registers: []*Register{
+			{Name: "A", Size: 32, Offset: 0}, {Name: "B", Size: 32, Offset: 2},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.p.RegisterMap(); err == nil {
+				t.Error("RegisterMap() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"42", 42, false},
+		{"0x2A", 42, false},
+		{"0X2a", 42, false},
+		{"#101010", 42, false},
+		{" 7 ", 7, false},
+		{"x", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNumber(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseNumber(%q) = %v, %v, want %v, err = %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package svd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lnear-dev/bitfield"
+)
+
+// The xml types mirror the subset of the CMSIS-SVD schema that is imported.
+
+type xmlDevice struct {
+	Name        string          `xml:"name"`
+	Peripherals []xmlPeripheral `xml:"peripherals>peripheral"`
+	props
+}
+
+type xmlPeripheral struct {
+	Name        string       `xml:"name"`
+	DerivedFrom string       `xml:"derivedFrom,attr"`
+	Description string       `xml:"description"`
+	BaseAddress string       `xml:"baseAddress"`
+	Registers   xmlRegisters `xml:"registers"`
+	props
+}
+
+type xmlRegisters struct {
+	Registers []xmlRegister `xml:"register"`
+	Clusters  []xmlCluster  `xml:"cluster"`
+}
+
+type xmlCluster struct {
+	Name          string `xml:"name"`
+	AddressOffset string `xml:"addressOffset"`
+	xmlRegisters
+	dim
+	props
+}
+
+type xmlRegister struct {
+	Name              string     `xml:"name"`
+	Description       string     `xml:"description"`
+	AddressOffset     string     `xml:"addressOffset"`
+	AlternateRegister string     `xml:"alternateRegister"`
+	AlternateGroup    string     `xml:"alternateGroup"`
+	Fields            []xmlField `xml:"fields>field"`
+	dim
+	props
+}
+
+type xmlField struct {
+	Name                string                `xml:"name"`
+	Description         string                `xml:"description"`
+	BitOffset           string                `xml:"bitOffset"`
+	BitWidth            string                `xml:"bitWidth"`
+	LSB                 string                `xml:"lsb"`
+	MSB                 string                `xml:"msb"`
+	BitRange            string                `xml:"bitRange"`
+	Access              string                `xml:"access"`
+	ModifiedWriteValues string                `xml:"modifiedWriteValues"`
+	ReadAction          string                `xml:"readAction"`
+	EnumeratedValues    []xmlEnumeratedValues `xml:"enumeratedValues"`
+}
+
+type xmlEnumeratedValues struct {
+	Usage  string               `xml:"usage"`
+	Values []xmlEnumeratedValue `xml:"enumeratedValue"`
+}
+
+type xmlEnumeratedValue struct {
+	Name  string `xml:"name"`
+	Value string `xml:"value"`
+}
+
+// props holds the register properties that are inherited down the hierarchy.
+type props struct {
+	Size       string `xml:"size"`
+	Access     string `xml:"access"`
+	ResetValue string `xml:"resetValue"`
+}
+
+// registerProps is the resolved form of props.
+type registerProps struct {
+	size   uint
+	access bitfield.Access
+	reset  uint64
+}
+
+// resolve applies the properties declared at this level on top of inherited.
+func (p props) resolve(inherited registerProps) (registerProps, error) {
+	r := inherited
+	if p.Size != "" {
+		n, err := parseNumber(p.Size)
+		if err != nil {
+			return r, fmt.Errorf("size: %w", err)
+		}
+		r.size = uint(n)
+	}
+	if p.Access != "" {
+		a, err := parseAccess(p.Access)
+		if err != nil {
+			return r, err
+		}
+		r.access = a
+	}
+	if p.ResetValue != "" {
+		n, err := parseNumber(p.ResetValue)
+		if err != nil {
+			return r, fmt.Errorf("resetValue: %w", err)
+		}
+		r.reset = n
+	}
+	return r, nil
+}
+
+// dim holds the array properties of registers and clusters.
+type dim struct {
+	Dim          string `xml:"dim"`
+	DimIncrement string `xml:"dimIncrement"`
+	DimIndex     string `xml:"dimIndex"`
+}
+
+// maxDim is the largest number of elements of a register or cluster array,
+// so that a malformed file cannot make Parse allocate without bound.
+const maxDim = 4096
+
+// instance is one element of an expanded array.
+type instance struct {
+	name   string
+	offset uint64 // Offset relative to the first element
+}
+
+// expand returns the elements described by the dim properties.
+// Names containing "%s" are expanded with the element index; elements
+// without dim properties expand to a single instance.
+func (d dim) expand(name string) ([]instance, error) {
+	if d.Dim == "" {
+		return []instance{{name: name}}, nil
+	}
+	n, err := parseNumber(d.Dim)
+	if err != nil {
+		return nil, fmt.Errorf("dim: %w", err)
+	}
+	if n == 0 || n > maxDim {
+		return nil, fmt.Errorf("dim %d out of range, want 1 to %d", n, maxDim)
+	}
+	inc, err := parseNumber(d.DimIncrement)
+	if err != nil {
+		return nil, fmt.Errorf("dimIncrement: %w", err)
+	}
+	indices, err := d.indices(int(n))
+	if err != nil {
+		return nil, err
+	}
+	name = strings.ReplaceAll(name, "[%s]", "%s")
+	instances := make([]instance, n)
+	for i := range instances {
+		instances[i] = instance{
+			name:   strings.ReplaceAll(name, "%s", indices[i]),
+			offset: uint64(i) * inc,
+		}
+	}
+	return instances, nil
+}
+
+// indices returns the index strings of an array with n elements.
+func (d dim) indices(n int) ([]string, error) {
+	indices := make([]string, 0, n)
+	switch idx := strings.TrimSpace(d.DimIndex); {
+	case idx == "":
+		for i := 0; i < n; i++ {
+			indices = append(indices, strconv.Itoa(i))
+		}
+	case strings.Contains(idx, "-") && !strings.Contains(idx, ","):
+		lo, hi, _ := strings.Cut(idx, "-")
+		if len(lo) == 1 && len(hi) == 1 && (lo[0] < '0' || lo[0] > '9') {
+			for c := lo[0]; c <= hi[0]; c++ {
+				indices = append(indices, string(c))
+			}
+			break
+		}
+		start, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dimIndex %q", idx)
+		}
+		end, err := strconv.Atoi(hi)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dimIndex %q", idx)
+		}
+		for i := start; i <= end; i++ {
+			indices = append(indices, strconv.Itoa(i))
+		}
+	default:
+		for _, s := range strings.Split(idx, ",") {
+			indices = append(indices, strings.TrimSpace(s))
+		}
+	}
+	if len(indices) != n {
+		return nil, fmt.Errorf("dimIndex %q has %d entries, want %d", d.DimIndex, len(indices), n)
+	}
+	return indices, nil
+}
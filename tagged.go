@@ -0,0 +1,91 @@
+package bitfield
+
+import (
+	"fmt"
+	"math/bits"
+	"unsafe"
+)
+
+// HighTagBits is the number of unused high address bits TaggedPtr stores tags in:
+// 7 on 64-bit platforms, where user space addresses fit in 57 bits even with
+// 5-level paging, and 0 on 32-bit platforms.
+const HighTagBits = 7 * (^uint(0) >> 63)
+
+// TaggedPtr is a pointer to T with a small tag packed into bits of the
+// address that are always zero: first the low bits that are zero because of
+// the alignment of T, then, for larger tags, the unused high bits of 64-bit
+// addresses. It fits in a single word, so it can be stored and swapped with
+// sync/atomic, as in lock-free data structures.
+//
+// A TaggedPtr is an integer to the garbage collector: it does not keep the
+// value it points to alive, and the value may be freed or moved while only
+// a TaggedPtr refers to it. Use it only for memory outside the Go heap, such
+// as mmap'ed or C memory, or keep another ordinary pointer to the value for
+// as long as the TaggedPtr is used.
+type TaggedPtr[T any] uintptr
+
+// LowTagBits returns the number of low address bits that are zero in every
+// pointer to T because of its alignment.
+func LowTagBits[T any]() uint {
+	var zero T
+	return uint(bits.TrailingZeros(uint(unsafe.Alignof(zero))))
+}
+
+// TagBits returns the number of tag bits of a TaggedPtr to T.
+func TagBits[T any]() uint {
+	return LowTagBits[T]() + HighTagBits
+}
+
+// ptrTag returns the field holding the tag of a TaggedPtr to T.
+func ptrTag[T any]() SplitField[uint64, uint] {
+	low := LowTagBits[T]()
+	segments := make([]Segment, 0, 2)
+	if low > 0 {
+		segments = append(segments, Segment{Shift: 0, Size: low})
+	}
+	if HighTagBits > 0 {
+		segments = append(segments, Segment{Shift: unsignedSizeOf[uint]() - HighTagBits, Size: HighTagBits, ValueShift: low})
+	}
+	return NewSplit[uint64, uint](segments...)
+}
+
+// NewTaggedPtr packs a pointer and a tag.
+// Returns an error if p is not aligned for T or uses the high tag bits,
+// neither of which happens for pointers to Go values, or the tag needs more
+// than TagBits[T]() bits.
+func NewTaggedPtr[T any](p *T, tag uint64) (TaggedPtr[T], error) {
+	field := ptrTag[T]()
+	addr := uint(uintptr(unsafe.Pointer(p)))
+	if addr&field.Mask != 0 {
+		return 0, fmt.Errorf("pointer 0x%X not aligned to %d bytes or above %d address bits",
+			addr, 1<<LowTagBits[T](), unsignedSizeOf[uint]()-HighTagBits)
+	}
+	if !field.IsValid(tag) {
+		return 0, fmt.Errorf("tag %v out of range, max %v", tag, field.ValueMask)
+	}
+	return TaggedPtr[T](addr | field.Encode(tag)), nil
+}
+
+// Ptr returns the pointer with the tag removed.
+// The result is only valid if the value is still alive; see TaggedPtr.
+func (tp TaggedPtr[T]) Ptr() *T {
+	addr := uintptr(uint(tp) &^ ptrTag[T]().Mask)
+	// Reinterpreting the word instead of converting the integer keeps vet and
+	// the checkptr instrumentation quiet; the GC caveats of TaggedPtr apply.
+	return *(**T)(unsafe.Pointer(&addr))
+}
+
+// Tag returns the tag.
+func (tp TaggedPtr[T]) Tag() uint64 {
+	return ptrTag[T]().Decode(uint(tp))
+}
+
+// WithTag returns the pointer with a different tag.
+// Returns an error if the tag needs more than TagBits[T]() bits.
+func (tp TaggedPtr[T]) WithTag(tag uint64) (TaggedPtr[T], error) {
+	field := ptrTag[T]()
+	if !field.IsValid(tag) {
+		return tp, fmt.Errorf("tag %v out of range, max %v", tag, field.ValueMask)
+	}
+	return TaggedPtr[T](field.Update(uint(tp), tag)), nil
+}
@@ -0,0 +1,72 @@
+package bitfield
+
+import (
+	"testing"
+	"unsafe"
+)
+
+type node struct {
+	next  *node
+	value int64
+}
+
+func TestTaggedPtr(t *testing.T) {
+	n := &node{value: 42}
+	if got := LowTagBits[node](); got != 3 {
+		t.Fatalf("LowTagBits[node]() = %d, want 3", got)
+	}
+	if got := LowTagBits[byte](); got != 0 {
+		t.Errorf("LowTagBits[byte]() = %d, want 0", got)
+	}
+
+	tests := []uint64{0, 1, 5, 7, 8, 0x3FF}
+	for _, tag := range tests {
+		if tag >= 1<<TagBits[node]() {
+			continue
+		}
+		tp, err := NewTaggedPtr(n, tag)
+		if err != nil {
+			t.Fatalf("NewTaggedPtr(tag %d): %v", tag, err)
+		}
+		if tp.Ptr() != n || tp.Tag() != tag {
+			t.Errorf("tag %d: Ptr() = %p, Tag() = %d, want %p, %d", tag, tp.Ptr(), tp.Tag(), n, tag)
+		}
+		if tag < 8 && uintptr(tp) != uintptr(unsafe.Pointer(n))|uintptr(tag) {
+			t.Errorf("tag %d: TaggedPtr = 0x%X, want tag in the alignment bits", tag, uintptr(tp))
+		}
+	}
+}
+
+func TestTaggedPtr_WithTag(t *testing.T) {
+	n := &node{}
+	tp, err := NewTaggedPtr(n, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp2, err := tp.WithTag(2)
+	if err != nil || tp2.Tag() != 2 || tp2.Ptr() != n {
+		t.Errorf("WithTag(2) = tag %d, ptr %p, %v, want tag 2, ptr %p", tp2.Tag(), tp2.Ptr(), err, n)
+	}
+	if tp.Tag() != 5 {
+		t.Errorf("WithTag modified the receiver to tag %d", tp.Tag())
+	}
+	if _, err := tp.WithTag(1 << TagBits[node]()); err == nil {
+		t.Error("WithTag beyond TagBits succeeded, want error")
+	}
+}
+
+func TestNewTaggedPtr_Errors(t *testing.T) {
+	if _, err := NewTaggedPtr(&node{}, 1<<TagBits[node]()); err == nil {
+		t.Error("NewTaggedPtr with oversized tag succeeded, want error")
+	}
+
+	// A *uint64 into the middle of a word is misaligned; a *uint32 is not.
+	buf := make([]uint64, 2)
+	mid := unsafe.Add(unsafe.Pointer(&buf[0]), 4)
+	if _, err := NewTaggedPtr((*uint64)(mid), 0); err == nil {
+		t.Error("NewTaggedPtr with misaligned pointer succeeded, want error")
+	}
+	if _, err := NewTaggedPtr((*uint32)(mid), 0); err != nil {
+		t.Errorf("NewTaggedPtr with aligned *uint32: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package bitfield
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// MarshalText renders the container in the canonical field=value form,
+// for example "active=1,priority=High,category=5".
+// Fields appear in the order they were added to the layout, and values with a
+// registered enum name are rendered as the name. Fields that are not present,
+// as declared with Layout.SetCondition, are left out.
+func (p Packed[U]) MarshalText() ([]byte, error) {
+	l, err := p.layout()
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	for i, f := range l.fields {
+		if !l.present(i, p.Value) {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(f.name)
+		b.WriteByte('=')
+		b.WriteString(f.field.DecodeString(p.Value))
+	}
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText sets fields from the field=value form produced by MarshalText.
+// Values may be enum names or numbers with an optional 0x, 0o or 0b prefix,
+// and spaces around names and values are ignored.
+// Fields that are not mentioned are left unchanged.
+// Returns an error, leaving p unchanged, if p has no layout, an entry is malformed,
+// a field is unknown, given more than once or not present in the result, or a
+// value does not fit in its field.
+func (p *Packed[U]) UnmarshalText(text []byte) error {
+	l, err := p.layout()
+	if err != nil {
+		return err
+	}
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		return nil
+	}
+	container := p.Value
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid entry %q, want field=value", strings.TrimSpace(entry))
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		bf, ok := l.Field(name)
+		if !ok {
+			return fmt.Errorf("unknown field %q", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate field %q", name)
+		}
+		seen[name] = true
+		v, err := bf.Parse(value)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", name, err)
+		}
+		container = bf.Update(container, v)
+	}
+	if err := l.checkPresent(container, maps.Keys(seen)); err != nil {
+		return err
+	}
+	p.Value = container
+	return nil
+}
@@ -0,0 +1,64 @@
+package bitfield
+
+import "testing"
+
+func TestPacked_MarshalText(t *testing.T) {
+	l := newStatusLayout(t)
+	tests := []struct {
+		value uint32
+		want  string
+	}{
+		{0x00002A57, "active=1,priority=High,category=5,error=42"},
+		{0x00000004, "active=0,priority=2,category=0,error=0"},
+	}
+
+	for _, tt := range tests {
+		got, err := l.Bind(tt.value).MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(0x%08X): %v", tt.value, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("MarshalText(0x%08X) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestPacked_UnmarshalText(t *testing.T) {
+	l := newStatusLayout(t)
+	tests := []struct {
+		name    string
+		initial uint32
+		input   string
+		want    uint32
+		wantErr bool
+	}{
+		{"round trip", 0, "active=1,priority=High,category=5,error=42", 0x00002A57, false},
+		{"spaces and prefixes", 0, " active = 1 , priority = 0b11, error=0x2A ", 0x00002A07, false},
+		{"partial update", 0x0000FFFF, "category=0", 0x0000FF0F, false},
+		{"empty", 0x0000FFFF, "", 0x0000FFFF, false},
+		{"missing value", 0x0000FFFF, "active", 0x0000FFFF, true},
+		{"unknown field", 0x0000FFFF, "bogus=1", 0x0000FFFF, true},
+		{"duplicate field", 0x0000FFFF, "active=1,active=0", 0x0000FFFF, true},
+		{"value too large", 0x0000FFFF, "active=0,category=16", 0x0000FFFF, true},
+		{"unknown name", 0x0000FFFF, "priority=Urgent", 0x0000FFFF, true},
+		{"trailing comma", 0x0000FFFF, "active=0,", 0x0000FFFF, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := l.Bind(tt.initial)
+			err := p.UnmarshalText([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText(%q): err = %v, want err = %v", tt.input, err, tt.wantErr)
+			}
+			if p.Value != tt.want {
+				t.Errorf("UnmarshalText(%q): Value = 0x%08X, want 0x%08X", tt.input, p.Value, tt.want)
+			}
+		})
+	}
+
+	var p Packed[uint32]
+	if err := p.UnmarshalText([]byte("active=1")); err == nil {
+		t.Error("UnmarshalText without layout succeeded, want error")
+	}
+}
@@ -0,0 +1,112 @@
+package bitfield
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// TimeField packs a time.Time into a BitField as the number of Resolution units
+// elapsed since Epoch, as found in telemetry frames, packed database keys and
+// file formats such as FAT or Windows FILETIME.
+// Times are truncated to the resolution when encoded.
+type TimeField[T Unsigned, U storageType] struct {
+	Field      BitField[T, U] // Field holding the number of units
+	Epoch      time.Time      // Time of raw value 0
+	Resolution time.Duration  // Length of one unit; must be positive
+}
+
+// NewTimeField creates a TimeField over field counting units of resolution since epoch.
+// Note: This function doesn't perform validation, use SafeTimeField for validated creation.
+func NewTimeField[T Unsigned, U storageType](field BitField[T, U], epoch time.Time, resolution time.Duration) TimeField[T, U] {
+	return TimeField[T, U]{Field: field, Epoch: epoch, Resolution: resolution}
+}
+
+// SafeTimeField creates a TimeField after validating the parameters.
+// Returns an error if resolution is not positive or the largest raw value of
+// the field lies beyond the range of time.Time.
+func SafeTimeField[T Unsigned, U storageType](field BitField[T, U], epoch time.Time, resolution time.Duration) (TimeField[T, U], error) {
+	tf := NewTimeField(field, epoch, resolution)
+	if resolution <= 0 {
+		return tf, fmt.Errorf("resolution must be positive")
+	}
+	hi, lo := bits.Mul64(uint64(field.Max()), uint64(resolution))
+	if hi >= uint64(time.Second) {
+		return tf, fmt.Errorf("field range exceeds time.Time")
+	}
+	if secs, _ := bits.Div64(hi, lo, uint64(time.Second)); secs > uint64(math.MaxInt64-max(epoch.Unix(), 0)) {
+		return tf, fmt.Errorf("field range exceeds time.Time")
+	}
+	return tf, nil
+}
+
+// Decode extracts the raw value from the container and converts it to a time.
+func (tf TimeField[T, U]) Decode(container U) time.Time {
+	return tf.Time(tf.Field.Decode(container))
+}
+
+// Time converts a raw value to the time it represents.
+func (tf TimeField[T, U]) Time(raw T) time.Time {
+	hi, lo := bits.Mul64(uint64(raw), uint64(tf.Resolution))
+	secs, nsec := bits.Div64(hi, lo, uint64(time.Second))
+	return time.Unix(tf.Epoch.Unix()+int64(secs), int64(tf.Epoch.Nanosecond())+int64(nsec)).In(tf.Epoch.Location())
+}
+
+// Raw converts a time to the number of whole units since the epoch.
+// Returns an error if Resolution is not positive, or the time is before the
+// epoch or too late for the field.
+func (tf TimeField[T, U]) Raw(t time.Time) (T, error) {
+	if tf.Resolution <= 0 {
+		return 0, fmt.Errorf("resolution must be positive")
+	}
+	secs := t.Unix() - tf.Epoch.Unix()
+	nsec := int64(t.Nanosecond()) - int64(tf.Epoch.Nanosecond())
+	if nsec < 0 {
+		secs, nsec = secs-1, nsec+int64(time.Second)
+	}
+	if secs < 0 {
+		return 0, fmt.Errorf("time %v before epoch %v", t, tf.Epoch)
+	}
+	hi, lo := bits.Mul64(uint64(secs), uint64(time.Second))
+	lo, carry := bits.Add64(lo, uint64(nsec), 0)
+	hi += carry
+	if hi >= uint64(tf.Resolution) {
+		return 0, fmt.Errorf("time %v out of range [%v, %v]", t, tf.Min(), tf.Max())
+	}
+	units, _ := bits.Div64(hi, lo, uint64(tf.Resolution))
+	if units > uint64(tf.Field.Max()) {
+		return 0, fmt.Errorf("time %v out of range [%v, %v]", t, tf.Min(), tf.Max())
+	}
+	return T(units), nil
+}
+
+// Encode converts a time to raw units and encodes it into the field.
+// Returns an error under the same conditions as Raw.
+func (tf TimeField[T, U]) Encode(t time.Time) (U, error) {
+	raw, err := tf.Raw(t)
+	if err != nil {
+		return 0, err
+	}
+	return tf.Field.Encode(raw), nil
+}
+
+// Update converts a time to raw units and sets the field within an existing container.
+// Returns an error under the same conditions as Raw, leaving the container unchanged.
+func (tf TimeField[T, U]) Update(previous U, t time.Time) (U, error) {
+	raw, err := tf.Raw(t)
+	if err != nil {
+		return previous, err
+	}
+	return tf.Field.Update(previous, raw), nil
+}
+
+// Min returns the earliest time the field can represent, the epoch.
+func (tf TimeField[T, U]) Min() time.Time {
+	return tf.Epoch
+}
+
+// Max returns the latest time the field can represent.
+func (tf TimeField[T, U]) Max() time.Time {
+	return tf.Time(tf.Field.Max())
+}
@@ -0,0 +1,104 @@
+package bitfield
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeField_Decode(t *testing.T) {
+	unix := time.Unix(0, 0).UTC()
+	fileTime := time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC)
+	newYear := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		tf        TimeField[uint64, uint64]
+		container uint64
+		want      time.Time
+	}{
+		{"unix seconds", NewTimeField(New[uint64, uint64](32, 32), unix, time.Second), 0x6592008000000000, newYear},
+		{"milliseconds", NewTimeField(New[uint64, uint64](0, 41), newYear, time.Millisecond), 1500, newYear.Add(1500 * time.Millisecond)},
+		{"filetime", NewTimeField(New[uint64, uint64](0, 63), fileTime, 100*time.Nanosecond), 133485408000000000, newYear},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.tf.Decode(tt.container)
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("Decode(0x%X) = %v, want %v", tt.container, got, tt.want)
+			}
+			if c, err := tt.tf.Encode(tt.want); err != nil || c != tt.container {
+				t.Errorf("Encode(%v) = 0x%X, %v, want 0x%X", tt.want, c, err, tt.container)
+			}
+		})
+	}
+}
+
+func TestTimeField_Raw(t *testing.T) {
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 500_000_000, time.UTC)
+	tf := NewTimeField(New[uint8, uint32](8, 8), epoch, time.Second)
+	tests := []struct {
+		name    string
+		t       time.Time
+		want    uint8
+		wantErr bool
+	}{
+		{"epoch", epoch, 0, false},
+		{"truncated", epoch.Add(1900 * time.Millisecond), 1, false},
+		{"max", epoch.Add(255 * time.Second), 255, false},
+		{"other zone", epoch.In(time.FixedZone("X", 3600)).Add(10 * time.Second), 10, false},
+		{"before epoch", epoch.Add(-time.Nanosecond), 0, true},
+		{"beyond max", epoch.Add(256 * time.Second), 0, true},
+		{"far future", time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tf.Raw(tt.t)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Raw(%v) = %v, %v, want %v, err = %v", tt.t, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+	if !tf.Max().Equal(epoch.Add(255 * time.Second)) {
+		t.Errorf("Max = %v, want epoch + 255s", tf.Max())
+	}
+}
+
+func TestTimeField_Update(t *testing.T) {
+	epoch := time.Unix(0, 0)
+	tf := NewTimeField(New[uint16, uint32](8, 16), epoch, time.Minute)
+	got, err := tf.Update(0xFF0000FF, epoch.Add(90*time.Minute))
+	if err != nil || got != 0xFF005AFF {
+		t.Errorf("Update = 0x%X, %v, want 0xFF005AFF", got, err)
+	}
+	if got, err := tf.Update(0xFF0000FF, epoch.Add(-time.Minute)); err == nil || got != 0xFF0000FF {
+		t.Errorf("Update before epoch = 0x%X, %v, want unchanged container and error", got, err)
+	}
+	if _, err := NewTimeField(New[uint16, uint32](0, 16), epoch, 0).Raw(epoch); err == nil {
+		t.Error("Raw with zero resolution succeeded, want error")
+	}
+}
+
+func TestSafeTimeField(t *testing.T) {
+	epoch := time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := SafeTimeField(New[uint32, uint32](0, 32), epoch, 2*time.Second); err != nil {
+		t.Errorf("SafeTimeField(32 bits of 2s) = %v, want success", err)
+	}
+	tests := []struct {
+		name       string
+		field      BitField[uint64, uint64]
+		resolution time.Duration
+	}{
+		{"zero resolution", New[uint64, uint64](0, 32), 0},
+		{"negative resolution", New[uint64, uint64](0, 32), -time.Second},
+		{"beyond time range", New[uint64, uint64](0, 63), time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SafeTimeField(tt.field, epoch, tt.resolution); err == nil {
+				t.Error("SafeTimeField succeeded, want error")
+			}
+		})
+	}
+}
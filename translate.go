@@ -0,0 +1,101 @@
+package bitfield
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// TranslatedField maps the raw codes of a BitField to logical values through a
+// sparse table, as is common in device registers where, for example, the code
+// 0b011 of a baud-rate field selects 115200 baud.
+// Codes missing from the table are reserved and rejected in both directions.
+type TranslatedField[T Unsigned, U storageType, V comparable] struct {
+	Field  BitField[T, U] // Field holding the raw code
+	values map[T]V        // Logical values by code
+	codes  map[V]T        // Codes by logical value
+}
+
+// NewTranslated creates a TranslatedField over field with a table of logical values by code.
+// Panics if a code does not fit in the field or a value is used for more than one code;
+// use SafeTranslated to get an error instead.
+func NewTranslated[T Unsigned, U storageType, V comparable](field BitField[T, U], table map[T]V) TranslatedField[T, U, V] {
+	tf, err := SafeTranslated(field, table)
+	if err != nil {
+		panic(err.Error())
+	}
+	return tf
+}
+
+// SafeTranslated creates a TranslatedField after validating the table.
+// Returns an error if a code does not fit in the field or a value is used for more than one code.
+func SafeTranslated[T Unsigned, U storageType, V comparable](field BitField[T, U], table map[T]V) (TranslatedField[T, U, V], error) {
+	tf := TranslatedField[T, U, V]{
+		Field:  field,
+		values: maps.Clone(table),
+		codes:  make(map[V]T, len(table)),
+	}
+	for _, code := range slices.Sorted(maps.Keys(table)) {
+		value := table[code]
+		if !field.IsValid(code) {
+			return TranslatedField[T, U, V]{}, fmt.Errorf("code %v: %w", code, field.invalidValue(code))
+		}
+		if other, ok := tf.codes[value]; ok {
+			return TranslatedField[T, U, V]{}, fmt.Errorf("value %v used for codes %v and %v", value, other, code)
+		}
+		tf.codes[value] = code
+	}
+	return tf, nil
+}
+
+// Value returns the logical value of a raw code.
+// Returns an error if the code is not in the table.
+func (tf TranslatedField[T, U, V]) Value(code T) (V, error) {
+	value, ok := tf.values[code]
+	if !ok {
+		var zero V
+		return zero, fmt.Errorf("unknown code %v", code)
+	}
+	return value, nil
+}
+
+// Code returns the raw code of a logical value.
+// Returns an error if the value is not in the table.
+func (tf TranslatedField[T, U, V]) Code(value V) (T, error) {
+	code, ok := tf.codes[value]
+	if !ok {
+		return 0, fmt.Errorf("no code for value %v", value)
+	}
+	return code, nil
+}
+
+// Codes returns the codes of the table in ascending order.
+func (tf TranslatedField[T, U, V]) Codes() []T {
+	return slices.Sorted(maps.Keys(tf.values))
+}
+
+// Decode extracts the raw code from the container and translates it to its logical value.
+// Returns an error if the container holds a code that is not in the table.
+func (tf TranslatedField[T, U, V]) Decode(container U) (V, error) {
+	return tf.Value(tf.Field.Decode(container))
+}
+
+// Encode translates a logical value to its code and encodes it into the field.
+// Returns an error if the value is not in the table.
+func (tf TranslatedField[T, U, V]) Encode(value V) (U, error) {
+	code, err := tf.Code(value)
+	if err != nil {
+		return 0, err
+	}
+	return tf.Field.Encode(code), nil
+}
+
+// Update translates a logical value to its code and sets the field within an existing container.
+// Returns an error if the value is not in the table, leaving the container unchanged.
+func (tf TranslatedField[T, U, V]) Update(previous U, value V) (U, error) {
+	code, err := tf.Code(value)
+	if err != nil {
+		return previous, err
+	}
+	return tf.Field.Update(previous, code), nil
+}
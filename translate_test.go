@@ -0,0 +1,99 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+// newBaudField returns a baud-rate field in bits 4..6 with a sparse code table.
+func newBaudField() TranslatedField[uint8, uint32, int] {
+	return NewTranslated(New[uint8, uint32](4, 3), map[uint8]int{
+		0b000: 9600,
+		0b001: 19200,
+		0b011: 115200,
+		0b111: 921600,
+	})
+}
+
+func TestTranslatedField_Decode(t *testing.T) {
+	baud := newBaudField()
+	tests := []struct {
+		container uint32
+		want      int
+		wantErr   bool
+	}{
+		{0x00000000, 9600, false},
+		{0x0000003F, 115200, false},
+		{0xFFFFFF7F, 921600, false},
+		{0x00000020, 0, true}, // Code 0b010 is reserved
+	}
+
+	for _, tt := range tests {
+		got, err := baud.Decode(tt.container)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Decode(0x%X) = %v, %v, want %v, error %v", tt.container, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTranslatedField_Encode(t *testing.T) {
+	baud := newBaudField()
+
+	if got, err := baud.Encode(115200); err != nil || got != 0x30 {
+		t.Errorf("Encode(115200) = 0x%X, %v, want 0x30", got, err)
+	}
+	if got, err := baud.Update(0xFFFFFFFF, 19200); err != nil || got != 0xFFFFFF9F {
+		t.Errorf("Update(0xFFFFFFFF, 19200) = 0x%X, %v, want 0xFFFFFF9F", got, err)
+	}
+	if got, err := baud.Update(0x1234, 57600); err == nil || got != 0x1234 {
+		t.Errorf("Update(0x1234, 57600) = 0x%X, %v, want 0x1234 and an error", got, err)
+	}
+	if _, err := baud.Encode(57600); err == nil {
+		t.Error("Encode(57600) succeeded, want error")
+	}
+}
+
+func TestTranslatedField_Lookup(t *testing.T) {
+	baud := newBaudField()
+
+	for _, code := range baud.Codes() {
+		value, err := baud.Value(code)
+		if err != nil {
+			t.Fatalf("Value(%d): %v", code, err)
+		}
+		if got, err := baud.Code(value); err != nil || got != code {
+			t.Errorf("Code(Value(%d)) = %d, %v", code, got, err)
+		}
+	}
+	if got, want := baud.Codes(), []uint8{0, 1, 3, 7}; !slices.Equal(got, want) {
+		t.Errorf("Codes() = %v, want %v", got, want)
+	}
+	if _, err := baud.Value(2); err == nil {
+		t.Error("Value(2) succeeded, want error")
+	}
+}
+
+func TestSafeTranslated(t *testing.T) {
+	field := New[uint8, uint32](0, 2)
+	tests := []struct {
+		name  string
+		table map[uint8]string
+	}{
+		{"code out of range", map[uint8]string{0: "off", 4: "on"}},
+		{"duplicate value", map[uint8]string{0: "off", 1: "on", 2: "on"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SafeTranslated(field, tt.table); err == nil {
+				t.Errorf("SafeTranslated(%v) succeeded, want error", tt.table)
+			}
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewTranslated(%v) did not panic", tt.table)
+				}
+			}()
+			NewTranslated(field, tt.table)
+		})
+	}
+}
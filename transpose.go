@@ -0,0 +1,31 @@
+package bitfield
+
+// Transpose8 transposes an 8x8 bit matrix held in a uint64, where byte i is row i
+// and bit j of a row is column j: bit 8i+j moves to bit 8j+i.
+// Transposing turns the bit planes of 8 bytes into 8 bytes of bit planes,
+// as needed for columnar extraction and monochrome bitmap rotation.
+func Transpose8(x uint64) uint64 {
+	// Swap the off-diagonal 1x1, 2x2 and 4x4 blocks in turn.
+	t := (x ^ x>>7) & 0x00AA00AA00AA00AA
+	x ^= t ^ t<<7
+	t = (x ^ x>>14) & 0x0000CCCC0000CCCC
+	x ^= t ^ t<<14
+	t = (x ^ x>>28) & 0x00000000F0F0F0F0
+	x ^= t ^ t<<28
+	return x
+}
+
+// Transpose64 transposes a 64x64 bit matrix in place, where m[i] is row i and
+// bit j of a row is column j: bit j of m[i] moves to bit i of m[j].
+func Transpose64(m *[64]uint64) {
+	mask := uint64(0x00000000FFFFFFFF)
+	for j := 32; j != 0; j >>= 1 {
+		// Swap the upper right and lower left j x j blocks of every 2j x 2j block.
+		for k := 0; k < 64; k = (k + j + 1) &^ j {
+			t := (m[k]>>j ^ m[k+j]) & mask
+			m[k] ^= t << j
+			m[k+j] ^= t
+		}
+		mask ^= mask << (j / 2)
+	}
+}
@@ -0,0 +1,57 @@
+package bitfield
+
+import "testing"
+
+func TestTranspose8(t *testing.T) {
+	tests := []struct {
+		in, want uint64
+	}{
+		{0, 0},
+		{0x8040201008040201, 0x8040201008040201}, // Diagonal
+		{0x00000000000000FF, 0x0101010101010101}, // Row 0 becomes column 0
+		{0x0000000000000002, 0x0000000000000100},
+		{0x8000000000000000, 0x8000000000000000},
+		{0x0102040810204080, 0x0102040810204080}, // Anti-diagonal
+	}
+
+	for _, tt := range tests {
+		if got := Transpose8(tt.in); got != tt.want {
+			t.Errorf("Transpose8(0x%016X) = 0x%016X, want 0x%016X", tt.in, got, tt.want)
+		}
+	}
+
+	for i := uint64(1); i < 1000; i++ {
+		x := i * 0x9E3779B97F4A7C15
+		got := Transpose8(x)
+		for r := range 8 {
+			for c := range 8 {
+				if x>>(8*r+c)&1 != got>>(8*c+r)&1 {
+					t.Fatalf("Transpose8(0x%016X) = 0x%016X: bit (%d, %d) not transposed", x, got, r, c)
+				}
+			}
+		}
+		if Transpose8(got) != x {
+			t.Fatalf("Transpose8 is not an involution for 0x%016X", x)
+		}
+	}
+}
+
+func TestTranspose64(t *testing.T) {
+	var m [64]uint64
+	for i := range m {
+		m[i] = uint64(i+1) * 0x9E3779B97F4A7C15
+	}
+	orig := m
+	Transpose64(&m)
+	for r := range 64 {
+		for c := range 64 {
+			if orig[r]>>c&1 != m[c]>>r&1 {
+				t.Fatalf("bit %d of row %d not moved to bit %d of row %d", c, r, r, c)
+			}
+		}
+	}
+	Transpose64(&m)
+	if m != orig {
+		t.Error("Transpose64 twice did not restore the matrix")
+	}
+}
@@ -0,0 +1,93 @@
+package bitfield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Tx collects field writes to a register and applies them together.
+// All writes are validated before any is applied, so a transaction either
+// updates every field or leaves the register untouched.
+// A Tx is created with Register.Begin and is not safe for concurrent use.
+type Tx[U storageType] struct {
+	reg    *Register[U]
+	writes []txWrite
+	done   bool
+}
+
+// txWrite is a single field write recorded by a Tx.
+type txWrite struct {
+	field string
+	value uint64
+}
+
+// Begin starts a transaction on the register.
+func (r *Register[U]) Begin() *Tx[U] {
+	return &Tx[U]{reg: r}
+}
+
+// Set records a write of a field. If a field is set more than once, the last value wins.
+// The write is validated and applied by Commit or CommitTo.
+func (tx *Tx[U]) Set(field string, value uint64) {
+	tx.writes = append(tx.writes, txWrite{field, value})
+}
+
+// Discard abandons the transaction. Later calls to Commit or CommitTo fail.
+func (tx *Tx[U]) Discard() {
+	tx.done = true
+}
+
+// word validates all recorded writes and returns the word that applies them to current.
+// Fields with write side effects that are not part of the transaction are written as 0.
+func (tx *Tx[U]) word(current U) (U, error) {
+	if tx.done {
+		return 0, fmt.Errorf("register %s: transaction already finished", tx.reg.Name)
+	}
+	var errs []error
+	word := tx.reg.writeBase(current)
+	for _, w := range tx.writes {
+		bf, err := tx.reg.writable(w.field, w.value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		word = bf.Update(word, w.value)
+	}
+	return word, errors.Join(errs...)
+}
+
+// Commit applies all recorded writes to the register value at once.
+// Returns an error, leaving the register unchanged, if the transaction has already
+// finished or any write names an unknown or read-only field or has a value that
+// does not fit. The error reports every invalid write.
+func (tx *Tx[U]) Commit() error {
+	word, err := tx.word(tx.reg.value)
+	if err != nil {
+		return err
+	}
+	tx.reg.applyWrite(word)
+	tx.done = true
+	return nil
+}
+
+// CommitTo applies all recorded writes to the device with a single read-modify-write
+// cycle on the bus, and to the register value.
+// Returns an error if validation fails, as for Commit, or a bus access fails.
+// Nothing is written if validation fails.
+func (tx *Tx[U]) CommitTo(ctx context.Context, acc RegisterAccessor) error {
+	if _, err := tx.word(tx.reg.value); err != nil {
+		return err
+	}
+	reg := tx.reg
+	if _, err := ReadRegister(ctx, acc, reg); err != nil {
+		return err
+	}
+	word, _ := tx.word(reg.value)
+	if err := acc.Write(ctx, reg.Offset, uint64(word)); err != nil {
+		return fmt.Errorf("register %s: write: %w", reg.Name, err)
+	}
+	reg.applyWrite(word)
+	tx.done = true
+	return nil
+}
@@ -0,0 +1,101 @@
+package bitfield
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTx_Commit(t *testing.T) {
+	r := newTestRegister(t)
+	tx := r.Begin()
+	tx.Set("mode", 1)
+	tx.Set("enable", 1)
+	tx.Set("command", 0x7F)
+	tx.Set("mode", 3)
+	if r.Value() != 0x00000A04 {
+		t.Fatalf("Set modified the register before Commit: 0x%08X", r.Value())
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if got := r.Value(); got != 0x007F0A07 {
+		t.Errorf("Value() = 0x%08X, want 0x007F0A07", got)
+	}
+	if err := tx.Commit(); err == nil {
+		t.Error("second Commit succeeded, want error")
+	}
+}
+
+func TestTx_CommitInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		value uint64
+	}{
+		{"read-only field", "status", 1},
+		{"value too large", "mode", 4},
+		{"unknown field", "missing", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRegister(t)
+			tx := r.Begin()
+			tx.Set("enable", 1)
+			tx.Set(tt.field, tt.value)
+			if err := tx.Commit(); err == nil {
+				t.Errorf("Commit with Set(%q, %v) succeeded, want error", tt.field, tt.value)
+			}
+			if got := r.Value(); got != 0x00000A04 {
+				t.Errorf("failed Commit modified the register: 0x%08X", got)
+			}
+		})
+	}
+}
+
+func TestTx_Discard(t *testing.T) {
+	r := newTestRegister(t)
+	tx := r.Begin()
+	tx.Set("enable", 1)
+	tx.Discard()
+	if err := tx.Commit(); err == nil {
+		t.Error("Commit after Discard succeeded, want error")
+	}
+	if got := r.Value(); got != 0x00000A04 {
+		t.Errorf("Value() = 0x%08X, want 0x00000A04", got)
+	}
+}
+
+func TestTx_CommitTo(t *testing.T) {
+	ctx := context.Background()
+	r := newInterruptRegister(t)
+	bus := NewMemoryAccessor()
+	_ = bus.Write(ctx, r.Offset, 0x0300000F)
+
+	tx := r.Begin()
+	tx.Set("pending", 0x3)
+	tx.Set("prio", 6)
+	if err := tx.CommitTo(ctx, bus); err != nil {
+		t.Fatalf("CommitTo: %v", err)
+	}
+	if got, _ := bus.Read(ctx, r.Offset); got != 0x06000003 {
+		t.Errorf("bus word = 0x%08X, want 0x06000003", got)
+	}
+	if got := r.Value(); got != 0x0600000C {
+		t.Errorf("Value() = 0x%08X, want 0x0600000C", got)
+	}
+
+	reads, writes := bus.Counts()
+	if reads != 2 || writes != 2 {
+		t.Errorf("bus traffic = %d reads, %d writes, want 2 reads, 2 writes", reads, writes)
+	}
+
+	bad := r.Begin()
+	bad.Set("prio", 8)
+	if err := bad.CommitTo(ctx, bus); err == nil {
+		t.Error("CommitTo with invalid value succeeded, want error")
+	}
+	if r2, w2 := bus.Counts(); r2 != reads || w2 != writes {
+		t.Error("invalid transaction reached the bus")
+	}
+}
@@ -0,0 +1,153 @@
+package bitfield
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Union describes a container whose bits are interpreted differently depending
+// on the value of a selector field, like hardware registers whose MODE field
+// reinterprets the other bits. A base layout holds the selector and the fields
+// common to every variant, and each variant layout holds the fields present
+// for one selector value. Variants may overlap each other but not the base.
+// The layouts must not be changed after they are added to the union.
+type Union[U storageType] struct {
+	base     *Layout[U]
+	selector BitField[uint64, U]
+	name     string // Name of the selector field
+	variants map[uint64]*Layout[U]
+}
+
+// NewUnion creates a Union whose variant is selected by the field named selector of base.
+// Panics if base has no such field; use SafeUnion to get an error instead.
+func NewUnion[U storageType](base *Layout[U], selector string) *Union[U] {
+	u, err := SafeUnion(base, selector)
+	if err != nil {
+		panic(err.Error())
+	}
+	return u
+}
+
+// SafeUnion creates a Union whose variant is selected by the field named selector of base.
+// Returns an error if base has no such field.
+func SafeUnion[U storageType](base *Layout[U], selector string) (*Union[U], error) {
+	bf, ok := base.Field(selector)
+	if !ok {
+		return nil, fmt.Errorf("unknown selector field %q", selector)
+	}
+	return &Union[U]{base: base, selector: bf, name: selector, variants: make(map[uint64]*Layout[U])}, nil
+}
+
+// Base returns the layout of the selector and the fields common to every variant.
+func (u *Union[U]) Base() *Layout[U] {
+	return u.base
+}
+
+// AddVariant registers the layout of the fields present when the selector holds value.
+// A layout may be registered for several values.
+// Returns an error if value does not fit in the selector, a variant is already
+// registered for it, or the layout has a field that overlaps a field of the base
+// or has the same name.
+func (u *Union[U]) AddVariant(value uint64, variant *Layout[U]) error {
+	if !u.selector.IsValid(value) {
+		return fmt.Errorf("selector %q: %w", u.name, u.selector.invalidValue(value))
+	}
+	if _, ok := u.variants[value]; ok {
+		return fmt.Errorf("duplicate variant for selector %q = %d", u.name, value)
+	}
+	for _, f := range variant.fields {
+		if _, ok := u.base.index[f.name]; ok {
+			return fmt.Errorf("variant field %q duplicates a base field", f.name)
+		}
+		if f.field.Mask&u.base.used != 0 {
+			return fmt.Errorf("variant field %q overlaps the base fields", f.name)
+		}
+	}
+	u.variants[value] = variant
+	return nil
+}
+
+// Variant returns the layout registered for a selector value.
+// The second return value reports whether a variant is registered.
+func (u *Union[U]) Variant(value uint64) (*Layout[U], bool) {
+	l, ok := u.variants[value]
+	return l, ok
+}
+
+// Values returns the selector values with a registered variant, in ascending order.
+func (u *Union[U]) Values() []uint64 {
+	return slices.Sorted(maps.Keys(u.variants))
+}
+
+// Select returns the variant selected by container.
+// Returns an error if no variant is registered for its selector value.
+func (u *Union[U]) Select(container U) (*Layout[U], error) {
+	value := u.selector.Decode(container)
+	l, ok := u.variants[value]
+	if !ok {
+		return nil, fmt.Errorf("no variant for selector %q = %d", u.name, value)
+	}
+	return l, nil
+}
+
+// DecodeAll extracts the base fields and the fields of the selected variant
+// that are present in container, as declared with Layout.SetCondition.
+// The returned map is keyed by field name.
+// Returns an error if no variant is registered for the selector value.
+func (u *Union[U]) DecodeAll(container U) (map[string]uint64, error) {
+	variant, err := u.Select(container)
+	if err != nil {
+		return nil, err
+	}
+	values := u.base.DecodeAll(container)
+	maps.Copy(values, variant.DecodeAll(container))
+	return values, nil
+}
+
+// EncodeAll builds a container from a map of field values, using the variant
+// selected by the value of the selector field.
+// Fields that are missing from values are left as 0.
+// Returns an error if no variant is registered for the selector value, or values
+// contains a field that is neither in the base nor in the selected variant, or
+// a value that does not fit in its field.
+func (u *Union[U]) EncodeAll(values map[string]uint64) (U, error) {
+	common := make(map[string]uint64)
+	specific := make(map[string]uint64)
+	for name, value := range values {
+		if _, ok := u.base.index[name]; ok {
+			common[name] = value
+		} else {
+			specific[name] = value
+		}
+	}
+	container, err := u.base.EncodeAll(common)
+	if err != nil {
+		return 0, err
+	}
+	variant, err := u.Select(container)
+	if err != nil {
+		return 0, err
+	}
+	rest, err := variant.EncodeAll(specific)
+	if err != nil {
+		return 0, fmt.Errorf("selector %q = %d: %w", u.name, u.selector.Decode(container), err)
+	}
+	return container | rest, nil
+}
+
+// Validate checks container against the base and the selected variant: its
+// reserved bits, those outside the base and the variant, must be zero and
+// fields restricted with SetAllowed must hold allowed values.
+// Returns an error if no variant is registered for the selector value, or an
+// error wrapping ErrReservedBits or ErrValueNotAllowed.
+func (u *Union[U]) Validate(container U) error {
+	variant, err := u.Select(container)
+	if err != nil {
+		return err
+	}
+	if err := u.base.Validate(container &^ variant.used); err != nil {
+		return err
+	}
+	return variant.Validate(container & variant.used)
+}
@@ -0,0 +1,173 @@
+package bitfield
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// newTimerUnion returns the control register of a timer whose mode field
+// selects between timer, PWM and capture fields.
+func newTimerUnion(t *testing.T) *Union[uint32] {
+	t.Helper()
+	base := NewLayout[uint32]()
+	timer := NewLayout[uint32]()
+	pwm := NewLayout[uint32]()
+	for _, err := range []error{
+		base.Add("enable", 0, 1),
+		base.Add("mode", 1, 2),
+		timer.Add("prescale", 4, 4),
+		timer.Add("reload", 8, 8),
+		pwm.Add("duty", 4, 8),
+		pwm.Add("polarity", 12, 1),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	u := NewUnion(base, "mode")
+	if err := u.AddVariant(0, timer); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.AddVariant(1, pwm); err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestUnion_DecodeAll(t *testing.T) {
+	u := newTimerUnion(t)
+
+	tests := []struct {
+		name      string
+		container uint32
+		want      map[string]uint64
+	}{
+		{"timer", 0x00001231, map[string]uint64{"enable": 1, "mode": 0, "prescale": 3, "reload": 0x12}},
+		{"pwm", 0x00001233, map[string]uint64{"enable": 1, "mode": 1, "duty": 0x23, "polarity": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := u.DecodeAll(tt.container)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("DecodeAll(0x%X) = %v, want %v", tt.container, got, tt.want)
+			}
+			container, err := u.EncodeAll(got)
+			if err != nil || container != tt.container {
+				t.Errorf("EncodeAll(%v) = 0x%X, %v, want 0x%X", got, container, err, tt.container)
+			}
+		})
+	}
+
+	if _, err := u.DecodeAll(0x4); err == nil {
+		t.Error("DecodeAll() with no variant for the selector succeeded")
+	}
+}
+
+func TestUnion_DecodeAllCondition(t *testing.T) {
+	base := mustLayout(t, testField{"mode", 0, 1})
+	capture := mustLayout(t, testField{"edge", 4, 1}, testField{"filter", 8, 4})
+	if err := capture.SetCondition("filter", "edge", 1); err != nil {
+		t.Fatal(err)
+	}
+	u := NewUnion(base, "mode")
+	if err := u.AddVariant(1, capture); err != nil {
+		t.Fatal(err)
+	}
+
+	for container, want := range map[uint32]map[string]uint64{
+		0x00000511: {"mode": 1, "edge": 1, "filter": 5},
+		0x00000501: {"mode": 1, "edge": 0},
+	} {
+		got, err := u.DecodeAll(container)
+		if err != nil || !maps.Equal(got, want) {
+			t.Errorf("DecodeAll(0x%X) = %v, %v, want %v", container, got, err, want)
+		}
+	}
+}
+
+func TestUnion_EncodeAll(t *testing.T) {
+	u := newTimerUnion(t)
+
+	tests := []struct {
+		name   string
+		values map[string]uint64
+		want   uint32
+		ok     bool
+	}{
+		{"default variant", map[string]uint64{"reload": 0xFF}, 0xFF00, true},
+		{"pwm", map[string]uint64{"mode": 1, "duty": 0x80}, 0x0802, true},
+		{"field of another variant", map[string]uint64{"mode": 1, "reload": 1}, 0, false},
+		{"no variant", map[string]uint64{"mode": 2}, 0, false},
+		{"out of range", map[string]uint64{"prescale": 16}, 0, false},
+		{"unknown field", map[string]uint64{"speed": 1}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := u.EncodeAll(tt.values)
+			if (err == nil) != tt.ok || got != tt.want {
+				t.Errorf("EncodeAll(%v) = 0x%X, %v, want 0x%X, ok %v", tt.values, got, err, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestUnion_Validate(t *testing.T) {
+	u := newTimerUnion(t)
+
+	tests := []struct {
+		container uint32
+		want      error
+	}{
+		{0x0000FFF1, nil},
+		{0x00001FF3, nil},
+		{0x0000FFF3, ErrReservedBits}, // Bits 13-15 are reserved in PWM mode
+		{0x00000008, ErrReservedBits},
+	}
+	for _, tt := range tests {
+		if err := u.Validate(tt.container); !errors.Is(err, tt.want) {
+			t.Errorf("Validate(0x%X) = %v, want %v", tt.container, err, tt.want)
+		}
+	}
+	if err := u.Validate(0x4); err == nil {
+		t.Error("Validate() with no variant for the selector succeeded")
+	}
+}
+
+func TestUnion_AddVariant(t *testing.T) {
+	u := newTimerUnion(t)
+	if got := u.Values(); !slices.Equal(got, []uint64{0, 1}) {
+		t.Errorf("Values() = %v, want [0 1]", got)
+	}
+	if l, ok := u.Variant(1); !ok || !slices.Equal(l.Names(), []string{"duty", "polarity"}) {
+		t.Errorf("Variant(1) = %v, %v", l, ok)
+	}
+
+	overlap := mustLayout(t, testField{"edge", 0, 2})
+	duplicate := mustLayout(t, testField{"enable", 4, 1})
+	tests := []struct {
+		name    string
+		value   uint64
+		variant *Layout[uint32]
+	}{
+		{"registered value", 0, NewLayout[uint32]()},
+		{"selector out of range", 4, NewLayout[uint32]()},
+		{"overlaps base", 2, overlap},
+		{"duplicate name", 2, duplicate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := u.AddVariant(tt.value, tt.variant); err == nil {
+				t.Error("AddVariant() succeeded")
+			}
+		})
+	}
+
+	if _, err := SafeUnion(NewLayout[uint32](), "mode"); err == nil {
+		t.Error("SafeUnion() without the selector field succeeded")
+	}
+}
@@ -0,0 +1,148 @@
+package bitfield
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// FieldRule describes how a field of a layout version gets its value when a
+// container is migrated from the previous version.
+// Without a rule, a field takes the value of the field of the same name in the
+// previous version, wherever it was and whatever its width, or 0 if the previous
+// version has no such field.
+type FieldRule struct {
+	Field   string                       // Field of the new version
+	From    string                       // Field of the previous version holding the value, if it was renamed
+	Default uint64                       // Value used if the previous version has no such field
+	Convert func(uint64) (uint64, error) // Optional conversion of the previous value, e.g. a change of unit
+}
+
+// Versions holds the successive versions of a Layout, so that containers
+// persisted with an older version can be migrated to a newer one as the layout
+// evolves: fields may move, be widened, be renamed, appear with a default value
+// or disappear.
+type Versions[U storageType] struct {
+	layouts map[int]*Layout[U]
+	rules   map[int]map[string]FieldRule // Rules by version and field
+}
+
+// NewVersions creates an empty set of layout versions.
+func NewVersions[U storageType]() *Versions[U] {
+	return &Versions[U]{
+		layouts: make(map[int]*Layout[U]),
+		rules:   make(map[int]map[string]FieldRule),
+	}
+}
+
+// Add declares the layout of a version. Versions do not need to be consecutive;
+// a container is migrated through every declared version in between.
+// Returns an error if the version is already declared.
+func (v *Versions[U]) Add(version int, layout *Layout[U]) error {
+	if _, ok := v.layouts[version]; ok {
+		return fmt.Errorf("layout version %d already declared", version)
+	}
+	v.layouts[version] = layout
+	return nil
+}
+
+// Layout returns the layout of a version.
+// The second return value reports whether the version is declared.
+func (v *Versions[U]) Layout(version int) (*Layout[U], bool) {
+	l, ok := v.layouts[version]
+	return l, ok
+}
+
+// Latest returns the newest version and its layout, or false if no version is declared.
+func (v *Versions[U]) Latest() (int, *Layout[U], bool) {
+	if len(v.layouts) == 0 {
+		return 0, nil, false
+	}
+	version := slices.Max(slices.Collect(maps.Keys(v.layouts)))
+	return version, v.layouts[version], true
+}
+
+// SetRule sets the rule for a field of a version, applied when migrating from
+// the previous declared version. It replaces any rule set for the field before.
+// Returns an error if the version is not declared, the field does not exist in
+// it, or the default value does not fit in the field.
+func (v *Versions[U]) SetRule(version int, rule FieldRule) error {
+	l, ok := v.layouts[version]
+	if !ok {
+		return fmt.Errorf("unknown layout version %d", version)
+	}
+	bf, ok := l.Field(rule.Field)
+	if !ok {
+		return fmt.Errorf("version %d: unknown field %q", version, rule.Field)
+	}
+	if !bf.IsValid(rule.Default) {
+		return fmt.Errorf("version %d: field %q: default %w", version, rule.Field, bf.invalidValue(rule.Default))
+	}
+	if v.rules[version] == nil {
+		v.rules[version] = make(map[string]FieldRule)
+	}
+	v.rules[version][rule.Field] = rule
+	return nil
+}
+
+// Migrate converts a container of version oldVer to version newVer, one declared
+// version at a time. Fields removed along the way are dropped, and the reserved
+// bits of the result are zero unless oldVer equals newVer.
+// Returns an error if a version is not declared, newVer is older than oldVer,
+// a conversion fails, or a value does not fit in its field in a newer version,
+// as happens when a field is narrowed.
+func (v *Versions[U]) Migrate(oldVer, newVer int, container U) (U, error) {
+	if _, ok := v.layouts[oldVer]; !ok {
+		return 0, fmt.Errorf("unknown layout version %d", oldVer)
+	}
+	if _, ok := v.layouts[newVer]; !ok {
+		return 0, fmt.Errorf("unknown layout version %d", newVer)
+	}
+	if newVer < oldVer {
+		return 0, fmt.Errorf("cannot migrate from version %d back to version %d", oldVer, newVer)
+	}
+	versions := slices.Sorted(maps.Keys(v.layouts))
+	from := oldVer
+	for _, version := range versions {
+		if version <= oldVer || version > newVer {
+			continue
+		}
+		var err error
+		if container, err = v.step(from, version, container); err != nil {
+			return 0, err
+		}
+		from = version
+	}
+	return container, nil
+}
+
+// step migrates a container from version from to the next declared version to.
+func (v *Versions[U]) step(from, to int, container U) (U, error) {
+	old, next := v.layouts[from], v.layouts[to]
+	var result U
+	for _, f := range next.fields {
+		rule, ok := v.rules[to][f.name]
+		if !ok {
+			rule = FieldRule{Field: f.name}
+		}
+		source := rule.From
+		if source == "" {
+			source = f.name
+		}
+		value := rule.Default
+		if bf, ok := old.Field(source); ok {
+			value = bf.Decode(container)
+			if rule.Convert != nil {
+				var err error
+				if value, err = rule.Convert(value); err != nil {
+					return 0, fmt.Errorf("version %d to %d: field %q: %w", from, to, f.name, err)
+				}
+			}
+		}
+		if !f.field.IsValid(value) {
+			return 0, fmt.Errorf("version %d to %d: field %q: %w", from, to, f.name, f.field.invalidValue(value))
+		}
+		result = f.field.Update(result, value)
+	}
+	return result, nil
+}
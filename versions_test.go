@@ -0,0 +1,133 @@
+package bitfield
+
+import (
+	"errors"
+	"testing"
+)
+
+// newConfigVersions returns three versions of a configuration word:
+// version 1 has mode(0,2) and speed(2,4); version 2 moves speed to bit 8 and
+// widens it to 8 bits, in units of 10, and adds enable(0,1) defaulting to 1;
+// version 4 renames mode to profile, drops enable and adds level(16,4).
+func newConfigVersions(t *testing.T) *Versions[uint32] {
+	t.Helper()
+	v1 := NewLayout[uint32]()
+	v2 := NewLayout[uint32]()
+	v4 := NewLayout[uint32]()
+	for _, err := range []error{
+		v1.Add("mode", 0, 2),
+		v1.Add("speed", 2, 4),
+		v2.Add("enable", 0, 1),
+		v2.Add("mode", 1, 2),
+		v2.Add("speed", 8, 8),
+		v4.Add("profile", 0, 3),
+		v4.Add("speed", 8, 8),
+		v4.Add("level", 16, 4),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	v := NewVersions[uint32]()
+	for version, l := range map[int]*Layout[uint32]{1: v1, 2: v2, 4: v4} {
+		if err := v.Add(version, l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, r := range []struct {
+		version int
+		rule    FieldRule
+	}{
+		{2, FieldRule{Field: "enable", Default: 1}},
+		{2, FieldRule{Field: "speed", Convert: func(value uint64) (uint64, error) { return value * 10, nil }}},
+		{4, FieldRule{Field: "profile", From: "mode"}},
+		{4, FieldRule{Field: "level", Default: 7}},
+	} {
+		if err := v.SetRule(r.version, r.rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return v
+}
+
+func TestVersions_Migrate(t *testing.T) {
+	v := newConfigVersions(t)
+
+	tests := []struct {
+		name           string
+		oldVer, newVer int
+		container      uint32
+		want           uint32
+	}{
+		{"same version", 1, 1, 0xF6, 0xF6},
+		{"1 to 2", 1, 2, 0x16, 0x3205},    // mode 2, speed 5 -> enable 1, mode 2, speed 50
+		{"2 to 4", 2, 4, 0x3204, 0x73202}, // mode 2, speed 50 -> profile 2, speed 50, level 7
+		{"1 to 4", 1, 4, 0x16, 0x73202},   // Through version 2
+		{"reserved bits dropped", 1, 2, 0xFFFFFF00, 0x0001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Migrate(tt.oldVer, tt.newVer, tt.container)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Migrate(%d, %d, 0x%X) = 0x%X, want 0x%X", tt.oldVer, tt.newVer, tt.container, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersions_MigrateErrors(t *testing.T) {
+	v := newConfigVersions(t)
+
+	if _, err := v.Migrate(1, 3, 0); err == nil {
+		t.Error("Migrate() to an unknown version succeeded")
+	}
+	if _, err := v.Migrate(4, 2, 0); err == nil {
+		t.Error("Migrate() to an older version succeeded")
+	}
+
+	// Version 5 narrows speed back to 4 bits
+	narrow := NewLayout[uint32]()
+	if err := narrow.Add("speed", 0, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Add(5, narrow); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Migrate(4, 5, 0x3200); err == nil {
+		t.Error("Migrate() of a value too wide for a narrowed field succeeded")
+	}
+
+	fail := errors.New("unsupported profile")
+	if err := v.SetRule(4, FieldRule{Field: "profile", From: "mode", Convert: func(uint64) (uint64, error) { return 0, fail }}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Migrate(2, 4, 0); !errors.Is(err, fail) {
+		t.Errorf("Migrate() error = %v, want %v", err, fail)
+	}
+}
+
+func TestVersions_Declare(t *testing.T) {
+	v := newConfigVersions(t)
+
+	if err := v.Add(2, NewLayout[uint32]()); err == nil {
+		t.Error("Add() of a declared version succeeded")
+	}
+	if err := v.SetRule(3, FieldRule{Field: "mode"}); err == nil {
+		t.Error("SetRule() for an unknown version succeeded")
+	}
+	if err := v.SetRule(2, FieldRule{Field: "level"}); err == nil {
+		t.Error("SetRule() for an unknown field succeeded")
+	}
+	if err := v.SetRule(2, FieldRule{Field: "enable", Default: 2}); err == nil {
+		t.Error("SetRule() with a default out of range succeeded")
+	}
+	if version, l, ok := v.Latest(); !ok || version != 4 || len(l.Names()) != 3 {
+		t.Errorf("Latest() = %d, %v, %v", version, l.Names(), ok)
+	}
+	if _, _, ok := NewVersions[uint32]().Latest(); ok {
+		t.Error("Latest() of no versions succeeded")
+	}
+}
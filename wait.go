@@ -0,0 +1,76 @@
+package bitfield
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WaitOption configures WaitForField.
+type WaitOption func(*waitConfig)
+
+type waitConfig struct {
+	factor float64       // Multiplier applied to the interval after each poll
+	max    time.Duration // Upper bound of the interval, 0 for none
+}
+
+// WithBackoff makes WaitForField multiply the poll interval by factor after
+// every unsuccessful poll, up to max. A max of 0 leaves the interval unbounded.
+// Factors below 1 are treated as 1.
+func WithBackoff(factor float64, max time.Duration) WaitOption {
+	return func(c *waitConfig) {
+		c.factor = factor
+		c.max = max
+	}
+}
+
+// WaitForField polls a field on the bus until it has the expected value.
+// The field is read immediately and then every pollInterval, until it matches
+// or ctx is done. The register is loaded with every value read.
+// Returns an error if the field does not exist, is write-only, expected does not fit,
+// a bus access fails, or ctx is done before the field matched; in the last case
+// the error wraps ctx.Err() and reports the last value read.
+func WaitForField[U storageType](ctx context.Context, acc RegisterAccessor, reg *Register[U], field string, expected uint64, pollInterval time.Duration, opts ...WaitOption) error {
+	bf, err := reg.readable(field)
+	if err != nil {
+		return err
+	}
+	if !bf.IsValid(expected) {
+		return fmt.Errorf("register %s: value %v out of range for field %q, max %v", reg.Name, expected, field, bf.Max())
+	}
+	if pollInterval <= 0 {
+		return fmt.Errorf("register %s: poll interval must be positive", reg.Name)
+	}
+	cfg := waitConfig{factor: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.factor < 1 {
+		cfg.factor = 1
+	}
+
+	timer := time.NewTimer(pollInterval)
+	defer timer.Stop()
+	interval := pollInterval
+	for {
+		raw, err := ReadRegister(ctx, acc, reg)
+		if err != nil {
+			return err
+		}
+		last := bf.Decode(raw)
+		if last == expected {
+			return nil
+		}
+
+		timer.Reset(interval) // Since Go 1.23, Reset discards a stale expiry
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("register %s: waiting for field %q to be %v, last value %v: %w", reg.Name, field, expected, last, ctx.Err())
+		case <-timer.C:
+		}
+		interval = time.Duration(float64(interval) * cfg.factor)
+		if cfg.max > 0 && interval > cfg.max {
+			interval = cfg.max
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package bitfield
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// delayedAccessor returns 0 for the first n reads and value afterwards.
+type delayedAccessor struct {
+	n     int
+	value uint64
+	reads int
+}
+
+func (d *delayedAccessor) Read(context.Context, uint64) (uint64, error) {
+	d.reads++
+	if d.reads <= d.n {
+		return 0, nil
+	}
+	return d.value, nil
+}
+
+func (d *delayedAccessor) Write(context.Context, uint64, uint64) error { return nil }
+
+func TestWaitForField(t *testing.T) {
+	ctx := context.Background()
+	reg := newTestRegister(t)
+	bus := &delayedAccessor{n: 3, value: 0x00000500}
+
+	if err := WaitForField(ctx, bus, reg, "status", 5, time.Microsecond); err != nil {
+		t.Fatalf("WaitForField: %v", err)
+	}
+	if bus.reads != 4 {
+		t.Errorf("reads = %d, want 4", bus.reads)
+	}
+	if got := reg.Value(); got != 0x00000500 {
+		t.Errorf("register value = 0x%08X, want 0x00000500", got)
+	}
+}
+
+func TestWaitForField_Backoff(t *testing.T) {
+	reg := newTestRegister(t)
+	bus := &delayedAccessor{n: 1 << 30}
+	ctx, cancel := context.WithTimeout(context.Background(), 40*time.Millisecond)
+	defer cancel()
+
+	err := WaitForField(ctx, bus, reg, "status", 1, time.Millisecond, WithBackoff(2, 0))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	// Polls at 0, 1, 3, 7, 15 and 31ms fit in the timeout;
+	// without backoff there would be about 40.
+	if bus.reads > 8 {
+		t.Errorf("reads = %d, want at most 8 with exponential backoff", bus.reads)
+	}
+}
+
+func TestWaitForField_Errors(t *testing.T) {
+	ctx := context.Background()
+	reg := newTestRegister(t)
+	bus := NewMemoryAccessor()
+
+	tests := []struct {
+		name     string
+		field    string
+		expected uint64
+		interval time.Duration
+	}{
+		{"write-only field", "command", 0, time.Millisecond},
+		{"unknown field", "missing", 0, time.Millisecond},
+		{"value too large", "status", 16, time.Millisecond},
+		{"zero interval", "status", 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := WaitForField(ctx, bus, reg, tt.field, tt.expected, tt.interval); err == nil {
+				t.Errorf("WaitForField(%q, %v, %v) succeeded, want error", tt.field, tt.expected, tt.interval)
+			}
+		})
+	}
+
+	errBus := errors.New("bus fault")
+	if err := WaitForField(ctx, failingAccessor{errBus}, reg, "status", 1, time.Millisecond); !errors.Is(err, errBus) {
+		t.Errorf("WaitForField with failing bus: err = %v, want %v", err, errBus)
+	}
+}
@@ -0,0 +1,123 @@
+package bitfield
+
+import "fmt"
+
+// Setter is a field that can be set through a Word or Compose.
+// It is implemented by BitField and Flag, whatever their value type.
+type Setter[U storageType] interface {
+	wordGet(container U) uint64
+	wordCheck(value uint64) error
+	wordSet(container U, value uint64) U
+	wordClear(container U) U
+	wordMask() U
+}
+
+// wordGet returns the value of the field as a uint64.
+func (bf BitField[T, U]) wordGet(container U) uint64 {
+	return uint64(bf.Decode(container))
+}
+
+// wordCheck returns an error if value does not fit in the field or is not allowed.
+func (bf BitField[T, U]) wordCheck(value uint64) error {
+	if value > uint64(bf.Max()) {
+		return fmt.Errorf("value %v out of range, max %v", value, bf.Max())
+	}
+	if !bf.IsValid(T(value)) {
+		return bf.invalidValue(T(value))
+	}
+	return nil
+}
+
+// wordSet sets the field to value, panicking like Update if it is not valid.
+func (bf BitField[T, U]) wordSet(container U, value uint64) U {
+	if err := bf.wordCheck(value); err != nil {
+		panic(err.Error())
+	}
+	return bf.Update(container, T(value))
+}
+
+// wordClear zeroes the bits of the field.
+func (bf BitField[T, U]) wordClear(container U) U {
+	return bf.Clear(container)
+}
+
+// wordMask returns the mask of the field.
+func (bf BitField[T, U]) wordMask() U {
+	return bf.Mask
+}
+
+// wordGet returns 1 if the flag is set and 0 otherwise.
+func (f Flag[U]) wordGet(container U) uint64 {
+	if f.IsSet(container) {
+		return 1
+	}
+	return 0
+}
+
+// wordCheck returns an error if value is neither 0 nor 1.
+func (f Flag[U]) wordCheck(value uint64) error {
+	if value > 1 {
+		return fmt.Errorf("value %v out of range, max 1", value)
+	}
+	return nil
+}
+
+// wordSet sets the flag if value is 1 and clears it if value is 0.
+// Panics for other values.
+func (f Flag[U]) wordSet(container U, value uint64) U {
+	if err := f.wordCheck(value); err != nil {
+		panic(err.Error())
+	}
+	return f.SetTo(container, value == 1)
+}
+
+// wordClear clears the flag.
+func (f Flag[U]) wordClear(container U) U {
+	return f.ClearFlag(container)
+}
+
+// wordMask returns the mask of the flag.
+func (f Flag[U]) wordMask() U {
+	return f.Mask
+}
+
+// Word wraps a container so that several fields can be set in one expression
+// instead of through repeated reassignment:
+//
+//	config := bitfield.NewWord[uint32](0).
+//		Set(priority, uint64(High)).
+//		Set(active, 1).
+//		Clear(errField).
+//		Value()
+//
+// Fields of any value type can be mixed; values are passed as uint64.
+// A Word is a value: each method returns a new Word and leaves the receiver unchanged.
+type Word[U storageType] struct {
+	value U
+}
+
+// NewWord returns a Word holding container.
+func NewWord[U storageType](container U) Word[U] {
+	return Word[U]{value: container}
+}
+
+// Set returns the word with field set to value.
+// Panics if the value does not fit in the field or is not one of its allowed values.
+func (w Word[U]) Set(field Setter[U], value uint64) Word[U] {
+	return Word[U]{value: field.wordSet(w.value, value)}
+}
+
+// Clear returns the word with the bits of field zeroed.
+func (w Word[U]) Clear(field Setter[U]) Word[U] {
+	return Word[U]{value: field.wordClear(w.value)}
+}
+
+// Get returns the value of field in the word.
+func (w Word[U]) Get(field Setter[U]) uint64 {
+	return field.wordGet(w.value)
+}
+
+// Value returns the container.
+func (w Word[U]) Value() U {
+	return w.value
+}
@@ -0,0 +1,69 @@
+package bitfield
+
+import "testing"
+
+func TestWord(t *testing.T) {
+	active := NewFlag[uint32](0)
+	priority := New[uint8, uint32](1, 3)
+	category := New[uint16, uint32](4, 4)
+	errField := New[uint64, uint32](8, 8)
+
+	w := NewWord[uint32](0xFF00).
+		Set(priority, 3).
+		Set(active, 1).
+		Set(category, 9).
+		Clear(errField)
+	if got := w.Value(); got != 0x97 {
+		t.Errorf("Value() = 0x%X, want 0x97", got)
+	}
+	if got := w.Get(priority); got != 3 {
+		t.Errorf("Get(priority) = %d, want 3", got)
+	}
+	if got := w.Get(active); got != 1 {
+		t.Errorf("Get(active) = %d, want 1", got)
+	}
+	if got := w.Clear(active).Set(category, 0).Value(); got != 0x06 {
+		t.Errorf("Value() = 0x%X, want 0x6", got)
+	}
+	if got := w.Value(); got != 0x97 {
+		t.Errorf("Value() after deriving another word = 0x%X, want 0x97", got)
+	}
+}
+
+func TestWord_Allowed(t *testing.T) {
+	mode := New[uint8, uint32](0, 4).WithAllowed(2, 5)
+
+	w := NewWord[uint32](0).Set(mode, 5)
+	if got := w.Clear(mode).Value(); got != 0 {
+		t.Errorf("Clear() = 0x%X, want 0", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Set(3) did not panic for a disallowed value")
+		}
+	}()
+	w.Set(mode, 3)
+}
+
+func TestWord_Panics(t *testing.T) {
+	w := NewWord[uint64](0)
+	tests := []struct {
+		name  string
+		field Setter[uint64]
+		value uint64
+	}{
+		{"out of range", New[uint8, uint64](0, 4), 16},
+		{"out of value type", New[uint8, uint64](0, 12), 256},
+		{"flag", NewFlag[uint64](3), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Set(%d) did not panic", tt.value)
+				}
+			}()
+			w.Set(tt.field, tt.value)
+		})
+	}
+}
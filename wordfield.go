@@ -0,0 +1,108 @@
+package bitfield
+
+import "fmt"
+
+// WordField represents a field of bits inside a slice of container words.
+// Unlike BitField, the field may cross word boundaries: bit Offset is counted
+// from the least significant bit of words[0], and bit i*w of the slice is the
+// least significant bit of words[i], where w is the bit size of U.
+// T represents the type of values that can be stored in the field.
+// U represents the type of the backing words.
+type WordField[T Unsigned, U storageType] struct {
+	Offset uint // Bit offset of the least significant bit of the field
+	Size   uint // Number of bits in the field
+}
+
+// NewWordField creates a new WordField with the given bit offset and size.
+// Note: This function doesn't perform validation, use SafeWordField for validated creation.
+func NewWordField[T Unsigned, U storageType](offset, size uint) WordField[T, U] {
+	return WordField[T, U]{Offset: offset, Size: size}
+}
+
+// SafeWordField creates a new WordField after validating the parameters.
+// Returns an error if size is 0 or exceeds the bit size of type T.
+func SafeWordField[T Unsigned, U storageType](offset, size uint) (WordField[T, U], error) {
+	if size == 0 || size > unsignedSizeOf[T]() {
+		return WordField[T, U]{}, ErrSizeOutOfRange
+	}
+	return NewWordField[T, U](offset, size), nil
+}
+
+// Words returns the number of words needed to hold the field.
+func (wf WordField[T, U]) Words() int {
+	width := unsignedSizeOf[U]()
+	return int((wf.Offset + wf.Size + width - 1) / width)
+}
+
+// IsValid checks if the value fits within the field.
+func (wf WordField[T, U]) IsValid(value T) bool {
+	return wf.Size >= 64 || uint64(value) < uint64(1)<<wf.Size
+}
+
+// Decode extracts the field from words.
+// Panics if words is too short to hold the field.
+func (wf WordField[T, U]) Decode(words []U) T {
+	wf.checkBounds(words)
+	width := unsignedSizeOf[U]()
+	var value uint64
+	pos, done := wf.Offset, uint(0)
+	for done < wf.Size {
+		bit := pos % width
+		n := min(width-bit, wf.Size-done)
+		value |= uint64(words[pos/width]>>bit) & lowBits(n) << done
+		pos += n
+		done += n
+	}
+	return T(value)
+}
+
+// Update writes value into the field within words, preserving all other bits.
+// Panics if the value is too large for the field or words is too short to hold the field.
+func (wf WordField[T, U]) Update(words []U, value T) {
+	if !wf.IsValid(value) {
+		panic(fmt.Sprintf("value %v out of range for %d-bit field", value, wf.Size))
+	}
+	wf.checkBounds(words)
+	width := unsignedSizeOf[U]()
+	v := uint64(value)
+	pos, done := wf.Offset, uint(0)
+	for done < wf.Size {
+		bit := pos % width
+		n := min(width-bit, wf.Size-done)
+		mask := U(lowBits(n)) << bit
+		chunk := U(v>>done) << bit
+		words[pos/width] = words[pos/width]&^mask | chunk&mask
+		pos += n
+		done += n
+	}
+}
+
+// Encode returns the shortest word slice that holds the field with the given value.
+// All bits outside the field are 0.
+// Panics if the value is too large for the field.
+func (wf WordField[T, U]) Encode(value T) []U {
+	words := make([]U, wf.Words())
+	wf.Update(words, value)
+	return words
+}
+
+// Clear zeroes out the bits of this field within words.
+// Panics if words is too short to hold the field.
+func (wf WordField[T, U]) Clear(words []U) {
+	wf.Update(words, 0)
+}
+
+// checkBounds panics if words cannot hold the field.
+func (wf WordField[T, U]) checkBounds(words []U) {
+	if need := wf.Words(); len(words) < need {
+		panic(fmt.Sprintf("field at bit %d with size %d needs %d words, got %d", wf.Offset, wf.Size, need, len(words)))
+	}
+}
+
+// lowBits returns a uint64 with the n least significant bits set.
+func lowBits(n uint) uint64 {
+	if n >= 64 {
+		return ^uint64(0)
+	}
+	return uint64(1)<<n - 1
+}
@@ -0,0 +1,126 @@
+package bitfield
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSafeWordField(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    uint
+		wantErr bool
+	}{
+		{"valid", 40, false},
+		{"full width", 64, false},
+		{"zero size", 0, true},
+		{"too wide", 65, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SafeWordField[uint64, uint32](28, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SafeWordField(28, %v): err = %v, want err = %v", tt.size, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWordField_Words(t *testing.T) {
+	tests := []struct {
+		offset, size uint
+		want         int
+	}{
+		{0, 32, 1},
+		{28, 40, 3},
+		{31, 2, 2},
+		{0, 64, 2},
+		{96, 1, 4},
+	}
+
+	for _, tt := range tests {
+		if got := NewWordField[uint64, uint32](tt.offset, tt.size).Words(); got != tt.want {
+			t.Errorf("Words() for offset=%d size=%d = %d, want %d", tt.offset, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestWordField_Encode(t *testing.T) {
+	// A 40-bit timestamp starting at bit 28 spans three 32-bit words.
+	wf := NewWordField[uint64, uint32](28, 40)
+	got := wf.Encode(0xAB_CDEF_0123)
+	want := []uint32{0x3000_0000, 0xBCDE_F012, 0x0000_000A}
+	if !slices.Equal(got, want) {
+		t.Errorf("Encode() = %08X, want %08X", got, want)
+	}
+	if v := wf.Decode(got); v != 0xAB_CDEF_0123 {
+		t.Errorf("Decode(Encode()) = 0x%X, want 0xABCDEF0123", v)
+	}
+}
+
+func TestWordField_Update(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset uint
+		size   uint
+		value  uint64
+	}{
+		{"within one word", 8, 16, 0xBEEF},
+		{"across boundary", 60, 8, 0xA5},
+		{"full width aligned", 64, 64, 0x0123456789ABCDEF},
+		{"full width unaligned", 3, 64, 0xFEDCBA9876543210},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := NewWordField[uint64, uint64](tt.offset, tt.size)
+			words := []uint64{^uint64(0), ^uint64(0), ^uint64(0)}
+			wf.Update(words, tt.value)
+			if got := wf.Decode(words); got != tt.value {
+				t.Errorf("Decode() = 0x%X, want 0x%X", got, tt.value)
+			}
+
+			// Every bit outside the field must still be set.
+			wf.Update(words, lowBits(tt.size))
+			for i, w := range words {
+				if w != ^uint64(0) {
+					t.Errorf("word %d = 0x%X, want all ones", i, w)
+				}
+			}
+		})
+	}
+}
+
+func TestWordField_Clear(t *testing.T) {
+	wf := NewWordField[uint16, uint32](30, 4)
+	words := []uint32{0xFFFFFFFF, 0xFFFFFFFF}
+	wf.Clear(words)
+	want := []uint32{0x3FFFFFFF, 0xFFFFFFFC}
+	if !slices.Equal(words, want) {
+		t.Errorf("Clear() = %08X, want %08X", words, want)
+	}
+}
+
+func TestWordField_Panics(t *testing.T) {
+	wf := NewWordField[uint8, uint32](30, 4)
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"decode short slice", func() { wf.Decode(make([]uint32, 1)) }},
+		{"update short slice", func() { wf.Update(make([]uint32, 1), 1) }},
+		{"value too large", func() { wf.Update(make([]uint32, 2), 16) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", tt.name)
+				}
+			}()
+			tt.fn()
+		})
+	}
+}